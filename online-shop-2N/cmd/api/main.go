@@ -4,10 +4,19 @@ import (
 	"log"
 	"online-shop-2N/pkg/config"
 	"online-shop-2N/pkg/di"
+	"os"
 )
 
 func main() {
 
+	// run the database migrations instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal("Failed to migrate database: ", err)
+		}
+		return
+	}
+
 	cfg, err := config.LoadConfig()
 
 	if err != nil {
@@ -19,7 +28,7 @@ func main() {
 		log.Fatal("Failed to initialize the api: ", err)
 	}
 
-	if server.Start(); err != nil {
+	if err := server.Start(); err != nil {
 		log.Fatal("failed to start server: ", err)
 	}
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/database"
+	"online-shop-2N/pkg/database/migrate"
+	"strconv"
+	"time"
+)
+
+// directory of the sql migration files from the module root
+const migrationsDir = "pkg/database/migrations"
+
+const migrateUsage = `usage: api migrate <command>
+
+commands:
+  up             apply all the pending migrations
+  down [steps]   revert the last applied migrations (default 1)
+  status         show the applied and pending migrations
+  create <name>  create new up and down sql migration files`
+
+// To run the migrate sub command with its args
+func runMigrate(args []string) error {
+
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+
+	// create only need the existing versions so it's not need config or database
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return fmt.Errorf("missing migration name\n%s", migrateUsage)
+		}
+		allMigrations, err := database.Migrations(config.Config{})
+		if err != nil {
+			return err
+		}
+		upFile, downFile, err := migrate.Create(migrationsDir, args[1], allMigrations)
+		if err != nil {
+			return err
+		}
+		log.Printf("created migration files %s and %s", upFile, downFile)
+		return nil
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load the config: %w", err)
+	}
+	// schema is not checked as the command is to migrate it
+	db, err := database.OpenDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := database.NewMigrator(db, cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		appliedMigrations, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range appliedMigrations {
+			log.Printf("applied migration %d_%s", migration.Version, migration.Name)
+		}
+		log.Printf("%d migrations applied", len(appliedMigrations))
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q for migrate down", args[1])
+			}
+		}
+		revertedMigrations, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		for _, migration := range revertedMigrations {
+			log.Printf("reverted migration %d_%s", migration.Version, migration.Name)
+		}
+		log.Printf("%d migrations reverted", len(revertedMigrations))
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%06d_%-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+
+	return nil
+}
@@ -8,9 +8,10 @@ import (
 	"online-shop-2N/pkg/api/handlers/interfaces"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
-	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/usecases"
 	usecaseInterface "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,18 +26,22 @@ func NewAdminHandler(adminUsecase usecaseInterface.AdminUseCase) interfaces.Admi
 	}
 }
 
-// // AdminSignUp godoc
-// // @summary api for admin to login
-// // @id AdminSignUp
-// // @tags Admin Login
-// // @Param input body domain.Admin{} true "inputs"
-// // @Router /admin/login [post]
-// // @Success 200 {object} responses.responses{} "successfully logged in"
-// // @Failure 400 {object} responses.responses{} "invalid input"
-// // @Failure 500 {object} responses.responses{} "failed to generate jwt token"
+// AdminSignUp godoc
+//
+//	@Summary		Create admin (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to create a new admin with its roles
+//	@Id				AdminSignUp
+//	@Tags			Admin Roles
+//	@Param			input	body	requests.AdminSignUp{}	true	"inputs"
+//	@Router			/admin/sign-up [post]
+//	@Success		201	{object}	responses.Response{}	"Successfully account created for admin"
+//	@Failure		400	{object}	responses.Response{}	"invalid input"
+//	@Failure		409	{object}	responses.Response{}	"admin already exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to create account for admin"
 func (a *adminHandler) AdminSignUp(ctx *gin.Context) {
 
-	var body models.Admin
+	var body requests.AdminSignUp
 
 	if err := ctx.ShouldBindJSON(&body); err != nil {
 		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
@@ -45,11 +50,20 @@ func (a *adminHandler) AdminSignUp(ctx *gin.Context) {
 
 	err := a.adminUseCase.SignUp(ctx, body)
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create account for admin", err, nil)
+		var statusCode int
+		switch {
+		case errors.Is(err, usecases.ErrAdminAlreadyExist):
+			statusCode = http.StatusConflict
+		case errors.Is(err, usecases.ErrRoleNotExist):
+			statusCode = http.StatusBadRequest
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to create account for admin", err, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, 200, "Successfully account created for admin", nil)
+	responses.SuccessResponse(ctx, http.StatusCreated, "Successfully account created for admin", nil)
 }
 
 // GetAllUsers godoc
@@ -61,15 +75,20 @@ func (a *adminHandler) AdminSignUp(ctx *gin.Context) {
 //	@Tags			Admin User
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/users [get]
 //	@Success		200	{object}	responses.responses{}	"Successfully got all users"
 //	@Success		204	{object}	responses.responses{}	"No users found"
 //	@Failure		500	{object}	responses.responses{}	"Failed to find all users"
 func (a *adminHandler) GetAllUsers(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	users, err := a.adminUseCase.FindAllUser(ctx, pagination)
+	users, meta, err := a.adminUseCase.FindAllUser(ctx, page)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all users", err, nil)
 		return
@@ -80,7 +99,7 @@ func (a *adminHandler) GetAllUsers(ctx *gin.Context) {
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all users", users)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all users", meta, users)
 }
 
 // BlockUser godoc
@@ -122,6 +141,7 @@ func (a *adminHandler) BlockUser(ctx *gin.Context) {
 //	@Param			end_date	query	string	false	"Sales report ending date"
 //	@Param			page_number	query	int		false	"Page Number"
 //	@Param			count		query	int		false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/sales [get]
 //	@Success		200	{object}	responses.responses{}	"ecommerce_sales_report.csv"
 //	@Success		204	{object}	responses.responses{}	"No sales report found"
@@ -139,15 +159,19 @@ func (c *adminHandler) GetFullSalesReport(ctx *gin.Context) {
 		return
 	}
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
 	reqData := requests.SalesReport{
 		StartDate:  startDate,
 		EndDate:    endDate,
-		Pagination: pagination,
+		Pagination: page,
 	}
 
-	salesReport, err := c.adminUseCase.GetFullSalesReport(ctx, reqData)
+	salesReport, meta, err := c.adminUseCase.GetFullSalesReport(ctx, reqData)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get full sales report", err, nil)
 		return
@@ -160,6 +184,11 @@ func (c *adminHandler) GetFullSalesReport(ctx *gin.Context) {
 
 	ctx.Header("Content-Type", "text/csv")
 	ctx.Header("Content-Disposition", "attachment;filename=ecommerce_sales_report.csv")
+	// csv have no meta, so the page details are given on headers
+	ctx.Header("X-Total-Count", strconv.FormatUint(meta.TotalCount, 10))
+	if meta.NextCursor != "" {
+		ctx.Header("X-Next-Cursor", meta.NextCursor)
+	}
 
 	csvWriter := csv.NewWriter(ctx.Writer)
 	headers := []string{
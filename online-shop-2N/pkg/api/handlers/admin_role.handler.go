@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllAdmins godoc
+//
+//	@Summary		Get all admins (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to get all admins with their roles
+//	@Id				GetAllAdmins
+//	@Tags			Admin Roles
+//	@Param			page_number	query	int	false	"Page Number"
+//	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
+//	@Router			/admin/admins [get]
+//	@Success		200	{object}	responses.Response{[]responses.Admin}	"Successfully found all admins"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find all admins"
+func (a *adminHandler) GetAllAdmins(ctx *gin.Context) {
+
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
+
+	admins, meta, err := a.adminUseCase.FindAllAdmins(ctx, page)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all admins", err, nil)
+		return
+	}
+
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all admins", meta, admins)
+}
+
+// UpdateAdminRoles godoc
+//
+//	@Summary		Change roles of admin (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to replace all the roles of an admin
+//	@Id				UpdateAdminRoles
+//	@Tags			Admin Roles
+//	@Param			admin_id	path	int						true	"Admin ID"
+//	@Param			input		body	requests.AdminRoles{}	true	"inputs"
+//	@Router			/admin/admins/{admin_id}/roles [put]
+//	@Success		200	{object}	responses.Response{}	"Successfully updated roles of admin"
+//	@Failure		400	{object}	responses.Response{}	"invalid input"
+//	@Failure		404	{object}	responses.Response{}	"admin not exist"
+//	@Failure		409	{object}	responses.Response{}	"can't remove super admin role from the last super admin"
+//	@Failure		500	{object}	responses.Response{}	"Failed to update roles of admin"
+func (a *adminHandler) UpdateAdminRoles(ctx *gin.Context) {
+
+	adminID, err := requests.GetParamAsUint(ctx, "admin_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	var body requests.AdminRoles
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
+		return
+	}
+
+	err = a.adminUseCase.UpdateAdminRoles(ctx, adminID, body.RoleIDs)
+	if err != nil {
+		var statusCode int
+		switch {
+		case errors.Is(err, usecases.ErrAdminNotExist):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, usecases.ErrRoleNotExist):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, usecases.ErrLastSuperAdmin):
+			statusCode = http.StatusConflict
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to update roles of admin", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully updated roles of admin")
+}
+
+// ResetAdminTwoFactor godoc
+//
+//	@Summary		Reset two factor authentication of admin (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to remove the two factor and recovery codes of an admin who lost them
+//	@Id				ResetAdminTwoFactor
+//	@Tags			Admin Roles
+//	@Param			admin_id	path	int	true	"Admin ID"
+//	@Router			/admin/admins/{admin_id}/two-factor [delete]
+//	@Success		200	{object}	responses.Response{}	"Successfully reset two factor of admin"
+//	@Failure		400	{object}	responses.Response{}	"invalid input"
+//	@Failure		404	{object}	responses.Response{}	"admin not exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to reset two factor of admin"
+func (a *adminHandler) ResetAdminTwoFactor(ctx *gin.Context) {
+
+	adminID, err := requests.GetParamAsUint(ctx, "admin_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	err = a.adminUseCase.ResetAdminTwoFactor(ctx, adminID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrAdminNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to reset two factor of admin", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully reset two factor of admin")
+}
+
+// GetAllRoles godoc
+//
+//	@Summary		Get all roles (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to get all roles with their permissions
+//	@Id				GetAllRoles
+//	@Tags			Admin Roles
+//	@Router			/admin/roles [get]
+//	@Success		200	{object}	responses.Response{[]responses.Role}	"Successfully found all roles"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find all roles"
+func (a *adminHandler) GetAllRoles(ctx *gin.Context) {
+
+	roles, err := a.adminUseCase.FindAllRoles(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all roles", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all roles", roles)
+}
+
+// GetAllPermissions godoc
+//
+//	@Summary		Get all permissions (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to get all the permissions which can be given to a role
+//	@Id				GetAllPermissions
+//	@Tags			Admin Roles
+//	@Router			/admin/roles/permissions [get]
+//	@Success		200	{object}	responses.Response{[]string}	"Successfully found all permissions"
+func (a *adminHandler) GetAllPermissions(ctx *gin.Context) {
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all permissions", commonConstant.AdminPermissions)
+}
+
+// SaveRole godoc
+//
+//	@Summary		Add a new role (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to add a new role with its permissions
+//	@Id				SaveRole
+//	@Tags			Admin Roles
+//	@Param			input	body	requests.Role{}	true	"inputs"
+//	@Router			/admin/roles [post]
+//	@Success		201	{object}	responses.Response{}	"Successfully role added"
+//	@Failure		400	{object}	responses.Response{}	"invalid input"
+//	@Failure		409	{object}	responses.Response{}	"role already exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to add role"
+func (a *adminHandler) SaveRole(ctx *gin.Context) {
+
+	var body requests.Role
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
+		return
+	}
+
+	roleID, err := a.adminUseCase.SaveRole(ctx, body)
+	if err != nil {
+		var statusCode int
+		switch {
+		case errors.Is(err, usecases.ErrInvalidPermission):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, usecases.ErrRoleAlreadyExist):
+			statusCode = http.StatusConflict
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to add role", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusCreated, "Successfully role added", gin.H{"role_id": roleID})
+}
+
+// UpdateRole godoc
+//
+//	@Summary		Update role (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to update a role and replace its permissions
+//	@Id				UpdateRole
+//	@Tags			Admin Roles
+//	@Param			role_id	path	int				true	"Role ID"
+//	@Param			input	body	requests.Role{}	true	"inputs"
+//	@Router			/admin/roles/{role_id} [put]
+//	@Success		200	{object}	responses.Response{}	"Successfully role updated"
+//	@Failure		400	{object}	responses.Response{}	"invalid input"
+//	@Failure		403	{object}	responses.Response{}	"super admin role can't be changed"
+//	@Failure		404	{object}	responses.Response{}	"role not exist"
+//	@Failure		409	{object}	responses.Response{}	"role already exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to update role"
+func (a *adminHandler) UpdateRole(ctx *gin.Context) {
+
+	roleID, err := requests.GetParamAsUint(ctx, "role_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	var body requests.Role
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
+		return
+	}
+
+	err = a.adminUseCase.UpdateRole(ctx, roleID, body)
+	if err != nil {
+		responses.ErrorResponse(ctx, roleErrorStatusCode(err), "Failed to update role", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully role updated")
+}
+
+// DeleteRole godoc
+//
+//	@Summary		Delete role (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to delete a role which is not assigned to any admin
+//	@Id				DeleteRole
+//	@Tags			Admin Roles
+//	@Param			role_id	path	int	true	"Role ID"
+//	@Router			/admin/roles/{role_id} [delete]
+//	@Success		200	{object}	responses.Response{}	"Successfully role deleted"
+//	@Failure		400	{object}	responses.Response{}	"invalid input"
+//	@Failure		403	{object}	responses.Response{}	"super admin role can't be removed"
+//	@Failure		404	{object}	responses.Response{}	"role not exist"
+//	@Failure		409	{object}	responses.Response{}	"role is assigned to admins"
+//	@Failure		500	{object}	responses.Response{}	"Failed to delete role"
+func (a *adminHandler) DeleteRole(ctx *gin.Context) {
+
+	roleID, err := requests.GetParamAsUint(ctx, "role_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	err = a.adminUseCase.DeleteRole(ctx, roleID)
+	if err != nil {
+		responses.ErrorResponse(ctx, roleErrorStatusCode(err), "Failed to delete role", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully role deleted")
+}
+
+// find the status code for errors of changing a role
+func roleErrorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidPermission):
+		return http.StatusBadRequest
+	case errors.Is(err, usecases.ErrSuperAdminRoleChange):
+		return http.StatusForbidden
+	case errors.Is(err, usecases.ErrRoleNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, usecases.ErrRoleAlreadyExist),
+		errors.Is(err, usecases.ErrRoleAssigned):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// UpdateRoleTwoFactor godoc
+//
+//	@Summary		Require two factor authentication for role (Super Admin)
+//	@Security		BearerAuth
+//	@Description	API for super admin to require two factor authentication from the admins of a role
+//	@Description	admins of the role can't use admin apis other than auth until they enable it
+//	@Id				UpdateRoleTwoFactor
+//	@Tags			Admin Roles
+//	@Param			role_id	path	int						true	"Role ID"
+//	@Param			input	body	requests.RoleTwoFactor{}	true	"inputs"
+//	@Router			/admin/roles/{role_id}/two-factor [put]
+//	@Success		200	{object}	responses.Response{}	"Successfully updated two factor requirement of role"
+//	@Failure		400	{object}	responses.Response{}	"invalid input"
+//	@Failure		404	{object}	responses.Response{}	"role not exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to update two factor requirement of role"
+func (a *adminHandler) UpdateRoleTwoFactor(ctx *gin.Context) {
+
+	roleID, err := requests.GetParamAsUint(ctx, "role_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	var body requests.RoleTwoFactor
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
+		return
+	}
+
+	err = a.adminUseCase.UpdateRoleTwoFactor(ctx, roleID, *body.Required)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrRoleNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to update two factor requirement of role", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully updated two factor requirement of role")
+}
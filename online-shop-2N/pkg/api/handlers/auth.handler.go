@@ -2,14 +2,18 @@ package handlers
 
 import (
 	"errors"
+	"math"
 	"net/http"
 	"online-shop-2N/pkg/api/handlers/interfaces"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
 	"online-shop-2N/pkg/config"
 	"online-shop-2N/pkg/models"
 	"online-shop-2N/pkg/services/tokens"
 	"online-shop-2N/pkg/usecases"
+	"online-shop-2N/pkg/utils"
+	"strconv"
 
 	usecaseInterface "online-shop-2N/pkg/usecases/interfaces"
 
@@ -46,6 +50,8 @@ func NewAuthHandler(authUsecase usecaseInterface.AuthUseCase, config config.Conf
 //	@Failure		400	{object}	responses.responses{}								"Invalid inputs"
 //	@Failure		403	{object}	responses.responses{}								"User blocked by admin"
 //	@Failure		401	{object}	responses.responses{}								"User not exist with given login credentials"
+//	@Failure		423	{object}	responses.responses{}								"Account locked by failed password attempts"
+//	@Failure		429	{object}	responses.responses{}								"Too many requests"
 //	@Failure		500	{object}	responses.responses{}								"Failed to login"
 func (c *AuthHandler) UserLogin(ctx *gin.Context) {
 
@@ -60,7 +66,10 @@ func (c *AuthHandler) UserLogin(ctx *gin.Context) {
 
 	if err != nil {
 
-		var statusCode int
+		var (
+			statusCode int
+			errorCode  string
+		)
 
 		switch {
 		case errors.Is(err, usecases.ErrEmptyLoginCredentials):
@@ -73,11 +82,13 @@ func (c *AuthHandler) UserLogin(ctx *gin.Context) {
 			statusCode = http.StatusUnauthorized
 		case errors.Is(err, usecases.ErrWrongPassword):
 			statusCode = http.StatusUnauthorized
+		case errors.Is(err, usecases.ErrAccountLocked):
+			statusCode, errorCode = http.StatusLocked, commonConstant.ErrorCodeAccountLocked
 		default:
 			statusCode = http.StatusInternalServerError
 		}
 
-		responses.ErrorResponse(ctx, statusCode, "Failed to login", err, nil)
+		errorResponseWithCode(ctx, statusCode, errorCode, "Failed to login", err)
 		return
 	}
 
@@ -97,6 +108,8 @@ func (c *AuthHandler) UserLogin(ctx *gin.Context) {
 //	@Failure		400	{object}	responses.responses{}							"Invalid Otp"
 //	@Failure		403	{object}	responses.responses{}							"User blocked by admin"
 //	@Failure		401	{object}	responses.responses{}							"User not exist with given login credentials"
+//	@Failure		422	{object}	responses.responses{}							"Invalid phone number of user"
+//	@Failure		429	{object}	responses.responses{}							"Otp already sent, wait before request a new otp"
 //	@Failure		500	{object}	responses.responses{}							"Failed to send otp"
 func (u *AuthHandler) UserLoginOtpSend(ctx *gin.Context) {
 
@@ -116,7 +129,10 @@ func (u *AuthHandler) UserLoginOtpSend(ctx *gin.Context) {
 	otpID, err := u.authUseCase.UserLoginOtpSend(ctx, body)
 
 	if err != nil {
-		var statusCode int
+		var (
+			statusCode int
+			errorCode  string
+		)
 
 		switch {
 		case errors.Is(err, usecases.ErrEmptyLoginCredentials):
@@ -125,10 +141,14 @@ func (u *AuthHandler) UserLoginOtpSend(ctx *gin.Context) {
 			statusCode = http.StatusForbidden
 		case errors.Is(err, usecases.ErrUserBlocked):
 			statusCode = http.StatusUnauthorized
+		case errors.Is(err, usecases.ErrInvalidPhoneNumber):
+			statusCode = http.StatusUnprocessableEntity
+		case errors.Is(err, usecases.ErrOtpResendCooldown):
+			statusCode, errorCode = http.StatusTooManyRequests, commonConstant.ErrorCodeOtpResendCooldown
 		default:
 			statusCode = http.StatusInternalServerError
 		}
-		responses.ErrorResponse(ctx, statusCode, "Failed to send otp", err, nil)
+		errorResponseWithCode(ctx, statusCode, errorCode, "Failed to send otp", err)
 		return
 	}
 
@@ -149,7 +169,8 @@ func (u *AuthHandler) UserLoginOtpSend(ctx *gin.Context) {
 //	@Success		200	{object}	responses.responses{data=responses.TokenResponse}	"Successfully user logged in"
 //	@Failure		400	{object}	responses.responses{}								"Invalid inputs"
 //	@Failure		401	{object}	responses.responses{}								"Otp not matched"
-//	@Failure		410	{object}	responses.responses{}								"Otp Expired"
+//	@Failure		410	{object}	responses.responses{}								"Otp Expired or already used"
+//	@Failure		429	{object}	responses.responses{}								"Too many wrong attempts on otp"
 //	@Failure		500	{object}	responses.responses{}								"Failed to verify otp
 func (c *AuthHandler) UserLoginOtpVerify(ctx *gin.Context) {
 
@@ -162,16 +183,21 @@ func (c *AuthHandler) UserLoginOtpVerify(ctx *gin.Context) {
 	// get the user using loginOtp usecases
 	userID, err := c.authUseCase.LoginOtpVerify(ctx, body)
 	if err != nil {
-		var statusCode int
+		var (
+			statusCode int
+			errorCode  string
+		)
 		switch {
-		case errors.Is(err, usecases.ErrOtpExpired):
+		case errors.Is(err, usecases.ErrOtpExpired), errors.Is(err, usecases.ErrOtpAlreadyUsed):
 			statusCode = http.StatusGone
 		case errors.Is(err, usecases.ErrInvalidOtp):
 			statusCode = http.StatusUnauthorized
+		case errors.Is(err, usecases.ErrOtpAttemptsExceeded):
+			statusCode, errorCode = http.StatusTooManyRequests, commonConstant.ErrorCodeOtpAttemptsExceeded
 		default:
 			statusCode = http.StatusInternalServerError
 		}
-		responses.ErrorResponse(ctx, statusCode, "Failed to verify otp", err, nil)
+		errorResponseWithCode(ctx, statusCode, errorCode, "Failed to verify otp", err)
 		return
 	}
 
@@ -186,7 +212,7 @@ func (c *AuthHandler) UserLoginOtpVerify(ctx *gin.Context) {
 //	@Tags			User Authentication
 //	@Param			input	body	requests.UserSignUp{}	true	"Input Fields"
 //	@Router			/auth/sign-up [post]
-//	@Success		200	{object}	responses.responses{data=responses.OTPResponse}	"Successfully account created and otp send to registered number"
+//	@Success		201	{object}	responses.responses{}								"Successfully account created and verification link sent to email"
 //	@Failure		400	{object}	responses.responses{}								"Invalid input"
 //	@Failure		409	{object}	responses.responses{}								"A verified user already exist with given user credentials"
 //	@Failure		500	{object}	responses.responses{}								"Failed to signup"
@@ -222,7 +248,7 @@ func (c *AuthHandler) UserSignUp(ctx *gin.Context) {
 	// }
 
 	responses.SuccessResponse(ctx, http.StatusCreated,
-		"Successfully account created")
+		"Successfully account created, verify the email with the link sent to sign in")
 }
 
 // UserSignUpVerify godoc
@@ -236,7 +262,8 @@ func (c *AuthHandler) UserSignUp(ctx *gin.Context) {
 //	@Success		200	{object}	responses.responses{data=responses.TokenResponse}	"Successfully otp verified for user sign up"
 //	@Failure		400	{object}	responses.responses{}								"Invalid inputs"
 //	@Failure		401	{object}	responses.responses{}								"Otp not matched"
-//	@Failure		410	{object}	responses.responses{}								"Otp Expired"
+//	@Failure		410	{object}	responses.responses{}								"Otp Expired or already used"
+//	@Failure		429	{object}	responses.responses{}								"Too many wrong attempts on otp"
 //	@Failure		500	{object}	responses.responses{}								"Failed to verify otp"
 func (c *AuthHandler) UserSignUpVerify(ctx *gin.Context) {
 
@@ -249,16 +276,21 @@ func (c *AuthHandler) UserSignUpVerify(ctx *gin.Context) {
 	// get the user using loginOtp usecases
 	userID, err := c.authUseCase.SingUpOtpVerify(ctx, body)
 	if err != nil {
-		var statusCode int
+		var (
+			statusCode int
+			errorCode  string
+		)
 		switch {
-		case errors.Is(err, usecases.ErrOtpExpired):
+		case errors.Is(err, usecases.ErrOtpExpired), errors.Is(err, usecases.ErrOtpAlreadyUsed):
 			statusCode = http.StatusGone
 		case errors.Is(err, usecases.ErrInvalidOtp):
 			statusCode = http.StatusUnauthorized
+		case errors.Is(err, usecases.ErrOtpAttemptsExceeded):
+			statusCode, errorCode = http.StatusTooManyRequests, commonConstant.ErrorCodeOtpAttemptsExceeded
 		default:
 			statusCode = http.StatusInternalServerError
 		}
-		responses.ErrorResponse(ctx, statusCode, "Failed to verify otp", err, nil)
+		errorResponseWithCode(ctx, statusCode, errorCode, "Failed to verify otp", err)
 		return
 	}
 
@@ -268,15 +300,19 @@ func (c *AuthHandler) UserSignUpVerify(ctx *gin.Context) {
 // AdminLogin godoc
 //
 //	@Summary		Login with password (Admin)
-//	@Description	API for admin to login with password
+//	@Description	API for admin to login with password, when two factor is enabled a challenge token is returned
+//	@Description	instead of tokens which should be completed with the two factor code
 //	@Id				AdminLogin
 //	@Tags			Admin Authentication
 //	@Param			input	body	requests.Login{}	true	"Login credentials"
 //	@Router			/admin/auth/sign-in [post]
 //	@Success		200	{object}	responses.responses{data=responses.TokenResponse}	"Successfully logged in"
+//	@Success		202	{object}	responses.responses{data=responses.TwoFactorChallenge}	"Two factor code required to complete sign in"
 //	@Failure		400	{object}	responses.responses{}								"Invalid input"
 //	@Failure		401	{object}	responses.responses{}								"Wrong password"
 //	@Failure		404	{object}	responses.responses{}								"Admin not exist with this details"
+//	@Failure		423	{object}	responses.responses{}								"Account locked by failed password attempts"
+//	@Failure		429	{object}	responses.responses{}								"Too many requests"
 //	@Failure		500	{object}	responses.responses{}								"Failed to login"
 func (c *AuthHandler) AdminLogin(ctx *gin.Context) {
 
@@ -287,10 +323,13 @@ func (c *AuthHandler) AdminLogin(ctx *gin.Context) {
 		return
 	}
 
-	adminID, err := c.authUseCase.AdminLogin(ctx, body)
+	adminID, challenge, err := c.authUseCase.AdminLogin(ctx, body)
 	if err != nil {
 
-		var statusCode int
+		var (
+			statusCode int
+			errorCode  string
+		)
 
 		switch {
 		case errors.Is(err, usecases.ErrEmptyLoginCredentials):
@@ -299,11 +338,18 @@ func (c *AuthHandler) AdminLogin(ctx *gin.Context) {
 			statusCode = http.StatusNotFound
 		case errors.Is(err, usecases.ErrWrongPassword):
 			statusCode = http.StatusUnauthorized
+		case errors.Is(err, usecases.ErrAccountLocked):
+			statusCode, errorCode = http.StatusLocked, commonConstant.ErrorCodeAccountLocked
 		default:
 			statusCode = http.StatusInternalServerError
 		}
 
-		responses.ErrorResponse(ctx, statusCode, "Failed to login", err, nil)
+		errorResponseWithCode(ctx, statusCode, errorCode, "Failed to login", err)
+		return
+	}
+
+	if challenge.TwoFactorRequired {
+		responses.SuccessResponse(ctx, http.StatusAccepted, "Two factor code required to complete sign in", challenge)
 		return
 	}
 
@@ -315,57 +361,69 @@ func (c *AuthHandler) AdminLogin(ctx *gin.Context) {
 // a common function for it.(differentiate user by user type )
 func (c *AuthHandler) setupTokenAndResponse(ctx *gin.Context, tokenUser tokens.UserType, userID uint) {
 
-	tokenParams := usecaseInterface.GenerateTokenParams{
-		UserID:   userID,
-		UserType: tokenUser,
-	}
-
-	accessToken, err := c.authUseCase.GenerateAccessToken(ctx, tokenParams)
-
+	tokenRes, err := c.generateTokens(ctx, tokenUser, userID)
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to generate access tokens", err, nil)
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to generate tokens", err, nil)
 		return
 	}
 
-	refreshToken, err := c.authUseCase.GenerateRefreshToken(ctx, usecaseInterface.GenerateTokenParams{
-		UserID:   userID,
-		UserType: tokenUser,
+	authorizationValue := authorizationType + " " + tokenRes.AccessToken
+	ctx.Header(authorizationHeaderKey, authorizationValue)
+
+	ctx.Header("access_token", tokenRes.AccessToken)
+	ctx.Header("refresh_token", tokenRes.RefreshToken)
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully logged in", tokenRes)
+}
+
+// To generate the refresh and access tokens on a new session for the signed in device
+func (c *AuthHandler) generateTokens(ctx *gin.Context, tokenUser tokens.UserType,
+	userID uint) (responses.TokenResponse, error) {
+
+	refreshSession, err := c.authUseCase.GenerateRefreshToken(ctx, usecaseInterface.GenerateTokenParams{
+		UserID:    userID,
+		UserType:  tokenUser,
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
 	})
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to generate refresh tokens", err, nil)
-		return
+		return responses.TokenResponse{}, utils.PrependMessageToError(err, "failed to generate refresh tokens")
 	}
 
-	authorizationValue := authorizationType + " " + accessToken
-	ctx.Header(authorizationHeaderKey, authorizationValue)
-
-	ctx.Header("access_token", accessToken)
-	ctx.Header("refresh_token", refreshToken)
+	tokenParams := usecaseInterface.GenerateTokenParams{
+		UserID:    userID,
+		UserType:  tokenUser,
+		SessionID: refreshSession.SessionID,
+	}
 
-	tokenRes := responses.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	accessToken, err := c.authUseCase.GenerateAccessToken(ctx, tokenParams)
+	if err != nil {
+		return responses.TokenResponse{}, utils.PrependMessageToError(err, "failed to generate access tokens")
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully logged in", tokenRes)
+	return responses.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshSession.RefreshToken,
+	}, nil
 }
 
 // UserRenewAccessToken godoc
 //
 //	@Summary		Renew Access tokens (User)
-//	@Description	API for user to renew access tokens using refresh tokens
+//	@Description	API for user to renew access tokens using refresh tokens, the refresh token is rotated with a new one
+//	@Description	and using an already rotated refresh token revoke all the tokens of its session
 //	@Security		ApiKeyAuth
 //	@Id				UserRenewAccessToken
 //	@Tags			User Authentication
 //	@Param			input	body	requests.RefreshToken{}	true	"Refresh tokens"
 //	@Router			/auth/renew-access-tokens [post]
-//	@Success		200	{object}	responses.responses{}	"Successfully generated access tokens using refresh tokens"
-//	@Failure		400	{object}	responses.responses{}	"Invalid input"
-//	@Failure		401	{object}	responses.responses{}	"Invalid refresh tokens"
-//	@Failure		404	{object}	responses.responses{}	"No session found for the given refresh tokens"
-//	@Failure		410	{object}	responses.responses{}	"Refresh tokens expired"
-//	@Failure		403	{object}	responses.responses{}	"Refresh tokens blocked"
-//	@Failure		500	{object}	responses.responses{}	"Failed generate access tokens"
+//	@Success		200	{object}	responses.responses{data=responses.TokenResponse}	"Successfully generated access tokens using refresh tokens"
+//	@Failure		400	{object}	responses.responses{}								"Invalid input"
+//	@Failure		401	{object}	responses.responses{}								"Invalid refresh tokens"
+//	@Failure		404	{object}	responses.responses{}								"No session found for the given refresh tokens"
+//	@Failure		410	{object}	responses.responses{}								"Refresh tokens expired"
+//	@Failure		403	{object}	responses.responses{}								"Refresh tokens blocked or reused"
+//	@Failure		500	{object}	responses.responses{}								"Failed generate access tokens"
 func (c *AuthHandler) UserRenewAccessToken() gin.HandlerFunc {
 	return c.renewAccessToken(tokens.User)
 }
@@ -373,19 +431,20 @@ func (c *AuthHandler) UserRenewAccessToken() gin.HandlerFunc {
 // AdminRenewAccessToken godoc
 //
 //	@Summary		Renew Access tokens (Admin)
-//	@Description	API for admin to renew access tokens using refresh tokens
+//	@Description	API for admin to renew access tokens using refresh tokens, the refresh token is rotated with a new one
+//	@Description	and using an already rotated refresh token revoke all the tokens of its session
 //	@Security		ApiKeyAuth
 //	@Id				AdminRenewAccessToken
 //	@Tags			Admin Authentication
 //	@Param			input	body	requests.RefreshToken{}	true	"Refresh tokens"
 //	@Router			/admin/auth/renew-access-tokens [post]
-//	@Success		200	{object}	responses.responses{}	"Successfully generated access tokens using refresh tokens"
-//	@Failure		400	{object}	responses.responses{}	"Invalid input"
-//	@Failure		401	{object}	responses.responses{}	"Invalid refresh tokens"
-//	@Failure		404	{object}	responses.responses{}	"No session found for the given refresh tokens"
-//	@Failure		410	{object}	responses.responses{}	"Refresh tokens expired"
-//	@Failure		403	{object}	responses.responses{}	"Refresh tokens blocked"
-//	@Failure		500	{object}	responses.responses{}	"Failed generate access tokens"
+//	@Success		200	{object}	responses.responses{data=responses.TokenResponse}	"Successfully generated access tokens using refresh tokens"
+//	@Failure		400	{object}	responses.responses{}								"Invalid input"
+//	@Failure		401	{object}	responses.responses{}								"Invalid refresh tokens"
+//	@Failure		404	{object}	responses.responses{}								"No session found for the given refresh tokens"
+//	@Failure		410	{object}	responses.responses{}								"Refresh tokens expired"
+//	@Failure		403	{object}	responses.responses{}								"Refresh tokens blocked or reused"
+//	@Failure		500	{object}	responses.responses{}								"Failed generate access tokens"
 func (c *AuthHandler) AdminRenewAccessToken() gin.HandlerFunc {
 	return c.renewAccessToken(tokens.Admin)
 }
@@ -401,7 +460,11 @@ func (c *AuthHandler) renewAccessToken(tokenUser tokens.UserType) gin.HandlerFun
 			return
 		}
 
-		refreshSession, err := c.authUseCase.VerifyAndGetRefreshTokenSession(ctx, body.RefreshToken, tokenUser)
+		refreshSession, err := c.authUseCase.RotateRefreshToken(ctx, body.RefreshToken, usecaseInterface.GenerateTokenParams{
+			UserType:  tokenUser,
+			UserAgent: ctx.Request.UserAgent(),
+			IPAddress: ctx.ClientIP(),
+		})
 
 		if err != nil {
 			var statusCode int
@@ -413,7 +476,8 @@ func (c *AuthHandler) renewAccessToken(tokenUser tokens.UserType) gin.HandlerFun
 				statusCode = http.StatusNotFound
 			case errors.Is(err, usecases.ErrRefreshSessionExpired):
 				statusCode = http.StatusGone
-			case errors.Is(err, usecases.ErrRefreshSessionBlocked):
+			case errors.Is(err, usecases.ErrRefreshSessionBlocked),
+				errors.Is(err, usecases.ErrRefreshTokenReused):
 				statusCode = http.StatusForbidden
 			default:
 				statusCode = http.StatusInternalServerError
@@ -423,8 +487,9 @@ func (c *AuthHandler) renewAccessToken(tokenUser tokens.UserType) gin.HandlerFun
 		}
 
 		accessTokenParams := usecaseInterface.GenerateTokenParams{
-			UserID:   refreshSession.UserID,
-			UserType: tokenUser,
+			UserID:    refreshSession.UserID,
+			UserType:  tokenUser,
+			SessionID: refreshSession.SessionID,
 		}
 
 		accessToken, err := c.authUseCase.GenerateAccessToken(ctx, accessTokenParams)
@@ -436,9 +501,234 @@ func (c *AuthHandler) renewAccessToken(tokenUser tokens.UserType) gin.HandlerFun
 		cookieName := "auth-" + string(tokenUser)
 		ctx.SetCookie(cookieName, accessToken, 15*60, "", "", false, true)
 
-		accessTokenRes := responses.TokenResponse{
-			AccessToken: accessToken,
+		tokenRes := responses.TokenResponse{
+			AccessToken:  accessToken,
+			RefreshToken: refreshSession.RefreshToken,
+		}
+		responses.SuccessResponse(ctx, http.StatusOK, "Successfully generated access tokens using refresh tokens", tokenRes)
+	}
+}
+
+// UserLogout godoc
+//
+//	@Summary		Logout (User)
+//	@Description	API for user to logout from current device by revoking the refresh and access tokens of the session
+//	@Security		BearerAuth
+//	@Id				UserLogout
+//	@Tags			User Authentication
+//	@Router			/auth/logout [post]
+//	@Success		200	{object}	responses.responses{}	"Successfully logged out"
+//	@Failure		500	{object}	responses.responses{}	"Failed to logout"
+func (c *AuthHandler) UserLogout() gin.HandlerFunc {
+	return c.logout(tokens.User)
+}
+
+// AdminLogout godoc
+//
+//	@Summary		Logout (Admin)
+//	@Description	API for admin to logout from current device by revoking the refresh and access tokens of the session
+//	@Security		BearerAuth
+//	@Id				AdminLogout
+//	@Tags			Admin Authentication
+//	@Router			/admin/auth/logout [post]
+//	@Success		200	{object}	responses.responses{}	"Successfully logged out"
+//	@Failure		500	{object}	responses.responses{}	"Failed to logout"
+func (c *AuthHandler) AdminLogout() gin.HandlerFunc {
+	return c.logout(tokens.Admin)
+}
+
+// common functionality of logout for user and admin
+func (c *AuthHandler) logout(tokenUser tokens.UserType) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		userID := utils.GetUserIdFromContext(ctx)
+		sessionID := utils.GetSessionIdFromContext(ctx)
+		tokenID := utils.GetTokenIdFromContext(ctx)
+
+		err := c.authUseCase.Logout(ctx, tokenUser, userID, sessionID, tokenID)
+		if err != nil {
+			responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to logout", err, nil)
+			return
+		}
+
+		ctx.SetCookie("auth-"+string(tokenUser), "", -1, "", "", false, true)
+
+		responses.SuccessResponse(ctx, http.StatusOK, "Successfully logged out")
+	}
+}
+
+// UserLogoutAll godoc
+//
+//	@Summary		Logout from all devices (User)
+//	@Description	API for user to logout from all devices by revoking the refresh and access tokens of all sessions
+//	@Security		BearerAuth
+//	@Id				UserLogoutAll
+//	@Tags			User Authentication
+//	@Router			/auth/logout-all [post]
+//	@Success		200	{object}	responses.responses{}	"Successfully logged out from all devices"
+//	@Failure		500	{object}	responses.responses{}	"Failed to logout from all devices"
+func (c *AuthHandler) UserLogoutAll() gin.HandlerFunc {
+	return c.logoutAll(tokens.User)
+}
+
+// AdminLogoutAll godoc
+//
+//	@Summary		Logout from all devices (Admin)
+//	@Description	API for admin to logout from all devices by revoking the refresh and access tokens of all sessions
+//	@Security		BearerAuth
+//	@Id				AdminLogoutAll
+//	@Tags			Admin Authentication
+//	@Router			/admin/auth/logout-all [post]
+//	@Success		200	{object}	responses.responses{}	"Successfully logged out from all devices"
+//	@Failure		500	{object}	responses.responses{}	"Failed to logout from all devices"
+func (c *AuthHandler) AdminLogoutAll() gin.HandlerFunc {
+	return c.logoutAll(tokens.Admin)
+}
+
+// common functionality of logout from all devices for user and admin
+func (c *AuthHandler) logoutAll(tokenUser tokens.UserType) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		userID := utils.GetUserIdFromContext(ctx)
+
+		err := c.authUseCase.RevokeAllSessions(ctx, tokenUser, userID)
+		if err != nil {
+			responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to logout from all devices", err, nil)
+			return
 		}
-		responses.SuccessResponse(ctx, http.StatusOK, "Successfully generated access tokens using refresh tokens", accessTokenRes)
+
+		ctx.SetCookie("auth-"+string(tokenUser), "", -1, "", "", false, true)
+
+		responses.SuccessResponse(ctx, http.StatusOK, "Successfully logged out from all devices")
+	}
+}
+
+// GetAllSessionsUser godoc
+//
+//	@Summary		Get all active sessions (User)
+//	@Description	API for user to get all signed in devices with its ip address
+//	@Security		BearerAuth
+//	@Id				GetAllSessionsUser
+//	@Tags			User Authentication
+//	@Router			/auth/sessions [get]
+//	@Success		200	{object}	responses.responses{data=[]responses.Session}	"Successfully found all active sessions"
+//	@Failure		500	{object}	responses.responses{}							"Failed to find all active sessions"
+func (c *AuthHandler) GetAllSessionsUser() gin.HandlerFunc {
+	return c.getAllSessions(tokens.User)
+}
+
+// GetAllSessionsAdmin godoc
+//
+//	@Summary		Get all active sessions (Admin)
+//	@Description	API for admin to get all signed in devices with its ip address
+//	@Security		BearerAuth
+//	@Id				GetAllSessionsAdmin
+//	@Tags			Admin Authentication
+//	@Router			/admin/auth/sessions [get]
+//	@Success		200	{object}	responses.responses{data=[]responses.Session}	"Successfully found all active sessions"
+//	@Failure		500	{object}	responses.responses{}							"Failed to find all active sessions"
+func (c *AuthHandler) GetAllSessionsAdmin() gin.HandlerFunc {
+	return c.getAllSessions(tokens.Admin)
+}
+
+// common functionality of finding active sessions for user and admin
+func (c *AuthHandler) getAllSessions(tokenUser tokens.UserType) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		userID := utils.GetUserIdFromContext(ctx)
+		sessionID := utils.GetSessionIdFromContext(ctx)
+
+		sessions, err := c.authUseCase.FindAllSessions(ctx, tokenUser, userID, sessionID)
+		if err != nil {
+			responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all active sessions", err, nil)
+			return
+		}
+
+		if len(sessions) == 0 {
+			responses.SuccessResponse(ctx, http.StatusOK, "No active sessions found")
+			return
+		}
+
+		responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all active sessions", sessions)
 	}
 }
+
+// RevokeSessionUser godoc
+//
+//	@Summary		Revoke a session (User)
+//	@Description	API for user to logout a signed in device by its session id
+//	@Security		BearerAuth
+//	@Id				RevokeSessionUser
+//	@Tags			User Authentication
+//	@Param			session_id	path	string	true	"Session ID"
+//	@Router			/auth/sessions/{session_id} [delete]
+//	@Success		200	{object}	responses.responses{}	"Successfully revoked session"
+//	@Failure		404	{object}	responses.responses{}	"No active session found with this id"
+//	@Failure		500	{object}	responses.responses{}	"Failed to revoke session"
+func (c *AuthHandler) RevokeSessionUser() gin.HandlerFunc {
+	return c.revokeSession(tokens.User)
+}
+
+// RevokeSessionAdmin godoc
+//
+//	@Summary		Revoke a session (Admin)
+//	@Description	API for admin to logout a signed in device by its session id
+//	@Security		BearerAuth
+//	@Id				RevokeSessionAdmin
+//	@Tags			Admin Authentication
+//	@Param			session_id	path	string	true	"Session ID"
+//	@Router			/admin/auth/sessions/{session_id} [delete]
+//	@Success		200	{object}	responses.responses{}	"Successfully revoked session"
+//	@Failure		404	{object}	responses.responses{}	"No active session found with this id"
+//	@Failure		500	{object}	responses.responses{}	"Failed to revoke session"
+func (c *AuthHandler) RevokeSessionAdmin() gin.HandlerFunc {
+	return c.revokeSession(tokens.Admin)
+}
+
+// common functionality of revoking a session for user and admin
+func (c *AuthHandler) revokeSession(tokenUser tokens.UserType) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		userID := utils.GetUserIdFromContext(ctx)
+		sessionID := ctx.Param("session_id")
+
+		err := c.authUseCase.RevokeSession(ctx, tokenUser, userID, sessionID)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if errors.Is(err, usecases.ErrSessionNotExist) {
+				statusCode = http.StatusNotFound
+			}
+			responses.ErrorResponse(ctx, statusCode, "Failed to revoke session", err, nil)
+			return
+		}
+
+		responses.SuccessResponse(ctx, http.StatusOK, "Successfully revoked session")
+	}
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Get public keys of tokens
+//	@Description	API for other services to get the public keys to verify the tokens offline, keys are empty for HS256 tokens
+//	@Id				GetJWKS
+//	@Tags			Token Keys
+//	@Router			/.well-known/jwks.json [get]
+//	@Success		200	{object}	tokens.JWKSet{}	"json web key set"
+func (c *AuthHandler) GetJWKS(ctx *gin.Context) {
+
+	jwks := c.authUseCase.FindJWKS(ctx)
+
+	// the keys only change on deploy so other services can cache it for a while
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, jwks)
+}
+
+// error response with the code of error, Retry-After header is set when the error can be retried after a time
+func errorResponseWithCode(ctx *gin.Context, statusCode int, errorCode, message string, err error) {
+
+	if retryAfter, ok := utils.GetRetryAfter(err); ok {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+
+	responses.ErrorResponseWithCode(ctx, statusCode, errorCode, message, err, nil)
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/services/oauth"
+	"online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/usecases"
+	"online-shop-2N/pkg/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// cookie which keep the signed oauth flow from the redirect to provider until the callback
+	oauthFlowCookieName = "oauth_flow"
+	// redirect mode which give the tokens to frontend instead of the one time code
+	oauthRedirectModeToken = "token"
+)
+
+// GetOAuthProviders godoc
+//
+//	@Summary		Get oauth providers (User)
+//	@Description	API for user to get the names of enabled oauth providers to sign in
+//	@Id				GetOAuthProviders
+//	@Tags			User Authentication
+//	@Router			/auth/oauth/providers [get]
+//	@Success		200	{object}	responses.responses{data=responses.OAuthProviders}	"Successfully found oauth providers"
+func (c *AuthHandler) GetOAuthProviders(ctx *gin.Context) {
+
+	providers := c.authUseCase.FindOAuthProviders(ctx)
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found oauth providers", providers)
+}
+
+// UserOAuthLogin godoc
+//
+//	@Summary		Sign in with oauth provider (User)
+//	@Description	API for user to redirect to the oauth provider to sign in
+//	@Id				UserOAuthLogin
+//	@Tags			User Authentication
+//	@Param			provider	path	string	true	"Name of provider"
+//	@Router			/auth/oauth/{provider}/login [get]
+//	@Success		307	"Redirected to oauth provider"
+//	@Failure		404	{object}	responses.responses{}	"Provider not exist"
+//	@Failure		500	{object}	responses.responses{}	"Failed to start sign in"
+func (c *AuthHandler) UserOAuthLogin(ctx *gin.Context) {
+
+	c.redirectToOAuthProvider(ctx, ctx.Param("provider"))
+}
+
+// UserOAuthCallback godoc
+//
+//	@Summary		Oauth provider callback (User)
+//	@Description	API for oauth provider to callback after authentication, user is signed in or the provider is linked
+//	@Description	a new account is created when no account exist, account of same email is used only when the email is verified
+//	@Description	user is redirected to the oauth callback of frontend with the one time code (or tokens on fragment on token mode),
+//	@Description	linked provider as linked or the code of error as error on query
+//	@Id				UserOAuthCallback
+//	@Tags			User Authentication
+//	@Param			provider	path	string	true	"Name of provider"
+//	@Router			/auth/oauth/{provider}/callback [get]
+//	@Success		303	"Redirected to frontend"
+func (c *AuthHandler) UserOAuthCallback(ctx *gin.Context) {
+
+	c.completeOAuth(ctx, ctx.Param("provider"))
+}
+
+// UserOAuthToken godoc
+//
+//	@Summary		Exchange oauth login code (User)
+//	@Description	API for user to exchange the one time code which is given on redirect after oauth sign in for tokens
+//	@Id				UserOAuthToken
+//	@Tags			User Authentication
+//	@Param			input	body	requests.OAuthLoginCode{}	true	"Login code"
+//	@Router			/auth/oauth/token [post]
+//	@Success		200	{object}	responses.responses{data=responses.TokenResponse}	"Successfully logged in"
+//	@Failure		400	{object}	responses.responses{}								"Invalid inputs"
+//	@Failure		401	{object}	responses.responses{}								"Invalid or expired login code"
+//	@Failure		500	{object}	responses.responses{}								"Failed to login"
+func (c *AuthHandler) UserOAuthToken(ctx *gin.Context) {
+
+	var body requests.OAuthLoginCode
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	userID, err := c.authUseCase.UseOAuthLoginCode(ctx, body.Code)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrInvalidUserToken) {
+			statusCode = http.StatusUnauthorized
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to login", err, nil)
+		return
+	}
+
+	c.setupTokenAndResponse(ctx, tokens.User, userID)
+}
+
+// GetAllUserIdentities godoc
+//
+//	@Summary		Get linked oauth providers (User)
+//	@Security		BearerAuth
+//	@Description	API for user to get the oauth providers which are linked to the account
+//	@Id				GetAllUserIdentities
+//	@Tags			User Account
+//	@Router			/account/identities [get]
+//	@Success		200	{object}	responses.responses{data=[]responses.UserIdentity}	"Successfully found linked providers"
+//	@Failure		500	{object}	responses.responses{}								"Failed to find linked providers"
+func (c *AuthHandler) GetAllUserIdentities(ctx *gin.Context) {
+
+	userID := utils.GetUserIdFromContext(ctx)
+
+	identities, err := c.authUseCase.FindAllUserIdentities(ctx, userID)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find linked providers", err, nil)
+		return
+	}
+
+	if len(identities) == 0 {
+		responses.SuccessResponse(ctx, http.StatusOK, "No providers linked to the account")
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found linked providers", identities)
+}
+
+// LinkUserIdentity godoc
+//
+//	@Summary		Link oauth provider (User)
+//	@Security		BearerAuth
+//	@Description	API for user to get the url of oauth provider to link it, provider is linked on the callback
+//	@Description	flow of oauth is kept on cookie, so it should be requested with credentials
+//	@Id				LinkUserIdentity
+//	@Tags			User Account
+//	@Param			provider	path	string	true	"Name of provider"
+//	@Router			/account/identities/{provider} [post]
+//	@Success		200	{object}	responses.responses{data=responses.OAuthURL}	"Successfully oauth url created"
+//	@Failure		404	{object}	responses.responses{}						"Provider not exist"
+//	@Failure		500	{object}	responses.responses{}						"Failed to link provider"
+func (c *AuthHandler) LinkUserIdentity(ctx *gin.Context) {
+
+	userID := utils.GetUserIdFromContext(ctx)
+	providerName := ctx.Param("provider")
+
+	authURL, err := c.beginOAuth(ctx, providerName, userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrOAuthProviderNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to link provider", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully oauth url created", responses.OAuthURL{
+		AuthURL: authURL,
+	})
+}
+
+// UnlinkUserIdentity godoc
+//
+//	@Summary		Unlink oauth provider (User)
+//	@Security		BearerAuth
+//	@Description	API for user to unlink an oauth provider, the only sign in method of account can't be unlinked
+//	@Id				UnlinkUserIdentity
+//	@Tags			User Account
+//	@Param			provider	path	string	true	"Name of provider"
+//	@Router			/account/identities/{provider} [delete]
+//	@Success		200	{object}	responses.responses{}	"Successfully provider unlinked"
+//	@Failure		404	{object}	responses.responses{}	"Provider not linked"
+//	@Failure		409	{object}	responses.responses{}	"Only sign in method of account"
+//	@Failure		500	{object}	responses.responses{}	"Failed to unlink provider"
+func (c *AuthHandler) UnlinkUserIdentity(ctx *gin.Context) {
+
+	userID := utils.GetUserIdFromContext(ctx)
+	providerName := ctx.Param("provider")
+
+	err := c.authUseCase.UnlinkUserIdentity(ctx, userID, providerName)
+	if err != nil {
+		var statusCode int
+		switch {
+		case errors.Is(err, usecases.ErrIdentityNotLinked):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, usecases.ErrLastSignInMethod):
+			statusCode = http.StatusConflict
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to unlink provider", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully provider unlinked")
+}
+
+// To redirect the user to the provider to sign in
+func (c *AuthHandler) redirectToOAuthProvider(ctx *gin.Context, providerName string) {
+
+	authURL, err := c.beginOAuth(ctx, providerName, 0)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrOAuthProviderNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to start sign in with provider", err, nil)
+		return
+	}
+
+	ctx.Redirect(http.StatusTemporaryRedirect, authURL)
+}
+
+// To get the url of provider and keep the signed flow on cookie to check it on callback
+func (c *AuthHandler) beginOAuth(ctx *gin.Context, providerName string, linkUserID uint) (string, error) {
+
+	authURL, signedFlow, err := c.authUseCase.GetOAuthURL(ctx, providerName, linkUserID)
+	if err != nil {
+		return "", err
+	}
+
+	setOAuthFlowCookie(ctx, signedFlow, int(oauth.FlowDuration.Seconds()))
+
+	return authURL, nil
+}
+
+// To complete the flow of callback, user is signed in or the provider is linked to the user who started the flow,
+// the result is given to the frontend by redirect
+func (c *AuthHandler) completeOAuth(ctx *gin.Context, providerName string) {
+
+	var params requests.OAuthCallback
+
+	if err := ctx.ShouldBind(&params); err != nil {
+		c.redirectOAuthError(ctx, commonConstant.ErrorCodeOAuthInvalidRequest, err)
+		return
+	}
+
+	signedFlow, _ := ctx.Cookie(oauthFlowCookieName)
+	// flow of cookie can be used only once
+	setOAuthFlowCookie(ctx, "", -1)
+
+	flow, err := c.authUseCase.VerifyOAuthFlow(ctx, providerName, signedFlow, params.State)
+	if err != nil {
+		c.redirectOAuthError(ctx, commonConstant.ErrorCodeOAuthInvalidState, err)
+		return
+	}
+
+	if params.Error != "" {
+		c.redirectOAuthError(ctx, commonConstant.ErrorCodeOAuthCancelled,
+			errors.New(params.Error+" "+params.ErrorDescription))
+		return
+	}
+
+	if flow.LinkUserID != 0 {
+		err := c.authUseCase.LinkUserIdentity(ctx, flow.LinkUserID, flow, params.Code)
+		if err != nil {
+			c.redirectOAuthError(ctx, oauthErrorCode(err), err)
+			return
+		}
+		c.redirectToFrontend(ctx, url.Values{"linked": {providerName}}, nil)
+		return
+	}
+
+	userID, err := c.authUseCase.OAuthLogin(ctx, flow, params.Code)
+	if err != nil {
+		c.redirectOAuthError(ctx, oauthErrorCode(err), err)
+		return
+	}
+
+	// tokens are given on the fragment so they are not sent to any server or kept on logs
+	if c.config.OAuthRedirectMode == oauthRedirectModeToken {
+		tokenRes, err := c.generateTokens(ctx, tokens.User, userID)
+		if err != nil {
+			c.redirectOAuthError(ctx, commonConstant.ErrorCodeInternal, err)
+			return
+		}
+		c.redirectToFrontend(ctx, nil, url.Values{
+			"access_token":  {tokenRes.AccessToken},
+			"refresh_token": {tokenRes.RefreshToken},
+		})
+		return
+	}
+
+	loginCode, err := c.authUseCase.NewOAuthLoginCode(ctx, userID)
+	if err != nil {
+		c.redirectOAuthError(ctx, commonConstant.ErrorCodeInternal, err)
+		return
+	}
+
+	c.redirectToFrontend(ctx, url.Values{"code": {loginCode}}, nil)
+}
+
+// To redirect to frontend with the code of error, the error details are only logged
+func (c *AuthHandler) redirectOAuthError(ctx *gin.Context, code string, err error) {
+
+	log.Printf("\033[0;31m%s\033[0m\n", err.Error())
+
+	c.redirectToFrontend(ctx, url.Values{"error": {code}}, nil)
+}
+
+// To redirect to the oauth callback of frontend with the values on query and fragment
+func (c *AuthHandler) redirectToFrontend(ctx *gin.Context, query, fragment url.Values) {
+
+	redirectURL := c.config.OAuthFrontendRedirectURL
+	if redirectURL == "" {
+		redirectURL = strings.TrimRight(c.config.FrontendURL, "/") + "/oauth/callback"
+	}
+
+	if len(query) > 0 {
+		separator := "?"
+		if strings.Contains(redirectURL, "?") {
+			separator = "&"
+		}
+		redirectURL += separator + query.Encode()
+	}
+	if len(fragment) > 0 {
+		redirectURL += "#" + fragment.Encode()
+	}
+
+	// see other make the browser to get the frontend even when the provider posted the callback
+	ctx.Redirect(http.StatusSeeOther, redirectURL)
+}
+
+// To set the cookie of signed flow, the provider which post the callback (apple) is a cross site request
+// so the cookie is sent on it only with same site none which need a secure cookie
+func setOAuthFlowCookie(ctx *gin.Context, value string, maxAge int) {
+
+	secure := ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+	if secure {
+		ctx.SetSameSite(http.SameSiteNoneMode)
+	} else {
+		ctx.SetSameSite(http.SameSiteLaxMode)
+	}
+
+	ctx.SetCookie(oauthFlowCookieName, value, maxAge, "/", "", secure, true)
+}
+
+// code of the errors of oauth sign in and link which is given to frontend
+func oauthErrorCode(err error) string {
+
+	switch {
+	case errors.Is(err, usecases.ErrOAuthFailed):
+		return commonConstant.ErrorCodeOAuthFailed
+	case errors.Is(err, usecases.ErrUserBlocked):
+		return commonConstant.ErrorCodeUserBlocked
+	case errors.Is(err, usecases.ErrOAuthProviderNotExist):
+		return commonConstant.ErrorCodeOAuthProviderNotExist
+	case errors.Is(err, usecases.ErrOAuthAccountNotLinked):
+		return commonConstant.ErrorCodeOAuthAccountNotLinked
+	case errors.Is(err, usecases.ErrIdentityAlreadyLinked), errors.Is(err, usecases.ErrProviderAlreadyLinked):
+		return commonConstant.ErrorCodeOAuthAlreadyLinked
+	case errors.Is(err, usecases.ErrOAuthEmailNotShared):
+		return commonConstant.ErrorCodeOAuthEmailNotShared
+	case errors.Is(err, usecases.ErrOAuthEmailNotVerified):
+		return commonConstant.ErrorCodeOAuthEmailNotVerified
+	default:
+		return commonConstant.ErrorCodeInternal
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserForgotPassword godoc
+//
+//	@Summary		Forgot password (User)
+//	@Description	API for user to get password reset link on email, response is same when email is not registered
+//	@Id				UserForgotPassword
+//	@Tags			User Authentication
+//	@Param			input	body	requests.Email{}	true	"Email input"
+//	@Router			/auth/password/forgot [post]
+//	@Success		200	{object}	responses.responses{}	"Password reset link sent if the email is registered"
+//	@Failure		400	{object}	responses.responses{}	"Invalid inputs"
+//	@Failure		500	{object}	responses.responses{}	"Failed to send password reset link"
+func (c *AuthHandler) UserForgotPassword(ctx *gin.Context) {
+
+	var body requests.Email
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
+		return
+	}
+
+	err := c.authUseCase.ForgotPassword(ctx, body.Email)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to send password reset link", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Password reset link sent if the email is registered")
+}
+
+// UserResetPassword godoc
+//
+//	@Summary		Reset password (User)
+//	@Description	API for user to set a new password with the token of password reset link, user is logged out from all devices
+//	@Id				UserResetPassword
+//	@Tags			User Authentication
+//	@Param			input	body	requests.ResetPassword{}	true	"Reset password input"
+//	@Router			/auth/password/reset [post]
+//	@Success		200	{object}	responses.responses{}	"Successfully password reset"
+//	@Failure		400	{object}	responses.responses{}	"Invalid inputs"
+//	@Failure		401	{object}	responses.responses{}	"Invalid, expired or already used token"
+//	@Failure		500	{object}	responses.responses{}	"Failed to reset password"
+func (c *AuthHandler) UserResetPassword(ctx *gin.Context) {
+
+	var body requests.ResetPassword
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	err := c.authUseCase.ResetPassword(ctx, body)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrInvalidUserToken) {
+			statusCode = http.StatusUnauthorized
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to reset password", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully password reset")
+}
+
+// UserVerifyEmail godoc
+//
+//	@Summary		Verify email (User)
+//	@Description	API for user to verify email with the token of verification link sent on sign up
+//	@Id				UserVerifyEmail
+//	@Tags			User Authentication
+//	@Param			input	body	requests.VerifyEmail{}	true	"Verify email input"
+//	@Router			/auth/email/verify [post]
+//	@Success		200	{object}	responses.responses{}	"Successfully email verified"
+//	@Failure		400	{object}	responses.responses{}	"Invalid inputs"
+//	@Failure		401	{object}	responses.responses{}	"Invalid, expired or already used token"
+//	@Failure		500	{object}	responses.responses{}	"Failed to verify email"
+func (c *AuthHandler) UserVerifyEmail(ctx *gin.Context) {
+
+	var body requests.VerifyEmail
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
+		return
+	}
+
+	err := c.authUseCase.VerifyEmail(ctx, body.Token)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrInvalidUserToken) {
+			statusCode = http.StatusUnauthorized
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to verify email", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully email verified")
+}
+
+// UserResendEmailVerification godoc
+//
+//	@Summary		Resend email verification (User)
+//	@Description	API for user to get the email verification link again, response is same when email is not registered
+//	@Id				UserResendEmailVerification
+//	@Tags			User Authentication
+//	@Param			input	body	requests.Email{}	true	"Email input"
+//	@Router			/auth/email/verify/resend [post]
+//	@Success		200	{object}	responses.responses{}	"Email verification link sent if the email is registered"
+//	@Failure		400	{object}	responses.responses{}	"Invalid inputs"
+//	@Failure		409	{object}	responses.responses{}	"Email already verified"
+//	@Failure		500	{object}	responses.responses{}	"Failed to send email verification link"
+func (c *AuthHandler) UserResendEmailVerification(ctx *gin.Context) {
+
+	var body requests.Email
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, body)
+		return
+	}
+
+	err := c.authUseCase.ResendEmailVerification(ctx, body.Email)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrEmailAlreadyVerified) {
+			statusCode = http.StatusConflict
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to send email verification link", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Email verification link sent if the email is registered")
+}
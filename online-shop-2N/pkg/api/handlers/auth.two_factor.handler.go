@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/usecases"
+	"online-shop-2N/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminLoginTwoFactor godoc
+//
+//	@Summary		Login with two factor code (Admin)
+//	@Description	API for admin to complete the sign in challenge with the code of authenticator app or a recovery code
+//	@Id				AdminLoginTwoFactor
+//	@Tags			Admin Authentication
+//	@Param			input	body	requests.AdminTwoFactorLogin{}	true	"Two factor login input"
+//	@Router			/admin/auth/sign-in/two-factor [post]
+//	@Success		200	{object}	responses.responses{data=responses.TokenResponse}	"Successfully logged in"
+//	@Failure		400	{object}	responses.responses{}								"Invalid input"
+//	@Failure		401	{object}	responses.responses{}								"Invalid challenge token or code"
+//	@Failure		429	{object}	responses.responses{}								"Too many wrong attempts on challenge"
+//	@Failure		500	{object}	responses.responses{}								"Failed to login"
+func (c *AuthHandler) AdminLoginTwoFactor(ctx *gin.Context) {
+
+	var body requests.AdminTwoFactorLogin
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	adminID, err := c.authUseCase.AdminLoginTwoFactor(ctx, body)
+	if err != nil {
+		var (
+			statusCode int
+			errorCode  string
+		)
+		switch {
+		case errors.Is(err, usecases.ErrInvalidChallengeToken), errors.Is(err, usecases.ErrInvalidTwoFactorCode),
+			errors.Is(err, usecases.ErrTwoFactorNotEnabled):
+			statusCode = http.StatusUnauthorized
+		case errors.Is(err, usecases.ErrChallengeAttemptsExceed):
+			statusCode, errorCode = http.StatusTooManyRequests, commonConstant.ErrorCodeChallengeAttemptsExceeded
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+		errorResponseWithCode(ctx, statusCode, errorCode, "Failed to login", err)
+		return
+	}
+
+	c.setupTokenAndResponse(ctx, tokens.Admin, adminID)
+}
+
+// GetAdminTwoFactor godoc
+//
+//	@Summary		Get two factor status (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to check two factor is enabled, required by its roles and the unused recovery codes count
+//	@Id				GetAdminTwoFactor
+//	@Tags			Admin Authentication
+//	@Router			/admin/auth/two-factor [get]
+//	@Success		200	{object}	responses.responses{data=responses.TwoFactorStatus}	"Successfully found two factor status"
+//	@Failure		500	{object}	responses.responses{}								"Failed to find two factor status"
+func (c *AuthHandler) GetAdminTwoFactor(ctx *gin.Context) {
+
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	status, err := c.authUseCase.FindAdminTwoFactorStatus(ctx, adminID)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find two factor status", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found two factor status", status)
+}
+
+// SetupAdminTwoFactor godoc
+//
+//	@Summary		Set up two factor (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to get a new secret and provisioning uri to show as qr code for authenticator apps
+//	@Description	two factor is enabled only after a code of it verified with the enable api
+//	@Id				SetupAdminTwoFactor
+//	@Tags			Admin Authentication
+//	@Router			/admin/auth/two-factor/setup [post]
+//	@Success		200	{object}	responses.responses{data=responses.TwoFactorSetup}	"Successfully two factor set up"
+//	@Failure		409	{object}	responses.responses{}								"Two factor already enabled"
+//	@Failure		500	{object}	responses.responses{}								"Failed to set up two factor"
+func (c *AuthHandler) SetupAdminTwoFactor(ctx *gin.Context) {
+
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	setup, err := c.authUseCase.SetupAdminTwoFactor(ctx, adminID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrTwoFactorAlreadyEnabled) {
+			statusCode = http.StatusConflict
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to set up two factor", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully two factor set up", setup)
+}
+
+// EnableAdminTwoFactor godoc
+//
+//	@Summary		Enable two factor (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to enable two factor with a code of authenticator app, recovery codes are shown only on this response
+//	@Id				EnableAdminTwoFactor
+//	@Tags			Admin Authentication
+//	@Param			input	body	requests.TwoFactorCode{}	true	"Code of authenticator app"
+//	@Router			/admin/auth/two-factor/enable [post]
+//	@Success		200	{object}	responses.responses{data=responses.RecoveryCodes}	"Successfully two factor enabled"
+//	@Failure		400	{object}	responses.responses{}								"Invalid input or two factor not set up"
+//	@Failure		401	{object}	responses.responses{}								"Invalid code"
+//	@Failure		409	{object}	responses.responses{}								"Two factor already enabled"
+//	@Failure		500	{object}	responses.responses{}								"Failed to enable two factor"
+func (c *AuthHandler) EnableAdminTwoFactor(ctx *gin.Context) {
+
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	var body requests.TwoFactorCode
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	recoveryCodes, err := c.authUseCase.EnableAdminTwoFactor(ctx, adminID, body.Code)
+	if err != nil {
+		responses.ErrorResponse(ctx, twoFactorErrorStatusCode(err), "Failed to enable two factor", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully two factor enabled", recoveryCodes)
+}
+
+// DisableAdminTwoFactor godoc
+//
+//	@Summary		Disable two factor (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to disable two factor with a code of authenticator app or a recovery code
+//	@Id				DisableAdminTwoFactor
+//	@Tags			Admin Authentication
+//	@Param			input	body	requests.TwoFactorCode{}	true	"Code of authenticator app or recovery code"
+//	@Router			/admin/auth/two-factor/disable [post]
+//	@Success		200	{object}	responses.responses{}	"Successfully two factor disabled"
+//	@Failure		400	{object}	responses.responses{}	"Invalid input or two factor not enabled"
+//	@Failure		401	{object}	responses.responses{}	"Invalid code"
+//	@Failure		403	{object}	responses.responses{}	"Two factor required by role of admin"
+//	@Failure		500	{object}	responses.responses{}	"Failed to disable two factor"
+func (c *AuthHandler) DisableAdminTwoFactor(ctx *gin.Context) {
+
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	var body requests.TwoFactorCode
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	err := c.authUseCase.DisableAdminTwoFactor(ctx, adminID, body.Code)
+	if err != nil {
+		responses.ErrorResponse(ctx, twoFactorErrorStatusCode(err), "Failed to disable two factor", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully two factor disabled")
+}
+
+// RegenerateAdminRecoveryCodes godoc
+//
+//	@Summary		Regenerate recovery codes (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to replace all the recovery codes with new codes, new codes are shown only on this response
+//	@Id				RegenerateAdminRecoveryCodes
+//	@Tags			Admin Authentication
+//	@Param			input	body	requests.TwoFactorCode{}	true	"Code of authenticator app or recovery code"
+//	@Router			/admin/auth/two-factor/recovery-codes [post]
+//	@Success		200	{object}	responses.responses{data=responses.RecoveryCodes}	"Successfully recovery codes regenerated"
+//	@Failure		400	{object}	responses.responses{}								"Invalid input or two factor not enabled"
+//	@Failure		401	{object}	responses.responses{}								"Invalid code"
+//	@Failure		500	{object}	responses.responses{}								"Failed to regenerate recovery codes"
+func (c *AuthHandler) RegenerateAdminRecoveryCodes(ctx *gin.Context) {
+
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	var body requests.TwoFactorCode
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	recoveryCodes, err := c.authUseCase.RegenerateAdminRecoveryCodes(ctx, adminID, body.Code)
+	if err != nil {
+		responses.ErrorResponse(ctx, twoFactorErrorStatusCode(err), "Failed to regenerate recovery codes", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully recovery codes regenerated", recoveryCodes)
+}
+
+// status code of the errors of two factor management
+func twoFactorErrorStatusCode(err error) int {
+
+	switch {
+	case errors.Is(err, usecases.ErrTwoFactorNotSetup), errors.Is(err, usecases.ErrTwoFactorNotEnabled):
+		return http.StatusBadRequest
+	case errors.Is(err, usecases.ErrInvalidTwoFactorCode):
+		return http.StatusUnauthorized
+	case errors.Is(err, usecases.ErrTwoFactorRequired):
+		return http.StatusForbidden
+	case errors.Is(err, usecases.ErrTwoFactorAlreadyEnabled):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
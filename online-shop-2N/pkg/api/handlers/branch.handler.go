@@ -99,15 +99,20 @@ func (b *brandHandler) FindOne(ctx *gin.Context) {
 // @Id				FindAllBrands
 // @Param			page_number	query	int	false	"Page number"
 // @Param			count		query	int	false	"Count"
+// @Param			cursor		query	string	false	"Cursor of next page"
 // @Router			/admin/brands [get]
 // @Success		200	{object}	responses.Response{[]models.Brand{}}	"successfully found all brands"
 // @Success		204	{object}	responses.Response{[]models.Brand{}}	"there is no brands to show"
 // @Failure		500	{object}	responses.Response{}	"failed to find brand"
 func (b *brandHandler) FindAll(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	brands, err := b.brandUseCase.FindAll(pagination)
+	brands, meta, err := b.brandUseCase.FindAll(page)
 
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "failed to find all brands", err, nil)
@@ -118,7 +123,7 @@ func (b *brandHandler) FindAll(ctx *gin.Context) {
 		responses.SuccessResponse(ctx, http.StatusNoContent, "there is no brands available to show")
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "successfully found all brands", brands)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "successfully found all brands", meta, brands)
 }
 
 // @Summary		Save Brand
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"online-shop-2N/pkg/api/handlers/interfaces"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/catalog"
+	"online-shop-2N/pkg/usecases"
+	usecaseInterface "online-shop-2N/pkg/usecases/interfaces"
+	"online-shop-2N/pkg/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type catalogHandler struct {
+	catalogUseCase usecaseInterface.CatalogUseCase
+}
+
+func NewCatalogHandler(catalogUseCase usecaseInterface.CatalogUseCase) interfaces.CatalogHandler {
+	return &catalogHandler{
+		catalogUseCase: catalogUseCase,
+	}
+}
+
+// ImportCatalog godoc
+//
+//	@Summary		Import catalog (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to import product items from a csv or json lines file, the import is run on background.
+//	@Description	each row is validated against categories, brands and variation options and upserted by sku,
+//	@Description	status of the import and the errors of rows can be found with the id of returned job
+//	@ID				ImportCatalog
+//	@Tags			Admin Catalog
+//	@Accept			multipart/form-data
+//	@Param			file	formData	file	true	"CSV or json lines file"
+//	@Param			format	formData	string	false	"Format of file, taken from extension of file when not given"	Enums(csv, jsonl)
+//	@Router			/admin/catalog/import [post]
+//	@Success		202	{object}	responses.Response{data=models.CatalogImportJob}	"Successfully started catalog import"
+//	@Failure		400	{object}	responses.Response{}								"Invalid file"
+//	@Failure		500	{object}	responses.Response{}								"Failed to start catalog import"
+func (c *catalogHandler) ImportCatalog(ctx *gin.Context) {
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxCatalogImportBytes)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindFormValueMessage, err, nil)
+		return
+	}
+
+	format, err := catalog.FormatOfFile(fileHeader.Filename)
+	if value := ctx.Request.PostFormValue("format"); value != "" {
+		format, err = catalog.ParseFormat(value)
+	}
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindFormValueMessage, err, nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to open file", err, nil)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read file", err, nil)
+		return
+	}
+
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	job, err := c.catalogUseCase.SaveImportJob(ctx, adminID, fileHeader.Filename, format, data)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrEmptyCatalogImportFile) ||
+			errors.Is(err, catalog.ErrInvalidHeader) || errors.Is(err, catalog.ErrInvalidFormat) {
+			statusCode = http.StatusBadRequest
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to start catalog import", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusAccepted, "Successfully started catalog import", job)
+}
+
+// GetImportJob godoc
+//
+//	@Summary		Get catalog import (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to get the status and count of created, updated and failed rows of a catalog import
+//	@ID				GetImportJob
+//	@Tags			Admin Catalog
+//	@Param			job_id	path	int	true	"Import Job ID"
+//	@Router			/admin/catalog/import/{job_id} [get]
+//	@Success		200	{object}	responses.Response{data=models.CatalogImportJob}	"Successfully found catalog import"
+//	@Failure		400	{object}	responses.Response{}								"Invalid input"
+//	@Failure		404	{object}	responses.Response{}								"Catalog import not exist"
+//	@Failure		500	{object}	responses.Response{}								"Failed to get catalog import"
+func (c *catalogHandler) GetImportJob(ctx *gin.Context) {
+
+	jobID, err := requests.GetParamAsUint(ctx, "job_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	job, err := c.catalogUseCase.FindImportJob(ctx, jobID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrCatalogImportJobNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to get catalog import", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found catalog import", job)
+}
+
+// GetAllImportErrors godoc
+//
+//	@Summary		Get all errors of catalog import (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to get the failed rows of a catalog import with the reason, in the order of rows
+//	@ID				GetAllImportErrors
+//	@Tags			Admin Catalog
+//	@Param			job_id		path	int		true	"Import Job ID"
+//	@Param			page_number	query	int		false	"Page Number"
+//	@Param			count		query	int		false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
+//	@Router			/admin/catalog/import/{job_id}/errors [get]
+//	@Success		200	{object}	responses.Response{data=[]models.CatalogImportError}	"Successfully found all errors of catalog import"
+//	@Failure		400	{object}	responses.Response{}									"Invalid input"
+//	@Failure		404	{object}	responses.Response{}									"Catalog import not exist"
+//	@Failure		500	{object}	responses.Response{}									"Failed to get errors of catalog import"
+func (c *catalogHandler) GetAllImportErrors(ctx *gin.Context) {
+
+	jobID, err := requests.GetParamAsUint(ctx, "job_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
+
+	importErrors, meta, err := c.catalogUseCase.FindAllImportErrors(ctx, jobID, page)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrCatalogImportJobNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to get errors of catalog import", err, nil)
+		return
+	}
+
+	if len(importErrors) == 0 {
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No errors found for catalog import", meta)
+		return
+	}
+
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all errors of catalog import",
+		meta, importErrors)
+}
+
+// ExportCatalog godoc
+//
+//	@Summary		Export catalog (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to download all product items of catalog as a csv or json lines file,
+//	@Description	the file is in the same format of import so it can be edited and imported back
+//	@ID				ExportCatalog
+//	@Tags			Admin Catalog
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Param			format	query	string	false	"Format of file, default is csv"	Enums(csv, jsonl)
+//	@Router			/admin/catalog/export [get]
+//	@Success		200	{file}		file					"Catalog file"
+//	@Failure		400	{object}	responses.Response{}	"Invalid format"
+//	@Failure		500	{object}	responses.Response{}	"Failed to export catalog"
+func (c *catalogHandler) ExportCatalog(ctx *gin.Context) {
+
+	format := catalog.FormatCSV
+	if value := ctx.Query("format"); value != "" {
+		var err error
+		if format, err = catalog.ParseFormat(value); err != nil {
+			responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+			return
+		}
+	}
+
+	fileName := fmt.Sprintf("catalog-%s.%s", time.Now().Format("20060102-150405"), format)
+
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	ctx.Status(http.StatusOK)
+
+	err := c.catalogUseCase.ExportCatalog(ctx, format, ctx.Writer)
+	if err == nil {
+		return
+	}
+
+	// the error can only be responded when nothing is written yet
+	if ctx.Writer.Written() {
+		log.Printf("failed to export catalog after the rows are written \nerror:%v", err)
+		ctx.Abort()
+		return
+	}
+	ctx.Writer.Header().Del("Content-Type")
+	ctx.Writer.Header().Del("Content-Disposition")
+	responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to export catalog", err, nil)
+}
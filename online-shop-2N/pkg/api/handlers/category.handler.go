@@ -33,14 +33,19 @@ func NewCategoryHandler(categoryUsecase usecaseInterface.CategoryUseCase) interf
 //	@Produce		json
 //	@Param			page_number	query	int	false	"Page number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/categories [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully retrieved all categories"
 //	@Failure		500	{object}	responses.Response{}	"Failed to retrieve categories"
 func (p *CategoryHandler) GetAllCategories(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	categories, err := p.categoryUseCase.FindAllCategories(ctx, pagination)
+	categories, meta, err := p.categoryUseCase.FindAllCategories(ctx, page)
 
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to retrieve categories", err, nil)
@@ -48,11 +53,11 @@ func (p *CategoryHandler) GetAllCategories(ctx *gin.Context) {
 	}
 
 	if len(categories) == 0 {
-		responses.SuccessResponse(ctx, http.StatusOK, "No categories found", nil)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No categories found", meta, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully retrieved all categories", categories)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully retrieved all categories", meta, categories)
 }
 
 // SaveCategory godoc
@@ -63,25 +63,30 @@ func (c *CouponHandler) SaveCoupon(ctx *gin.Context) {
 //	@Id				GetAllCouponsAdmin
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/coupons [get]
 //	@Success		200	{object}	responses.Response{}	"successfully go all the coupons
 //	@Failure		500	{object}	responses.Response{}	"failed to get all coupons"
 func (c *CouponHandler) GetAllCouponsAdmin(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	coupons, err := c.couponUseCase.GetAllCoupons(ctx, pagination)
+	coupons, meta, err := c.couponUseCase.GetAllCoupons(ctx, page)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get all coupons", err, nil)
 		return
 	}
 
 	if len(coupons) == 0 {
-		responses.SuccessResponse(ctx, http.StatusOK, "No Coupons found", nil)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No Coupons found", meta, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found coupons", coupons)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found coupons", meta, coupons)
 }
 
 // GetAllCouponsForUser godoc
@@ -93,15 +98,20 @@ func (c *CouponHandler) GetAllCouponsAdmin(ctx *gin.Context) {
 //	@id				GetAllCouponsForUser
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count Of Order"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/account/coupons [get]
 //	@Success		200	{object}	responses.Response{}	""Successfully	found	all	coupons	for	user"
 //	@Failure		500	{object}	responses.Response{}	"Failed to find all user"
 func (c *CouponHandler) GetAllCouponsForUser(ctx *gin.Context) {
 
 	userID := utils.GetUserIdFromContext(ctx)
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	coupons, err := c.couponUseCase.GetCouponsForUser(ctx, userID, pagination)
+	coupons, meta, err := c.couponUseCase.GetCouponsForUser(ctx, userID, page)
 
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to find all user", err, nil)
@@ -109,11 +119,11 @@ func (c *CouponHandler) GetAllCouponsForUser(ctx *gin.Context) {
 	}
 
 	if len(coupons) == 0 {
-		responses.SuccessResponse(ctx, http.StatusOK, "No coupons found", nil)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No coupons found", meta, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all coupons for user", coupons)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all coupons for user", meta, coupons)
 }
 
 // UpdateCoupon godoc
@@ -7,5 +7,17 @@ type AdminHandler interface {
 	BlockUser(ctx *gin.Context)
 
 	AdminSignUp(ctx *gin.Context)
+	GetAllAdmins(ctx *gin.Context)
+	UpdateAdminRoles(ctx *gin.Context)
+	ResetAdminTwoFactor(ctx *gin.Context)
+
+	// role
+	GetAllRoles(ctx *gin.Context)
+	GetAllPermissions(ctx *gin.Context)
+	SaveRole(ctx *gin.Context)
+	UpdateRole(ctx *gin.Context)
+	DeleteRole(ctx *gin.Context)
+	UpdateRoleTwoFactor(ctx *gin.Context)
+
 	GetFullSalesReport(ctx *gin.Context)
 }
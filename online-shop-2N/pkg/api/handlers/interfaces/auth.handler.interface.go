@@ -9,15 +9,43 @@ type AuthHandler interface {
 	UserSignUpVerify(ctx *gin.Context)
 
 	UserGoogleAuthInitialize(ctx *gin.Context)
-	UserGoogleAuthLoginPage(ctx *gin.Context)
 	UserGoogleAuthCallBack(ctx *gin.Context)
 
+	GetOAuthProviders(ctx *gin.Context)
+	UserOAuthLogin(ctx *gin.Context)
+	UserOAuthCallback(ctx *gin.Context)
+	UserOAuthToken(ctx *gin.Context)
+	GetAllUserIdentities(ctx *gin.Context)
+	LinkUserIdentity(ctx *gin.Context)
+	UnlinkUserIdentity(ctx *gin.Context)
+
 	UserLoginOtpVerify(ctx *gin.Context)
 	UserLoginOtpSend(ctx *gin.Context)
 
+	UserForgotPassword(ctx *gin.Context)
+	UserResetPassword(ctx *gin.Context)
+	UserVerifyEmail(ctx *gin.Context)
+	UserResendEmailVerification(ctx *gin.Context)
+
 	UserRenewAccessToken() gin.HandlerFunc
+	UserLogout() gin.HandlerFunc
+	UserLogoutAll() gin.HandlerFunc
+	GetAllSessionsUser() gin.HandlerFunc
+	RevokeSessionUser() gin.HandlerFunc
 
 	//admin side
 	AdminLogin(ctx *gin.Context)
+	AdminLoginTwoFactor(ctx *gin.Context)
+	GetAdminTwoFactor(ctx *gin.Context)
+	SetupAdminTwoFactor(ctx *gin.Context)
+	EnableAdminTwoFactor(ctx *gin.Context)
+	DisableAdminTwoFactor(ctx *gin.Context)
+	RegenerateAdminRecoveryCodes(ctx *gin.Context)
 	AdminRenewAccessToken() gin.HandlerFunc
+	AdminLogout() gin.HandlerFunc
+	AdminLogoutAll() gin.HandlerFunc
+	GetAllSessionsAdmin() gin.HandlerFunc
+	RevokeSessionAdmin() gin.HandlerFunc
+
+	GetJWKS(ctx *gin.Context)
 }
@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/gin-gonic/gin"
+
+type CatalogHandler interface {
+	ImportCatalog(ctx *gin.Context)
+	GetImportJob(ctx *gin.Context)
+	GetAllImportErrors(ctx *gin.Context)
+	ExportCatalog(ctx *gin.Context)
+}
@@ -12,11 +12,17 @@ type OrderHandler interface {
 	SubmitReturnRequest(ctx *gin.Context)
 	GetAllOrderItemsUser() func(ctx *gin.Context)
 	GetUserOrder(ctx *gin.Context)
+	GetOrderStatusHistoryUser(ctx *gin.Context)
+	GetOrderPaymentsUser(ctx *gin.Context)
+	GetOrderRefundsUser(ctx *gin.Context)
 
 	//admin side
 	GetAllShopOrders(ctx *gin.Context)
 	GetAllOrderItemsAdmin() func(ctx *gin.Context)
 	UpdateOrderStatus(ctx *gin.Context)
+	GetOrderStatusHistoryAdmin(ctx *gin.Context)
+	GetOrderPaymentsAdmin(ctx *gin.Context)
+	GetOrderRefundsAdmin(ctx *gin.Context)
 	GetAllOrderReturns(ctx *gin.Context)
 	GetAllPendingReturns(ctx *gin.Context)
 	UpdateReturnRequest(ctx *gin.Context)
@@ -24,4 +30,5 @@ type OrderHandler interface {
 	// wallet
 	GetUserWallet(ctx *gin.Context)
 	GetUserWalletTransactions(ctx *gin.Context)
+	GetWalletReconciliation(ctx *gin.Context)
 }
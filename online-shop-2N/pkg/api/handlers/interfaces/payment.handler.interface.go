@@ -12,10 +12,13 @@ type PaymentHandler interface {
 	GetAllPaymentMethodsUser() func(ctx *gin.Context)
 
 	PaymentCOD(ctx *gin.Context)
+	PaymentWallet(ctx *gin.Context)
 
 	RazorpayCheckout(ctx *gin.Context)
 	RazorpayVerify(ctx *gin.Context)
+	RazorpayWebhook(ctx *gin.Context)
 
 	StripePaymentVeify(ctx *gin.Context)
 	StripPaymentCheckout(ctx *gin.Context)
+	StripeWebhook(ctx *gin.Context)
 }
@@ -9,6 +9,8 @@ type ProductHandler interface {
 
 	GetAllProductsAdmin() func(ctx *gin.Context)
 	GetAllProductsUser() func(ctx *gin.Context)
+	GetProductFacets(ctx *gin.Context)
+	GetProductDetail(ctx *gin.Context)
 
 	SaveProduct(ctx *gin.Context)
 	UpdateProduct(ctx *gin.Context)
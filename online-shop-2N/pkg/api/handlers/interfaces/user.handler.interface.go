@@ -5,6 +5,7 @@ import "github.com/gin-gonic/gin"
 type UserHandler interface {
 	GetProfile(ctx *gin.Context)
 	UpdateProfile(ctx *gin.Context)
+	ChangePassword(ctx *gin.Context)
 
 	SaveAddress(ctx *gin.Context)
 	GetAllAddresses(ctx *gin.Context)
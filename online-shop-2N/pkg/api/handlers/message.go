@@ -6,3 +6,9 @@ const (
 	BindParamFailMessage = "Failed to bind param input from url"
 	BindFormValueMessage = "Failed to bind form values from request"
 )
+
+// maximum size of webhook request body
+const maxWebhookPayloadBytes int64 = 65536
+
+// maximum size of catalog import file
+const maxCatalogImportBytes int64 = 32 << 20
@@ -73,14 +73,19 @@ func (p *offerHandler) SaveOffer(ctx *gin.Context) {
 //	@Tags			Admin Offers
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/offers [get]
 //	@Success		200	{object}	responses.Response{}	""Successfully	found	all	offers"
 //	@Failure		500	{object}	responses.Response{}	"Failed to get all offers"
 func (c *offerHandler) GetAllOffers(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	offers, err := c.offerUseCase.FindAllOffers(ctx, pagination)
+	offers, meta, err := c.offerUseCase.FindAllOffers(ctx, page)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get all offers", err, nil)
 
@@ -88,12 +93,12 @@ func (c *offerHandler) GetAllOffers(ctx *gin.Context) {
 	}
 
 	if offers == nil {
-		responses.SuccessResponse(ctx, http.StatusOK, "No offer found", offers)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No offer found", meta, offers)
 
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all offers", offers)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all offers", meta, offers)
 }
 
 // RemoveOffer godoc
@@ -171,14 +176,19 @@ func (c *offerHandler) SaveCategoryOffer(ctx *gin.Context) {
 //	@Tags			Admin Offers
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/offers/category [get]
 //	@Success		200	{object}	responses.Response{}	"successfully got all offer_category"
 //	@Failure		500	{object}	responses.Response{}	"failed to get offers_category"
 func (c *offerHandler) GetAllCategoryOffers(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	offerCategories, err := c.offerUseCase.FindAllCategoryOffers(ctx, pagination)
+	offerCategories, meta, err := c.offerUseCase.FindAllCategoryOffers(ctx, page)
 
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get offer categories", err, nil)
@@ -186,11 +196,11 @@ func (c *offerHandler) GetAllCategoryOffers(ctx *gin.Context) {
 	}
 
 	if len(offerCategories) == 0 {
-		responses.SuccessResponse(ctx, http.StatusOK, "No offer categories found", nil)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No offer categories found", meta, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found offers categories", offerCategories)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found offers categories", meta, offerCategories)
 }
 
 // RemoveCategoryOffer godoc
@@ -292,25 +302,30 @@ func (c *offerHandler) SaveProductOffer(ctx *gin.Context) {
 //	@Tags			Admin Offers
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/offers/products [get]
 //	@Success		200	{object}	responses.Response{}	"successfully got all offers_categories"
 //	@Failure		500	{object}	responses.Response{}	"failed to get offer_products"
 func (c *offerHandler) GetAllProductsOffers(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	offersOfCategories, err := c.offerUseCase.FindAllProductOffers(ctx, pagination)
+	offersOfCategories, meta, err := c.offerUseCase.FindAllProductOffers(ctx, page)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get all offer products", err, nil)
 		return
 	}
 
 	if offersOfCategories == nil {
-		responses.SuccessResponse(ctx, http.StatusOK, "No offer products found", nil)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No offer products found", meta, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all offer products", offersOfCategories)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all offer products", meta, offersOfCategories)
 }
 
 // RemoveProductOffer godoc
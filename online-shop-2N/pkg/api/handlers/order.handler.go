@@ -107,6 +107,7 @@ func (c *OrderHandler) SaveOrder(ctx *gin.Context) {
 //	@tags			User Orders
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count Of Order"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/orders [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully retrieved all user orders"
 //	@Success		204	{object}	responses.Response{}	"No shop orders for user"
@@ -114,9 +115,13 @@ func (c *OrderHandler) SaveOrder(ctx *gin.Context) {
 func (c *OrderHandler) GetUserOrder(ctx *gin.Context) {
 
 	userId := utils.GetUserIdFromContext(ctx)
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	orders, err := c.orderUseCase.FindUserShopOrder(ctx, userId, pagination)
+	orders, meta, err := c.orderUseCase.FindUserShopOrder(ctx, userId, page)
 
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to retrieve all user shop orders", err, nil)
@@ -128,7 +133,7 @@ func (c *OrderHandler) GetUserOrder(ctx *gin.Context) {
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully retrieved all user orders", orders)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully retrieved all user orders", meta, orders)
 }
 
 // GetAllShopOrders godoc
@@ -140,15 +145,20 @@ func (c *OrderHandler) GetUserOrder(ctx *gin.Context) {
 //	@Tags			Admin Orders
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/orders/all [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully retrieved all shop orders"
 //	@Success		204	{object}	responses.Response{}	"No shop order found"
 //	@Failure		500	{object}	responses.Response{}	"Failed to find all shop orders"
 func (c *OrderHandler) GetAllShopOrders(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	shopOrders, err := c.orderUseCase.FindAllShopOrders(ctx, pagination)
+	shopOrders, meta, err := c.orderUseCase.FindAllShopOrders(ctx, page)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all shop orders", err, nil)
 		return
@@ -159,7 +169,7 @@ func (c *OrderHandler) GetAllShopOrders(ctx *gin.Context) {
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully retrieved all shop orders", shopOrders)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully retrieved all shop orders", meta, shopOrders)
 }
 
 // GetAllOrderItemsUser godoc
@@ -172,6 +182,7 @@ func (c *OrderHandler) GetAllShopOrders(ctx *gin.Context) {
 //	@Param			shop_order_id	path	int	true	"Shop Order ID"
 //	@Param			page_number		query	int	false	"Page Number"
 //	@Param			count			query	int	false	"Count Of Order"
+//	@Param			cursor			query	string	false	"Cursor of next page"
 //	@Router			/orders/{shop_order_id}/items  [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully found order items"
 //	@Failure		500	{object}	responses.Response{}	"Failed to find order items"
@@ -189,6 +200,7 @@ func (c *OrderHandler) GetAllOrderItemsUser() func(ctx *gin.Context) {
 //	@Param			shop_order_id	path	int	true	"Shop Order ID"
 //	@Param			page_number		query	int	false	"Page Number"
 //	@Param			count			query	int	false	"Count"
+//	@Param			cursor			query	string	false	"Cursor of next page"
 //	@Router			/admin/orders/{shop_order_id}/items [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully found order items"
 //	@Success		204	{object}	responses.Response{}	"No order items found"
@@ -204,9 +216,13 @@ func (c *OrderHandler) findAllOrderItems() func(ctx *gin.Context) {
 		if err != nil {
 			responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
 		}
-		pagination := requests.GetPagination(ctx)
+		page, err := requests.GetPagination(ctx)
+		if err != nil {
+			responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+			return
+		}
 
-		orderItems, err := c.orderUseCase.FindOrderItems(ctx, shopOrderID, pagination)
+		orderItems, meta, err := c.orderUseCase.FindOrderItems(ctx, shopOrderID, page)
 
 		if err != nil {
 			responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find order items", err, nil)
@@ -218,7 +234,7 @@ func (c *OrderHandler) findAllOrderItems() func(ctx *gin.Context) {
 			return
 		}
 
-		responses.SuccessResponse(ctx, http.StatusOK, "Successfully found order items", orderItems)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found order items", meta, orderItems)
 	}
 }
 
@@ -226,10 +242,11 @@ func (c *OrderHandler) findAllOrderItems() func(ctx *gin.Context) {
 //
 //	@Summary		Cancel order (User)
 //	@Security		BearerAuth
-//	@Description	Api for user to cancel a order
+//	@Description	Api for user to cancel a order or some items of it, without lines all the items are cancelled
 //	@Id				CancelOrder
 //	@Tags			User Orders
-//	@Param			shop_order_id	path	int	true	"Shop Order ID"
+//	@Param			shop_order_id	path	int						true	"Shop Order ID"
+//	@Param			input			body	requests.CancelOrder	false	"Input Fields"
 //	@Router			/orders/{shop_order_id}/cancel [post]
 //	@Success		200	{object}	responses.Response{}	"Successfully order cancelled"
 //	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
@@ -239,17 +256,246 @@ func (c *OrderHandler) CancelOrder(ctx *gin.Context) {
 	shopOrderID, err := requests.GetParamAsUint(ctx, "shop_order_id")
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	// body is optional, without it the whole order is cancelled
+	var body requests.CancelOrder
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+			return
+		}
 	}
 
-	err = c.orderUseCase.CancelOrder(ctx, shopOrderID)
+	userID := utils.GetUserIdFromContext(ctx)
+
+	err = c.orderUseCase.CancelOrder(ctx, userID, shopOrderID, body)
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to cancel order", err, nil)
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, usecases.ErrShopOrderNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to cancel order", err, nil)
 		return
 	}
 
 	responses.SuccessResponse(ctx, http.StatusOK, "successfully order cancelled", nil)
 }
 
+// GetOrderStatusHistoryUser godoc
+//
+//	@Summary		Get order status history (User)
+//	@Security		BearerAuth
+//	@Description	API for user to get all status changes of a specific order
+//	@Id				GetOrderStatusHistoryUser
+//	@Tags			User Orders
+//	@Param			shop_order_id	path	int	true	"Shop Order ID"
+//	@Router			/orders/{shop_order_id}/history [get]
+//	@Success		200	{object}	responses.Response{}	"Successfully found order status history"
+//	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
+//	@Failure		404	{object}	responses.Response{}	"Shop order not exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find order status history"
+func (c *OrderHandler) GetOrderStatusHistoryUser(ctx *gin.Context) {
+
+	shopOrderID, err := requests.GetParamAsUint(ctx, "shop_order_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	userID := utils.GetUserIdFromContext(ctx)
+
+	histories, err := c.orderUseCase.FindUserOrderStatusHistory(ctx, userID, shopOrderID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrShopOrderNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to find order status history", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found order status history", histories)
+}
+
+// GetOrderStatusHistoryAdmin godoc
+//
+//	@Summary		Get order status history (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to get all status changes of a specific order
+//	@Id				GetOrderStatusHistoryAdmin
+//	@Tags			Admin Orders
+//	@Param			shop_order_id	path	int	true	"Shop Order ID"
+//	@Router			/admin/orders/{shop_order_id}/history [get]
+//	@Success		200	{object}	responses.Response{}	"Successfully found order status history"
+//	@Success		204	{object}	responses.Response{}	"No order status history found"
+//	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find order status history"
+func (c *OrderHandler) GetOrderStatusHistoryAdmin(ctx *gin.Context) {
+
+	shopOrderID, err := requests.GetParamAsUint(ctx, "shop_order_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	histories, err := c.orderUseCase.FindOrderStatusHistory(ctx, shopOrderID)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find order status history", err, nil)
+		return
+	}
+
+	if len(histories) == 0 {
+		responses.SuccessResponse(ctx, http.StatusNoContent, "No order status history found", nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found order status history", histories)
+}
+
+// GetOrderPaymentsUser godoc
+//
+//	@Summary		Get order payments (User)
+//	@Security		BearerAuth
+//	@Description	API for user to get the amount paid by each payment method for a specific order
+//	@Id				GetOrderPaymentsUser
+//	@Tags			User Orders
+//	@Param			shop_order_id	path	int	true	"Shop Order ID"
+//	@Router			/orders/{shop_order_id}/payments [get]
+//	@Success		200	{object}	responses.Response{}	"Successfully found order payments"
+//	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
+//	@Failure		404	{object}	responses.Response{}	"Shop order not exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find order payments"
+func (c *OrderHandler) GetOrderPaymentsUser(ctx *gin.Context) {
+
+	shopOrderID, err := requests.GetParamAsUint(ctx, "shop_order_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	userID := utils.GetUserIdFromContext(ctx)
+
+	orderPayments, err := c.orderUseCase.FindUserOrderPayments(ctx, userID, shopOrderID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrShopOrderNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to find order payments", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found order payments", orderPayments)
+}
+
+// GetOrderPaymentsAdmin godoc
+//
+//	@Summary		Get order payments (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to get the amount paid by each payment method for a specific order
+//	@Id				GetOrderPaymentsAdmin
+//	@Tags			Admin Orders
+//	@Param			shop_order_id	path	int	true	"Shop Order ID"
+//	@Router			/admin/orders/{shop_order_id}/payments [get]
+//	@Success		200	{object}	responses.Response{}	"Successfully found order payments"
+//	@Success		204	{object}	responses.Response{}	"No order payments found"
+//	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find order payments"
+func (c *OrderHandler) GetOrderPaymentsAdmin(ctx *gin.Context) {
+
+	shopOrderID, err := requests.GetParamAsUint(ctx, "shop_order_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	orderPayments, err := c.orderUseCase.FindOrderPayments(ctx, shopOrderID)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find order payments", err, nil)
+		return
+	}
+
+	if len(orderPayments) == 0 {
+		responses.SuccessResponse(ctx, http.StatusNoContent, "No order payments found", nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found order payments", orderPayments)
+}
+
+// GetOrderRefundsUser godoc
+//
+//	@Summary		Get order refunds (User)
+//	@Security		BearerAuth
+//	@Description	API for user to get the refunds of a specific order with its status
+//	@Id				GetOrderRefundsUser
+//	@Tags			User Orders
+//	@Param			shop_order_id	path	int	true	"Shop Order ID"
+//	@Router			/orders/{shop_order_id}/refunds [get]
+//	@Success		200	{object}	responses.Response{}	"Successfully found order refunds"
+//	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
+//	@Failure		404	{object}	responses.Response{}	"Shop order not exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find order refunds"
+func (c *OrderHandler) GetOrderRefundsUser(ctx *gin.Context) {
+
+	shopOrderID, err := requests.GetParamAsUint(ctx, "shop_order_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	userID := utils.GetUserIdFromContext(ctx)
+
+	refunds, err := c.orderUseCase.FindUserOrderRefunds(ctx, userID, shopOrderID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrShopOrderNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to find order refunds", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found order refunds", refunds)
+}
+
+// GetOrderRefundsAdmin godoc
+//
+//	@Summary		Get order refunds (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to get the refunds of a specific order with its status and gateway reference
+//	@Id				GetOrderRefundsAdmin
+//	@Tags			Admin Orders
+//	@Param			shop_order_id	path	int	true	"Shop Order ID"
+//	@Router			/admin/orders/{shop_order_id}/refunds [get]
+//	@Success		200	{object}	responses.Response{}	"Successfully found order refunds"
+//	@Success		204	{object}	responses.Response{}	"No order refunds found"
+//	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
+//	@Failure		500	{object}	responses.Response{}	"Failed to find order refunds"
+func (c *OrderHandler) GetOrderRefundsAdmin(ctx *gin.Context) {
+
+	shopOrderID, err := requests.GetParamAsUint(ctx, "shop_order_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	refunds, err := c.orderUseCase.FindOrderRefunds(ctx, shopOrderID)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find order refunds", err, nil)
+		return
+	}
+
+	if len(refunds) == 0 {
+		responses.SuccessResponse(ctx, http.StatusNoContent, "No order refunds found", nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found order refunds", refunds)
+}
+
 // UpdateOrderStatus godoc
 //
 //	@Summary		Change order status (Admin)
@@ -270,7 +516,9 @@ func (c *OrderHandler) UpdateOrderStatus(ctx *gin.Context) {
 		return
 	}
 
-	err := c.orderUseCase.UpdateOrderStatus(ctx, body.ShopOrderID, body.OrderStatusID)
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	err := c.orderUseCase.UpdateOrderStatus(ctx, adminID, body)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to update order status", err, nil)
 		return
@@ -298,9 +546,15 @@ func (c OrderHandler) SubmitReturnRequest(ctx *gin.Context) {
 		return
 	}
 
-	err := c.orderUseCase.SubmitReturnRequest(ctx, body)
+	userID := utils.GetUserIdFromContext(ctx)
+
+	err := c.orderUseCase.SubmitReturnRequest(ctx, userID, body)
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to submit return requests", err, nil)
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, usecases.ErrShopOrderNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to submit return requests", err, nil)
 		return
 	}
 
@@ -316,25 +570,30 @@ func (c OrderHandler) SubmitReturnRequest(ctx *gin.Context) {
 //	@Tags			Admin Orders
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count Of Order"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/orders/returns [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully found all order returns"
 //	@Failure		500	{object}	responses.Response{}	"Failed to find all order returns"
 func (c *OrderHandler) GetAllOrderReturns(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	orderReturns, err := c.orderUseCase.FindAllOrderReturns(ctx, pagination)
+	orderReturns, meta, err := c.orderUseCase.FindAllOrderReturns(ctx, page)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all order returns", err, nil)
 		return
 	}
 
 	if len(orderReturns) == 0 {
-		responses.SuccessResponse(ctx, http.StatusOK, "No order returns found", nil)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No order returns found", meta, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all order returns", orderReturns)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all order returns", meta, orderReturns)
 }
 
 // GetAllPendingReturns godoc
@@ -346,25 +605,30 @@ func (c *OrderHandler) GetAllOrderReturns(ctx *gin.Context) {
 //	@Tags			Admin Orders
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count Of Order"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/orders/returns/pending [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully found all pending orders return requests"
 //	@Failure		500	{object}	responses.Response{}	"Failed to find all pending order return requests"
 func (c *OrderHandler) GetAllPendingReturns(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	orderReturns, err := c.orderUseCase.FindAllPendingOrderReturns(ctx, pagination)
+	orderReturns, meta, err := c.orderUseCase.FindAllPendingOrderReturns(ctx, page)
 	if err != nil {
 		responses.ErrorResponse(ctx, 500, "Failed to find all pending order return requests", err, nil)
 		return
 	}
 
 	if len(orderReturns) == 0 {
-		responses.SuccessResponse(ctx, 200, "No pending order returns requests found", nil)
+		responses.SuccessResponseWithMeta(ctx, 200, "No pending order returns requests found", meta, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, 200, "Successfully found all pending orders return requests", orderReturns)
+	responses.SuccessResponseWithMeta(ctx, 200, "Successfully found all pending orders return requests", meta, orderReturns)
 }
 
 // UpdateReturnRequest godoc
@@ -387,7 +651,9 @@ func (c *OrderHandler) UpdateReturnRequest(ctx *gin.Context) {
 		return
 	}
 
-	err := c.orderUseCase.UpdateReturnDetails(ctx, body)
+	adminID := utils.GetUserIdFromContext(ctx)
+
+	err := c.orderUseCase.UpdateReturnDetails(ctx, adminID, body)
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to update order return", err, nil)
 		return
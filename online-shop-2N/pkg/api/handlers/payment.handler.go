@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	handlerInterface "online-shop-2N/pkg/api/handlers/interfaces"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/usecases"
 	"online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
 
@@ -150,9 +152,25 @@ func (c *paymentHandler) PaymentCOD(ctx *gin.Context) {
 	err = c.paymentUseCase.ApproveShopOrderAndClearCart(ctx, UserID, approveReq)
 
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to approve order and clear cart", err, nil)
+		responses.ErrorResponse(ctx, verifyPaymentErrorStatusCode(err), "Failed to approve order and clear cart", err, nil)
 		return
 	}
 
 	responses.SuccessResponse(ctx, http.StatusOK, "Successfully order placed for cod")
 }
+
+// status code of the errors on verifying a gateway payment and approving its order
+func verifyPaymentErrorStatusCode(err error) int {
+
+	switch {
+	case errors.Is(err, usecases.ErrShopOrderNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, usecases.ErrPaymentNotApproved),
+		errors.Is(err, usecases.ErrPaymentAmountMismatch):
+		return http.StatusPaymentRequired
+	case errors.Is(err, usecases.ErrInvalidOrderPayment):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
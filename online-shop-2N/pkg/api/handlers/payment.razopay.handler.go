@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
@@ -63,7 +64,9 @@ func (c *paymentHandler) RazorpayCheckout(ctx *gin.Context) {
 //	@Param			shop_order_id		formData	string	true	"Shop Order ID"
 //	@Router			/carts/place-order/razorpay-verify [post]
 //	@Success		200	{object}	responses.responses{}	"Successfully razorpay payment verified"
+//	@Failure		400	{object}	responses.responses{}	"Payment not found for this order"
 //	@Failure		402	{object}	responses.responses{}	"Payment not approved"
+//	@Failure		404	{object}	responses.responses{}	"Shop order not exist"
 //	@Failure		500	{object}	responses.responses{}	"Failed to Approve order"
 func (c *paymentHandler) RazorpayVerify(ctx *gin.Context) {
 
@@ -88,26 +91,44 @@ func (c *paymentHandler) RazorpayVerify(ctx *gin.Context) {
 		Signature: razorpaySignature,
 	}
 
-	err = c.paymentUseCase.VerifyRazorPay(ctx, verifyReq)
+	err = c.paymentUseCase.VerifyRazorPay(ctx, userID, shopOrderID, verifyReq)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if errors.Is(err, usecases.ErrPaymentNotApproved) {
-			statusCode = http.StatusPaymentRequired
-		}
-		responses.ErrorResponse(ctx, statusCode, "Failed to verify razorpay payment", err, nil)
+		responses.ErrorResponse(ctx, verifyPaymentErrorStatusCode(err), "Failed to verify razorpay payment", err, nil)
 		return
 	}
 
-	approveReq := requests.ApproveOrder{
-		ShopOrderID: shopOrderID,
-		PaymentType: commonConstant.RazopayPayment,
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully razorpay payment verified", nil)
+}
+
+// RazorpayWebhook godoc
+//
+//	@Summary		Razorpay webhook
+//	@Description	API for razorpay to send payment events
+//	@Tags			Webhooks
+//	@Id				RazorpayWebhook
+//	@Param			X-Razorpay-Signature	header	string	true	"Razorpay signature"
+//	@Param			X-Razorpay-Event-Id		header	string	false	"Razorpay event id"
+//	@Router			/webhooks/razorpay [post]
+//	@Success		200	{object}	responses.Response{}	"Successfully razorpay webhook processed"
+//	@Failure		400	{object}	responses.Response{}	"Invalid webhook signature or event"
+//	@Failure		500	{object}	responses.Response{}	"Failed to process razorpay webhook"
+func (c *paymentHandler) RazorpayWebhook(ctx *gin.Context) {
+
+	payload, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxWebhookPayloadBytes))
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read webhook payload", err, nil)
+		return
 	}
 
-	err = c.paymentUseCase.ApproveShopOrderAndClearCart(ctx, userID, approveReq)
+	err = c.paymentUseCase.HandlePaymentWebhook(ctx, commonConstant.RazopayPayment, payload, ctx.Request.Header)
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to Approve order", err, nil)
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrInvalidWebhookSignature) || errors.Is(err, usecases.ErrInvalidWebhookEvent) {
+			statusCode = http.StatusBadRequest
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to process razorpay webhook", err, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully razorpay payment verified", nil)
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully razorpay webhook processed")
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
@@ -59,7 +60,9 @@ func (c *paymentHandler) StripPaymentCheckout(ctx *gin.Context) {
 //	@Param			shop_order_id		formData	string	true	"Shop Order ID"
 //	@Router			/carts/place-order/stripe-verify [post]
 //	@Success		200	{object}	responses.responses{}	"Successfully stripe payment verified"
+//	@Failure		400	{object}	responses.responses{}	"Payment not found for this order"
 //	@Failure		402	{object}	responses.responses{}	"Payment not approved"
+//	@Failure		404	{object}	responses.responses{}	"Shop order not exist"
 //	@Failure		500	{object}	responses.responses{}	"Failed to Approve order"
 func (c *paymentHandler) StripePaymentVeify(ctx *gin.Context) {
 
@@ -75,26 +78,43 @@ func (c *paymentHandler) StripePaymentVeify(ctx *gin.Context) {
 
 	userID := utils.GetUserIdFromContext(ctx)
 
-	err = c.paymentUseCase.VerifyStripOrder(ctx, stripePaymentID)
+	err = c.paymentUseCase.VerifyStripOrder(ctx, userID, shopOrderID, stripePaymentID)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if errors.Is(err, usecases.ErrPaymentNotApproved) {
-			statusCode = http.StatusPaymentRequired
-		}
-		responses.ErrorResponse(ctx, statusCode, "Failed to verify stripe payment", err, nil)
+		responses.ErrorResponse(ctx, verifyPaymentErrorStatusCode(err), "Failed to verify stripe payment", err, nil)
 		return
 	}
 
-	approveReq := requests.ApproveOrder{
-		ShopOrderID: shopOrderID,
-		PaymentType: commonConstant.StripePayment,
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully stripe payment verified", nil)
+}
+
+// StripeWebhook godoc
+//
+//	@Summary		Stripe webhook
+//	@Description	API for stripe to send payment events
+//	@Tags			Webhooks
+//	@Id				StripeWebhook
+//	@Param			Stripe-Signature	header	string	true	"Stripe signature"
+//	@Router			/webhooks/stripe [post]
+//	@Success		200	{object}	responses.Response{}	"Successfully stripe webhook processed"
+//	@Failure		400	{object}	responses.Response{}	"Invalid webhook signature or event"
+//	@Failure		500	{object}	responses.Response{}	"Failed to process stripe webhook"
+func (c *paymentHandler) StripeWebhook(ctx *gin.Context) {
+
+	payload, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxWebhookPayloadBytes))
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read webhook payload", err, nil)
+		return
 	}
 
-	err = c.paymentUseCase.ApproveShopOrderAndClearCart(ctx, userID, approveReq)
+	err = c.paymentUseCase.HandlePaymentWebhook(ctx, commonConstant.StripePayment, payload, ctx.Request.Header)
 	if err != nil {
-		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to Approve order", err, nil)
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrInvalidWebhookSignature) || errors.Is(err, usecases.ErrInvalidWebhookEvent) {
+			statusCode = http.StatusBadRequest
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to process stripe webhook", err, nil)
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully stripe payment verified", nil)
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully stripe webhook processed")
 }
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/usecases"
+	"online-shop-2N/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentWallet godoc
+//
+//	@Summary		Pay order with wallet (User)
+//	@Security		BearerAuth
+//	@Description	API for user to pay order from wallet, if wallet balance is not enough then remaining amount can pay with cod, razorpay or stripe
+//	@Tags			User Payment
+//	@Id				PaymentWallet
+//	@Param			shop_order_id	formData	string	true	"Shop Order ID"
+//	@Router			/carts/place-order/wallet [post]
+//	@Success		200	{object}	responses.Response{}	"Successfully paid order with wallet"
+//	@Failure		400	{object}	responses.Response{}	"Invalid inputs"
+//	@Failure		404	{object}	responses.Response{}	"Shop order not exist"
+//	@Failure		500	{object}	responses.Response{}	"Failed to pay order with wallet"
+func (c *paymentHandler) PaymentWallet(ctx *gin.Context) {
+
+	shopOrderID, err := requests.GetFormValuesAsUint(ctx, "shop_order_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindFormValueMessage, err, nil)
+		return
+	}
+
+	userID := utils.GetUserIdFromContext(ctx)
+
+	walletOrder, err := c.paymentUseCase.PayWithWallet(ctx, userID, shopOrderID)
+	if err != nil {
+		var statusCode int
+
+		switch {
+		case errors.Is(err, usecases.ErrShopOrderNotExist):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, usecases.ErrEmptyWallet),
+			errors.Is(err, usecases.ErrOrderNotWaitingPayment),
+			errors.Is(err, usecases.ErrBlockedPayment):
+			statusCode = http.StatusBadRequest
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to pay order with wallet", err, nil)
+		return
+	}
+
+	if walletOrder.OrderPlaced {
+		responses.SuccessResponse(ctx, http.StatusOK, "Successfully order placed with wallet", walletOrder)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully paid order with wallet, pay the remaining amount with another payment method", walletOrder)
+}
@@ -210,14 +210,24 @@ func (p *ProductHandler) SaveProduct(ctx *gin.Context) {
 //
 //	@Summary		Get all products (Admin)
 //	@Security		BearerAuth
-//	@Description	API for admin to get all products
+//	@Description	API for admin to get all products, products can be searched and filtered same as for user
 //	@ID				GetAllProductsAdmin
 //	@Tags			Admin Products
-//	@Param			page_number	query	int	false	"Page Number"
-//	@Param			count		query	int	false	"Count"
+//	@Param			q			query	string	false	"Search on name and description"
+//	@Param			category_id	query	int		false	"Category or sub category ID"
+//	@Param			brand_id	query	int		false	"Brand ID"
+//	@Param			min_price	query	int		false	"Minimum price"
+//	@Param			max_price	query	int		false	"Maximum price"
+//	@Param			option_ids	query	string	false	"Variation option IDs separated by comma"
+//	@Param			in_stock	query	bool	false	"Only products in stock"
+//	@Param			sort		query	string	false	"Sort by"	Enums(relevance, price_asc, price_desc, newest, popularity)
+//	@Param			page_number	query	int		false	"Page Number"
+//	@Param			count		query	int		false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/products [get]
-//	@Success		200	{object}	responses.Response{}	"Successfully found all products"
-//	@Failure		500	{object}	responses.Response{}	"Failed to Get all products"
+//	@Success		200	{object}	responses.Response{data=[]responses.Product}	"Successfully found all products"
+//	@Failure		400	{object}	responses.Response{}							"Invalid filters"
+//	@Failure		500	{object}	responses.Response{}							"Failed to Get all products"
 func (p *ProductHandler) GetAllProductsAdmin() func(ctx *gin.Context) {
 	return p.getAllProducts()
 }
@@ -226,14 +236,25 @@ func (p *ProductHandler) GetAllProductsAdmin() func(ctx *gin.Context) {
 //
 //	@Summary		Get all products (User)
 //	@Security		BearerAuth
-//	@Description	API for user to get all products
+//	@Description	API for user to get all products, products are searched by full text on name and description
+//	@Description	and filtered by category, brand, price range and variation options
 //	@ID				GetAllProductsUser
 //	@Tags			User Products
-//	@Param			page_number	query	int	false	"Page Number"
-//	@Param			count		query	int	false	"Count"
+//	@Param			q			query	string	false	"Search on name and description"
+//	@Param			category_id	query	int		false	"Category or sub category ID"
+//	@Param			brand_id	query	int		false	"Brand ID"
+//	@Param			min_price	query	int		false	"Minimum price"
+//	@Param			max_price	query	int		false	"Maximum price"
+//	@Param			option_ids	query	string	false	"Variation option IDs separated by comma"
+//	@Param			in_stock	query	bool	false	"Only products in stock"
+//	@Param			sort		query	string	false	"Sort by"	Enums(relevance, price_asc, price_desc, newest, popularity)
+//	@Param			page_number	query	int		false	"Page Number"
+//	@Param			count		query	int		false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/products [get]
-//	@Success		200	{object}	responses.Response{}	"Successfully found all products"
-//	@Failure		500	{object}	responses.Response{}	"Failed to get all products"
+//	@Success		200	{object}	responses.Response{data=[]responses.Product}	"Successfully found all products"
+//	@Failure		400	{object}	responses.Response{}							"Invalid filters"
+//	@Failure		500	{object}	responses.Response{}							"Failed to get all products"
 func (p *ProductHandler) GetAllProductsUser() func(ctx *gin.Context) {
 	return p.getAllProducts()
 }
@@ -243,9 +264,19 @@ func (p *ProductHandler) getAllProducts() func(ctx *gin.Context) {
 
 	return func(ctx *gin.Context) {
 
-		pagination := requests.GetPagination(ctx)
+		filter, err := bindProductFilter(ctx)
+		if err != nil {
+			responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+			return
+		}
+
+		page, err := requests.GetPagination(ctx)
+		if err != nil {
+			responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+			return
+		}
 
-		products, err := p.productUseCase.FindAllProducts(ctx, pagination)
+		products, meta, err := p.productUseCase.FindAllProducts(ctx, filter, page)
 
 		if err != nil {
 			responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to Get all products", err, nil)
@@ -253,13 +284,103 @@ func (p *ProductHandler) getAllProducts() func(ctx *gin.Context) {
 		}
 
 		if len(products) == 0 {
-			responses.SuccessResponse(ctx, http.StatusOK, "No products found", nil)
+			responses.SuccessResponseWithMeta(ctx, http.StatusOK, "No products found", meta, nil)
 			return
 		}
 
-		responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all products", products)
+		responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all products", meta, products)
+	}
+
+}
+
+// GetProductFacets godoc
+//
+//	@Summary		Get product facets (User)
+//	@Description	API for user to get the count of products for each brand, sub category, variation option and price range
+//	@Description	on the same search and filters of products, counts are only of items in stock when in_stock is true.
+//	@Description	each facet is counted without its own filter, so the other brands, sub categories of the same main category,
+//	@Description	options of the same variation and price ranges are given with the count they would have when chosen
+//	@ID				GetProductFacets
+//	@Tags			User Products
+//	@Param			q			query	string	false	"Search on name and description"
+//	@Param			category_id	query	int		false	"Category or sub category ID"
+//	@Param			brand_id	query	int		false	"Brand ID"
+//	@Param			min_price	query	int		false	"Minimum price"
+//	@Param			max_price	query	int		false	"Maximum price"
+//	@Param			option_ids	query	string	false	"Variation option IDs separated by comma"
+//	@Param			in_stock	query	bool	false	"Only products in stock"
+//	@Router			/products/facets [get]
+//	@Success		200	{object}	responses.Response{data=responses.ProductFacets}	"Successfully found product facets"
+//	@Failure		400	{object}	responses.Response{}								"Invalid filters"
+//	@Failure		500	{object}	responses.Response{}								"Failed to get product facets"
+func (p *ProductHandler) GetProductFacets(ctx *gin.Context) {
+
+	filter, err := bindProductFilter(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
+
+	facets, err := p.productUseCase.FindProductFacets(ctx, filter)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get product facets", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found product facets", facets)
+}
+
+// GetProductDetail godoc
+//
+//	@Summary		Get product detail (User)
+//	@Security		BearerAuth
+//	@Description	API for user to get a product with its brand, category path, all items with variation values,
+//	@Description	stock status and image urls, and the offer running on the product
+//	@ID				GetProductDetail
+//	@Tags			User Products
+//	@Param			product_id	path	int	true	"Product ID"
+//	@Router			/products/{product_id} [get]
+//	@Success		200	{object}	responses.Response{data=responses.ProductDetail}	"Successfully found product"
+//	@Failure		400	{object}	responses.Response{}								"Invalid input"
+//	@Failure		404	{object}	responses.Response{}								"Product not exist"
+//	@Failure		500	{object}	responses.Response{}								"Failed to get product"
+func (p *ProductHandler) GetProductDetail(ctx *gin.Context) {
+
+	productID, err := requests.GetParamAsUint(ctx, "product_id")
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindParamFailMessage, err, nil)
+		return
+	}
+
+	product, err := p.productUseCase.FindProductDetail(ctx, productID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrProductNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to get product", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found product", product)
+}
+
+// To bind the search and filters of products from query
+func bindProductFilter(ctx *gin.Context) (requests.ProductFilter, error) {
+
+	var filter requests.ProductFilter
+
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		return filter, err
+	}
+
+	optionIDs, err := requests.GetQueryArrayAsUint(ctx, "option_ids")
+	if err != nil {
+		return filter, err
 	}
+	filter.OptionIDs = optionIDs
 
+	return filter, nil
 }
 
 // UpdateProduct godoc
@@ -0,0 +1,35 @@
+package requests
+
+import commonConstant "online-shop-2N/pkg/common/constants"
+
+// admin is created with at least one role
+type AdminSignUp struct {
+	UserName string `json:"user_name" binding:"required,min=3,max=15"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=5,max=30"`
+	RoleIDs  []uint `json:"role_ids" binding:"required,min=1"`
+}
+
+type Role struct {
+	Name        string                           `json:"name" binding:"required,min=3,max=30"`
+	Description string                           `json:"description" binding:"omitempty,max=150"`
+	Permissions []commonConstant.AdminPermission `json:"permissions" binding:"required,min=1"`
+}
+
+type AdminRoles struct {
+	RoleIDs []uint `json:"role_ids" binding:"required,min=1"`
+}
+
+type RoleTwoFactor struct {
+	Required *bool `json:"required" binding:"required"`
+}
+
+type AdminTwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// code of authenticator app or a recovery code
+	Code string `json:"code" binding:"required,min=6,max=20"`
+}
+
+type TwoFactorCode struct {
+	Code string `json:"code" binding:"required,min=6,max=20"`
+}
@@ -10,3 +10,30 @@ type Login struct {
 type RefreshToken struct {
 	RefreshToken string `json:"refresh_token" binding:"min=10"`
 }
+
+type Email struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPassword struct {
+	Token           string `json:"token" binding:"required"`
+	Password        string `json:"password" binding:"required,min=5,max=30,eqfield=ConfirmPassword"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+type VerifyEmail struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// query or form values which the oauth provider send to callback
+type OAuthCallback struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// one time code which is given to frontend on redirect after oauth sign in
+type OAuthLoginCode struct {
+	Code string `json:"code" binding:"required"`
+}
@@ -1,6 +1,9 @@
 package requests
 
-import "time"
+import (
+	"online-shop-2N/pkg/pagination"
+	"time"
+)
 
 type OTPLogin struct {
 	Email    string `json:"email" binding:"omitempty,email"`
@@ -19,9 +22,9 @@ type BlockUser struct {
 }
 
 type SalesReport struct {
-	StartDate  time.Time  `json:"start_date"`
-	EndDate    time.Time  `json:"end_date"`
-	Pagination Pagination `json:"pagination"`
+	StartDate  time.Time          `json:"start_date"`
+	EndDate    time.Time          `json:"end_date"`
+	Pagination pagination.Request `json:"-"`
 }
 
 // stock
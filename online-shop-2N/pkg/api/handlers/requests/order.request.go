@@ -1,16 +1,38 @@
 package requests
 
-import "time"
+import (
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"time"
+)
 
 type UpdateOrder struct {
-	ShopOrderID   uint `json:"shop_order_id" binding:"required"`
-	OrderStatusID uint `json:"order_status_id"`
+	ShopOrderID   uint   `json:"shop_order_id" binding:"required"`
+	OrderStatusID uint   `json:"order_status_id"`
+	Comment       string `json:"comment" binding:"omitempty,max=150"`
 }
 
-// return request
+// quantity of an order item to return or cancel
+type OrderLineQty struct {
+	OrderLineID uint `json:"order_line_id" binding:"required"`
+	Qty         uint `json:"qty" binding:"required,min=1"`
+}
+
+// cancel request, without lines all the items of the order are cancelled
+type CancelOrder struct {
+	CancelReason       string                          `json:"cancel_reason" binding:"omitempty,max=150"`
+	Lines              []OrderLineQty                  `json:"lines" binding:"omitempty,dive"`
+	RefundPolicy       commonConstant.RefundPolicyType `json:"refund_policy" binding:"omitempty,oneof=wallet original_method split"`
+	WalletRefundAmount uint                            `json:"wallet_refund_amount" binding:"omitempty"`
+}
+
+// return request, without lines all the items of the order are returned
 type Return struct {
-	ShopOrderID  uint   `json:"shop_order_id" binding:"required"`
-	ReturnReason string `json:"return_reason" binding:"required,min=6,max=150"`
+	ShopOrderID  uint           `json:"shop_order_id" binding:"required"`
+	ReturnReason string         `json:"return_reason" binding:"required,min=6,max=150"`
+	Lines        []OrderLineQty `json:"lines" binding:"omitempty,dive"`
+	// refund policy default to wallet, for split the wallet amount is refunded to wallet and the rest to original payment
+	RefundPolicy       commonConstant.RefundPolicyType `json:"refund_policy" binding:"omitempty,oneof=wallet original_method split"`
+	WalletRefundAmount uint                            `json:"wallet_refund_amount" binding:"omitempty"`
 }
 
 type UpdateOrderReturn struct {
@@ -1,36 +1,12 @@
 package requests
 
 import (
-	"strconv"
+	"online-shop-2N/pkg/pagination"
 
 	"github.com/gin-gonic/gin"
 )
 
-type Pagination struct {
-	PageNumber uint64
-	Count      uint64
-}
-
-const (
-	defaultPageNumber = 1
-	defaultPageCount  = 10
-)
-
-func GetPagination(ctx *gin.Context) Pagination {
-
-	pagination := Pagination{
-		PageNumber: defaultPageNumber,
-		Count:      defaultPageCount,
-	}
-
-	num, err := strconv.ParseUint(ctx.Query("page_number"), 10, 64)
-	if err == nil {
-		pagination.PageNumber = num
-	}
-
-	num, err = strconv.ParseUint(ctx.Query("count"), 10, 64)
-	if err == nil {
-		pagination.Count = num
-	}
-	return pagination
+// To get the page of list from the query values page_number, count and cursor of request
+func GetPagination(ctx *gin.Context) (pagination.Request, error) {
+	return pagination.FromQuery(ctx.Request.URL.Query())
 }
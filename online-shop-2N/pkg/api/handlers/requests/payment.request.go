@@ -23,4 +23,10 @@ type RazorpayVerify struct {
 type ApproveOrder struct {
 	ShopOrderID uint
 	PaymentType commonConstant.PaymentType
+	// order/intent id of the payment gateway if paid through a gateway
+	PaymentReference string
+	// payment id given by gateway which is used to refund the payment
+	GatewayPaymentID string
+	// amount paid on gateway which is verified with the gateway
+	PaidAmount uint
 }
@@ -30,6 +30,29 @@ type ProductItem struct {
 	ImageFileHeaders   []*multipart.FileHeader `json:"images" binding:"required,gte=1"`
 }
 
+// sort options of product search
+const (
+	ProductSortRelevance  = "relevance"
+	ProductSortPriceAsc   = "price_asc"
+	ProductSortPriceDesc  = "price_desc"
+	ProductSortNewest     = "newest"
+	ProductSortPopularity = "popularity"
+)
+
+// query values to search and filter the products, the products are sorted by relevance on search otherwise by newest
+type ProductFilter struct {
+	Query      string `form:"q" binding:"omitempty,max=100"`
+	CategoryID uint   `form:"category_id"`
+	BrandID    uint   `form:"brand_id"`
+	MinPrice   uint   `form:"min_price"`
+	MaxPrice   uint   `form:"max_price" binding:"omitempty,gtefield=MinPrice"`
+	// products which have an item with the options, options of same variation are matched as any of them
+	OptionIDs []uint `form:"-"`
+	// only the products and items which have quantity in stock
+	InStock bool   `form:"in_stock"`
+	Sort    string `form:"sort" binding:"omitempty,oneof=relevance price_asc price_desc newest popularity"`
+}
+
 type Variation struct {
 	Names []string `json:"variation_names" binding:"required,dive,min=1"`
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"mime/multipart"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -44,6 +45,27 @@ func GetQueryValueAsUint(ctx *gin.Context, key string) (uint, error) {
 	return uint(uintVal), nil
 }
 
+// Get query values as array of uint from request, values can be repeated or separated by comma
+func GetQueryArrayAsUint(ctx *gin.Context, key string) ([]uint, error) {
+
+	var uintValues []uint
+
+	for _, values := range ctx.QueryArray(key) {
+		for _, value := range strings.Split(values, ",") {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			num, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || num == 0 {
+				return nil, fmt.Errorf("failed to get %s from query as array of int", key)
+			}
+			uintValues = append(uintValues, uint(num))
+		}
+	}
+
+	return uintValues, nil
+}
+
 // Get query params as uint from request url
 func GetParamAsUint(ctx *gin.Context, key string) (uint, error) {
 
@@ -60,3 +60,9 @@ type EditUser struct {
 	Password        string `json:"password"  binding:"omitempty,eqfield=ConfirmPassword"`
 	ConfirmPassword string `json:"confirm_password" binding:"omitempty"`
 }
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Password        string `json:"password" binding:"required,min=5,max=30,eqfield=ConfirmPassword"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
@@ -1,6 +1,9 @@
 package responses
 
-import "time"
+import (
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"time"
+)
 
 var ResoposeMap map[string]string
 
@@ -33,3 +36,44 @@ type Stock struct {
 	QtyInStock       uint              `json:"qty_in_stock"`
 	VariationOptions []VariationOption `gorm:"-"`
 }
+
+type Role struct {
+	ID               uint                             `json:"id"`
+	Name             string                           `json:"name"`
+	Description      string                           `json:"description"`
+	RequireTwoFactor bool                             `json:"require_two_factor"`
+	Permissions      []commonConstant.AdminPermission `json:"permissions" gorm:"-"`
+}
+
+type Admin struct {
+	ID        uint      `json:"id"`
+	UserName  string    `json:"user_name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	Roles     []Role    `json:"roles" gorm:"-"`
+}
+
+// challenge of sign in when two factor authentication is enabled for admin
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpireAt          time.Time `json:"expire_at"`
+}
+
+type TwoFactorStatus struct {
+	Enabled bool `json:"enabled"`
+	// required by any of the roles of admin
+	Required          bool `json:"required"`
+	RecoveryCodesLeft uint `json:"recovery_codes_left"`
+}
+
+// secret and uri are shown only on setup, uri can be shown as qr code to scan with authenticator apps
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// recovery codes are shown only once when they are generated
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
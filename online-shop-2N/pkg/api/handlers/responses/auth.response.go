@@ -1,5 +1,7 @@
 package responses
 
+import "time"
+
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -7,3 +9,30 @@ type TokenResponse struct {
 type OTPResponse struct {
 	OtpID string `json:"otp_id"`
 }
+
+// a signed in device of user or admin
+type Session struct {
+	SessionID       string    `json:"session_id"`
+	UserAgent       string    `json:"user_agent"`
+	IPAddress       string    `json:"ip_address"`
+	SignedInAt      time.Time `json:"signed_in_at"`
+	LastRefreshedAt time.Time `json:"last_refreshed_at"`
+	ExpireAt        time.Time `json:"expire_at"`
+	Current         bool      `json:"current"`
+}
+
+// oauth provider linked to the account of user
+type UserIdentity struct {
+	Provider string    `json:"provider"`
+	Email    string    `json:"email"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+type OAuthProviders struct {
+	Providers []string `json:"providers"`
+}
+
+// url of provider which the user should be redirected to
+type OAuthURL struct {
+	AuthURL string `json:"auth_url"`
+}
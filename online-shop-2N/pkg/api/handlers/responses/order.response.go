@@ -23,11 +23,14 @@ type PlaceOrder struct {
 }
 
 type OrderItem struct {
+	OrderLineID   uint   `json:"order_line_id"`
 	ProductItemID uint   `json:"product_item_id"`
 	ProductName   string `json:"product_name"`
 	Image         string `json:""`
 	Price         uint   `json:"price"`
 	Qty           uint   `json:"qty"`
+	ReturnedQty   uint   `json:"returned_qty"`
+	CancelledQty  uint   `json:"cancelled_qty"`
 	SubTotal      uint   `json:"sub_total"`
 	OrderDate     string `json:"order_date" `
 	Status        string `json:"status"`
@@ -47,6 +50,31 @@ type ShopOrder struct {
 	PaymentMethodName string    `json:"payment_method_name" gorm:"unique;not null"`
 }
 
+// order status history
+type OrderStatusHistory struct {
+	HistoryID   uint      `json:"history_id"`
+	ShopOrderID uint      `json:"shop_order_id"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	Actor       string    `json:"actor"`
+	ActorID     uint      `json:"actor_id"`
+	Comment     string    `json:"comment"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// amount paid by each payment method for an order
+type ShopOrderPayment struct {
+	OrderPaymentID   uint      `json:"order_payment_id"`
+	ShopOrderID      uint      `json:"shop_order_id"`
+	PaymentMethodID  uint      `json:"payment_method_id"`
+	PaymentType      string    `json:"payment_type"`
+	Amount           uint      `json:"amount"`
+	Status           string    `json:"status"`
+	GatewayOrderID   string    `json:"gateway_order_id"`
+	GatewayPaymentID string    `json:"gateway_payment_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
 // checkout
 type CheckOut struct {
 	Addresses    []Address  `json:"addresses"`
@@ -61,6 +89,7 @@ type OrderReturn struct {
 	RequestDate   time.Time `json:"request_date" `
 	ReturnReason  string    `json:"return_reason" `
 	RefundAmount  uint      `json:"refund_amount" `
+	RefundPolicy  string    `json:"refund_policy"`
 
 	OrderStatusID uint      `json:"order_status_id"`
 	OrderStatus   string    `json:"order_status"`
@@ -83,6 +112,16 @@ type RazorpayOrder struct {
 	ShopOrderID uint `json:"shop_order_id"`
 }
 
+type WalletOrder struct {
+	ShopOrderID uint `json:"shop_order_id"`
+	// amount paid from wallet on this request
+	WalletAmount  uint `json:"wallet_amount"`
+	WalletBalance uint `json:"wallet_balance"`
+	// remaining amount to pay with another payment method
+	AmountToPay uint `json:"amount_to_pay"`
+	OrderPlaced bool `json:"order_placed"`
+}
+
 type StripeOrder struct {
 	ClientSecret   string `json:"client_secret"`
 	PublishableKey string `json:"publishable_key"`
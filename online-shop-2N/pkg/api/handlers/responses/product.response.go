@@ -19,6 +19,105 @@ type Product struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 }
 
+// detail of a single product with all of its items and the offer running on it
+type ProductDetail struct {
+	ID            uint                `json:"product_id"`
+	Name          string              `json:"product_name"`
+	Description   string              `json:"description"`
+	Price         uint                `json:"price"`
+	DiscountPrice uint                `json:"discount_price"`
+	Image         string              `json:"image"`
+	BrandID       uint                `json:"brand_id"`
+	BrandName     string              `json:"brand_name"`
+	CategoryPath  []ProductCategory   `json:"category_path" gorm:"-"`
+	StockStatus   StockStatus         `json:"stock_status" gorm:"-"`
+	Items         []ProductItemDetail `json:"items" gorm:"-"`
+	Offer         *ProductOffer       `json:"offer,omitempty" gorm:"-"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	// categories of product to make the category path
+	CategoryID       uint   `json:"-"`
+	CategoryName     string `json:"-"`
+	MainCategoryID   uint   `json:"-"`
+	MainCategoryName string `json:"-"`
+}
+
+// category of product path, main category first
+type ProductCategory struct {
+	ID   uint   `json:"category_id"`
+	Name string `json:"category_name"`
+}
+
+type ProductItemDetail struct {
+	ID              uint                    `json:"product_item_id"`
+	SKU             string                  `json:"sku"`
+	Price           uint                    `json:"price"`
+	DiscountPrice   uint                    `json:"discount_price"`
+	QtyInStock      uint                    `json:"qty_in_stock"`
+	StockStatus     StockStatus             `json:"stock_status" gorm:"-"`
+	VariationValues []ProductVariationValue `json:"variation_values" gorm:"-"`
+	Images          []string                `json:"images" gorm:"-"`
+}
+
+// variation value or image of a product item found for all items of product together
+type ProductItemVariationValue struct {
+	ProductItemID uint
+	ProductVariationValue
+}
+
+type ProductItemImage struct {
+	ProductItemID uint
+	Image         string
+}
+
+type StockStatus string
+
+const (
+	InStock    StockStatus = "in_stock"
+	LowStock   StockStatus = "low_stock"
+	OutOfStock StockStatus = "out_of_stock"
+)
+
+// offer currently running on product, offer of the product itself or of its category
+type ProductOffer struct {
+	OfferID      uint      `json:"offer_id"`
+	OfferName    string    `json:"offer_name"`
+	Description  string    `json:"description"`
+	DiscountRate uint      `json:"discount_rate"`
+	OfferType    string    `json:"offer_type"`
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"`
+}
+
+// count of products for each value of the facets on the current search and filters
+type ProductFacets struct {
+	Brands           []FacetCount           `json:"brands"`
+	SubCategories    []FacetCount           `json:"sub_categories"`
+	VariationOptions []VariationOptionFacet `json:"variation_options"`
+	PriceRanges      []PriceRangeFacet      `json:"price_ranges"`
+}
+
+type FacetCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count uint64 `json:"count"`
+}
+
+type VariationOptionFacet struct {
+	VariationID   uint   `json:"variation_id"`
+	VariationName string `json:"variation_name"`
+	ID            uint   `json:"variation_option_id"`
+	Value         string `json:"value"`
+	Count         uint64 `json:"count"`
+}
+
+// max price is zero for the last range which have no upper limit
+type PriceRangeFacet struct {
+	MinPrice uint   `json:"min_price"`
+	MaxPrice uint   `json:"max_price,omitempty"`
+	Count    uint64 `json:"count"`
+}
+
 // for a specific category representation
 type Category struct {
 	ID          uint          `json:"category_id"`
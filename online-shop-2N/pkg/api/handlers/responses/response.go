@@ -2,16 +2,21 @@ package responses
 
 import (
 	"log"
+	"online-shop-2N/pkg/pagination"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Response struct {
-	Status  bool        `json:"success"`
-	Message string      `json:"message"`
-	Error   interface{} `json:"error,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
+	Status  bool   `json:"success"`
+	Message string `json:"message"`
+	// code of error for the clients to handle the error without parsing the message
+	Code  string      `json:"code,omitempty"`
+	Error interface{} `json:"error,omitempty"`
+	Data  interface{} `json:"data,omitempty"`
+	// page details of the lists
+	Meta *pagination.Meta `json:"meta,omitempty"`
 }
 
 func SuccessResponse(ctx *gin.Context, statusCode int, message string, data ...interface{}) {
@@ -27,6 +32,21 @@ func SuccessResponse(ctx *gin.Context, statusCode int, message string, data ...i
 	ctx.JSON(statusCode, response)
 }
 
+// response of a page of list with the meta of page
+func SuccessResponseWithMeta(ctx *gin.Context, statusCode int, message string, meta pagination.Meta, data ...interface{}) {
+
+	log.Printf("\033[0;32m%s\033[0m\n", message)
+
+	response := Response{
+		Status:  true,
+		Message: message,
+		Error:   nil,
+		Data:    data,
+		Meta:    &meta,
+	}
+	ctx.JSON(statusCode, response)
+}
+
 func ErrorResponse(ctx *gin.Context, statusCode int, message string, err error, data interface{}) {
 
 	log.Printf("\033[0;31m%s\033[0m\n", err.Error())
@@ -41,3 +61,19 @@ func ErrorResponse(ctx *gin.Context, statusCode int, message string, err error,
 
 	ctx.JSON(statusCode, response)
 }
+
+func ErrorResponseWithCode(ctx *gin.Context, statusCode int, code, message string, err error, data interface{}) {
+
+	log.Printf("\033[0;31m%s\033[0m\n", err.Error())
+
+	errFields := strings.Split(err.Error(), "\n")
+	response := Response{
+		Status:  false,
+		Message: message,
+		Code:    code,
+		Error:   errFields,
+		Data:    data,
+	}
+
+	ctx.JSON(statusCode, response)
+}
@@ -63,3 +63,12 @@ type WishListItem struct {
 	QtyInStock      uint                    `json:"qty_in_stock"`
 	VariationValues []ProductVariationValue `gorm:"-"`
 }
+
+// wallet which total amount not match with its transactions
+type WalletReconciliation struct {
+	WalletID     uint  `json:"wallet_id"`
+	UserID       uint  `json:"user_id"`
+	TotalAmount  uint  `json:"total_amount"`
+	LedgerAmount int64 `json:"ledger_amount"`
+	Difference   int64 `json:"difference"`
+}
@@ -30,15 +30,20 @@ func NewStockHandler(stockUseCase usecaseInterface.StockUseCase) interfaces.Stoc
 //	@Tags			Admin Stock
 //	@Param			page_number	query	int	false	"Page Number"
 //	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/admin/stocks [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully found all stocks"
 //	@Success		204	{object}	responses.Response{}	"No stocks found"
 //	@Failure		500	{object}	responses.Response{}	"Failed to Get all stocks"
 func (c *stockHandler) GetAllStocks(ctx *gin.Context) {
 
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	stocks, err := c.stockUseCase.GetAllStockDetails(ctx, pagination)
+	stocks, meta, err := c.stockUseCase.GetAllStockDetails(ctx, page)
 
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to Get all stocks", err, nil)
@@ -50,7 +55,7 @@ func (c *stockHandler) GetAllStocks(ctx *gin.Context) {
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully found all stocks", stocks)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found all stocks", meta, stocks)
 }
 
 // UpdateStock godoc
@@ -131,6 +131,43 @@ func (u *UserHandler) UpdateProfile(ctx *gin.Context) {
 	responses.SuccessResponse(ctx, http.StatusOK, "Successfully profile updated", nil)
 }
 
+// ChangePassword godoc
+//
+//	@Summary		Change password (User)
+//	@Security		BearerAuth
+//	@Description	API for user to change password with current password, user is logged out from all devices
+//	@Id				ChangePassword
+//	@Tags			User Profile
+//	@Param			input	body	requests.ChangePassword{}	true	"Password input"
+//	@Router			/account/password [put]
+//	@Success		200	{object}	responses.responses{}	"Successfully password changed"
+//	@Failure		400	{object}	responses.responses{}	"Invalid inputs"
+//	@Failure		401	{object}	responses.responses{}	"Current password doesn't match"
+//	@Failure		500	{object}	responses.responses{}	"Failed to change password"
+func (u *UserHandler) ChangePassword(ctx *gin.Context) {
+
+	userID := utils.GetUserIdFromContext(ctx)
+
+	var body requests.ChangePassword
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindJsonFailMessage, err, nil)
+		return
+	}
+
+	err := u.userUseCase.ChangePassword(ctx, userID, body)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, usecases.ErrWrongPassword) {
+			statusCode = http.StatusUnauthorized
+		}
+		responses.ErrorResponse(ctx, statusCode, "Failed to change password", err, nil)
+		return
+	}
+
+	responses.SuccessResponse(ctx, http.StatusOK, "Successfully password changed, sign in again with the new password")
+}
+
 // SaveAddress godoc
 //
 //	@Summary		Add a new address (User)
@@ -40,6 +40,9 @@ func (c *OrderHandler) GetUserWallet(ctx *gin.Context) {
 //	@Description	API for user to get user wallet transaction
 //	@Id				GetUserWalletTransactions
 //	@Tags			User Profile
+//	@Param			page_number	query	int		false	"Page Number"
+//	@Param			count		query	int		false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
 //	@Router			/account/wallet/transactions [get]
 //	@Success		200	{object}	responses.Response{}	"Successfully retrieved user wallet transactions"
 //	@Success		204	{object}	responses.Response{}	"No wallet transaction for user"
@@ -47,9 +50,13 @@ func (c *OrderHandler) GetUserWallet(ctx *gin.Context) {
 func (c *OrderHandler) GetUserWalletTransactions(ctx *gin.Context) {
 
 	userID := utils.GetUserIdFromContext(ctx)
-	pagination := requests.GetPagination(ctx)
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
 
-	transactions, err := c.orderUseCase.FindUserWalletTransactions(ctx, userID, pagination)
+	transactions, meta, err := c.orderUseCase.FindUserWalletTransactions(ctx, userID, page)
 
 	if err != nil {
 		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to retrieve user wallet transactions", err, nil)
@@ -61,5 +68,41 @@ func (c *OrderHandler) GetUserWalletTransactions(ctx *gin.Context) {
 		return
 	}
 
-	responses.SuccessResponse(ctx, http.StatusOK, "Successfully retrieved user wallet transactions", transactions)
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully retrieved user wallet transactions", meta, transactions)
+}
+
+// GetWalletReconciliation godoc
+//
+//	@Summary		Get wallet reconciliation (Admin)
+//	@Security		BearerAuth
+//	@Description	API for admin to get wallets which total amount not match with the sum of wallet transactions
+//	@Id				GetWalletReconciliation
+//	@Tags			Admin Wallet
+//	@Param			page_number	query	int	false	"Page Number"
+//	@Param			count		query	int	false	"Count"
+//	@Param			cursor		query	string	false	"Cursor of next page"
+//	@Router			/admin/wallets/reconciliation [get]
+//	@Success		200	{object}	responses.Response{}	"Successfully found mismatched wallets"
+//	@Success		204	{object}	responses.Response{}	"All wallets are matching with transactions"
+//	@Failure		500	{object}	responses.Response{}	"Failed to reconcile wallets"
+func (c *OrderHandler) GetWalletReconciliation(ctx *gin.Context) {
+
+	page, err := requests.GetPagination(ctx)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusBadRequest, BindQueryFailMessage, err, nil)
+		return
+	}
+
+	wallets, meta, err := c.orderUseCase.FindMismatchedWallets(ctx, page)
+	if err != nil {
+		responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to reconcile wallets", err, nil)
+		return
+	}
+
+	if len(wallets) == 0 {
+		responses.SuccessResponse(ctx, http.StatusNoContent, "All wallets are matching with transactions", nil)
+		return
+	}
+
+	responses.SuccessResponseWithMeta(ctx, http.StatusOK, "Successfully found mismatched wallets", meta, wallets)
 }
@@ -7,6 +7,7 @@ import (
 
 	"online-shop-2N/pkg/api/handlers/responses"
 	token "online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/usecases"
 
 	"github.com/gin-gonic/gin"
 )
@@ -54,19 +55,27 @@ func (c *middleware) authorize(tokenUser token.UserType) gin.HandlerFunc {
 			return
 		}
 
-		tokenVerifyReq := token.VerifyTokenRequest{
-			TokenString: accessToken,
-			UsedFor:     tokenUser,
-		}
-
-		verifyRes, err := c.tokenService.VerifyToken(tokenVerifyReq)
+		// verify the token signature and check it's not revoked or the user is not blocked
+		verifyRes, err := c.authUseCase.VerifyAccessToken(ctx, accessToken, tokenUser)
 
 		if err != nil {
-			responses.ErrorResponse(ctx, http.StatusUnauthorized, "Unauthorized user", err, nil)
+			var statusCode int
+			switch {
+			case errors.Is(err, usecases.ErrInvalidAccessToken),
+				errors.Is(err, usecases.ErrAccessTokenRevoked):
+				statusCode = http.StatusUnauthorized
+			case errors.Is(err, usecases.ErrUserBlocked):
+				statusCode = http.StatusForbidden
+			default:
+				statusCode = http.StatusInternalServerError
+			}
+			responses.ErrorResponse(ctx, statusCode, "Unauthorized user", err, nil)
 			ctx.Abort()
 			return
 		}
 
 		ctx.Set("userId", verifyRes.UserID)
+		ctx.Set("sessionId", verifyRes.SessionID)
+		ctx.Set("tokenId", verifyRes.TokenID)
 	}
 }
@@ -1,7 +1,11 @@
 package middlewares
 
 import (
-	token "online-shop-2N/pkg/services/tokens"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/services/ratelimit"
+	usecaseInterface "online-shop-2N/pkg/usecases/interfaces"
+	"online-shop-2N/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,15 +13,32 @@ import (
 type Middleware interface {
 	AuthenticateUser() gin.HandlerFunc
 	AuthenticateAdmin() gin.HandlerFunc
+	RequirePermission(permissions ...commonConstant.AdminPermission) gin.HandlerFunc
 	TrimSpaces() gin.HandlerFunc
+
+	RateLimitByIP(limit ratelimit.Limit) gin.HandlerFunc
+	RateLimitByIdentifier(limit ratelimit.Limit) gin.HandlerFunc
+	RateLimitByUser(limit ratelimit.Limit) gin.HandlerFunc
 }
 
 type middleware struct {
-	tokenService token.TokenService
+	authUseCase    usecaseInterface.AuthUseCase
+	rateLimitStore ratelimit.Store
+	// to normalize the phone numbers of rate limit keys same as the numbers of users
+	countryCode string
 }
 
-func NewMiddleware(tokenService token.TokenService) Middleware {
+func NewMiddleware(authUseCase usecaseInterface.AuthUseCase, rateLimitStore ratelimit.Store,
+	cfg config.Config) Middleware {
+
+	countryCode := cfg.DefaultCountryCode
+	if countryCode == "" {
+		countryCode = utils.DefaultCountryCode
+	}
+
 	return &middleware{
-		tokenService: tokenService,
+		authUseCase:    authUseCase,
+		rateLimitStore: rateLimitStore,
+		countryCode:    countryCode,
 	}
 }
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/usecases"
+	"online-shop-2N/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get middleware to allow only the admins which have all the given permissions
+// should be used after the AuthenticateAdmin middleware
+func (c *middleware) RequirePermission(permissions ...commonConstant.AdminPermission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		adminID := utils.GetUserIdFromContext(ctx)
+
+		err := c.authUseCase.VerifyAdminPermissions(ctx, adminID, permissions...)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if errors.Is(err, usecases.ErrPermissionDenied) || errors.Is(err, usecases.ErrTwoFactorRequired) {
+				statusCode = http.StatusForbidden
+			}
+			responses.ErrorResponse(ctx, statusCode, "Permission denied", err, nil)
+			ctx.Abort()
+			return
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/services/ratelimit"
+	"online-shop-2N/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// body larger than this is not read to find the identifier
+const maxIdentifierBodySize = 1 << 16
+
+var ErrRateLimited = errors.New("too many requests, retry after some time")
+
+// Get middleware to limit the requests of a client ip on the route,
+// the ip is taken from the forwarded headers only when the request is from a trusted proxy of engine
+func (c *middleware) RateLimitByIP(limit ratelimit.Limit) gin.HandlerFunc {
+	return c.rateLimit(limit, func(ctx *gin.Context) string {
+		return "ip:" + ctx.ClientIP()
+	})
+}
+
+// Get middleware to limit the requests for an account on the route, account is identified by
+// the email, user_name, phone, otp_id or challenge_token on json body, so it's limited even when the ip changes.
+// email and phone are normalized so the different ways of writing them are limited on the same key
+func (c *middleware) RateLimitByIdentifier(limit ratelimit.Limit) gin.HandlerFunc {
+	return c.rateLimit(limit, c.bodyIdentifier)
+}
+
+// Get middleware to limit the requests of the authenticated user or admin on the route
+// should be used after the AuthenticateUser or AuthenticateAdmin middleware
+func (c *middleware) RateLimitByUser(limit ratelimit.Limit) gin.HandlerFunc {
+	return c.rateLimit(limit, func(ctx *gin.Context) string {
+		return "user:" + strconv.FormatUint(uint64(utils.GetUserIdFromContext(ctx)), 10)
+	})
+}
+
+// key of bucket is the route with the key of client, requests without key are not limited
+func (c *middleware) rateLimit(limit ratelimit.Limit, keyFunc func(ctx *gin.Context) string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		clientKey := keyFunc(ctx)
+		if clientKey == "" {
+			return
+		}
+		key := ctx.Request.Method + " " + ctx.FullPath() + " " + clientKey
+
+		result, err := c.rateLimitStore.Take(ctx, key, limit)
+		if err != nil {
+			responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to check rate limit", err, nil)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			responses.ErrorResponseWithCode(ctx, http.StatusTooManyRequests, commonConstant.ErrorCodeRateLimited,
+				"Too many requests", ErrRateLimited, nil)
+			ctx.Abort()
+			return
+		}
+	}
+}
+
+// To find the identifier of account from json body, body is restored for the handlers
+func (c *middleware) bodyIdentifier(ctx *gin.Context) string {
+
+	if ctx.Request.Body == nil || ctx.Request.ContentLength > maxIdentifierBodySize {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxIdentifierBodySize))
+	if err != nil {
+		return ""
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var identifiers struct {
+		Email    string `json:"email"`
+		UserName string `json:"user_name"`
+		Phone    string `json:"phone"`
+		OtpID    string `json:"otp_id"`
+		// sign in challenge of two factor
+		ChallengeToken string `json:"challenge_token"`
+	}
+	if err := json.Unmarshal(body, &identifiers); err != nil {
+		return ""
+	}
+
+	switch {
+	case identifiers.Email != "":
+		return "email:" + normalizeEmail(identifiers.Email)
+	case identifiers.UserName != "":
+		return "user_name:" + strings.TrimSpace(identifiers.UserName)
+	case identifiers.Phone != "":
+		return "phone:" + c.normalizePhone(identifiers.Phone)
+	case identifiers.OtpID != "":
+		return "otp_id:" + identifiers.OtpID
+	case identifiers.ChallengeToken != "":
+		return "challenge_token:" + identifiers.ChallengeToken
+	}
+
+	return ""
+}
+
+// email is lower cased and the sub address (+tag) is removed as the mails of all tags go to the same inbox
+func normalizeEmail(email string) string {
+
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	localPart, domain := email[:at], email[at:]
+	if plus := strings.Index(localPart, "+"); plus > 0 {
+		localPart = localPart[:plus]
+	}
+
+	return localPart + domain
+}
+
+// phone is changed to E.164 format, an invalid number is only stripped of the separators
+func (c *middleware) normalizePhone(phone string) string {
+
+	normalized, err := utils.NormalizePhoneNumber(phone, c.countryCode)
+	if err != nil {
+		return strings.Join(strings.FieldsFunc(phone, func(r rune) bool {
+			return strings.ContainsRune(" -.()", r)
+		}), "")
+	}
+
+	return normalized
+}
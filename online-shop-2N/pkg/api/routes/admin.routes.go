@@ -3,6 +3,9 @@ package routes
 import (
 	handlerInterface "online-shop-2N/pkg/api/handlers/interfaces"
 	"online-shop-2N/pkg/api/middlewares"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/services/ratelimit"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,46 +16,108 @@ func AdminRoutes(api *gin.RouterGroup, authHandler handlerInterface.AuthHandler,
 	paymentHandler handlerInterface.PaymentHandler, orderHandler handlerInterface.OrderHandler,
 	couponHandler handlerInterface.CouponHandler, offerHandler handlerInterface.OfferHandler,
 	stockHandler handlerInterface.StockHandler, branHandler handlerInterface.BrandHandler,
+	catalogHandler handlerInterface.CatalogHandler,
 ) {
 	auth := api.Group("/auth")
 	{
-		login := auth.Group("/sign-in")
+		// limits of auth apis for a client ip, for an account and for the signed in admin
+		var (
+			ipLimit      = middleware.RateLimitByIP(ratelimit.Limit{Requests: 10, Period: time.Minute})
+			accountLimit = middleware.RateLimitByIdentifier(ratelimit.Limit{Requests: 5, Period: time.Minute})
+			adminLimit   = middleware.RateLimitByUser(ratelimit.Limit{Requests: 5, Period: time.Minute})
+		)
+
+		login := auth.Group("/sign-in", ipLimit)
 		{
-			login.POST("/", authHandler.AdminLogin)
+			login.POST("/", accountLimit, authHandler.AdminLogin)
+			login.POST("/two-factor", accountLimit, authHandler.AdminLoginTwoFactor)
 		}
 
-		signup := api.Group("/sign-up")
+		auth.POST("/renew-access-token", authHandler.AdminRenewAccessToken())
+
+		auth.POST("/logout", middleware.AuthenticateAdmin(), authHandler.AdminLogout())
+		auth.POST("/logout-all", middleware.AuthenticateAdmin(), authHandler.AdminLogoutAll())
+
+		sessions := auth.Group("/sessions", middleware.AuthenticateAdmin())
 		{
-			signup.POST("/", adminHandler.AdminSignUp)
+			sessions.GET("/", authHandler.GetAllSessionsAdmin())
+			sessions.DELETE("/:session_id", authHandler.RevokeSessionAdmin())
 		}
 
-		auth.POST("/renew-access-token", authHandler.AdminRenewAccessToken())
+		// without permissions so admins can enable two factor when their roles require it
+		twoFactor := auth.Group("/two-factor", middleware.AuthenticateAdmin())
+		{
+			twoFactor.GET("/", authHandler.GetAdminTwoFactor)
+			twoFactor.POST("/setup", adminLimit, authHandler.SetupAdminTwoFactor)
+			twoFactor.POST("/enable", adminLimit, authHandler.EnableAdminTwoFactor)
+			twoFactor.POST("/disable", adminLimit, authHandler.DisableAdminTwoFactor)
+			twoFactor.POST("/recovery-codes", adminLimit, authHandler.RegenerateAdminRecoveryCodes)
+		}
 	}
 
 	api.Use(middleware.AuthenticateAdmin())
 	{
+		// permissions of admin for each route from its roles
+		var (
+			readUsers        = middleware.RequirePermission(commonConstant.PermissionReadUsers)
+			blockUsers       = middleware.RequirePermission(commonConstant.PermissionBlockUsers)
+			readCatalog      = middleware.RequirePermission(commonConstant.PermissionReadCatalog)
+			manageCatalog    = middleware.RequirePermission(commonConstant.PermissionManageCatalog)
+			readOrders       = middleware.RequirePermission(commonConstant.PermissionReadOrders)
+			manageOrders     = middleware.RequirePermission(commonConstant.PermissionManageOrders)
+			readPayments     = middleware.RequirePermission(commonConstant.PermissionReadPayments)
+			managePayments   = middleware.RequirePermission(commonConstant.PermissionManagePayments)
+			readPromotions   = middleware.RequirePermission(commonConstant.PermissionReadPromotions)
+			managePromotions = middleware.RequirePermission(commonConstant.PermissionManagePromotions)
+			readWallets      = middleware.RequirePermission(commonConstant.PermissionReadWallets)
+			readSales        = middleware.RequirePermission(commonConstant.PermissionReadSales)
+			superAdmin       = middleware.RequirePermission(commonConstant.PermissionAll)
+		)
+
+		// only super admins can create admins and manage their roles
+		signup := api.Group("/sign-up", superAdmin)
+		{
+			signup.POST("/", adminHandler.AdminSignUp)
+		}
+
+		admins := api.Group("/admins", superAdmin)
+		{
+			admins.GET("/", adminHandler.GetAllAdmins)
+			admins.PUT("/:admin_id/roles", adminHandler.UpdateAdminRoles)
+			admins.DELETE("/:admin_id/two-factor", adminHandler.ResetAdminTwoFactor)
+		}
+
+		role := api.Group("/roles", superAdmin)
+		{
+			role.GET("/", adminHandler.GetAllRoles)
+			role.GET("/permissions", adminHandler.GetAllPermissions)
+			role.POST("/", middleware.TrimSpaces(), adminHandler.SaveRole)
+			role.PUT("/:role_id", middleware.TrimSpaces(), adminHandler.UpdateRole)
+			role.DELETE("/:role_id", adminHandler.DeleteRole)
+			role.PUT("/:role_id/two-factor", adminHandler.UpdateRoleTwoFactor)
+		}
 
 		// user side
 		user := api.Group("/users")
 		{
-			user.GET("/", adminHandler.GetAllUsers)
-			user.PATCH("/block", adminHandler.BlockUser)
+			user.GET("/", readUsers, adminHandler.GetAllUsers)
+			user.PATCH("/block", blockUsers, adminHandler.BlockUser)
 		}
 		// category
 		category := api.Group("/categories")
 		{
-			category.GET("/", categoryHandler.GetAllCategories)
-			category.POST("/", middleware.TrimSpaces(), categoryHandler.SaveCategory)
-			category.POST("/sub-categories", middleware.TrimSpaces(), categoryHandler.SaveSubCategory)
+			category.GET("/", readCatalog, categoryHandler.GetAllCategories)
+			category.POST("/", manageCatalog, middleware.TrimSpaces(), categoryHandler.SaveCategory)
+			category.POST("/sub-categories", manageCatalog, middleware.TrimSpaces(), categoryHandler.SaveSubCategory)
 
 			variation := category.Group("/:category_id/variations")
 			{
-				variation.POST("/", middleware.TrimSpaces(), productHandler.SaveVariation)
-				variation.GET("/", productHandler.GetAllVariations)
+				variation.POST("/", manageCatalog, middleware.TrimSpaces(), productHandler.SaveVariation)
+				variation.GET("/", readCatalog, productHandler.GetAllVariations)
 
 				variationOption := variation.Group("/:variation_id/options")
 				{
-					variationOption.POST("/", middleware.TrimSpaces(), productHandler.SaveVariationOption)
+					variationOption.POST("/", manageCatalog, middleware.TrimSpaces(), productHandler.SaveVariationOption)
 				}
 			}
 
@@ -60,89 +125,107 @@ func AdminRoutes(api *gin.RouterGroup, authHandler handlerInterface.AuthHandler,
 		// brand
 		brand := api.Group("/brands")
 		{
-			brand.POST("", branHandler.Save)
-			brand.GET("", branHandler.FindAll)
-			brand.GET("/:brand_id", branHandler.FindOne)
-			brand.PUT("/:brand_id", branHandler.Update)
-			brand.DELETE("/:brand_id", branHandler.Delete)
+			brand.POST("", manageCatalog, branHandler.Save)
+			brand.GET("", readCatalog, branHandler.FindAll)
+			brand.GET("/:brand_id", readCatalog, branHandler.FindOne)
+			brand.PUT("/:brand_id", manageCatalog, branHandler.Update)
+			brand.DELETE("/:brand_id", manageCatalog, branHandler.Delete)
 		}
 
 		// product
 		product := api.Group("/products")
 		{
-			product.GET("/", productHandler.GetAllProductsAdmin())
-			product.POST("/", middleware.TrimSpaces(), productHandler.SaveProduct)
-			product.PUT("/", middleware.TrimSpaces(), productHandler.UpdateProduct)
+			product.GET("/", readCatalog, productHandler.GetAllProductsAdmin())
+			product.POST("/", manageCatalog, middleware.TrimSpaces(), productHandler.SaveProduct)
+			product.PUT("/", manageCatalog, middleware.TrimSpaces(), productHandler.UpdateProduct)
 
 			productItem := product.Group("/:product_id/items")
 			{
-				productItem.GET("/", productHandler.GetAllProductItemsAdmin())
-				productItem.POST("/", productHandler.SaveProductItem)
+				productItem.GET("/", readCatalog, productHandler.GetAllProductItemsAdmin())
+				productItem.POST("/", manageCatalog, productHandler.SaveProductItem)
 			}
 		}
+
+		// bulk import and export of product items
+		catalog := api.Group("/catalog")
+		{
+			catalog.POST("/import", manageCatalog, catalogHandler.ImportCatalog)
+			catalog.GET("/import/:job_id", readCatalog, catalogHandler.GetImportJob)
+			catalog.GET("/import/:job_id/errors", readCatalog, catalogHandler.GetAllImportErrors)
+			catalog.GET("/export", readCatalog, catalogHandler.ExportCatalog)
+		}
 		// 	// order
 		order := api.Group("/orders")
 		{
-			order.GET("/all", orderHandler.GetAllShopOrders)
-			order.GET("/:shop_order_id/items", orderHandler.GetAllOrderItemsAdmin())
-			order.PUT("/", orderHandler.UpdateOrderStatus)
+			order.GET("/all", readOrders, orderHandler.GetAllShopOrders)
+			order.GET("/:shop_order_id/items", readOrders, orderHandler.GetAllOrderItemsAdmin())
+			order.GET("/:shop_order_id/history", readOrders, orderHandler.GetOrderStatusHistoryAdmin)
+			order.GET("/:shop_order_id/payments", readOrders, orderHandler.GetOrderPaymentsAdmin)
+			order.GET("/:shop_order_id/refunds", readOrders, orderHandler.GetOrderRefundsAdmin)
+			order.PUT("/", manageOrders, orderHandler.UpdateOrderStatus)
 
 			status := order.Group("/statuses")
 			{
-				status.GET("/", orderHandler.GetAllOrderStatuses)
+				status.GET("/", readOrders, orderHandler.GetAllOrderStatuses)
 			}
 
 			//return requests
-			order.GET("/returns", orderHandler.GetAllOrderReturns)
-			order.GET("/returns/pending", orderHandler.GetAllPendingReturns)
-			order.PUT("/returns/pending", orderHandler.UpdateReturnRequest)
+			order.GET("/returns", readOrders, orderHandler.GetAllOrderReturns)
+			order.GET("/returns/pending", readOrders, orderHandler.GetAllPendingReturns)
+			order.PUT("/returns/pending", manageOrders, orderHandler.UpdateReturnRequest)
+		}
+
+		// wallet
+		wallet := api.Group("/wallets", readWallets)
+		{
+			wallet.GET("/reconciliation", orderHandler.GetWalletReconciliation)
 		}
 
 		// payment_method
 		paymentMethod := api.Group("/payment-methods")
 		{
-			paymentMethod.GET("/", paymentHandler.GetAllPaymentMethodsAdmin())
+			paymentMethod.GET("/", readPayments, paymentHandler.GetAllPaymentMethodsAdmin())
 			// paymentMethod.POST("/", paymentHandler.AddPaymentMethod)
-			paymentMethod.PUT("/", paymentHandler.UpdatePaymentMethod)
+			paymentMethod.PUT("/", managePayments, paymentHandler.UpdatePaymentMethod)
 		}
 
 		// offer
 		offer := api.Group("/offers")
 		{
-			offer.POST("/", middleware.TrimSpaces(), offerHandler.SaveOffer) // add a new offer
-			offer.GET("/", offerHandler.GetAllOffers)                        // get all offers
-			offer.DELETE("/:offer_id", offerHandler.RemoveOffer)
-
-			offer.GET("/category", offerHandler.GetAllCategoryOffers)                        // to get all offers of categories
-			offer.POST("/category", middleware.TrimSpaces(), offerHandler.SaveCategoryOffer) // add offer for categories
-			offer.PATCH("/category", offerHandler.ChangeCategoryOffer)
-			offer.DELETE("/category/:offer_category_id", offerHandler.RemoveCategoryOffer)
-
-			offer.GET("/products", offerHandler.GetAllProductsOffers)                       // to get all offers of products
-			offer.POST("/products", middleware.TrimSpaces(), offerHandler.SaveProductOffer) // add offer for products
-			offer.PATCH("/products", offerHandler.ChangeProductOffer)
-			offer.DELETE("/products/:offer_product_id", offerHandler.RemoveProductOffer)
+			offer.POST("/", managePromotions, middleware.TrimSpaces(), offerHandler.SaveOffer) // add a new offer
+			offer.GET("/", readPromotions, offerHandler.GetAllOffers)                          // get all offers
+			offer.DELETE("/:offer_id", managePromotions, offerHandler.RemoveOffer)
+
+			offer.GET("/category", readPromotions, offerHandler.GetAllCategoryOffers)                          // to get all offers of categories
+			offer.POST("/category", managePromotions, middleware.TrimSpaces(), offerHandler.SaveCategoryOffer) // add offer for categories
+			offer.PATCH("/category", managePromotions, offerHandler.ChangeCategoryOffer)
+			offer.DELETE("/category/:offer_category_id", managePromotions, offerHandler.RemoveCategoryOffer)
+
+			offer.GET("/products", readPromotions, offerHandler.GetAllProductsOffers)                         // to get all offers of products
+			offer.POST("/products", managePromotions, middleware.TrimSpaces(), offerHandler.SaveProductOffer) // add offer for products
+			offer.PATCH("/products", managePromotions, offerHandler.ChangeProductOffer)
+			offer.DELETE("/products/:offer_product_id", managePromotions, offerHandler.RemoveProductOffer)
 		}
 
 		// coupons
 		coupons := api.Group("/coupons")
 		{
-			coupons.POST("/", middleware.TrimSpaces(), couponHandler.SaveCoupon)
-			coupons.GET("/", couponHandler.GetAllCouponsAdmin)
-			coupons.PUT("/", middleware.TrimSpaces(), couponHandler.UpdateCoupon)
+			coupons.POST("/", managePromotions, middleware.TrimSpaces(), couponHandler.SaveCoupon)
+			coupons.GET("/", readPromotions, couponHandler.GetAllCouponsAdmin)
+			coupons.PUT("/", managePromotions, middleware.TrimSpaces(), couponHandler.UpdateCoupon)
 		}
 
 		// sales report
-		sales := api.Group("/sales")
+		sales := api.Group("/sales", readSales)
 		{
 			sales.GET("/", adminHandler.GetFullSalesReport)
 		}
 
 		stock := api.Group("/stocks")
 		{
-			stock.GET("/", stockHandler.GetAllStocks)
+			stock.GET("/", readCatalog, stockHandler.GetAllStocks)
 
-			stock.PATCH("/", stockHandler.UpdateStock)
+			stock.PATCH("/", manageCatalog, stockHandler.UpdateStock)
 		}
 
 	}
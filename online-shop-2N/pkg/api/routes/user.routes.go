@@ -3,6 +3,8 @@ package routes
 import (
 	handlerInterface "online-shop-2N/pkg/api/handlers/interfaces"
 	"online-shop-2N/pkg/api/middlewares"
+	"online-shop-2N/pkg/services/ratelimit"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,40 +15,76 @@ func UserRoutes(api *gin.RouterGroup, authHandler handlerInterface.AuthHandler,
 	orderHandler handlerInterface.OrderHandler, couponHandler handlerInterface.CouponHandler) {
 	auth := api.Group("/auth")
 	{
-		signup := auth.Group("/sign-up")
+		// limits of auth apis for a client ip and for an account
+		var (
+			ipLimit        = middleware.RateLimitByIP(ratelimit.Limit{Requests: 20, Period: time.Minute})
+			accountLimit   = middleware.RateLimitByIdentifier(ratelimit.Limit{Requests: 10, Period: time.Minute})
+			otpSendIPLimit = middleware.RateLimitByIP(ratelimit.Limit{Requests: 5, Period: time.Minute * 10})
+			// sms of otp cost money so it's limited more for an account
+			otpSendAccountLimit = middleware.RateLimitByIdentifier(ratelimit.Limit{Requests: 3, Period: time.Minute * 10})
+		)
+
+		signup := auth.Group("/sign-up", ipLimit)
 		{
-			signup.POST("/", authHandler.UserSignUp)
-			signup.POST("/verify", authHandler.UserSignUpVerify)
+			signup.POST("/", accountLimit, authHandler.UserSignUp)
+			signup.POST("/verify", accountLimit, authHandler.UserSignUpVerify)
 		}
 
 		login := auth.Group("/sign-in")
 		{
-			login.POST("/", authHandler.UserLogin)
-			login.POST("/otp/send", authHandler.UserLoginOtpSend)
-			login.POST("/otp/verify", authHandler.UserLoginOtpVerify)
+			login.POST("/", ipLimit, accountLimit, authHandler.UserLogin)
+			login.POST("/otp/send", otpSendIPLimit, otpSendAccountLimit, authHandler.UserLoginOtpSend)
+			login.POST("/otp/verify", ipLimit, accountLimit, authHandler.UserLoginOtpVerify)
 		}
 
 		goath := auth.Group("/google-auth")
 		{
-			goath.GET("/", authHandler.UserGoogleAuthLoginPage)
 			goath.GET("/initialize", authHandler.UserGoogleAuthInitialize)
 			goath.GET("/callback", authHandler.UserGoogleAuthCallBack)
 		}
 
+		oauth := auth.Group("/oauth")
+		{
+			oauth.GET("/providers", authHandler.GetOAuthProviders)
+			oauth.GET("/:provider/login", ipLimit, authHandler.UserOAuthLogin)
+			// apple post the callback as form
+			oauth.GET("/:provider/callback", ipLimit, authHandler.UserOAuthCallback)
+			oauth.POST("/:provider/callback", ipLimit, authHandler.UserOAuthCallback)
+			oauth.POST("/token", ipLimit, authHandler.UserOAuthToken)
+		}
+
+		password := auth.Group("/password", ipLimit)
+		{
+			password.POST("/forgot", accountLimit, authHandler.UserForgotPassword)
+			password.POST("/reset", authHandler.UserResetPassword)
+		}
+
+		email := auth.Group("/email", ipLimit)
+		{
+			email.POST("/verify", authHandler.UserVerifyEmail)
+			email.POST("/verify/resend", accountLimit, authHandler.UserResendEmailVerification)
+		}
+
 		auth.POST("/renew-access-token", authHandler.UserRenewAccessToken())
 
-		// api.POST("/logout")
+		auth.POST("/logout", middleware.AuthenticateUser(), authHandler.UserLogout())
+		auth.POST("/logout-all", middleware.AuthenticateUser(), authHandler.UserLogoutAll())
 
+		sessions := auth.Group("/sessions", middleware.AuthenticateUser())
+		{
+			sessions.GET("/", authHandler.GetAllSessionsUser())
+			sessions.DELETE("/:session_id", authHandler.RevokeSessionUser())
+		}
 	}
 
 	api.Use(middleware.AuthenticateUser())
 	{
 
-		// api.POST("/logout", userHandler.UserLogout)
-
 		product := api.Group("/products")
 		{
 			product.GET("/", productHandler.GetAllProductsUser())
+			product.GET("/facets", productHandler.GetProductFacets)
+			product.GET("/:product_id", productHandler.GetProductDetail)
 
 			productItem := product.Group("/:product_id/items")
 			{
@@ -70,6 +108,8 @@ func UserRoutes(api *gin.RouterGroup, authHandler handlerInterface.AuthHandler,
 
 			// 		//cart.GET("/checkout", userHandler.CheckOutCart, orderHandler.GetAllPaymentMethods)
 			cart.POST("/place-order/cod", paymentHandler.PaymentCOD)
+			// wallet payment, remaining amount can pay with cod, razorpay or stripe
+			cart.POST("/place-order/wallet", paymentHandler.PaymentWallet)
 
 			// razorpay payment
 			cart.POST("/place-order/razorpay-checkout", paymentHandler.RazorpayCheckout)
@@ -85,6 +125,14 @@ func UserRoutes(api *gin.RouterGroup, authHandler handlerInterface.AuthHandler,
 		{
 			account.GET("/", userHandler.GetProfile)
 			account.PUT("/", userHandler.UpdateProfile)
+			account.PUT("/password", userHandler.ChangePassword)
+
+			identities := account.Group("/identities")
+			{
+				identities.GET("/", authHandler.GetAllUserIdentities)
+				identities.POST("/:provider", authHandler.LinkUserIdentity)
+				identities.DELETE("/:provider", authHandler.UnlinkUserIdentity)
+			}
 
 			account.GET("/address", userHandler.GetAllAddresses) // to show all address and // show countries
 			account.POST("/address", userHandler.SaveAddress)    // to add a new address
@@ -121,6 +169,9 @@ func UserRoutes(api *gin.RouterGroup, authHandler handlerInterface.AuthHandler,
 		{
 			orders.GET("/", orderHandler.GetUserOrder)                               // get all order list for user
 			orders.GET("/:shop_order_id/items", orderHandler.GetAllOrderItemsUser()) //get order items for specific order
+			orders.GET("/:shop_order_id/history", orderHandler.GetOrderStatusHistoryUser)
+			orders.GET("/:shop_order_id/payments", orderHandler.GetOrderPaymentsUser)
+			orders.GET("/:shop_order_id/refunds", orderHandler.GetOrderRefundsUser)
 
 			orders.POST("/return", orderHandler.SubmitReturnRequest)
 			orders.POST("/:shop_order_id/cancel", orderHandler.CancelOrder) // cancel an order
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	handlerInterface "online-shop-2N/pkg/api/handlers/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhooks are called by payment gateways so they are verified by signature instead of auth middleware
+func WebhookRoutes(api *gin.RouterGroup, paymentHandler handlerInterface.PaymentHandler) {
+
+	api.POST("/stripe", paymentHandler.StripeWebhook)
+	api.POST("/razorpay", paymentHandler.RazorpayWebhook)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	handlerInterface "online-shop-2N/pkg/api/handlers/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// well known routes are public and used by other services
+func WellKnownRoutes(api *gin.RouterGroup, authHandler handlerInterface.AuthHandler) {
+
+	api.GET("/jwks.json", authHandler.GetJWKS)
+}
@@ -1,29 +1,52 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
 	handlerInterface "online-shop-2N/pkg/api/handlers/interfaces"
 	"online-shop-2N/pkg/api/middlewares"
 	"online-shop-2N/pkg/api/routes"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/workers"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// time given to the running requests to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
 type ServerHTTP struct {
-	Engine *gin.Engine
+	Engine                *gin.Engine
+	orderExpiryWorker     workers.OrderExpiryWorker
+	refundReconcileWorker workers.RefundReconcileWorker
+	catalogImportWorker   workers.CatalogImportWorker
 }
 
-func NewServerHTTP(authHandler handlerInterface.AuthHandler, middlewares middlewares.Middleware,
+func NewServerHTTP(cfg config.Config, authHandler handlerInterface.AuthHandler, middlewares middlewares.Middleware,
 	adminHandler handlerInterface.AdminHandler, userHandler handlerInterface.UserHandler,
 	cartHandler handlerInterface.CartHandler, paymentHandler handlerInterface.PaymentHandler,
 	productHandler handlerInterface.ProductHandler, categoryHandler handlerInterface.CategoryHandler,
 	orderHandler handlerInterface.OrderHandler,
 	couponHandler handlerInterface.CouponHandler, offerHandler handlerInterface.OfferHandler,
 	stockHandler handlerInterface.StockHandler, branHandler handlerInterface.BrandHandler,
-) *ServerHTTP {
+	catalogHandler handlerInterface.CatalogHandler,
+	orderExpiryWorker workers.OrderExpiryWorker, refundReconcileWorker workers.RefundReconcileWorker,
+	catalogImportWorker workers.CatalogImportWorker,
+) (*ServerHTTP, error) {
 	engine := gin.New()
 
-	// engine.LoadHTMLGlob("views/*.html")
+	// without trusted proxies the client ip is the remote address of request, so it can't be spoofed with headers
+	if err := engine.SetTrustedProxies(splitList(cfg.TrustedProxies)); err != nil {
+		return nil, err
+	}
 
 	engine.Use(gin.Logger())
 
@@ -31,7 +54,10 @@ func NewServerHTTP(authHandler handlerInterface.AuthHandler, middlewares middlew
 	routes.UserRoutes(engine.Group("/api"), authHandler, middlewares, userHandler, cartHandler,
 		productHandler, paymentHandler, orderHandler, couponHandler)
 	routes.AdminRoutes(engine.Group("/api/admin"), authHandler, middlewares, adminHandler,
-		productHandler, categoryHandler, paymentHandler, orderHandler, couponHandler, offerHandler, stockHandler, branHandler)
+		productHandler, categoryHandler, paymentHandler, orderHandler, couponHandler, offerHandler, stockHandler, branHandler,
+		catalogHandler)
+	routes.WebhookRoutes(engine.Group("/api/webhooks"), paymentHandler)
+	routes.WellKnownRoutes(engine.Group("/.well-known"), authHandler)
 
 	// No hanldlers
 	engine.NoRoute(func(context *gin.Context) {
@@ -39,9 +65,69 @@ func NewServerHTTP(authHandler handlerInterface.AuthHandler, middlewares middlew
 			"message": "Invalid URL provided",
 		})
 	})
-	return &ServerHTTP{Engine: engine}
+	return &ServerHTTP{
+		Engine:                engine,
+		orderExpiryWorker:     orderExpiryWorker,
+		refundReconcileWorker: refundReconcileWorker,
+		catalogImportWorker:   catalogImportWorker,
+	}, nil
 }
 
+// To run the server with the background workers until an interrupt or terminate signal,
+// workers are stopped with the server and the running requests are given time to finish
 func (s *ServerHTTP) Start() error {
-	return s.Engine.Run(":8000")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	for _, start := range []func(ctx context.Context){
+		s.orderExpiryWorker.Start, s.refundReconcileWorker.Start, s.catalogImportWorker.Start,
+	} {
+		wg.Add(1)
+		go func(start func(ctx context.Context)) {
+			defer wg.Done()
+			start(ctx)
+		}(start)
+	}
+
+	server := &http.Server{
+		Addr:    ":8000",
+		Handler: s.Engine,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serverErr:
+		// workers are stopped when the server failed
+		stop()
+	case <-ctx.Done():
+		log.Printf("shutting down the server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err = server.Shutdown(shutdownCtx)
+	}
+
+	wg.Wait()
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func splitList(value string) []string {
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
 }
@@ -0,0 +1,101 @@
+// Package catalog is the file format of the bulk import and export of the catalog, each row of a file is a product item
+// with the names of its product, category, brand and variation options so the files can be edited and moved between shops.
+// files are csv with a header row or json lines with an object on each line
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+var (
+	ErrInvalidFormat = errors.New("format should be csv or jsonl")
+	ErrInvalidHeader = errors.New("invalid header of csv")
+	// error of a row which is reported and the rows after it are continued
+	ErrInvalidRow = errors.New("invalid row")
+)
+
+// names of the fields on csv header and json lines
+const (
+	fieldSKU              = "sku"
+	fieldProductName      = "product_name"
+	fieldDescription      = "description"
+	fieldMainCategory     = "main_category"
+	fieldCategory         = "category"
+	fieldBrand            = "brand"
+	fieldPrice            = "price"
+	fieldQtyInStock       = "qty_in_stock"
+	fieldVariationOptions = "variation_options"
+)
+
+// fields of csv in the order they are exported
+var csvFields = []string{
+	fieldSKU, fieldProductName, fieldDescription, fieldMainCategory, fieldCategory,
+	fieldBrand, fieldPrice, fieldQtyInStock, fieldVariationOptions,
+}
+
+// fields which can be left out from the csv header of import
+var optionalCSVFields = map[string]bool{
+	fieldMainCategory:     true,
+	fieldVariationOptions: true,
+}
+
+// Row is a product item of catalog, category is the sub category of product
+// and main category is only needed when the name of sub category is used on more than one main category.
+// variation options are the value of each variation of the category by the variation name (Color: Red)
+type Row struct {
+	SKU              string            `json:"sku"`
+	ProductName      string            `json:"product_name"`
+	Description      string            `json:"description"`
+	MainCategory     string            `json:"main_category,omitempty"`
+	Category         string            `json:"category"`
+	Brand            string            `json:"brand"`
+	Price            uint              `json:"price"`
+	QtyInStock       uint              `json:"qty_in_stock"`
+	VariationOptions map[string]string `json:"variation_options,omitempty"`
+}
+
+// To get the format from its name, ndjson is same as json lines
+func ParseFormat(name string) (Format, error) {
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	}
+
+	return "", ErrInvalidFormat
+}
+
+// To get the format of a file from its extension
+func FormatOfFile(fileName string) (Format, error) {
+
+	ext := strings.TrimPrefix(filepath.Ext(fileName), ".")
+	if ext == "" {
+		return "", fmt.Errorf("%w: file have no extension", ErrInvalidFormat)
+	}
+
+	return ParseFormat(ext)
+}
+
+// content type of the format on export
+func (f Format) ContentType() string {
+	if f == FormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv"
+}
+
+func invalidRowError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRow, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,210 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Category is a sub category with the name of its main category
+type Category struct {
+	ID               uint
+	Name             string
+	MainCategoryName string
+}
+
+type Brand struct {
+	ID   uint
+	Name string
+}
+
+// VariationOption is an option of a variation of category,
+// option is empty (id zero) for the variations which have no options yet
+type VariationOption struct {
+	CategoryID    uint
+	VariationID   uint
+	VariationName string
+	ID            uint
+	Value         string
+}
+
+type variation struct {
+	name string
+	// id of each option by its value
+	options map[string]uint
+}
+
+// Lookup is the categories, brands and variation options of the catalog which the rows are validated against,
+// all the names and values are matched case insensitively
+type Lookup struct {
+	categories map[string][]Category
+	brands     map[string]uint
+	// variations of each category by the variation name
+	variations map[uint]map[string]*variation
+}
+
+// ResolvedRow is a valid row with the ids of its category, brand and variation options
+type ResolvedRow struct {
+	Row
+	CategoryID uint
+	BrandID    uint
+	// sorted option ids, one option of each variation of the category
+	VariationOptionIDs []uint
+}
+
+func NewLookup(categories []Category, brands []Brand, variationOptions []VariationOption) *Lookup {
+
+	lookup := &Lookup{
+		categories: map[string][]Category{},
+		brands:     map[string]uint{},
+		variations: map[uint]map[string]*variation{},
+	}
+
+	for _, category := range categories {
+		key := lookupKey(category.Name)
+		lookup.categories[key] = append(lookup.categories[key], category)
+	}
+
+	for _, brand := range brands {
+		lookup.brands[lookupKey(brand.Name)] = brand.ID
+	}
+
+	for _, option := range variationOptions {
+		variations, ok := lookup.variations[option.CategoryID]
+		if !ok {
+			variations = map[string]*variation{}
+			lookup.variations[option.CategoryID] = variations
+		}
+		key := lookupKey(option.VariationName)
+		if _, ok := variations[key]; !ok {
+			variations[key] = &variation{
+				name:    option.VariationName,
+				options: map[string]uint{},
+			}
+		}
+		if option.ID != 0 {
+			variations[key].options[lookupKey(option.Value)] = option.ID
+		}
+	}
+
+	return lookup
+}
+
+// To check the row is valid and find the ids of its names,
+// the error is an ErrInvalidRow with all the problems of the row
+func (c *Lookup) Resolve(row Row) (ResolvedRow, error) {
+
+	resolved := ResolvedRow{Row: row}
+
+	var problems []string
+	addProblem := func(problem string) {
+		problems = append(problems, problem)
+	}
+
+	if row.SKU == "" {
+		addProblem("sku is required")
+	}
+	if length := utf8.RuneCountInString(row.ProductName); length < 3 || length > 50 {
+		addProblem("product_name should be 3 to 50 characters")
+	}
+	if length := utf8.RuneCountInString(row.Description); length < 10 || length > 100 {
+		addProblem("description should be 10 to 100 characters")
+	}
+	if row.Price < 1 {
+		addProblem("price should be at least 1")
+	}
+
+	if row.Brand == "" {
+		addProblem("brand is required")
+	} else if brandID, ok := c.brands[lookupKey(row.Brand)]; ok {
+		resolved.BrandID = brandID
+	} else {
+		addProblem("brand '" + row.Brand + "' not exist")
+	}
+
+	categoryID, problem := c.findCategory(row.MainCategory, row.Category)
+	if problem != "" {
+		addProblem(problem)
+	} else {
+		resolved.CategoryID = categoryID
+		optionIDs, optionProblems := c.findVariationOptions(categoryID, row.Category, row.VariationOptions)
+		problems = append(problems, optionProblems...)
+		resolved.VariationOptionIDs = optionIDs
+	}
+
+	if len(problems) > 0 {
+		return resolved, invalidRowError("%s", strings.Join(problems, ", "))
+	}
+
+	return resolved, nil
+}
+
+func (c *Lookup) findCategory(mainCategoryName, name string) (categoryID uint, problem string) {
+
+	if name == "" {
+		return 0, "category is required"
+	}
+
+	var found []Category
+	for _, category := range c.categories[lookupKey(name)] {
+		if mainCategoryName == "" || lookupKey(category.MainCategoryName) == lookupKey(mainCategoryName) {
+			found = append(found, category)
+		}
+	}
+
+	switch {
+	case len(found) == 0 && mainCategoryName != "":
+		return 0, "category '" + name + "' not exist under main category '" + mainCategoryName + "'"
+	case len(found) == 0:
+		return 0, "category '" + name + "' not exist"
+	case len(found) > 1:
+		return 0, "category '" + name + "' exist under more than one main category, main_category is required"
+	}
+
+	return found[0].ID, ""
+}
+
+// each variation of the category should have a value on row
+func (c *Lookup) findVariationOptions(categoryID uint, categoryName string,
+	options map[string]string) (optionIDs []uint, problems []string) {
+
+	variations := c.variations[categoryID]
+
+	given := map[string]bool{}
+	for name, value := range options {
+		key := lookupKey(name)
+		variation, ok := variations[key]
+		if !ok {
+			problems = append(problems, "variation '"+name+"' not exist for category '"+categoryName+"'")
+			continue
+		}
+		if given[key] {
+			problems = append(problems, "variation '"+name+"' is repeated")
+			continue
+		}
+		given[key] = true
+
+		optionID, ok := variation.options[lookupKey(value)]
+		if !ok {
+			problems = append(problems, "value '"+value+"' not exist for variation '"+variation.name+"'")
+			continue
+		}
+		optionIDs = append(optionIDs, optionID)
+	}
+
+	for key, variation := range variations {
+		if !given[key] {
+			problems = append(problems, "value of variation '"+variation.name+"' is required")
+		}
+	}
+
+	// problems are sorted as the map have no order
+	sort.Strings(problems)
+	sort.Slice(optionIDs, func(i, j int) bool { return optionIDs[i] < optionIDs[j] })
+
+	return optionIDs, problems
+}
+
+func lookupKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
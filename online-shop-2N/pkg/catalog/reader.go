@@ -0,0 +1,220 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maximum length of a line of json lines
+const maxJSONLineBytes = 1 << 20
+
+// Reader read the rows of an import file one by one
+type Reader interface {
+	// Read the next row with its line number on the file, io.EOF is returned after the last row.
+	// error of a row which can't be parsed is an ErrInvalidRow and the rows after it can be read
+	Read() (row Row, line int, err error)
+}
+
+// To create a reader of the format, header of csv is read and checked on creation
+func NewReader(format Format, r io.Reader) (Reader, error) {
+
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSONL:
+		return newJSONLReader(r), nil
+	}
+
+	return nil, ErrInvalidFormat
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	// index of each field on the record
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: file is empty", ErrInvalidHeader)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+
+	known := map[string]bool{}
+	for _, field := range csvFields {
+		known[field] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// excel keep the byte order mark at the start of file
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			return nil, fmt.Errorf("%w: unknown column '%s'", ErrInvalidHeader, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: column '%s' is repeated", ErrInvalidHeader, name)
+		}
+		columns[name] = i
+	}
+	for _, field := range csvFields {
+		if _, ok := columns[field]; !ok && !optionalCSVFields[field] {
+			return nil, fmt.Errorf("%w: column '%s' is required", ErrInvalidHeader, field)
+		}
+	}
+
+	return &csvReader{
+		reader:  reader,
+		columns: columns,
+	}, nil
+}
+
+func (c *csvReader) Read() (Row, int, error) {
+
+	record, err := c.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Row{}, parseErr.StartLine, invalidRowError("%v", parseErr.Err)
+		}
+		return Row{}, 0, err
+	}
+	line, _ := c.reader.FieldPos(0)
+
+	value := func(field string) string {
+		if i, ok := c.columns[field]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	row := Row{
+		SKU:          value(fieldSKU),
+		ProductName:  value(fieldProductName),
+		Description:  value(fieldDescription),
+		MainCategory: value(fieldMainCategory),
+		Category:     value(fieldCategory),
+		Brand:        value(fieldBrand),
+	}
+
+	if row.Price, err = parseUint(fieldPrice, value(fieldPrice)); err != nil {
+		return row, line, err
+	}
+	if row.QtyInStock, err = parseUint(fieldQtyInStock, value(fieldQtyInStock)); err != nil {
+		return row, line, err
+	}
+	if row.VariationOptions, err = parseVariationOptions(value(fieldVariationOptions)); err != nil {
+		return row, line, err
+	}
+
+	return row, line, nil
+}
+
+// empty value is taken as zero
+func parseUint(field, value string) (uint, error) {
+
+	if value == "" {
+		return 0, nil
+	}
+
+	num, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, invalidRowError("%s should be a positive number", field)
+	}
+
+	return uint(num), nil
+}
+
+// variation options of csv are the pairs of variation name and value separated by semicolon (Color=Red;Size=M)
+func parseVariationOptions(value string) (map[string]string, error) {
+
+	if value == "" {
+		return nil, nil
+	}
+
+	options := map[string]string{}
+	for _, pair := range strings.Split(value, ";") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, optionValue, ok := strings.Cut(pair, "=")
+		name, optionValue = strings.TrimSpace(name), strings.TrimSpace(optionValue)
+		if !ok || name == "" || optionValue == "" {
+			return nil, invalidRowError("variation option '%s' should be as name=value", pair)
+		}
+		if _, ok := options[name]; ok {
+			return nil, invalidRowError("variation '%s' is repeated", name)
+		}
+		options[name] = optionValue
+	}
+
+	return options, nil
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLineBytes)
+
+	return &jsonlReader{
+		scanner: scanner,
+	}
+}
+
+func (c *jsonlReader) Read() (Row, int, error) {
+
+	for c.scanner.Scan() {
+		c.line++
+
+		data := bytes.TrimSpace(c.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		var row Row
+		if err := decoder.Decode(&row); err != nil {
+			return Row{}, c.line, invalidRowError("invalid json: %v", err)
+		}
+		if decoder.More() {
+			return Row{}, c.line, invalidRowError("invalid json: more than one object on the line")
+		}
+
+		row.SKU = strings.TrimSpace(row.SKU)
+		row.ProductName = strings.TrimSpace(row.ProductName)
+		row.Description = strings.TrimSpace(row.Description)
+		row.MainCategory = strings.TrimSpace(row.MainCategory)
+		row.Category = strings.TrimSpace(row.Category)
+		row.Brand = strings.TrimSpace(row.Brand)
+
+		return row, c.line, nil
+	}
+
+	if err := c.scanner.Err(); err != nil {
+		return Row{}, c.line + 1, err
+	}
+
+	return Row{}, 0, io.EOF
+}
@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Writer write the rows of catalog on export in the same format which can be imported back
+type Writer interface {
+	Write(row Row) error
+	// Flush write the buffered rows, it should be called after the last row
+	Flush() error
+}
+
+// To create a writer of the format, header of csv is written on creation
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	}
+
+	return nil, ErrInvalidFormat
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvFields); err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{
+		writer: writer,
+		record: make([]string, len(csvFields)),
+	}, nil
+}
+
+func (c *csvWriter) Write(row Row) error {
+
+	c.record[0] = row.SKU
+	c.record[1] = row.ProductName
+	c.record[2] = row.Description
+	c.record[3] = row.MainCategory
+	c.record[4] = row.Category
+	c.record[5] = row.Brand
+	c.record[6] = strconv.FormatUint(uint64(row.Price), 10)
+	c.record[7] = strconv.FormatUint(uint64(row.QtyInStock), 10)
+	c.record[8] = formatVariationOptions(row.VariationOptions)
+
+	return c.writer.Write(c.record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// variation options are sorted by name so the same item is exported same always
+func formatVariationOptions(options map[string]string) string {
+
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + options[name]
+	}
+
+	return strings.Join(pairs, ";")
+}
+
+type jsonlWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	return &jsonlWriter{
+		buffer:  buffer,
+		encoder: encoder,
+	}
+}
+
+// encoder end each row with a new line
+func (c *jsonlWriter) Write(row Row) error {
+	return c.encoder.Encode(row)
+}
+
+func (c *jsonlWriter) Flush() error {
+	return c.buffer.Flush()
+}
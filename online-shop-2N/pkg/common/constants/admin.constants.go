@@ -0,0 +1,49 @@
+package common
+
+// permission of an admin to do an action, roles are a group of permissions
+type AdminPermission string
+
+const (
+	// all permissions including the permissions added later
+	// managing admins and roles is only allowed with this permission
+	PermissionAll AdminPermission = "*"
+
+	PermissionReadUsers        AdminPermission = "users:read"
+	PermissionBlockUsers       AdminPermission = "users:block"
+	PermissionReadCatalog      AdminPermission = "catalog:read"
+	PermissionManageCatalog    AdminPermission = "catalog:manage"
+	PermissionReadPromotions   AdminPermission = "promotions:read"
+	PermissionManagePromotions AdminPermission = "promotions:manage"
+	PermissionReadOrders       AdminPermission = "orders:read"
+	PermissionManageOrders     AdminPermission = "orders:manage"
+	PermissionReadPayments     AdminPermission = "payments:read"
+	PermissionManagePayments   AdminPermission = "payments:manage"
+	PermissionReadWallets      AdminPermission = "wallets:read"
+	PermissionReadSales        AdminPermission = "sales:read"
+)
+
+// all the permissions which can be given to a role
+var AdminPermissions = []AdminPermission{
+	PermissionAll,
+	PermissionReadUsers,
+	PermissionBlockUsers,
+	PermissionReadCatalog,
+	PermissionManageCatalog,
+	PermissionReadPromotions,
+	PermissionManagePromotions,
+	PermissionReadOrders,
+	PermissionManageOrders,
+	PermissionReadPayments,
+	PermissionManagePayments,
+	PermissionReadWallets,
+	PermissionReadSales,
+}
+
+// predefined roles, super admin can't be changed or removed from the last super admin
+const (
+	RoleSuperAdmin     = "super admin"
+	RoleCatalogManager = "catalog manager"
+	RoleOrderManager   = "order manager"
+	RoleSupport        = "support"
+	RoleFinance        = "finance"
+)
@@ -0,0 +1,11 @@
+package common
+
+// status of a bulk import of catalog
+type CatalogImportStatusType string
+
+const (
+	CatalogImportPending   CatalogImportStatusType = "pending"
+	CatalogImportRunning   CatalogImportStatusType = "running"
+	CatalogImportCompleted CatalogImportStatusType = "completed"
+	CatalogImportFailed    CatalogImportStatusType = "failed"
+)
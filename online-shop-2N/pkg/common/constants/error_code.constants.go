@@ -0,0 +1,25 @@
+package common
+
+// codes of errors on response, clients can handle these errors without parsing the message
+const (
+	ErrorCodeRateLimited               = "RATE_LIMITED"
+	ErrorCodeAccountLocked             = "ACCOUNT_LOCKED"
+	ErrorCodeOtpResendCooldown         = "OTP_RESEND_COOLDOWN"
+	ErrorCodeOtpAttemptsExceeded       = "OTP_ATTEMPTS_EXCEEDED"
+	ErrorCodeChallengeAttemptsExceeded = "CHALLENGE_ATTEMPTS_EXCEEDED"
+	ErrorCodeUserBlocked               = "USER_BLOCKED"
+	ErrorCodeInternal                  = "INTERNAL_ERROR"
+)
+
+// codes of errors which the oauth callback give to frontend on redirect
+const (
+	ErrorCodeOAuthInvalidRequest   = "OAUTH_INVALID_REQUEST"
+	ErrorCodeOAuthInvalidState     = "OAUTH_INVALID_STATE"
+	ErrorCodeOAuthCancelled        = "OAUTH_CANCELLED"
+	ErrorCodeOAuthFailed           = "OAUTH_FAILED"
+	ErrorCodeOAuthProviderNotExist = "OAUTH_PROVIDER_NOT_EXIST"
+	ErrorCodeOAuthAccountNotLinked = "OAUTH_ACCOUNT_NOT_LINKED"
+	ErrorCodeOAuthAlreadyLinked    = "OAUTH_ALREADY_LINKED"
+	ErrorCodeOAuthEmailNotShared   = "OAUTH_EMAIL_NOT_SHARED"
+	ErrorCodeOAuthEmailNotVerified = "OAUTH_EMAIL_NOT_VERIFIED"
+)
@@ -6,16 +6,57 @@ type OrderStatusType string
 // payment types
 type PaymentType string
 
+// who made a change on order status
+type OrderActorType string
+
+// status of a payment made for an order
+type PaymentStatusType string
+
+// where the amount of an order return is refunded to
+type RefundPolicyType string
+
+// status of a refund made for an order
+type RefundStatusType string
+
 const (
 	// order status
 	StatusPaymentPending  OrderStatusType = "payment pending"
+	StatusPaymentFailed   OrderStatusType = "payment failed"
+	StatusPaymentExpired  OrderStatusType = "payment expired"
 	StatusOrderPlaced     OrderStatusType = "order placed"
+	StatusOrderShipped    OrderStatusType = "order shipped"
+	StatusOutForDelivery  OrderStatusType = "out for delivery"
 	StatusOrderCancelled  OrderStatusType = "order cancelled"
 	StatusOrderDelivered  OrderStatusType = "order delivered"
 	StatusReturnRequested OrderStatusType = "return requested"
 	StatusReturnApproved  OrderStatusType = "return approved"
 	StatusReturnCancelled OrderStatusType = "return cancelled"
 	StatusOrderReturned   OrderStatusType = "order returned"
+	// some of the items returned and the rest can return later
+	StatusPartiallyReturned OrderStatusType = "partially returned"
+
+	// order status actors
+	ActorAdmin  OrderActorType = "admin"
+	ActorUser   OrderActorType = "user"
+	ActorSystem OrderActorType = "system"
+
+	// order payment status
+	PaymentStatusCreated   PaymentStatusType = "created"
+	PaymentStatusSucceeded PaymentStatusType = "succeeded"
+	PaymentStatusFailed    PaymentStatusType = "failed"
+	PaymentStatusRefunded  PaymentStatusType = "refunded"
+	// gateway payment not paid yet which can't be used after the amount due of order changed
+	PaymentStatusCancelled PaymentStatusType = "cancelled"
+
+	// order return refund policy
+	RefundToWallet         RefundPolicyType = "wallet"
+	RefundToOriginalMethod RefundPolicyType = "original_method"
+	RefundSplit            RefundPolicyType = "split"
+
+	// refund status
+	RefundStatusPending   RefundStatusType = "pending"
+	RefundStatusProcessed RefundStatusType = "processed"
+	RefundStatusFailed    RefundStatusType = "failed"
 
 	// payment type
 	RazopayPayment        PaymentType = "razor pay"
@@ -24,4 +65,6 @@ const (
 	CodMaximumAmount                  = 20000
 	StripePayment         PaymentType = "stripe"
 	StripeMaximumAmount               = 50000
+	WalletPayment         PaymentType = "wallet"
+	WalletMaximumAmount               = 50000
 )
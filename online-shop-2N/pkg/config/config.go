@@ -1,12 +1,23 @@
 package config
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
 
+// environments of application, fake services are allowed only on development and test
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+)
+
 // to store env variables
 type Config struct {
+	AppEnv string `mapstructure:"APP_ENV" validate:"omitempty,oneof=development test production"`
+
 	AdminEmail    string `mapstructure:"ADMIN_EMAIL"`
 	AdminUserName string `mapstructure:"ADMIN_USER_NAME"`
 	AdminPassword string `mapstructure:"ADMIN_PASSWORD"`
@@ -16,15 +27,59 @@ type Config struct {
 	DBPassword    string `mapstructure:"DB_PASSWORD"`
 	DBPort        string `mapstructure:"DB_PORT"`
 
+	// comma separated ips or cidrs of the proxies which the client ip is taken from forwarded headers,
+	// no proxy is trusted when it's not configured
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
+
 	AdminAuthKey string `mapstructure:"ADMIN_AUTH_KEY"`
 	UserAuthKey  string `mapstructure:"USER_AUTH_KEY"`
+	// comma separated keys which only verify tokens, the previous keys on rotation
+	AdminAuthVerifyKeys string `mapstructure:"ADMIN_AUTH_VERIFY_KEYS"`
+	UserAuthVerifyKeys  string `mapstructure:"USER_AUTH_VERIFY_KEYS"`
+
+	TokenIssuer     string `mapstructure:"TOKEN_ISSUER"`
+	TokenSigningAlg string `mapstructure:"TOKEN_SIGNING_ALG" validate:"omitempty,oneof=HS256 RS256 EdDSA"`
+	// pem files of private key for RS256 or EdDSA, verify key files are comma separated
+	TokenSigningKeyFile string `mapstructure:"TOKEN_SIGNING_KEY_FILE"`
+	TokenVerifyKeyFiles string `mapstructure:"TOKEN_VERIFY_KEY_FILES"`
+
+	TokenDenylistStore string `mapstructure:"TOKEN_DENYLIST_STORE"`
+
+	RateLimitStore string `mapstructure:"RATE_LIMIT_STORE" validate:"omitempty,oneof=memory"`
+	// account is locked for the duration after the max failed password attempts
+	LoginMaxFailedAttempts uint          `mapstructure:"LOGIN_MAX_FAILED_ATTEMPTS"`
+	LoginLockDuration      time.Duration `mapstructure:"LOGIN_LOCK_DURATION"`
+
+	// totp secrets of admins are encrypted with the encryption key which is required
+	TotpIssuer        string `mapstructure:"TOTP_ISSUER"`
+	TotpEncryptionKey string `mapstructure:"TOTP_ENCRYPTION_KEY" validate:"required"`
+
+	// url of frontend which is used for the links on mails
+	FrontendURL string `mapstructure:"FRONTEND_URL"`
+
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     string `mapstructure:"SMTP_PORT"`
+	SMTPUserName string `mapstructure:"SMTP_USER_NAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailerMode   string `mapstructure:"MAILER_MODE" validate:"omitempty,oneof=smtp file memory"`
+	MailerDir    string `mapstructure:"MAILER_DIR"`
+
+	// twilio verify or the local otp which send the codes with sms sender, local otp need its own hash key and sender
+	OtpProvider   string `mapstructure:"OTP_PROVIDER" validate:"required,oneof=twilio local"`
+	OtpHashKey    string `mapstructure:"OTP_HASH_KEY"`
+	SMSSender     string `mapstructure:"SMS_SENDER" validate:"omitempty,oneof=console file"`
+	SMSSenderFile string `mapstructure:"SMS_SENDER_FILE"`
+	// country code of phone numbers which are saved without country code
+	DefaultCountryCode string `mapstructure:"DEFAULT_COUNTRY_CODE"`
 
 	TwilioAuthToken  string `mapstructure:"AUTH_TOKEN"`
 	TwilioAccountSID string `mapstructure:"ACCOUNT_SID"`
 	TwilioServiceID  string `mapstructure:"SERVICE_SID"`
 
-	RazorPayKey    string `mapstructure:"RAZOR_PAY_KEY"`
-	RazorPaySecret string `mapstructure:"RAZOR_PAY_SECRET"`
+	RazorPayKey           string `mapstructure:"RAZOR_PAY_KEY"`
+	RazorPaySecret        string `mapstructure:"RAZOR_PAY_SECRET"`
+	RazorPayWebhookSecret string `mapstructure:"RAZOR_PAY_WEBHOOK"`
 
 	StripSecretKey      string `mapstructure:"STRIPE_SECRET"`
 	StripPublishKey     string `mapstructure:"STRIPE_PUBLISH_KEY"`
@@ -34,22 +89,81 @@ type Config struct {
 	GoauthClientSecret string `mapstructure:"GOAUTH_CLIENT_SECRET"`
 	GoauthCallbackUrl  string `mapstructure:"GOAUTH_CALL_BACK_URL"`
 
+	// oauth providers are enabled when their client id is configured,
+	// callback url of a provider is the redirect base url with /<provider>/callback
+	OAuthRedirectBaseURL string `mapstructure:"OAUTH_REDIRECT_BASE_URL"`
+	GithubClientID       string `mapstructure:"GITHUB_CLIENT_ID"`
+	GithubClientSecret   string `mapstructure:"GITHUB_CLIENT_SECRET"`
+	FacebookClientID     string `mapstructure:"FACEBOOK_CLIENT_ID"`
+	FacebookClientSecret string `mapstructure:"FACEBOOK_CLIENT_SECRET"`
+	// client secret of apple is signed with the private key (.p8 file) of the key id
+	AppleClientID       string `mapstructure:"APPLE_CLIENT_ID"`
+	AppleTeamID         string `mapstructure:"APPLE_TEAM_ID"`
+	AppleKeyID          string `mapstructure:"APPLE_KEY_ID"`
+	ApplePrivateKeyFile string `mapstructure:"APPLE_PRIVATE_KEY_FILE"`
+	// any openid connect provider which support discovery on its issuer
+	OIDCProviderName string `mapstructure:"OIDC_PROVIDER_NAME"`
+	OIDCIssuer       string `mapstructure:"OIDC_ISSUER"`
+	OIDCClientID     string `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `mapstructure:"OIDC_CLIENT_SECRET"`
+	// signs the flow cookie of oauth, user auth key is used when it's not configured
+	OAuthStateKey string `mapstructure:"OAUTH_STATE_KEY"`
+	// frontend url which the oauth callback redirects to, default is /oauth/callback of the frontend url
+	OAuthFrontendRedirectURL string `mapstructure:"OAUTH_FRONTEND_REDIRECT_URL"`
+	// code redirects with a one time code to exchange for tokens, token redirects with the tokens on url fragment
+	OAuthRedirectMode string `mapstructure:"OAUTH_REDIRECT_MODE" validate:"omitempty,oneof=code token"`
+
 	AwsAccessKeyID string `mapstructure:"AWS_ACCESS_KEY_ID"`
 	AwsSecretKey   string `mapstructure:"AWS_SECRET_ACCESS_KEY"`
 	AwsRegion      string `mapstructure:"AWS_REGION"`
 	AwsBucketName  string `mapstructure:"AWS_BUCKET_NAME"`
+
+	// fake mode is allowed only when the app env is development or test
+	PaymentGatewayMode    string        `mapstructure:"PAYMENT_GATEWAY_MODE" validate:"omitempty,oneof=fake"`
+	PaymentPendingTTL     time.Duration `mapstructure:"PAYMENT_PENDING_TTL"`
+	PaymentExpiryInterval time.Duration `mapstructure:"PAYMENT_EXPIRY_INTERVAL"`
+
+	// gateway refunds pending for this ttl are reconciled with the gateway
+	RefundPendingTTL        time.Duration `mapstructure:"REFUND_PENDING_TTL"`
+	RefundReconcileInterval time.Duration `mapstructure:"REFUND_RECONCILE_INTERVAL"`
+
+	// how often the pending catalog imports are checked, an import is stopped after the timeout
+	CatalogImportInterval time.Duration `mapstructure:"CATALOG_IMPORT_INTERVAL"`
+	CatalogImportTimeout  time.Duration `mapstructure:"CATALOG_IMPORT_TIMEOUT"`
 }
 
 // name of envs and used to read from system envs
 var envsNames = []string{
+	"APP_ENV", // development, test or production
 	"ADMIN_EMAIL", "ADMIN_USER_NAME", "ADMIN_PASSWORD",
 	"DB_HOST", "DB_NAME", "DB_USER", "DB_PASSWORD", "DB_PORT", // database
+	"TRUSTED_PROXIES",                 // proxies which forward the client ip
 	"ADMIN_AUTH_KEY", "USER_AUTH_KEY", // token auth
+	"ADMIN_AUTH_VERIFY_KEYS", "USER_AUTH_VERIFY_KEYS", // previous keys of token auth
+	"TOKEN_ISSUER", "TOKEN_SIGNING_ALG", "TOKEN_SIGNING_KEY_FILE", "TOKEN_VERIFY_KEY_FILES", // RS256 or EdDSA token signing
+	"TOKEN_DENYLIST_STORE",                             // set memory to keep revoked tokens only on the instance
+	"RATE_LIMIT_STORE",                                 // rate limit of auth apis
+	"LOGIN_MAX_FAILED_ATTEMPTS", "LOGIN_LOCK_DURATION", // account lock on failed passwords
+	"TOTP_ISSUER", "TOTP_ENCRYPTION_KEY", // admin two factor authentication
+	"FRONTEND_URL",
+	"SMTP_HOST", "SMTP_PORT", "SMTP_USER_NAME", "SMTP_PASSWORD", "MAIL_FROM", // smtp mail
+	"MAILER_MODE", "MAILER_DIR", // set file or memory to send mails without smtp
+	"OTP_PROVIDER", "OTP_HASH_KEY", "DEFAULT_COUNTRY_CODE", // otp
+	"SMS_SENDER", "SMS_SENDER_FILE", // console or file sender of local otp, file keeps the sms on a file
 	"AUTH_TOKEN", "ACCOUNT_SID", "SERVICE_SID", // twilio
-	"RAZOR_PAY_KEY", "RAZOR_PAY_SECRET", // razor pay
+	"RAZOR_PAY_KEY", "RAZOR_PAY_SECRET", "RAZOR_PAY_WEBHOOK", // razor pay
 	"STRIPE_SECRET", "STRIPE_PUBLISH_KEY", "STRIPE_WEBHOOK", // stripe
 	"GOAUTH_CLIENT_ID", "GOAUTH_CLIENT_SECRET", "GOAUTH_CALL_BACK_URL", //goath
+	"OAUTH_REDIRECT_BASE_URL",                                                                  // callback base url of oauth providers
+	"GITHUB_CLIENT_ID", "GITHUB_CLIENT_SECRET", "FACEBOOK_CLIENT_ID", "FACEBOOK_CLIENT_SECRET", // github and facebook login
+	"APPLE_CLIENT_ID", "APPLE_TEAM_ID", "APPLE_KEY_ID", "APPLE_PRIVATE_KEY_FILE", // apple login
+	"OIDC_PROVIDER_NAME", "OIDC_ISSUER", "OIDC_CLIENT_ID", "OIDC_CLIENT_SECRET", // generic openid connect login
+	"OAUTH_STATE_KEY", "OAUTH_FRONTEND_REDIRECT_URL", "OAUTH_REDIRECT_MODE", // oauth redirect to frontend
 	"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_REGION", "AWS_BUCKET_NAME", // aws s3
+	"PAYMENT_GATEWAY_MODE",                           // set fake to run payments without gateways on development or test
+	"PAYMENT_PENDING_TTL", "PAYMENT_EXPIRY_INTERVAL", // pending order payment expiry
+	"REFUND_PENDING_TTL", "REFUND_RECONCILE_INTERVAL", // pending gateway refund reconcile
+	"CATALOG_IMPORT_INTERVAL", "CATALOG_IMPORT_TIMEOUT", // background catalog import
 }
 
 func LoadConfig() (config Config, err error) {
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/database/migrate"
+	"online-shop-2N/pkg/database/migrations"
+	"online-shop-2N/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// version of migrations which need go code, it should not collide with the sql migration files
+const adminSeedMigrationVersion = 4
+
+// To find all the migrations of the database which are the embedded sql files and the go migrations
+func Migrations(cfg config.Config) ([]migrate.Migration, error) {
+
+	sqlMigrations, err := migrate.LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(sqlMigrations, adminSeedMigration(cfg)), nil
+}
+
+func NewMigrator(db *gorm.DB, cfg config.Config) (migrate.Migrator, error) {
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql db from gorm: %w", err)
+	}
+
+	allMigrations, err := Migrations(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewMigrator(sqlDB, allMigrations)
+}
+
+// To save the admin of config on database if its not exist
+// password need to hash on the application so it's a go migration
+func adminSeedMigration(cfg config.Config) migrate.Migration {
+
+	return migrate.Migration{
+		Version: adminSeedMigrationVersion,
+		Name:    "seed_admin",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+
+			var exist bool
+			err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM admins WHERE email = $1)`,
+				cfg.AdminEmail).Scan(&exist)
+			if err != nil {
+				return fmt.Errorf("failed to check admin already exist err: %w", err)
+			}
+			if exist {
+				return nil
+			}
+
+			hashPass, err := utils.GetHashedPassword(cfg.AdminPassword)
+			if err != nil {
+				return fmt.Errorf("failed to hash password err: %w", err)
+			}
+
+			_, err = tx.ExecContext(ctx, `INSERT INTO admins (email, user_name, password, created_at) VALUES ($1, $2, $3, $4)`,
+				cfg.AdminEmail, cfg.AdminUserName, hashPass, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to save admin details %w", err)
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+
+			_, err := tx.ExecContext(ctx, `DELETE FROM admins WHERE email = $1`, cfg.AdminEmail)
+			return err
+		},
+	}
+}
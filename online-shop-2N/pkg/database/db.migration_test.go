@@ -0,0 +1,31 @@
+package database
+
+import (
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/database/migrate"
+	"testing"
+)
+
+// the embedded migrations should load and not collide with the go migrations
+func TestMigrations(t *testing.T) {
+
+	allMigrations, err := Migrations(config.Config{})
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	if _, err := migrate.NewMigrator(nil, allMigrations); err != nil {
+		t.Fatalf("failed to create migrator: %v", err)
+	}
+
+	for _, migration := range allMigrations {
+		t.Run(migration.Name, func(t *testing.T) {
+			if migration.UpSQL == "" && migration.Up == nil {
+				t.Fatalf("migration %d has no up", migration.Version)
+			}
+			if migration.DownSQL == "" && migration.Down == nil {
+				t.Fatalf("migration %d has no down", migration.Version)
+			}
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"online-shop-2N/pkg/config"
-	"online-shop-2N/pkg/models"
 
 	log "github.com/sirupsen/logrus"
 
@@ -11,7 +12,26 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrSchemaNotUpToDate = errors.New("database schema is not up to date, run 'api migrate up' to apply the pending migrations")
+
+// To open the database and check its schema is up to date with the migrations of application,
+// migrations are not applied on start, they are applied only with the migrate command
 func ConnectToDB(config config.Config) (*gorm.DB, error) {
+
+	db, err := OpenDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSchemaVersion(context.Background(), db, config); err != nil {
+		log.Error("Failed to check database schema. Due to error: ", err)
+		return nil, err
+	}
+	return db, nil
+}
+
+// To open the database without checking its schema
+func OpenDB(config config.Config) (*gorm.DB, error) {
 	url := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s",
 		config.DBHost,
@@ -27,69 +47,26 @@ func ConnectToDB(config config.Config) (*gorm.DB, error) {
 		log.Error("Failed to open database connection. Due to error: ", err)
 		return nil, err
 	}
-	// Migrate database models to database
-	err = db.AutoMigrate(
-		//auth
-		models.RefreshSession{},
-		models.OtpSession{},
-		//user
-		models.User{},
-		models.Country{},
-		models.Address{},
-		models.UserAddress{},
-
-		//admin
-		models.Admin{},
-
-		//product
-		models.Category{},
-		models.Product{},
-		models.ProductItem{},
-		models.ProductImage{},
-
-		// wish list
-		models.WishList{},
-
-		// cart
-		models.Cart{},
-		models.CartItem{},
-
-		// order
-		models.OrderStatus{},
-		models.ShopOrder{},
-		models.OrderLine{},
-		models.OrderReturn{},
-
-		//offer
-		models.Offer{},
-		models.OfferCategory{},
-		models.OfferProduct{},
+	return db, nil
+}
 
-		// coupon
-		models.Coupon{},
-		models.CouponUses{},
+func checkSchemaVersion(ctx context.Context, db *gorm.DB, config config.Config) error {
 
-		//wallet
-		models.Wallet{},
-		models.Transaction{},
-	)
+	migrator, err := NewMigrator(db, config)
 	if err != nil {
-		log.Error("Failed to migrate database tables. Due to error: ", err)
+		return err
 	}
-	// setup the triggers
-	if err := SetUpDBTriggers(db); err != nil {
-		log.Error("Failed to setup database triggers. Due to error: ", err)
-		return nil, err
+
+	pendingMigrations, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
 	}
-	if err := saveAdmin(db, config.AdminEmail, config.AdminUserName, config.AdminPassword); err != nil {
-		return nil, err
+	if len(pendingMigrations) == 0 {
+		return nil
 	}
 
-	if err := saveOrderStatuses(db); err != nil {
-		return nil, err
+	for _, migration := range pendingMigrations {
+		log.Warnf("Database migration %d_%s is not applied", migration.Version, migration.Name)
 	}
-	if err := savePaymentMethods(db); err != nil {
-		return nil, err
-	}
-	return db, nil
+	return fmt.Errorf("%w: %d migrations pending", ErrSchemaNotUpToDate, len(pendingMigrations))
 }
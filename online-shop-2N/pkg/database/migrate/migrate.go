@@ -0,0 +1,301 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// key of postgres advisory lock which is hold while running migrations
+// so only one instance of the application migrate the database at a time
+const advisoryLockKey int64 = 7263548190234
+
+var (
+	ErrIrreversibleMigration = errors.New("migration has no down to revert")
+	ErrDuplicateVersion      = errors.New("more than one migration have same version")
+)
+
+// Migration is a versioned change of database which is applied with Up and reverted with Down
+// sql migrations have the UpSQL and DownSQL, Up and Down funcs are used for migrations which need go code
+type Migration struct {
+	Version uint
+	Name    string
+	UpSQL   string
+	DownSQL string
+	Up      func(ctx context.Context, tx *sql.Tx) error
+	Down    func(ctx context.Context, tx *sql.Tx) error
+}
+
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator interface {
+	// To apply all the pending migrations in the order of version
+	Up(ctx context.Context) ([]Migration, error)
+	// To revert the given number of last applied migrations
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	// To find the migrations which are not applied yet, it's not change the database
+	Pending(ctx context.Context) ([]Migration, error)
+}
+
+type migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, migrations []Migration) (Migrator, error) {
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("%w: version %d", ErrDuplicateVersion, sorted[i].Version)
+		}
+	}
+
+	return &migrator{
+		db:         db,
+		migrations: sorted,
+	}, nil
+}
+
+func (c *migrator) Up(ctx context.Context) (appliedMigrations []Migration, err error) {
+
+	err = c.withLock(ctx, func(conn *sql.Conn) error {
+
+		applied, err := findAppliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range c.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err = runInTx(ctx, conn, func(tx *sql.Tx) error {
+				if err := migration.runUp(ctx, tx); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			appliedMigrations = append(appliedMigrations, migration)
+		}
+		return nil
+	})
+
+	return appliedMigrations, err
+}
+
+func (c *migrator) Down(ctx context.Context, steps int) (revertedMigrations []Migration, err error) {
+
+	err = c.withLock(ctx, func(conn *sql.Conn) error {
+
+		applied, err := findAppliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(c.migrations) - 1; i >= 0 && len(revertedMigrations) < steps; i-- {
+			migration := c.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if !migration.reversible() {
+				return fmt.Errorf("%w: %d_%s", ErrIrreversibleMigration, migration.Version, migration.Name)
+			}
+
+			err = runInTx(ctx, conn, func(tx *sql.Tx) error {
+				if err := migration.runDown(ctx, tx); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			revertedMigrations = append(revertedMigrations, migration)
+		}
+		return nil
+	})
+
+	return revertedMigrations, err
+}
+
+func (c *migrator) Status(ctx context.Context) (statuses []MigrationStatus, err error) {
+
+	err = c.withLock(ctx, func(conn *sql.Conn) error {
+
+		applied, err := findAppliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range c.migrations {
+			appliedAt, ok := applied[migration.Version]
+			statuses = append(statuses, MigrationStatus{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Applied:   ok,
+				AppliedAt: appliedAt,
+			})
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+func (c *migrator) Pending(ctx context.Context) (pendingMigrations []Migration, err error) {
+
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	// all the migrations are pending when the migrations table is not created yet
+	var tableExist bool
+	err = conn.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&tableExist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations table exist: %w", err)
+	}
+	if !tableExist {
+		return append(pendingMigrations, c.migrations...), nil
+	}
+
+	applied, err := findAppliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range c.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pendingMigrations = append(pendingMigrations, migration)
+		}
+	}
+
+	return pendingMigrations, nil
+}
+
+// To run the given func on a single connection which hold the advisory lock
+// the migrations table is created if not exist before running the func
+func (c *migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	// wait until other instances release the lock
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// use background context so the lock is released even the ctx is cancelled
+		_, unlockErr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockKey)
+		if unlockErr != nil && err == nil {
+			err = fmt.Errorf("failed to release migration lock: %w", unlockErr)
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// To find the applied versions with its applied time
+func findAppliedVersions(ctx context.Context, conn *sql.Conn) (map[uint]time.Time, error) {
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[uint(version)] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// To run the func in a transaction which commit on success and rollback on error
+func runInTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m Migration) runUp(ctx context.Context, tx *sql.Tx) error {
+
+	if m.UpSQL != "" {
+		if _, err := tx.ExecContext(ctx, m.UpSQL); err != nil {
+			return err
+		}
+	}
+	if m.Up != nil {
+		return m.Up(ctx, tx)
+	}
+	return nil
+}
+
+func (m Migration) runDown(ctx context.Context, tx *sql.Tx) error {
+
+	if m.DownSQL != "" {
+		if _, err := tx.ExecContext(ctx, m.DownSQL); err != nil {
+			return err
+		}
+	}
+	if m.Down != nil {
+		return m.Down(ctx, tx)
+	}
+	return nil
+}
+
+func (m Migration) reversible() bool {
+	return m.DownSQL != "" || m.Down != nil
+}
@@ -0,0 +1,77 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestNewMigrator(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		migrations   []Migration
+		wantVersions []uint
+		wantErr      error
+	}{
+		{
+			name:         "sorted by version",
+			migrations:   []Migration{{Version: 3}, {Version: 1}, {Version: 2}},
+			wantVersions: []uint{1, 2, 3},
+		},
+		{
+			name:       "duplicate version",
+			migrations: []Migration{{Version: 1, Name: "a"}, {Version: 1, Name: "b"}},
+			wantErr:    ErrDuplicateVersion,
+		},
+		{name: "no migrations"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := NewMigrator(nil, test.migrations)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			sorted := got.(*migrator).migrations
+			if len(sorted) != len(test.wantVersions) {
+				t.Fatalf("got %d migrations, want %d", len(sorted), len(test.wantVersions))
+			}
+			for i, migration := range sorted {
+				if migration.Version != test.wantVersions[i] {
+					t.Fatalf("got version %d at %d, want %d", migration.Version, i, test.wantVersions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMigrationReversible(t *testing.T) {
+
+	down := func(ctx context.Context, tx *sql.Tx) error { return nil }
+
+	tests := []struct {
+		name      string
+		migration Migration
+		want      bool
+	}{
+		{name: "sql down", migration: Migration{UpSQL: "CREATE TABLE a ();", DownSQL: "DROP TABLE a;"}, want: true},
+		{name: "go down", migration: Migration{Down: down}, want: true},
+		{name: "no down", migration: Migration{UpSQL: "CREATE TABLE a ();"}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			if got := test.migration.reversible(); got != test.want {
+				t.Fatalf("got reversible %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// sql migration files are named as <version>_<name>.up.sql and <version>_<name>.down.sql
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrInvalidMigrationName = errors.New("migration name should only have lower case letters, digits and underscore")
+	ErrMissingUpMigration   = errors.New("migration has no up file")
+)
+
+// To load the sql migrations from the files on root of the given file system
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration files: %w", err)
+	}
+
+	migrationsByVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+
+		matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version on migration file %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := migrationsByVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: matches[2]}
+			migrationsByVersion[uint(version)] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: version %d", ErrDuplicateVersion, version)
+		}
+
+		if matches[3] == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingUpMigration, migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	return migrations, nil
+}
+
+// To create up and down sql files on the dir for a new migration
+// version of the new migration is next to the last version of the given migrations
+func Create(dir, name string, migrations []Migration) (upFile, downFile string, err error) {
+
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return "", "", ErrInvalidMigrationName
+	}
+
+	var lastVersion uint
+	for _, migration := range migrations {
+		if migration.Version > lastVersion {
+			lastVersion = migration.Version
+		}
+	}
+
+	prefix := fmt.Sprintf("%06d_%s", lastVersion+1, name)
+	upFile = filepath.Join(dir, prefix+".up.sql")
+	downFile = filepath.Join(dir, prefix+".down.sql")
+
+	files := map[string]string{
+		upFile:   fmt.Sprintf("-- %s\n", name),
+		downFile: fmt.Sprintf("-- revert %s\n", name),
+	}
+	for file, content := range files {
+		// O_EXCL so an existing migration is never overwritten
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration file: %w", err)
+		}
+		_, err = f.WriteString(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write migration file: %w", err)
+		}
+	}
+
+	return upFile, downFile, nil
+}
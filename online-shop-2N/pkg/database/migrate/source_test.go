@@ -0,0 +1,116 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []uint
+		wantErr      error
+	}{
+		{
+			name: "up and down files",
+			files: fstest.MapFS{
+				"000001_init.up.sql":         {Data: []byte("CREATE TABLE a ();")},
+				"000001_init.down.sql":       {Data: []byte("DROP TABLE a;")},
+				"000002_add_b.up.sql":        {Data: []byte("CREATE TABLE b ();")},
+				"README.md":                  {Data: []byte("not a migration")},
+				"000003_Invalid_Name.up.sql": {Data: []byte("not a migration")},
+			},
+			wantVersions: []uint{1, 2},
+		},
+		{
+			name: "down file without up",
+			files: fstest.MapFS{
+				"000001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+			},
+			wantErr: ErrMissingUpMigration,
+		},
+		{
+			name: "same version with different names",
+			files: fstest.MapFS{
+				"000001_init.up.sql":  {Data: []byte("CREATE TABLE a ();")},
+				"000001_other.up.sql": {Data: []byte("CREATE TABLE b ();")},
+			},
+			wantErr: ErrDuplicateVersion,
+		},
+		{name: "no files", files: fstest.MapFS{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			migrations, err := LoadMigrations(test.files)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+
+			var versions []uint
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+			}
+			sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+			if len(versions) != len(test.wantVersions) {
+				t.Fatalf("got versions %v, want %v", versions, test.wantVersions)
+			}
+			for i := range versions {
+				if versions[i] != test.wantVersions[i] {
+					t.Fatalf("got versions %v, want %v", versions, test.wantVersions)
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+
+	migrations := []Migration{{Version: 3}, {Version: 12}, {Version: 7}}
+
+	tests := []struct {
+		name          string
+		migrationName string
+		wantUpFile    string
+		wantErr       error
+	}{
+		{name: "next version", migrationName: "add_index", wantUpFile: "000013_add_index.up.sql"},
+		{name: "invalid name", migrationName: "Add Index", wantErr: ErrInvalidMigrationName},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			dir := t.TempDir()
+
+			upFile, downFile, err := Create(dir, test.migrationName, migrations)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if upFile != filepath.Join(dir, test.wantUpFile) {
+				t.Fatalf("got up file %s, want %s", upFile, test.wantUpFile)
+			}
+			for _, file := range []string{upFile, downFile} {
+				if _, err := os.Stat(file); err != nil {
+					t.Fatalf("migration file %s not created: %v", file, err)
+				}
+			}
+
+			// an existing migration is never overwritten
+			if _, _, err := Create(dir, test.migrationName, migrations); err == nil {
+				t.Fatalf("got no error on creating the existing migration again")
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS wallets;
+DROP TABLE IF EXISTS coupon_uses;
+DROP TABLE IF EXISTS coupons;
+DROP TABLE IF EXISTS offer_products;
+DROP TABLE IF EXISTS offer_categories;
+DROP TABLE IF EXISTS offers;
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS order_payments;
+DROP TABLE IF EXISTS order_status_history;
+DROP TABLE IF EXISTS order_cancel_lines;
+DROP TABLE IF EXISTS order_cancels;
+DROP TABLE IF EXISTS order_return_lines;
+DROP TABLE IF EXISTS order_returns;
+DROP TABLE IF EXISTS order_lines;
+DROP TABLE IF EXISTS shop_orders;
+DROP TABLE IF EXISTS payment_methods;
+DROP TABLE IF EXISTS order_statuses;
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS carts;
+DROP TABLE IF EXISTS wish_lists;
+DROP TABLE IF EXISTS product_configurations;
+DROP TABLE IF EXISTS variation_options;
+DROP TABLE IF EXISTS variations;
+DROP TABLE IF EXISTS product_images;
+DROP TABLE IF EXISTS product_items;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS brands;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS admins;
+DROP TABLE IF EXISTS user_addresses;
+DROP TABLE IF EXISTS addresses;
+DROP TABLE IF EXISTS countries;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS otp_sessions;
+DROP TABLE IF EXISTS refresh_sessions;
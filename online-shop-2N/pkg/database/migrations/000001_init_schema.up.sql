@@ -0,0 +1,376 @@
+-- baseline schema of the application
+-- tables are created only if not exist so a database created before migrations can be adopted,
+-- columns added after that are added to the existing tables too
+
+-- auth
+CREATE TABLE IF NOT EXISTS refresh_sessions (
+    token_id text PRIMARY KEY,
+    user_id bigint NOT NULL,
+    refresh_token text NOT NULL,
+    expire_at timestamptz NOT NULL,
+    is_blocked boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS otp_sessions (
+    id bigserial PRIMARY KEY,
+    otp_id text NOT NULL UNIQUE,
+    user_id bigint NOT NULL,
+    phone text NOT NULL,
+    expire_at timestamptz NOT NULL
+);
+
+-- user
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    age bigint,
+    google_image text,
+    first_name text,
+    last_name text,
+    user_name text NOT NULL UNIQUE,
+    email text NOT NULL UNIQUE,
+    phone text UNIQUE,
+    password text,
+    verified boolean DEFAULT true,
+    block_status boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS countries (
+    id bigserial PRIMARY KEY,
+    country_name text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS addresses (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    phone_number text NOT NULL,
+    detail_address text NOT NULL,
+    commune text NOT NULL,
+    district text NOT NULL,
+    province text NOT NULL,
+    pincode bigint,
+    country_id bigint NOT NULL REFERENCES countries (id),
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id),
+    address_id bigint NOT NULL REFERENCES addresses (id),
+    is_default boolean
+);
+
+-- admin
+CREATE TABLE IF NOT EXISTS admins (
+    id bigserial PRIMARY KEY,
+    user_name text NOT NULL,
+    email text NOT NULL,
+    password text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+
+-- product
+CREATE TABLE IF NOT EXISTS categories (
+    id bigserial PRIMARY KEY,
+    category_id bigint REFERENCES categories (id),
+    name text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS brands (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    description text NOT NULL,
+    category_id bigint REFERENCES categories (id),
+    brand_id bigint NOT NULL REFERENCES brands (id),
+    price bigint NOT NULL,
+    discount_price bigint,
+    image text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS product_items (
+    id bigserial PRIMARY KEY,
+    product_id bigint NOT NULL REFERENCES products (id),
+    qty_in_stock bigint NOT NULL,
+    price bigint NOT NULL,
+    sku text NOT NULL UNIQUE,
+    discount_price bigint,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS product_images (
+    id bigserial PRIMARY KEY,
+    product_item_id bigint NOT NULL REFERENCES product_items (id),
+    image text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS variations (
+    id bigserial PRIMARY KEY,
+    category_id bigint NOT NULL REFERENCES categories (id),
+    name text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS variation_options (
+    id bigserial PRIMARY KEY,
+    variation_id bigint NOT NULL REFERENCES variations (id),
+    value text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS product_configurations (
+    product_item_id bigint NOT NULL REFERENCES product_items (id),
+    variation_option_id bigint NOT NULL REFERENCES variation_options (id),
+    PRIMARY KEY (product_item_id, variation_option_id)
+);
+
+-- wish list
+CREATE TABLE IF NOT EXISTS wish_lists (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id),
+    product_item_id bigint NOT NULL REFERENCES product_items (id)
+);
+
+-- cart
+CREATE TABLE IF NOT EXISTS carts (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    total_price bigint NOT NULL,
+    applied_coupon_id bigint,
+    discount_amount bigint
+);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id bigserial PRIMARY KEY,
+    cart_id bigint REFERENCES carts (id),
+    product_item_id bigint NOT NULL REFERENCES product_items (id),
+    qty bigint NOT NULL
+);
+
+-- order
+CREATE TABLE IF NOT EXISTS order_statuses (
+    id bigserial PRIMARY KEY,
+    status text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    block_status boolean NOT NULL DEFAULT false,
+    maximum_amount bigint NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS shop_orders (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id),
+    order_date timestamptz NOT NULL,
+    address_id bigint NOT NULL REFERENCES addresses (id),
+    order_total_price bigint NOT NULL,
+    discount bigint NOT NULL,
+    order_status_id bigint NOT NULL REFERENCES order_statuses (id),
+    payment_method_id bigint REFERENCES payment_methods (id)
+);
+
+CREATE TABLE IF NOT EXISTS order_lines (
+    id bigserial PRIMARY KEY,
+    product_item_id bigint NOT NULL,
+    shop_order_id bigint NOT NULL REFERENCES shop_orders (id),
+    qty bigint NOT NULL,
+    price bigint NOT NULL,
+    returned_qty bigint NOT NULL DEFAULT 0,
+    cancelled_qty bigint NOT NULL DEFAULT 0,
+    refunded_amount bigint NOT NULL DEFAULT 0
+);
+ALTER TABLE order_lines
+    ADD COLUMN IF NOT EXISTS returned_qty bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cancelled_qty bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS refunded_amount bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS order_returns (
+    id bigserial PRIMARY KEY,
+    shop_order_id bigint NOT NULL REFERENCES shop_orders (id),
+    request_date timestamptz NOT NULL,
+    return_reason text NOT NULL,
+    refund_amount bigint NOT NULL,
+    refund_policy text NOT NULL DEFAULT 'wallet',
+    wallet_refund_amount bigint,
+    is_approved boolean,
+    return_date timestamptz,
+    approval_date timestamptz,
+    admin_comment text
+);
+ALTER TABLE order_returns
+    ADD COLUMN IF NOT EXISTS refund_policy text NOT NULL DEFAULT 'wallet',
+    ADD COLUMN IF NOT EXISTS wallet_refund_amount bigint;
+-- an order can have more than one return for its items
+ALTER TABLE order_returns DROP CONSTRAINT IF EXISTS order_returns_shop_order_id_key;
+CREATE INDEX IF NOT EXISTS idx_order_returns_shop_order_id ON order_returns (shop_order_id);
+
+CREATE TABLE IF NOT EXISTS order_return_lines (
+    id bigserial PRIMARY KEY,
+    order_return_id bigint NOT NULL REFERENCES order_returns (id),
+    order_line_id bigint NOT NULL REFERENCES order_lines (id),
+    qty bigint NOT NULL,
+    refund_amount bigint NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_order_return_lines_order_return_id ON order_return_lines (order_return_id);
+
+CREATE TABLE IF NOT EXISTS order_cancels (
+    id bigserial PRIMARY KEY,
+    shop_order_id bigint NOT NULL REFERENCES shop_orders (id),
+    cancel_reason text,
+    actor text NOT NULL,
+    actor_id bigint,
+    refund_amount bigint NOT NULL,
+    refund_policy text NOT NULL DEFAULT 'wallet',
+    wallet_refund_amount bigint,
+    created_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_order_cancels_shop_order_id ON order_cancels (shop_order_id);
+
+CREATE TABLE IF NOT EXISTS order_cancel_lines (
+    id bigserial PRIMARY KEY,
+    order_cancel_id bigint NOT NULL REFERENCES order_cancels (id),
+    order_line_id bigint NOT NULL REFERENCES order_lines (id),
+    qty bigint NOT NULL,
+    refund_amount bigint NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_order_cancel_lines_order_cancel_id ON order_cancel_lines (order_cancel_id);
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id bigserial PRIMARY KEY,
+    shop_order_id bigint NOT NULL REFERENCES shop_orders (id),
+    from_status_id bigint,
+    to_status_id bigint NOT NULL,
+    actor text NOT NULL,
+    actor_id bigint,
+    comment text,
+    created_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_order_status_history_shop_order_id ON order_status_history (shop_order_id);
+
+CREATE TABLE IF NOT EXISTS order_payments (
+    id bigserial PRIMARY KEY,
+    shop_order_id bigint NOT NULL REFERENCES shop_orders (id),
+    payment_method_id bigint NOT NULL REFERENCES payment_methods (id),
+    amount bigint NOT NULL,
+    gateway_order_id text,
+    gateway_payment_id text,
+    status text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_order_payments_shop_order_id ON order_payments (shop_order_id);
+CREATE INDEX IF NOT EXISTS idx_order_payments_gateway_order_id ON order_payments (gateway_order_id);
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id bigserial PRIMARY KEY,
+    provider text NOT NULL,
+    event_id text NOT NULL,
+    event_type text NOT NULL,
+    received_at timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_events_provider_event_id ON webhook_events (provider, event_id);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id bigserial PRIMARY KEY,
+    shop_order_id bigint NOT NULL REFERENCES shop_orders (id),
+    order_return_id bigint NOT NULL DEFAULT 0,
+    order_cancel_id bigint NOT NULL DEFAULT 0,
+    order_payment_id bigint,
+    refund_to text NOT NULL,
+    amount bigint NOT NULL,
+    status text NOT NULL,
+    gateway_refund_id text,
+    failure_reason text,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_refunds_shop_order_id ON refunds (shop_order_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_order_return_id ON refunds (order_return_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_order_cancel_id ON refunds (order_cancel_id);
+
+-- offer
+CREATE TABLE IF NOT EXISTS offers (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    description text NOT NULL,
+    discount_rate bigint NOT NULL,
+    start_date timestamptz NOT NULL,
+    end_date timestamptz NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS offer_categories (
+    id bigserial PRIMARY KEY,
+    offer_id bigint NOT NULL REFERENCES offers (id),
+    category_id bigint NOT NULL REFERENCES categories (id)
+);
+
+CREATE TABLE IF NOT EXISTS offer_products (
+    id bigserial PRIMARY KEY,
+    offer_id bigint NOT NULL REFERENCES offers (id),
+    product_id bigint NOT NULL REFERENCES products (id)
+);
+
+-- coupon
+CREATE TABLE IF NOT EXISTS coupons (
+    coupon_id bigserial PRIMARY KEY,
+    coupon_name text NOT NULL UNIQUE,
+    coupon_code text NOT NULL UNIQUE,
+    expire_date timestamptz NOT NULL,
+    description text NOT NULL,
+    discount_rate bigint NOT NULL,
+    minimum_cart_price bigint NOT NULL,
+    image text,
+    block_status boolean NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS coupon_uses (
+    coupon_uses_id bigserial PRIMARY KEY,
+    coupon_id bigint NOT NULL REFERENCES coupons (coupon_id),
+    user_id bigint NOT NULL REFERENCES users (id),
+    used_at timestamptz NOT NULL
+);
+
+-- wallet
+CREATE TABLE IF NOT EXISTS wallets (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id),
+    total_amount bigint NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    transaction_id bigserial PRIMARY KEY,
+    wallet_id bigint NOT NULL REFERENCES wallets (id),
+    transaction_date timestamptz NOT NULL,
+    amount bigint NOT NULL,
+    transaction_type text NOT NULL,
+    source_type text NOT NULL DEFAULT '',
+    source_id bigint NOT NULL DEFAULT 0,
+    balance_after bigint NOT NULL DEFAULT 0,
+    note text
+);
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS source_type text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS source_id bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS balance_after bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS note text;
+-- transactions of an adopted database have no balance after, calculate it from the transactions of the wallet in time order
+UPDATE transactions t SET balance_after = b.balance_after
+FROM (
+    SELECT transaction_id, GREATEST(SUM(CASE WHEN transaction_type = 'CREDIT' THEN amount ELSE -amount END)
+        OVER (PARTITION BY wallet_id ORDER BY transaction_date, transaction_id), 0) AS balance_after
+    FROM transactions
+) b
+WHERE t.transaction_id = b.transaction_id;
+CREATE INDEX IF NOT EXISTS idx_transactions_wallet_id ON transactions (wallet_id);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS update_product_quantity ON order_lines;
+DROP FUNCTION IF EXISTS update_product_quantity();
+
+DROP TRIGGER IF EXISTS update_cart_total_price ON cart_items;
+DROP FUNCTION IF EXISTS update_cart_total_price();
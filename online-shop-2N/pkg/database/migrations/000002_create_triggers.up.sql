@@ -0,0 +1,58 @@
+-- function which return total price calculation on cart when product_item added or remove delete cart
+-- in here checking  first it delete any row from cart_item then take its cart_id an find all cart_items with this id and calculate total price and update it
+-- cart with this cart_id
+-- any other like update or inset then take the cart_id and calculate the total price update on cart
+CREATE OR REPLACE FUNCTION update_cart_total_price()
+RETURNS TRIGGER AS $$
+BEGIN
+IF (TG_OP = 'DELETE') THEN
+    UPDATE carts c
+        SET total_price = (
+            SELECT COALESCE ( SUM ( CASE WHEN pi.discount_price > 0 THEN pi.discount_price * ci.qty ELSE pi.price * ci.qty END), 0)::bigint
+            FROM cart_items ci INNER JOIN product_items pi ON ci.product_item_id = pi.id
+            WHERE ci.cart_id = OLD.cart_id
+        ), applied_coupon_id = 0, discount_amount = 0
+    WHERE c.id = OLD.cart_id;
+    RETURN NEW;
+ELSE
+    UPDATE carts c
+        SET total_price = (
+            SELECT SUM (CASE WHEN pi.discount_price > 0 THEN pi.discount_price * ci.qty ELSE pi.price * ci.qty END)
+            FROM cart_items ci INNER JOIN product_items pi ON ci.product_item_id = pi.id
+            WHERE ci.cart_id = NEW.cart_id
+        ), applied_coupon_id = 0, discount_amount = 0
+        WHERE c.id = NEW.cart_id;
+
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- for calling the trigger function above when an event of insert or update or delte happen on cart_items
+CREATE OR REPLACE TRIGGER update_cart_total_price
+AFTER INSERT OR UPDATE OR DELETE ON cart_items
+FOR EACH ROW EXECUTE FUNCTION update_cart_total_price();
+
+-- for updating product_item quantity when order place
+CREATE OR REPLACE FUNCTION update_product_quantity()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        UPDATE product_items pi
+        SET qty_in_stock = pi.qty_in_stock - NEW.qty
+        WHERE pi.id = NEW.product_item_id;
+
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER update_product_quantity
+AFTER INSERT ON order_lines
+FOR EACH ROW EXECUTE FUNCTION update_product_quantity();
+
+-- product_item qty of returned or cancelled items are updated by the application for only the quantity taken back
+-- so remove the old trigger which update all order items qty on order returned
+DROP TRIGGER IF EXISTS update_product_qty_on_order_return ON shop_orders;
+DROP FUNCTION IF EXISTS update_product_quantity_on_return();
+DROP FUNCTION IF EXISTS get_order_status_id(text);
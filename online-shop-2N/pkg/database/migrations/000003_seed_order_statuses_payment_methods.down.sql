@@ -0,0 +1,7 @@
+DELETE FROM payment_methods WHERE name IN ('cod', 'razor pay', 'stripe', 'wallet');
+
+DELETE FROM order_statuses WHERE status IN (
+    'payment pending', 'payment failed', 'payment expired', 'order placed', 'order shipped', 'out for delivery',
+    'order cancelled', 'order delivered', 'return requested', 'return approved', 'return cancelled',
+    'order returned', 'partially returned'
+);
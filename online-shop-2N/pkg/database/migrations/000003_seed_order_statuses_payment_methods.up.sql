@@ -0,0 +1,24 @@
+-- predefined order statuses
+INSERT INTO order_statuses (status) VALUES
+    ('payment pending'),
+    ('payment failed'),
+    ('payment expired'),
+    ('order placed'),
+    ('order shipped'),
+    ('out for delivery'),
+    ('order cancelled'),
+    ('order delivered'),
+    ('return requested'),
+    ('return approved'),
+    ('return cancelled'),
+    ('order returned'),
+    ('partially returned')
+ON CONFLICT (status) DO NOTHING;
+
+-- predefined payment methods, the maximum amount is only for initial admin can later change this
+INSERT INTO payment_methods (name, maximum_amount) VALUES
+    ('cod', 20000),
+    ('razor pay', 50000),
+    ('stripe', 50000),
+    ('wallet', 50000)
+ON CONFLICT (name) DO NOTHING;
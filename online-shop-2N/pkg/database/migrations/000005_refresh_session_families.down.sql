@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_refresh_sessions_user_type_user_id;
+DROP INDEX IF EXISTS idx_refresh_sessions_session_id;
+
+ALTER TABLE refresh_sessions
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS replaced_by_token_id,
+    DROP COLUMN IF EXISTS session_id,
+    DROP COLUMN IF EXISTS user_type;
@@ -0,0 +1,14 @@
+-- refresh sessions are kept per user type and session (token family) with the device which signed in
+ALTER TABLE refresh_sessions
+    ADD COLUMN IF NOT EXISTS user_type text NOT NULL DEFAULT 'user',
+    ADD COLUMN IF NOT EXISTS session_id text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS replaced_by_token_id text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT now();
+
+-- old sessions are not known to be of user or admin so they are revoked and need to sign in again
+UPDATE refresh_sessions SET session_id = token_id, is_blocked = true WHERE session_id = '';
+
+CREATE INDEX IF NOT EXISTS idx_refresh_sessions_session_id ON refresh_sessions (session_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_sessions_user_type_user_id ON refresh_sessions (user_type, user_id);
@@ -0,0 +1,6 @@
+-- ids of revoked access tokens and sessions which are checked on each authorized request
+CREATE TABLE IF NOT EXISTS token_denylist (
+    id text PRIMARY KEY,
+    expire_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_token_denylist_expire_at ON token_denylist (expire_at);
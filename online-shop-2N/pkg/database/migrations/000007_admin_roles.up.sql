@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    description text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id bigint NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission text NOT NULL,
+    PRIMARY KEY (role_id, permission)
+);
+
+CREATE TABLE IF NOT EXISTS admin_roles (
+    admin_id bigint NOT NULL REFERENCES admins (id) ON DELETE CASCADE,
+    role_id bigint NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (admin_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_admin_roles_role_id ON admin_roles (role_id);
+
+-- predefined roles
+INSERT INTO roles (name, description) VALUES
+    ('super admin', 'all permissions including managing admins and roles'),
+    ('catalog manager', 'manage categories, brands, products, stocks, offers and coupons'),
+    ('order manager', 'manage orders, returns and cancels'),
+    ('support', 'help users with their orders and block users'),
+    ('finance', 'manage payment methods, view payments, wallets and sales')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, p.permission FROM roles r
+INNER JOIN (VALUES
+    ('super admin', '*'),
+    ('catalog manager', 'catalog:read'),
+    ('catalog manager', 'catalog:manage'),
+    ('catalog manager', 'promotions:read'),
+    ('catalog manager', 'promotions:manage'),
+    ('order manager', 'orders:read'),
+    ('order manager', 'orders:manage'),
+    ('order manager', 'catalog:read'),
+    ('order manager', 'users:read'),
+    ('support', 'users:read'),
+    ('support', 'users:block'),
+    ('support', 'orders:read'),
+    ('support', 'catalog:read'),
+    ('finance', 'orders:read'),
+    ('finance', 'payments:read'),
+    ('finance', 'payments:manage'),
+    ('finance', 'wallets:read'),
+    ('finance', 'sales:read')
+) AS p (role_name, permission) ON p.role_name = r.name
+ON CONFLICT DO NOTHING;
+
+-- admins before roles could do everything so they keep it as super admins
+INSERT INTO admin_roles (admin_id, role_id)
+SELECT a.id, r.id FROM admins a INNER JOIN roles r ON r.name = 'super admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_otp_sessions_user_id_created_at;
+
+ALTER TABLE otp_sessions
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS used_at,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS code_hash;
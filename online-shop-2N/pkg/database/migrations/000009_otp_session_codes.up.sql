@@ -0,0 +1,7 @@
+-- codes of local otp are stored as hash with the verify attempts of session and used time to make it single use
+ALTER TABLE otp_sessions ADD COLUMN IF NOT EXISTS code_hash text NOT NULL DEFAULT '';
+ALTER TABLE otp_sessions ADD COLUMN IF NOT EXISTS attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE otp_sessions ADD COLUMN IF NOT EXISTS used_at timestamptz;
+ALTER TABLE otp_sessions ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT now();
+-- to find the last otp of user for the resend cooldown
+CREATE INDEX IF NOT EXISTS idx_otp_sessions_user_id_created_at ON otp_sessions (user_id, created_at DESC);
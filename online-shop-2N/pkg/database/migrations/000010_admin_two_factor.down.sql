@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS admin_login_challenges;
+DROP TABLE IF EXISTS admin_recovery_codes;
+DROP TABLE IF EXISTS admin_two_factors;
+
+ALTER TABLE roles DROP COLUMN IF EXISTS require_two_factor;
@@ -0,0 +1,32 @@
+-- roles which require two factor authentication from their admins
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS require_two_factor boolean NOT NULL DEFAULT false;
+
+-- totp secret of admin encrypted, enabled after the first code is verified
+CREATE TABLE IF NOT EXISTS admin_two_factors (
+    admin_id bigint PRIMARY KEY REFERENCES admins (id) ON DELETE CASCADE,
+    secret text NOT NULL,
+    enabled_at timestamptz,
+    -- time step of the last used code so a code can't be used twice
+    last_used_step bigint NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- single use recovery codes, only the hash of code is stored
+CREATE TABLE IF NOT EXISTS admin_recovery_codes (
+    id bigserial PRIMARY KEY,
+    admin_id bigint NOT NULL REFERENCES admins (id) ON DELETE CASCADE,
+    code_hash text NOT NULL,
+    used_at timestamptz,
+    UNIQUE (admin_id, code_hash)
+);
+
+-- challenges of sign in which are completed with the two factor code
+CREATE TABLE IF NOT EXISTS admin_login_challenges (
+    id bigserial PRIMARY KEY,
+    admin_id bigint NOT NULL REFERENCES admins (id) ON DELETE CASCADE,
+    token_hash text NOT NULL UNIQUE,
+    attempts integer NOT NULL DEFAULT 0,
+    expire_at timestamptz NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
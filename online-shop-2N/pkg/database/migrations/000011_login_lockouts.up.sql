@@ -0,0 +1,9 @@
+-- failed password attempts of users and admins, account is locked until the time after max failed attempts
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    user_type text NOT NULL,
+    user_id bigint NOT NULL,
+    failed_attempts integer NOT NULL DEFAULT 0,
+    locked_until timestamptz,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_type, user_id)
+);
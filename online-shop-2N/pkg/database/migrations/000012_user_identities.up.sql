@@ -0,0 +1,14 @@
+-- sign in identities of users on oauth providers, a user can link one identity of each provider
+CREATE TABLE IF NOT EXISTS user_identities (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider text NOT NULL,
+    provider_user_id text NOT NULL,
+    email text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (provider, provider_user_id),
+    UNIQUE (user_id, provider)
+);
+
+-- users of oauth have no phone, empty phone is saved as null so the unique phone not conflict between them
+UPDATE users SET phone = NULL WHERE phone = '';
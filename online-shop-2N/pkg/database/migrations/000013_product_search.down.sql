@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_order_lines_product_item_id;
+DROP INDEX IF EXISTS idx_product_configurations_variation_option_id;
+DROP INDEX IF EXISTS idx_product_items_product_id;
+DROP INDEX IF EXISTS idx_products_created_at;
+DROP INDEX IF EXISTS idx_products_brand_id;
+DROP INDEX IF EXISTS idx_products_category_id;
+DROP INDEX IF EXISTS idx_products_search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
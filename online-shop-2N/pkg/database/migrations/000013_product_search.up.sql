@@ -0,0 +1,15 @@
+-- full text search of products, name is weighted more than the description on ranking
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
+
+-- filters and sorting of product search
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
+CREATE INDEX IF NOT EXISTS idx_products_brand_id ON products (brand_id);
+CREATE INDEX IF NOT EXISTS idx_products_created_at ON products (created_at);
+CREATE INDEX IF NOT EXISTS idx_product_items_product_id ON product_items (product_id);
+CREATE INDEX IF NOT EXISTS idx_product_configurations_variation_option_id ON product_configurations (variation_option_id);
+CREATE INDEX IF NOT EXISTS idx_order_lines_product_item_id ON order_lines (product_item_id);
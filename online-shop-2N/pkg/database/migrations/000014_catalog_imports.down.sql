@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS catalog_import_errors;
+DROP TABLE IF EXISTS catalog_import_jobs;
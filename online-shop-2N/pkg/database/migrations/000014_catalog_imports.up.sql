@@ -0,0 +1,28 @@
+-- bulk imports of catalog, file of the import is kept until the job is run by the import worker
+CREATE TABLE IF NOT EXISTS catalog_import_jobs (
+    id bigserial PRIMARY KEY,
+    admin_id bigint NOT NULL REFERENCES admins (id),
+    file_name text NOT NULL,
+    format text NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    data bytea,
+    total_rows integer NOT NULL DEFAULT 0,
+    created_rows integer NOT NULL DEFAULT 0,
+    updated_rows integer NOT NULL DEFAULT 0,
+    failed_rows integer NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    started_at timestamptz,
+    finished_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_catalog_import_jobs_status ON catalog_import_jobs (status, id);
+
+-- rows of an import which are failed with the reason
+CREATE TABLE IF NOT EXISTS catalog_import_errors (
+    id bigserial PRIMARY KEY,
+    job_id bigint NOT NULL REFERENCES catalog_import_jobs (id) ON DELETE CASCADE,
+    row_number integer NOT NULL,
+    sku text NOT NULL DEFAULT '',
+    message text NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_catalog_import_errors_job_id ON catalog_import_errors (job_id, id);
@@ -0,0 +1,2 @@
+-- pending gateway refunds are reconciled on background
+CREATE INDEX IF NOT EXISTS idx_refunds_pending ON refunds (id) WHERE status = 'pending';
@@ -0,0 +1,33 @@
+-- a user have only one wallet, wallets created twice for a user are merged into the first wallet of the user
+UPDATE wallets w SET total_amount = d.total_amount
+FROM (
+    SELECT MIN(id) AS id, SUM(total_amount) AS total_amount
+    FROM wallets GROUP BY user_id HAVING COUNT(id) > 1
+) d
+WHERE w.id = d.id;
+
+UPDATE transactions t SET wallet_id = k.first_wallet_id
+FROM (
+    SELECT id, MIN(id) OVER (PARTITION BY user_id) AS first_wallet_id FROM wallets
+) k
+WHERE t.wallet_id = k.id AND k.id != k.first_wallet_id;
+
+-- balance after of the moved transactions is of their old wallet, calculate it again for the merged wallet in time order
+-- so the balance after of its last transaction is the total amount of the merged wallet
+UPDATE transactions t SET balance_after = b.balance_after
+FROM (
+    SELECT mt.transaction_id, GREATEST(mw.total_amount - COALESCE(SUM(
+        CASE WHEN mt.transaction_type = 'CREDIT' THEN mt.amount ELSE -mt.amount END
+    ) OVER (
+        PARTITION BY mt.wallet_id ORDER BY mt.transaction_date, mt.transaction_id
+        ROWS BETWEEN 1 FOLLOWING AND UNBOUNDED FOLLOWING
+    ), 0), 0) AS balance_after
+    FROM transactions mt
+    JOIN wallets mw ON mw.id = mt.wallet_id
+    WHERE mw.id IN (SELECT MIN(id) FROM wallets GROUP BY user_id HAVING COUNT(id) > 1)
+) b
+WHERE t.transaction_id = b.transaction_id;
+
+DELETE FROM wallets w USING wallets f WHERE w.user_id = f.user_id AND w.id > f.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallets_user_id ON wallets (user_id);
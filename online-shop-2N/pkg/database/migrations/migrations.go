@@ -0,0 +1,10 @@
+// Package migrations holds the versioned sql migrations of the database which are embedded on the binary
+//
+// each migration is a pair of files named as <version>_<name>.up.sql and <version>_<name>.down.sql
+// new pair of files can be created with `go run ./cmd/api migrate create <name>`
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
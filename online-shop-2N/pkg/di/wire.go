@@ -11,9 +11,16 @@ import (
 	"online-shop-2N/pkg/database"
 	"online-shop-2N/pkg/repositories"
 	"online-shop-2N/pkg/services/cloud"
+	"online-shop-2N/pkg/services/denylist"
+	"online-shop-2N/pkg/services/mailer"
+	"online-shop-2N/pkg/services/oauth"
 	"online-shop-2N/pkg/services/otp"
+	"online-shop-2N/pkg/services/payment"
+	"online-shop-2N/pkg/services/ratelimit"
 	"online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/services/totp"
 	"online-shop-2N/pkg/usecases"
+	"online-shop-2N/pkg/workers"
 
 	"github.com/google/wire"
 )
@@ -24,7 +31,13 @@ func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
 		//external
 		tokens.NewTokenService,
 		otp.NewOtpAuth,
+		totp.NewTotpService,
+		ratelimit.NewStore,
 		cloud.NewAWSCloudService,
+		payment.NewPaymentGatewayRegistry,
+		oauth.NewProviderRegistry,
+		denylist.NewDenylist,
+		mailer.NewMailer,
 
 		// repositories
 
@@ -41,6 +54,7 @@ func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
 		repositories.NewOfferRepository,
 		repositories.NewStockRepository,
 		repositories.NewBrandDatabaseRepository,
+		repositories.NewCatalogRepository,
 
 		//usecases
 		usecases.NewAuthUseCase,
@@ -55,6 +69,7 @@ func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
 		usecases.NewOfferUseCase,
 		usecases.NewStockUseCase,
 		usecases.NewBrandUseCase,
+		usecases.NewCatalogUseCase,
 		// handlers
 		handlers.NewAuthHandler,
 		handlers.NewAdminHandler,
@@ -68,6 +83,12 @@ func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
 		handlers.NewOfferHandler,
 		handlers.NewStockHandler,
 		handlers.NewBrandHandler,
+		handlers.NewCatalogHandler,
+
+		// workers
+		workers.NewOrderExpiryWorker,
+		workers.NewRefundReconcileWorker,
+		workers.NewCatalogImportWorker,
 
 		http.NewServerHTTP,
 	)
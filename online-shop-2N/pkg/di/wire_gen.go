@@ -14,9 +14,16 @@ import (
 	"online-shop-2N/pkg/database"
 	"online-shop-2N/pkg/repositories"
 	"online-shop-2N/pkg/services/cloud"
+	"online-shop-2N/pkg/services/denylist"
+	"online-shop-2N/pkg/services/mailer"
+	"online-shop-2N/pkg/services/oauth"
 	"online-shop-2N/pkg/services/otp"
+	"online-shop-2N/pkg/services/payment"
+	"online-shop-2N/pkg/services/ratelimit"
 	"online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/services/totp"
 	"online-shop-2N/pkg/usecases"
+	"online-shop-2N/pkg/workers"
 )
 
 // Injectors from wire.go:
@@ -27,25 +34,52 @@ func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
 		return nil, err
 	}
 	authRepository := repositories.NewAuthRepository(db)
-	tokenService := tokens.NewTokenService(cfg)
+	tokenService, err := tokens.NewTokenService(cfg)
+	if err != nil {
+		return nil, err
+	}
 	userRepository := repositories.NewUserRepository(db)
 	adminRepository := repositories.NewAdminRepository(db)
-	otpAuth := otp.NewOtpAuth(cfg)
-	authUseCase := usecases.NewAuthUseCase(authRepository, tokenService, userRepository, adminRepository, otpAuth)
+	otpAuth, err := otp.NewOtpAuth(cfg)
+	if err != nil {
+		return nil, err
+	}
+	totpService, err := totp.NewTotpService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	denylistDenylist := denylist.NewDenylist(cfg, db)
+	mailerMailer, err := mailer.NewMailer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := oauth.NewProviderRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	authUseCase := usecases.NewAuthUseCase(authRepository, tokenService, userRepository, adminRepository, otpAuth, totpService, denylistDenylist, mailerMailer, registry, cfg)
 	authHandler := handlers.NewAuthHandler(authUseCase, cfg)
-	middleware := middlewares.NewMiddleware(tokenService)
-	adminUseCase := usecases.NewAdminUseCase(adminRepository, userRepository)
+	store, err := ratelimit.NewStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	middleware := middlewares.NewMiddleware(authUseCase, store, cfg)
+	adminUseCase := usecases.NewAdminUseCase(adminRepository, userRepository, authRepository, denylistDenylist)
 	adminHandler := handlers.NewAdminHandler(adminUseCase)
 	cartRepository := repositories.NewCartRepository(db)
 	productRepository := repositories.NewProductRepository(db)
-	userUseCase := usecases.NewUserUseCase(userRepository, cartRepository, productRepository)
+	userUseCase := usecases.NewUserUseCase(userRepository, cartRepository, productRepository, authRepository, denylistDenylist)
 	userHandler := handlers.NewUserHandler(userUseCase)
 	cartUseCase := usecases.NewCartUseCase(cartRepository, productRepository)
 	cartHandler := handlers.NewCartHandler(cartUseCase)
 	paymentRepository := repositories.NewPaymentRepository(db)
 	orderRepository := repositories.NewOrderRepository(db)
 	couponRepository := repositories.NewCouponRepository(db)
-	paymentUseCase := usecases.NewPaymentUseCase(paymentRepository, orderRepository, userRepository, cartRepository, couponRepository, cfg)
+	paymentRegistry, err := payment.NewPaymentGatewayRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	paymentUseCase := usecases.NewPaymentUseCase(paymentRepository, orderRepository, userRepository, cfg, paymentRegistry)
 	paymentHandler := handlers.NewPaymentHandler(paymentUseCase)
 	cloudService, err := cloud.NewAWSCloudService(cfg)
 	if err != nil {
@@ -56,7 +90,7 @@ func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
 	categoryRepository := repositories.NewCategoryRepository(db)
 	categoryUseCase := usecases.NewCategoryUseCase(categoryRepository)
 	categoryHandler := handlers.NewCategoryHandler(categoryUseCase)
-	orderUseCase := usecases.NewOrderUseCase(orderRepository, cartRepository, userRepository, paymentRepository)
+	orderUseCase := usecases.NewOrderUseCase(orderRepository, cartRepository, userRepository, paymentRepository, paymentRegistry)
 	orderHandler := handlers.NewOrderHandler(orderUseCase)
 	couponUseCase := usecases.NewCouponUseCase(couponRepository, cartRepository)
 	couponHandler := handlers.NewCouponHandler(couponUseCase)
@@ -69,6 +103,15 @@ func InitializeApi(cfg config.Config) (*http.ServerHTTP, error) {
 	brandRepository := repositories.NewBrandDatabaseRepository(db)
 	brandUseCase := usecases.NewBrandUseCase(brandRepository)
 	brandHandler := handlers.NewBrandHandler(brandUseCase)
-	serverHTTP := http.NewServerHTTP(authHandler, middleware, adminHandler, userHandler, cartHandler, paymentHandler, productHandler, categoryHandler, orderHandler, couponHandler, offerHandler, stockHandler, brandHandler)
+	catalogRepository := repositories.NewCatalogRepository(db)
+	catalogUseCase := usecases.NewCatalogUseCase(catalogRepository)
+	catalogHandler := handlers.NewCatalogHandler(catalogUseCase)
+	orderExpiryWorker := workers.NewOrderExpiryWorker(orderUseCase, cfg)
+	refundReconcileWorker := workers.NewRefundReconcileWorker(orderUseCase, cfg)
+	catalogImportWorker := workers.NewCatalogImportWorker(catalogUseCase, cfg)
+	serverHTTP, err := http.NewServerHTTP(cfg, authHandler, middleware, adminHandler, userHandler, cartHandler, paymentHandler, productHandler, categoryHandler, orderHandler, couponHandler, offerHandler, stockHandler, brandHandler, catalogHandler, orderExpiryWorker, refundReconcileWorker, catalogImportWorker)
+	if err != nil {
+		return nil, err
+	}
 	return serverHTTP, nil
 }
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"time"
+)
 
 type Admin struct {
 	ID        uint      `json:"id" gorm:"primaryKey;not null"`
@@ -10,3 +13,57 @@ type Admin struct {
 	CreatedAt time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type Role struct {
+	ID          uint   `json:"id" gorm:"primaryKey;not null"`
+	Name        string `json:"name" gorm:"unique;not null"`
+	Description string `json:"description" gorm:"not null;default:''"`
+	// admins of the role can't use the admin apis until they enable two factor authentication
+	RequireTwoFactor bool      `json:"require_two_factor" gorm:"not null;default:false"`
+	CreatedAt        time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type RolePermission struct {
+	RoleID     uint                           `json:"role_id" gorm:"primaryKey;not null"`
+	Role       Role                           `json:"-"`
+	Permission commonConstant.AdminPermission `json:"permission" gorm:"primaryKey;not null"`
+}
+
+type AdminRole struct {
+	AdminID uint  `json:"admin_id" gorm:"primaryKey;not null"`
+	Admin   Admin `json:"-"`
+	RoleID  uint  `json:"role_id" gorm:"primaryKey;not null"`
+	Role    Role  `json:"-"`
+}
+
+// totp secret of admin, secret is stored encrypted and it's enabled after the first code verified
+type AdminTwoFactor struct {
+	AdminID      uint       `json:"admin_id" gorm:"primaryKey;not null"`
+	Admin        Admin      `json:"-"`
+	Secret       string     `json:"-" gorm:"not null"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"-" gorm:"not null;default:0"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"not null"`
+}
+
+// single use recovery code to sign in when the authenticator is lost, only the hash of code is stored
+type AdminRecoveryCode struct {
+	ID       uint       `json:"id" gorm:"primaryKey;not null"`
+	AdminID  uint       `json:"admin_id" gorm:"not null"`
+	Admin    Admin      `json:"-"`
+	CodeHash string     `json:"-" gorm:"not null"`
+	UsedAt   *time.Time `json:"used_at"`
+}
+
+// challenge of sign in which is completed with the two factor code, only the hash of token is stored
+type AdminLoginChallenge struct {
+	ID        uint       `json:"id" gorm:"primaryKey;not null"`
+	AdminID   uint       `json:"admin_id" gorm:"not null"`
+	Admin     Admin      `json:"-"`
+	TokenHash string     `json:"-" gorm:"unique;not null"`
+	Attempts  uint       `json:"attempts" gorm:"not null;default:0"`
+	ExpireAt  time.Time  `json:"expire_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
+}
@@ -2,18 +2,64 @@ package models
 
 import "time"
 
+// each refresh of tokens create a new refresh session on the same session id (token family)
+// the old one is marked as replaced so presenting it again means the token is stolen
 type RefreshSession struct {
-	TokenID      string    `json:"token_id" gorm:"primaryKey;not null"`
-	UserID       uint      `json:"user_id" gorm:"not null"`
-	RefreshToken string    `json:"refresh_token" gorm:"not null"`
-	ExpireAt     time.Time `json:"expire_at" gorm:"not null"`
-	IsBlocked    bool      `json:"is_blocked" gorm:"not null;default:false"`
+	TokenID           string    `json:"token_id" gorm:"primaryKey;not null"`
+	UserID            uint      `json:"user_id" gorm:"not null"`
+	UserType          string    `json:"user_type" gorm:"not null;default:'user'"`
+	SessionID         string    `json:"session_id" gorm:"not null;index"`
+	RefreshToken      string    `json:"refresh_token" gorm:"not null"`
+	ExpireAt          time.Time `json:"expire_at" gorm:"not null"`
+	IsBlocked         bool      `json:"is_blocked" gorm:"not null;default:false"`
+	ReplacedByTokenID string    `json:"replaced_by_token_id" gorm:"not null;default:''"`
+	UserAgent         string    `json:"user_agent" gorm:"not null;default:''"`
+	IPAddress         string    `json:"ip_address" gorm:"not null;default:''"`
+	CreatedAt         time.Time `json:"created_at" gorm:"not null"`
 }
 
 type OtpSession struct {
-	ID       uint      `json:"id" gorm:"primaryKey;not null"`
-	OtpID    string    `json:"otp_id" gorm:"unique;not null"`
-	UserID   uint      `json:"user_id" gorm:"not null"`
-	Phone    string    `json:"phone" gorm:"not null"`
-	ExpireAt time.Time `json:"expire_at" gorm:"not null"`
+	ID     uint   `json:"id" gorm:"primaryKey;not null"`
+	OtpID  string `json:"otp_id" gorm:"unique;not null"`
+	UserID uint   `json:"user_id" gorm:"not null"`
+	// phone number on E.164 format which the otp sent to
+	Phone string `json:"phone" gorm:"not null"`
+	// hash of code when it's generated locally, empty when the provider verify the code
+	CodeHash  string     `json:"-" gorm:"not null;default:''"`
+	Attempts  uint       `json:"attempts" gorm:"not null;default:0"`
+	ExpireAt  time.Time  `json:"expire_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
+}
+
+// single use token which is sent to user by mail, only the hash of token is stored
+type UserToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey;not null"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	User      User       `json:"-"`
+	Purpose   string     `json:"purpose" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"unique;not null"`
+	ExpireAt  time.Time  `json:"expire_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
+}
+
+// failed password attempts of user or admin, account is locked until the time after max failed attempts
+type LoginLockout struct {
+	UserType       string     `json:"user_type" gorm:"primaryKey;not null"`
+	UserID         uint       `json:"user_id" gorm:"primaryKey;not null"`
+	FailedAttempts uint       `json:"failed_attempts" gorm:"not null;default:0"`
+	LockedUntil    *time.Time `json:"locked_until"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+// sign in identity of user on an oauth provider, a user can have one identity of each provider
+type UserIdentity struct {
+	ID             uint      `json:"id" gorm:"primaryKey;not null"`
+	UserID         uint      `json:"user_id" gorm:"not null;index"`
+	User           User      `json:"-"`
+	Provider       string    `json:"provider" gorm:"not null"`
+	ProviderUserID string    `json:"provider_user_id" gorm:"not null"`
+	Email          string    `json:"email" gorm:"not null;default:''"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
 }
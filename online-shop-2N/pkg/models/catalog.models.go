@@ -0,0 +1,37 @@
+package models
+
+import (
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"time"
+)
+
+// bulk import of catalog from a csv or json lines file which is run on background by the import worker,
+// data of the file is removed after the job is run
+type CatalogImportJob struct {
+	ID          uint                                   `json:"id" gorm:"primaryKey;not null"`
+	AdminID     uint                                   `json:"admin_id" gorm:"not null"`
+	Admin       Admin                                  `json:"-"`
+	FileName    string                                 `json:"file_name" gorm:"not null"`
+	Format      string                                 `json:"format" gorm:"not null"`
+	Status      commonConstant.CatalogImportStatusType `json:"status" gorm:"not null;default:'pending'"`
+	Data        []byte                                 `json:"-"`
+	TotalRows   uint                                   `json:"total_rows" gorm:"not null;default:0"`
+	CreatedRows uint                                   `json:"created_rows" gorm:"not null;default:0"`
+	UpdatedRows uint                                   `json:"updated_rows" gorm:"not null;default:0"`
+	FailedRows  uint                                   `json:"failed_rows" gorm:"not null;default:0"`
+	// error which stopped the job, errors of rows are saved as import errors
+	Error      string     `json:"error,omitempty" gorm:"not null;default:''"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"not null"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// a row of import which is failed with the reason
+type CatalogImportError struct {
+	ID        uint             `json:"id" gorm:"primaryKey;not null"`
+	JobID     uint             `json:"job_id" gorm:"not null;index"`
+	Job       CatalogImportJob `json:"-"`
+	RowNumber uint             `json:"row_number" gorm:"not null"`
+	SKU       string           `json:"sku" gorm:"not null;default:''"`
+	Message   string           `json:"message" gorm:"not null"`
+}
@@ -12,6 +12,30 @@ type PaymentMethod struct {
 	MaximumAmount uint                       `json:"maximum_amount" gorm:"not null"`
 }
 
+// a payment made with a payment gateway for a shop order
+type OrderPayment struct {
+	ID               uint                             `json:"id" gorm:"primaryKey;not null"`
+	ShopOrderID      uint                             `json:"shop_order_id" gorm:"not null;index"`
+	ShopOrder        ShopOrder                        `json:"-"`
+	PaymentMethodID  uint                             `json:"payment_method_id" gorm:"not null"`
+	PaymentMethod    PaymentMethod                    `json:"-"`
+	Amount           uint                             `json:"amount" gorm:"not null"`
+	GatewayOrderID   string                           `json:"gateway_order_id" gorm:"index"`
+	GatewayPaymentID string                           `json:"gateway_payment_id"`
+	Status           commonConstant.PaymentStatusType `json:"status" gorm:"not null"`
+	CreatedAt        time.Time                        `json:"created_at" gorm:"not null"`
+	UpdatedAt        time.Time                        `json:"updated_at"`
+}
+
+// processed webhook events of payment gateways to not process an event twice
+type WebhookEvent struct {
+	ID         uint                       `json:"id" gorm:"primaryKey;not null"`
+	Provider   commonConstant.PaymentType `json:"provider" gorm:"not null;uniqueIndex:idx_webhook_events_provider_event_id"`
+	EventID    string                     `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_events_provider_event_id"`
+	EventType  string                     `json:"event_type" gorm:"not null"`
+	ReceivedAt time.Time                  `json:"received_at" gorm:"not null"`
+}
+
 type OrderStatus struct {
 	ID     uint                           `json:"id" gorm:"primaryKey;not null"`
 	Status commonConstant.OrderStatusType `json:"status" gorm:"unique;not null"`
@@ -39,18 +63,97 @@ type OrderLine struct {
 	ShopOrder     ShopOrder `json:"-"`
 	Qty           uint      `json:"qty" gorm:"not null"`
 	Price         uint      `json:"price" gorm:"not null"`
+	// quantities taken back from the order and the amount refunded for them
+	ReturnedQty    uint `json:"returned_qty" gorm:"not null;default:0"`
+	CancelledQty   uint `json:"cancelled_qty" gorm:"not null;default:0"`
+	RefundedAmount uint `json:"refunded_amount" gorm:"not null;default:0"`
 }
 
 type OrderReturn struct {
 	ID           uint      `json:"id" gorm:"primaryKey;not null"`
-	ShopOrderID  uint      `json:"shop_order_id" gorm:"not null;unique"`
+	ShopOrderID  uint      `json:"shop_order_id" gorm:"not null;index"`
 	ShopOrder    ShopOrder `json:"-"`
 	RequestDate  time.Time `json:"request_date" gorm:"not null"`
 	ReturnReason string    `json:"return_reason" gorm:"not null"`
 	RefundAmount uint      `json:"refund_amount" gorm:"not null"`
+	// refund policy chosen by user and the amount to wallet for a split refund
+	RefundPolicy       commonConstant.RefundPolicyType `json:"refund_policy" gorm:"not null;default:'wallet'"`
+	WalletRefundAmount uint                            `json:"wallet_refund_amount"`
 
 	IsApproved   bool      `json:"is_approved"`
 	ReturnDate   time.Time `json:"return_date"`
 	ApprovalDate time.Time `json:"approval_date"`
 	AdminComment string    `json:"admin_comment"`
 }
+
+// quantity of an order line requested to return with its refund amount
+type OrderReturnLine struct {
+	ID            uint        `json:"id" gorm:"primaryKey;not null"`
+	OrderReturnID uint        `json:"order_return_id" gorm:"not null;index"`
+	OrderReturn   OrderReturn `json:"-"`
+	OrderLineID   uint        `json:"order_line_id" gorm:"not null"`
+	OrderLine     OrderLine   `json:"-"`
+	Qty           uint        `json:"qty" gorm:"not null"`
+	RefundAmount  uint        `json:"refund_amount" gorm:"not null"`
+}
+
+// cancel of some or all items of an order before it's shipped
+type OrderCancel struct {
+	ID           uint                          `json:"id" gorm:"primaryKey;not null"`
+	ShopOrderID  uint                          `json:"shop_order_id" gorm:"not null;index"`
+	ShopOrder    ShopOrder                     `json:"-"`
+	CancelReason string                        `json:"cancel_reason"`
+	Actor        commonConstant.OrderActorType `json:"actor" gorm:"not null"`
+	ActorID      uint                          `json:"actor_id"`
+	// amount refunded which is limited to the amount paid for the order
+	RefundAmount       uint                            `json:"refund_amount" gorm:"not null"`
+	RefundPolicy       commonConstant.RefundPolicyType `json:"refund_policy" gorm:"not null;default:'wallet'"`
+	WalletRefundAmount uint                            `json:"wallet_refund_amount"`
+	CreatedAt          time.Time                       `json:"created_at" gorm:"not null"`
+}
+
+type OrderCancelLine struct {
+	ID            uint        `json:"id" gorm:"primaryKey;not null"`
+	OrderCancelID uint        `json:"order_cancel_id" gorm:"not null;index"`
+	OrderCancel   OrderCancel `json:"-"`
+	OrderLineID   uint        `json:"order_line_id" gorm:"not null"`
+	OrderLine     OrderLine   `json:"-"`
+	Qty           uint        `json:"qty" gorm:"not null"`
+	RefundAmount  uint        `json:"refund_amount" gorm:"not null"`
+}
+
+// refund of an order return or cancel amount to user wallet or to a payment made on gateway
+type Refund struct {
+	ID          uint      `json:"id" gorm:"primaryKey;not null"`
+	ShopOrderID uint      `json:"shop_order_id" gorm:"not null;index"`
+	ShopOrder   ShopOrder `json:"-"`
+	// refund is made for either an order return or an order cancel
+	OrderReturnID uint `json:"order_return_id" gorm:"not null;default:0;index"`
+	OrderCancelID uint `json:"order_cancel_id" gorm:"not null;default:0;index"`
+	// order payment refunding to, it's zero for a refund to wallet
+	OrderPaymentID  uint                            `json:"order_payment_id"`
+	RefundTo        commonConstant.PaymentType      `json:"refund_to" gorm:"not null"`
+	Amount          uint                            `json:"amount" gorm:"not null"`
+	Status          commonConstant.RefundStatusType `json:"status" gorm:"not null"`
+	GatewayRefundID string                          `json:"gateway_refund_id"`
+	FailureReason   string                          `json:"failure_reason"`
+	CreatedAt       time.Time                       `json:"created_at" gorm:"not null"`
+	UpdatedAt       time.Time                       `json:"updated_at"`
+}
+
+// every change of order status with who made it
+type OrderStatusHistory struct {
+	ID           uint                          `json:"id" gorm:"primaryKey;not null"`
+	ShopOrderID  uint                          `json:"shop_order_id" gorm:"not null;index"`
+	ShopOrder    ShopOrder                     `json:"-"`
+	FromStatusID uint                          `json:"from_status_id"`
+	ToStatusID   uint                          `json:"to_status_id" gorm:"not null"`
+	Actor        commonConstant.OrderActorType `json:"actor" gorm:"not null"`
+	ActorID      uint                          `json:"actor_id"`
+	Comment      string                        `json:"comment"`
+	CreatedAt    time.Time                     `json:"created_at" gorm:"not null"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
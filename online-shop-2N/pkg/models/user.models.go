@@ -78,7 +78,7 @@ type CartItem struct {
 // for ENUM Data type
 type Wallet struct {
 	ID          uint `json:"wallet_id" gorm:"primaryKey;not null"`
-	UserID      uint `json:"user_id" gorm:"not null"`
+	UserID      uint `json:"user_id" gorm:"not null;unique"`
 	User        User `json:"-"`
 	TotalAmount uint `json:"total_amount" gorm:"not null"`
 }
@@ -90,13 +90,29 @@ const (
 	Credit TransactionType = "CREDIT"
 )
 
+// the other side of a wallet transaction which the amount came from or went to
+type TransactionSourceType string
+
+const (
+	SourceOrder           TransactionSourceType = "order"
+	SourceOrderReturn     TransactionSourceType = "order return"
+	SourceOrderCancel     TransactionSourceType = "order cancel"
+	SourceAdminAdjustment TransactionSourceType = "admin adjustment"
+	SourcePromo           TransactionSourceType = "promo"
+)
+
 type Transaction struct {
-	TransactionID   uint            `json:"transaction_id" gorm:"primaryKey;not null"`
-	WalletID        uint            `json:"wallet_id" gorm:"not null"`
-	Wallet          Wallet          `json:"-"`
-	TransactionDate time.Time       `json:"transaction_time" gorm:"not null"`
-	Amount          uint            `json:"amount" gorm:"not null"`
-	TransactionType TransactionType `json:"transaction_type" gorm:"not null"`
+	TransactionID   uint                  `json:"transaction_id" gorm:"primaryKey;not null"`
+	WalletID        uint                  `json:"wallet_id" gorm:"not null;index"`
+	Wallet          Wallet                `json:"-"`
+	TransactionDate time.Time             `json:"transaction_time" gorm:"not null"`
+	Amount          uint                  `json:"amount" gorm:"not null"`
+	TransactionType TransactionType       `json:"transaction_type" gorm:"not null"`
+	SourceType      TransactionSourceType `json:"source_type" gorm:"not null;default:''"`
+	SourceID        uint                  `json:"source_id" gorm:"not null;default:0"`
+	// wallet balance after this transaction
+	BalanceAfter uint   `json:"balance_after" gorm:"not null;default:0"`
+	Note         string `json:"note"`
 }
 
 // wallet end
@@ -0,0 +1,74 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// Cursor is the position after the last row of a page which is the values of the sort key and unique id of the row,
+// lists which can't be paged by keys (like sort by relevance) keep the offset of the next page
+type Cursor struct {
+	Time   time.Time
+	Number int64
+	ID     uint
+	Offset uint64
+}
+
+// compact form of cursor which is encoded
+type cursorJSON struct {
+	Time   int64  `json:"t,omitempty"`
+	Number int64  `json:"n,omitempty"`
+	ID     uint   `json:"i,omitempty"`
+	Offset uint64 `json:"o,omitempty"`
+}
+
+func (c Cursor) IsZero() bool {
+	return c == Cursor{}
+}
+
+// To encode the cursor as an opaque url safe string
+func (c Cursor) Encode() string {
+
+	value := cursorJSON{
+		Number: c.Number,
+		ID:     c.ID,
+		Offset: c.Offset,
+	}
+	if !c.Time.IsZero() {
+		value.Time = c.Time.UnixMicro()
+	}
+
+	data, _ := json.Marshal(value)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// To decode the cursor of request, empty cursor is the zero cursor of first page
+func DecodeCursor(encoded string) (Cursor, error) {
+
+	if encoded == "" {
+		return Cursor{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var value cursorJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	cursor := Cursor{
+		Number: value.Number,
+		ID:     value.ID,
+		Offset: value.Offset,
+	}
+	if value.Time != 0 {
+		cursor.Time = time.UnixMicro(value.Time)
+	}
+
+	return cursor, nil
+}
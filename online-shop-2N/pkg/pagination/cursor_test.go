@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorEncodeDecode(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "zero cursor", cursor: Cursor{}},
+		{name: "time and id", cursor: Cursor{Time: time.UnixMicro(1_700_000_000_123_456), ID: 42}},
+		{name: "number and id", cursor: Cursor{Number: 1999, ID: 7}},
+		{name: "negative number", cursor: Cursor{Number: -5, ID: 1}},
+		{name: "offset", cursor: Cursor{Offset: 30}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			encoded := test.cursor.Encode()
+			if strings.ContainsAny(encoded, "+/=") {
+				t.Fatalf("encoded cursor %s is not url safe", encoded)
+			}
+
+			decoded, err := DecodeCursor(encoded)
+			if err != nil {
+				t.Fatalf("failed to decode cursor %s: %v", encoded, err)
+			}
+			if !decoded.Time.Equal(test.cursor.Time) || decoded.Number != test.cursor.Number ||
+				decoded.ID != test.cursor.ID || decoded.Offset != test.cursor.Offset {
+				t.Fatalf("got cursor %+v, want %+v", decoded, test.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		encoded string
+		want    Cursor
+		wantErr error
+	}{
+		{name: "empty is first page", encoded: "", want: Cursor{}},
+		{name: "not base64", encoded: "not a cursor!", wantErr: ErrInvalidCursor},
+		{name: "not json", encoded: "bm90IGpzb24", wantErr: ErrInvalidCursor},
+		{name: "wrong type of value", encoded: "eyJpIjoiYSJ9", wantErr: ErrInvalidCursor},
+		{name: "valid", encoded: "eyJpIjozfQ", want: Cursor{ID: 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := DecodeCursor(test.encoded)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err == nil && got != test.want {
+				t.Fatalf("got cursor %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
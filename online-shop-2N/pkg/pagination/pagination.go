@@ -0,0 +1,156 @@
+// Package pagination is the shared pagination of the list apis, a page is requested by page number (offset mode)
+// or by the opaque cursor of the previous page (cursor mode) which find the next rows by the sort keys of the list
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultPageSize uint64 = 10
+	MaxPageSize     uint64 = 100
+)
+
+// names of the query values
+const (
+	pageNumberKey = "page_number"
+	pageSizeKey   = "count"
+	cursorKey     = "cursor"
+)
+
+var (
+	ErrInvalidPageNumber  = errors.New("page number should be a number starting from 1")
+	ErrInvalidPageSize    = fmt.Errorf("count should be a number from 1 to %d", MaxPageSize)
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrPageNumberOnCursor = errors.New("page number can't be used with cursor")
+)
+
+// Request is the page requested, the first page of cursor mode is requested with an empty cursor
+type Request struct {
+	PageNumber uint64
+	PageSize   uint64
+	CursorMode bool
+	Cursor     Cursor
+}
+
+// Meta is the details of the page given with the rows of list
+type Meta struct {
+	PageSize uint64 `json:"page_size"`
+	// page number is only on offset mode
+	PageNumber uint64 `json:"page_number,omitempty"`
+	TotalCount uint64 `json:"total_count"`
+	TotalPages uint64 `json:"total_pages"`
+	HasMore    bool   `json:"has_more"`
+	// cursor to request the next page, it's given on both modes when there are more rows
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// To get the page request from the query values, cursor mode is used when the cursor is on query
+func FromQuery(query url.Values) (Request, error) {
+
+	req := Request{
+		PageNumber: 1,
+		PageSize:   DefaultPageSize,
+	}
+
+	if value := query.Get(pageSizeKey); value != "" {
+		pageSize, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || pageSize == 0 || pageSize > MaxPageSize {
+			return req, ErrInvalidPageSize
+		}
+		req.PageSize = pageSize
+	}
+
+	pageNumber := query.Get(pageNumberKey)
+
+	if _, ok := query[cursorKey]; ok {
+		if pageNumber != "" {
+			return req, ErrPageNumberOnCursor
+		}
+		cursor, err := DecodeCursor(query.Get(cursorKey))
+		if err != nil {
+			return req, err
+		}
+		req.CursorMode = true
+		req.Cursor = cursor
+		return req, nil
+	}
+
+	if pageNumber != "" {
+		num, err := strconv.ParseUint(pageNumber, 10, 64)
+		if err != nil || num == 0 {
+			return req, ErrInvalidPageNumber
+		}
+		req.PageNumber = num
+	}
+
+	return req, nil
+}
+
+// Limit of the query, one more row than the page size is taken to know there are more rows
+func (r Request) Limit() uint64 {
+	return r.PageSize + 1
+}
+
+// Offset of the query, on cursor mode it's only used for the lists which page by offset cursor
+func (r Request) Offset() uint64 {
+	if r.CursorMode {
+		return r.Cursor.Offset
+	}
+	return (r.PageNumber - 1) * r.PageSize
+}
+
+// To get the condition to find the rows after the cursor with the values of the keys of cursor,
+// columns are the sort keys of list with the unique column as the last one.
+// condition is TRUE when there is no cursor so it can be used on the queries always
+func (r Request) After(columns string, desc bool, keys ...interface{}) (string, []interface{}) {
+
+	if !r.CursorMode || r.Cursor.IsZero() {
+		return "TRUE", nil
+	}
+
+	operator, placeholders := ">", "?"
+	if desc {
+		operator = "<"
+	}
+	for i := 1; i < len(keys); i++ {
+		placeholders += ", ?"
+	}
+
+	return fmt.Sprintf("(%s) %s (%s)", columns, operator, placeholders), keys
+}
+
+// To make the page of the rows which are found with the limit and offset of request,
+// the extra row is removed from the page and the next cursor is made from the last row with the cursorOf func.
+// when the cursorOf is nil the next cursor keep the offset of next page
+func NewPage[T any](r Request, rows []T, totalCount uint64, cursorOf func(row T) Cursor) ([]T, Meta) {
+
+	meta := Meta{
+		PageSize:   r.PageSize,
+		TotalCount: totalCount,
+		TotalPages: (totalCount + r.PageSize - 1) / r.PageSize,
+	}
+	if !r.CursorMode {
+		meta.PageNumber = r.PageNumber
+	}
+
+	if uint64(len(rows)) <= r.PageSize {
+		return rows, meta
+	}
+
+	rows = rows[:r.PageSize]
+	meta.HasMore = true
+
+	var next Cursor
+	if cursorOf != nil {
+		next = cursorOf(rows[len(rows)-1])
+	} else {
+		next = Cursor{Offset: r.Offset() + r.PageSize}
+	}
+	meta.NextCursor = next.Encode()
+
+	return rows, meta
+}
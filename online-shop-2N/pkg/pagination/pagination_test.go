@@ -0,0 +1,81 @@
+package pagination
+
+import "testing"
+
+func TestRequestAfter(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		request  Request
+		desc     bool
+		keys     []interface{}
+		want     string
+		wantArgs int
+	}{
+		{name: "offset mode", request: Request{PageNumber: 2, PageSize: 10}, keys: []interface{}{1, 2}, want: "TRUE"},
+		{name: "first page of cursor mode", request: Request{CursorMode: true, PageSize: 10},
+			keys: []interface{}{1, 2}, want: "TRUE"},
+		{name: "ascending", request: Request{CursorMode: true, PageSize: 10, Cursor: Cursor{Number: 5, ID: 2}},
+			keys: []interface{}{int64(5), uint(2)}, want: "(price, id) > (?, ?)", wantArgs: 2},
+		{name: "descending", request: Request{CursorMode: true, PageSize: 10, Cursor: Cursor{Number: 5, ID: 2}},
+			desc: true, keys: []interface{}{int64(5), uint(2)}, want: "(price, id) < (?, ?)", wantArgs: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, args := test.request.After("price, id", test.desc, test.keys...)
+			if got != test.want || len(args) != test.wantArgs {
+				t.Fatalf("got condition %s with %d args, want %s with %d args", got, len(args), test.want, test.wantArgs)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+
+	rows := []int{1, 2, 3, 4}
+
+	tests := []struct {
+		name       string
+		request    Request
+		rows       []int
+		wantRows   int
+		wantCursor Cursor
+	}{
+		{name: "last page", request: Request{PageNumber: 1, PageSize: 5}, rows: rows, wantRows: 4},
+		{name: "more rows on offset mode", request: Request{PageNumber: 2, PageSize: 3}, rows: rows,
+			wantRows: 3, wantCursor: Cursor{Offset: 6}},
+		{name: "more rows on cursor mode", request: Request{CursorMode: true, PageSize: 3}, rows: rows,
+			wantRows: 3, wantCursor: Cursor{ID: 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var cursorOf func(row int) Cursor
+			if test.request.CursorMode {
+				cursorOf = func(row int) Cursor { return Cursor{ID: uint(row)} }
+			}
+
+			page, meta := NewPage(test.request, test.rows, 12, cursorOf)
+			if len(page) != test.wantRows {
+				t.Fatalf("got %d rows, want %d", len(page), test.wantRows)
+			}
+			if meta.TotalPages != (12+test.request.PageSize-1)/test.request.PageSize {
+				t.Fatalf("got %d total pages for page size %d", meta.TotalPages, test.request.PageSize)
+			}
+
+			if test.wantCursor.IsZero() {
+				if meta.HasMore || meta.NextCursor != "" {
+					t.Fatalf("got more rows with cursor %s, want no more rows", meta.NextCursor)
+				}
+				return
+			}
+			next, err := DecodeCursor(meta.NextCursor)
+			if err != nil || !meta.HasMore || next != test.wantCursor {
+				t.Fatalf("got next cursor %+v with error %v, want %+v", next, err, test.wantCursor)
+			}
+		})
+	}
+}
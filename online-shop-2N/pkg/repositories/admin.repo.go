@@ -6,6 +6,7 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 	"time"
 
@@ -20,6 +21,21 @@ func NewAdminRepository(DB *gorm.DB) interfaces.AdminRepository {
 	return &adminDatabase{DB: DB}
 }
 
+func (c *adminDatabase) Transaction(callBack func(trxRepo interfaces.AdminRepository) error) error {
+
+	trx := c.DB.Begin()
+	transactionRepo := NewAdminRepository(trx)
+
+	err := callBack(transactionRepo)
+	if err != nil {
+		trx.Rollback()
+		return fmt.Errorf("failed to complete transaction \nerror:%w", err)
+	}
+
+	err = trx.Commit().Error
+	return err
+}
+
 func (c *adminDatabase) FindAdminByEmail(ctx context.Context, email string) (models.Admin, error) {
 
 	var admin models.Admin
@@ -36,43 +52,80 @@ func (c *adminDatabase) FindAdminByUserName(ctx context.Context, userName string
 	return admin, err
 }
 
-func (c *adminDatabase) SaveAdmin(ctx context.Context, admin models.Admin) error {
-	fmt.Println(admin)
-	query := `INSERT INTO admins (user_name, email, password, created_at) VALUES ($1, $2, $3, $4)`
+func (c *adminDatabase) FindAdminByID(ctx context.Context, adminID uint) (models.Admin, error) {
+
+	var admin models.Admin
+	err := c.DB.Raw("SELECT * FROM admins WHERE id = $1", adminID).Scan(&admin).Error
+
+	return admin, err
+}
+
+func (c *adminDatabase) SaveAdmin(ctx context.Context, admin models.Admin) (adminID uint, err error) {
+
+	query := `INSERT INTO admins (user_name, email, password, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
 	createdAt := time.Now()
-	err := c.DB.Exec(query, admin.UserName, admin.Email, admin.Password, createdAt).Error
+	err = c.DB.Raw(query, admin.UserName, admin.Email, admin.Password, createdAt).Scan(&adminID).Error
 
-	return err
+	return adminID, err
 }
 
-func (c *adminDatabase) FindAllUser(ctx context.Context, pagination requests.Pagination) (users []responses.User, err error) {
+func (c *adminDatabase) FindAllUser(ctx context.Context,
+	page pagination.Request) (users []responses.User, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM users`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	query := `SELECT * FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	err = c.DB.Raw(query, limit, offset).Scan(&users).Error
+	after, keys := page.After("created_at, id", true, page.Cursor.Time, page.Cursor.ID)
 
-	return users, err
+	query = `SELECT * FROM users WHERE ` + after + ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&users).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	users, meta = pagination.NewPage(page, users, totalCount, func(user responses.User) pagination.Cursor {
+		return pagination.Cursor{Time: user.CreatedAt, ID: user.ID}
+	})
+
+	return users, meta, nil
 }
 
 // sales report from order // !add  product wise report
-func (c *adminDatabase) CreateFullSalesReport(ctc context.Context, salesReq requests.SalesReport) (salesReport []responses.SalesReport, err error) {
+func (c *adminDatabase) CreateFullSalesReport(ctc context.Context,
+	salesReq requests.SalesReport) (salesReport []responses.SalesReport, meta pagination.Meta, err error) {
 
-	limit := salesReq.Pagination.Count
-	offset := (salesReq.Pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM shop_orders WHERE order_date >= $1 AND order_date <= $2`
+	err = c.DB.Raw(query, salesReq.StartDate, salesReq.EndDate).Scan(&totalCount).Error
+	if err != nil {
+		return nil, meta, err
+	}
 
-	query := `SELECT u.first_name, u.email,  so.id AS shop_order_id, so.user_id, so.order_date, 
+	page := salesReq.Pagination
+	after, keys := page.After("so.order_date, so.id", false, page.Cursor.Time, page.Cursor.ID)
+
+	query = `SELECT u.first_name, u.email,  so.id AS shop_order_id, so.user_id, so.order_date, 
 	so.order_total_price, so.discount, os.status AS order_status, pm.payment_type FROM shop_orders so
 	INNER JOIN order_statuses os ON so.order_status_id = os.id 
 	INNER JOIN  payment_methods pm ON so.payment_method_id = pm.id 
 	INNER JOIN users u ON so.user_id = u.id 
-	WHERE order_date >= $1 AND order_date <= $2
-	ORDER BY so.order_date LIMIT  $3 OFFSET $4`
+	WHERE order_date >= ? AND order_date <= ? AND ` + after + ` 
+	ORDER BY so.order_date, so.id LIMIT ? OFFSET ?`
+
+	values := append([]interface{}{salesReq.StartDate, salesReq.EndDate}, keys...)
+	err = c.DB.Raw(query, append(values, page.Limit(), page.Offset())...).Scan(&salesReport).Error
+	if err != nil {
+		return nil, meta, err
+	}
 
-	err = c.DB.Raw(query, salesReq.StartDate, salesReq.EndDate, limit, offset).Scan(&salesReport).Error
+	salesReport, meta = pagination.NewPage(page, salesReport, totalCount, func(sales responses.SalesReport) pagination.Cursor {
+		return pagination.Cursor{Time: sales.OrderDate, ID: sales.ShopOrderID}
+	})
 
-	return
+	return salesReport, meta, nil
 }
 
 // stock side
@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"time"
+)
+
+func (c *adminDatabase) FindAllAdmins(ctx context.Context,
+	page pagination.Request) ([]responses.Admin, pagination.Meta, error) {
+
+	var (
+		totalCount uint64
+		meta       pagination.Meta
+	)
+	query := `SELECT COUNT(id) FROM admins`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
+
+	after, keys := page.After("created_at, id", true, page.Cursor.Time, page.Cursor.ID)
+
+	var admins []responses.Admin
+	query = `SELECT id, user_name, email, created_at FROM admins WHERE ` + after + ` 
+	ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	err := c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&admins).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	admins, meta = pagination.NewPage(page, admins, totalCount, func(admin responses.Admin) pagination.Cursor {
+		return pagination.Cursor{Time: admin.CreatedAt, ID: admin.ID}
+	})
+	if len(admins) == 0 {
+		return admins, meta, nil
+	}
+
+	adminIDs := make([]uint, len(admins))
+	for i, admin := range admins {
+		adminIDs[i] = admin.ID
+	}
+
+	var adminRoles []struct {
+		AdminID uint
+		responses.Role
+	}
+	query = `SELECT ar.admin_id, r.id, r.name, r.description, r.require_two_factor FROM admin_roles ar 
+	INNER JOIN roles r ON r.id = ar.role_id 
+	WHERE ar.admin_id IN ? ORDER BY r.id`
+	err = c.DB.Raw(query, adminIDs).Scan(&adminRoles).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	rolesByAdminID := make(map[uint][]responses.Role, len(admins))
+	for _, adminRole := range adminRoles {
+		rolesByAdminID[adminRole.AdminID] = append(rolesByAdminID[adminRole.AdminID], adminRole.Role)
+	}
+	for i := range admins {
+		admins[i].Roles = rolesByAdminID[admins[i].ID]
+	}
+
+	return admins, meta, nil
+}
+
+// find all roles with its permissions
+func (c *adminDatabase) FindAllRoles(ctx context.Context) ([]responses.Role, error) {
+
+	var roles []responses.Role
+	query := `SELECT id, name, description, require_two_factor FROM roles ORDER BY id`
+	err := c.DB.Raw(query).Scan(&roles).Error
+	if err != nil || len(roles) == 0 {
+		return roles, err
+	}
+
+	var rolePermissions []models.RolePermission
+	query = `SELECT role_id, permission FROM role_permissions ORDER BY permission`
+	err = c.DB.Raw(query).Scan(&rolePermissions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	permissionsByRoleID := make(map[uint][]commonConstant.AdminPermission, len(roles))
+	for _, rolePermission := range rolePermissions {
+		permissionsByRoleID[rolePermission.RoleID] = append(permissionsByRoleID[rolePermission.RoleID],
+			rolePermission.Permission)
+	}
+	for i := range roles {
+		roles[i].Permissions = permissionsByRoleID[roles[i].ID]
+	}
+
+	return roles, nil
+}
+
+func (c *adminDatabase) FindRoleByID(ctx context.Context, roleID uint) (role models.Role, err error) {
+
+	query := `SELECT * FROM roles WHERE id = $1`
+	err = c.DB.Raw(query, roleID).Scan(&role).Error
+
+	return role, err
+}
+
+func (c *adminDatabase) FindRoleByName(ctx context.Context, name string) (role models.Role, err error) {
+
+	query := `SELECT * FROM roles WHERE name = $1`
+	err = c.DB.Raw(query, name).Scan(&role).Error
+
+	return role, err
+}
+
+func (c *adminDatabase) FindRolesByIDs(ctx context.Context, roleIDs []uint) (roles []models.Role, err error) {
+
+	query := `SELECT * FROM roles WHERE id IN ? ORDER BY id`
+	err = c.DB.Raw(query, roleIDs).Scan(&roles).Error
+
+	return roles, err
+}
+
+func (c *adminDatabase) SaveRole(ctx context.Context, role models.Role) (roleID uint, err error) {
+
+	query := `INSERT INTO roles (name, description, created_at) VALUES ($1, $2, $3) RETURNING id`
+	createdAt := time.Now()
+	err = c.DB.Raw(query, role.Name, role.Description, createdAt).Scan(&roleID).Error
+
+	return roleID, err
+}
+
+func (c *adminDatabase) UpdateRole(ctx context.Context, role models.Role) error {
+
+	query := `UPDATE roles SET name = $1, description = $2, updated_at = $3 WHERE id = $4`
+	updatedAt := time.Now()
+	err := c.DB.Exec(query, role.Name, role.Description, updatedAt, role.ID).Error
+
+	return err
+}
+
+func (c *adminDatabase) UpdateRoleTwoFactor(ctx context.Context, roleID uint, required bool) error {
+
+	query := `UPDATE roles SET require_two_factor = $1, updated_at = $2 WHERE id = $3`
+	updatedAt := time.Now()
+	err := c.DB.Exec(query, required, updatedAt, roleID).Error
+
+	return err
+}
+
+// permissions and admin roles of the role are removed with it by cascade
+func (c *adminDatabase) DeleteRole(ctx context.Context, roleID uint) error {
+
+	query := `DELETE FROM roles WHERE id = $1`
+	err := c.DB.Exec(query, roleID).Error
+
+	return err
+}
+
+func (c *adminDatabase) SaveRolePermissions(ctx context.Context, roleID uint,
+	permissions []commonConstant.AdminPermission) error {
+
+	query := `INSERT INTO role_permissions (role_id, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	for _, permission := range permissions {
+		if err := c.DB.Exec(query, roleID, permission).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *adminDatabase) DeleteRolePermissions(ctx context.Context, roleID uint) error {
+
+	query := `DELETE FROM role_permissions WHERE role_id = $1`
+	err := c.DB.Exec(query, roleID).Error
+
+	return err
+}
+
+func (c *adminDatabase) FindAdminRoles(ctx context.Context, adminID uint) (roles []models.Role, err error) {
+
+	query := `SELECT r.* FROM roles r 
+	INNER JOIN admin_roles ar ON ar.role_id = r.id 
+	WHERE ar.admin_id = $1 ORDER BY r.id`
+	err = c.DB.Raw(query, adminID).Scan(&roles).Error
+
+	return roles, err
+}
+
+// find the permissions of all the roles of admin
+func (c *adminDatabase) FindAdminPermissions(ctx context.Context,
+	adminID uint) (permissions []commonConstant.AdminPermission, err error) {
+
+	query := `SELECT DISTINCT rp.permission FROM role_permissions rp 
+	INNER JOIN admin_roles ar ON ar.role_id = rp.role_id 
+	WHERE ar.admin_id = $1`
+	err = c.DB.Raw(query, adminID).Scan(&permissions).Error
+
+	return permissions, err
+}
+
+// replace all the roles of admin with the given roles
+func (c *adminDatabase) SetAdminRoles(ctx context.Context, adminID uint, roleIDs []uint) error {
+
+	query := `DELETE FROM admin_roles WHERE admin_id = $1`
+	if err := c.DB.Exec(query, adminID).Error; err != nil {
+		return err
+	}
+
+	query = `INSERT INTO admin_roles (admin_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	for _, roleID := range roleIDs {
+		if err := c.DB.Exec(query, adminID, roleID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *adminDatabase) CountAdminsWithRole(ctx context.Context, roleID uint) (count uint, err error) {
+
+	query := `SELECT COUNT(*) FROM admin_roles WHERE role_id = $1`
+	err = c.DB.Raw(query, roleID).Scan(&count).Error
+
+	return count, err
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/models"
+	"time"
+)
+
+func (c *adminDatabase) FindAdminTwoFactor(ctx context.Context, adminID uint) (twoFactor models.AdminTwoFactor, err error) {
+
+	query := `SELECT * FROM admin_two_factors WHERE admin_id = $1`
+	err = c.DB.Raw(query, adminID).Scan(&twoFactor).Error
+
+	return twoFactor, err
+}
+
+// find two factor is enabled, required by any role of admin and the count of unused recovery codes
+func (c *adminDatabase) FindAdminTwoFactorStatus(ctx context.Context,
+	adminID uint) (status responses.TwoFactorStatus, err error) {
+
+	query := `SELECT
+	EXISTS (SELECT 1 FROM admin_two_factors WHERE admin_id = $1 AND enabled_at IS NOT NULL) AS enabled,
+	EXISTS (SELECT 1 FROM admin_roles ar INNER JOIN roles r ON r.id = ar.role_id
+		WHERE ar.admin_id = $1 AND r.require_two_factor) AS required,
+	(SELECT COUNT(*) FROM admin_recovery_codes WHERE admin_id = $1 AND used_at IS NULL) AS recovery_codes_left`
+	err = c.DB.Raw(query, adminID).Scan(&status).Error
+
+	return status, err
+}
+
+func (c *adminDatabase) SaveAdminTwoFactor(ctx context.Context, twoFactor models.AdminTwoFactor) error {
+
+	query := `INSERT INTO admin_two_factors (admin_id, secret, created_at) VALUES ($1, $2, $3)
+	ON CONFLICT (admin_id) DO UPDATE SET secret = EXCLUDED.secret, enabled_at = NULL, last_used_step = 0,
+	created_at = EXCLUDED.created_at`
+	createdAt := time.Now()
+	err := c.DB.Exec(query, twoFactor.AdminID, twoFactor.Secret, createdAt).Error
+
+	return err
+}
+
+func (c *adminDatabase) EnableAdminTwoFactor(ctx context.Context, adminID uint, usedStep int64) error {
+
+	query := `UPDATE admin_two_factors SET enabled_at = $1, last_used_step = $2 WHERE admin_id = $3`
+	enabledAt := time.Now()
+	err := c.DB.Exec(query, enabledAt, usedStep, adminID).Error
+
+	return err
+}
+
+func (c *adminDatabase) UseAdminTwoFactorStep(ctx context.Context, adminID uint, step int64) (bool, error) {
+
+	query := `UPDATE admin_two_factors SET last_used_step = $1 WHERE admin_id = $2 AND last_used_step < $1`
+	result := c.DB.Exec(query, step, adminID)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (c *adminDatabase) DeleteAdminTwoFactor(ctx context.Context, adminID uint) error {
+
+	query := `DELETE FROM admin_two_factors WHERE admin_id = $1`
+	err := c.DB.Exec(query, adminID).Error
+
+	return err
+}
+
+func (c *adminDatabase) SaveAdminRecoveryCodes(ctx context.Context, adminID uint, codeHashes []string) error {
+
+	if err := c.DeleteAdminRecoveryCodes(ctx, adminID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO admin_recovery_codes (admin_id, code_hash) VALUES ($1, $2)`
+	for _, codeHash := range codeHashes {
+		if err := c.DB.Exec(query, adminID, codeHash).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recovery code is marked as used on the same query so it can't be used twice on concurrent requests
+func (c *adminDatabase) UseAdminRecoveryCode(ctx context.Context, adminID uint, codeHash string) (bool, error) {
+
+	query := `UPDATE admin_recovery_codes SET used_at = $1
+	WHERE admin_id = $2 AND code_hash = $3 AND used_at IS NULL`
+	result := c.DB.Exec(query, time.Now(), adminID, codeHash)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (c *adminDatabase) DeleteAdminRecoveryCodes(ctx context.Context, adminID uint) error {
+
+	query := `DELETE FROM admin_recovery_codes WHERE admin_id = $1`
+	err := c.DB.Exec(query, adminID).Error
+
+	return err
+}
+
+func (c *adminDatabase) SaveAdminLoginChallenge(ctx context.Context, challenge models.AdminLoginChallenge) error {
+
+	query := `INSERT INTO admin_login_challenges (admin_id, token_hash, expire_at, created_at)
+	VALUES ($1, $2, $3, $4)`
+	createdAt := time.Now()
+	err := c.DB.Exec(query, challenge.AdminID, challenge.TokenHash, challenge.ExpireAt, createdAt).Error
+
+	return err
+}
+
+func (c *adminDatabase) FindAdminLoginChallenge(ctx context.Context,
+	tokenHash string) (challenge models.AdminLoginChallenge, err error) {
+
+	query := `SELECT * FROM admin_login_challenges WHERE token_hash = $1`
+	err = c.DB.Raw(query, tokenHash).Scan(&challenge).Error
+
+	return challenge, err
+}
+
+// attempt is counted on the same query so concurrent requests can't exceed the max attempts
+func (c *adminDatabase) AddAdminLoginChallengeAttempt(ctx context.Context, tokenHash string,
+	maxAttempts uint) (bool, error) {
+
+	query := `UPDATE admin_login_challenges SET attempts = attempts + 1 WHERE token_hash = $1 AND attempts < $2`
+	result := c.DB.Exec(query, tokenHash, maxAttempts)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (c *adminDatabase) UseAdminLoginChallenge(ctx context.Context, tokenHash string) (bool, error) {
+
+	query := `UPDATE admin_login_challenges SET used_at = $1
+	WHERE token_hash = $2 AND used_at IS NULL AND expire_at > $1`
+	result := c.DB.Exec(query, time.Now(), tokenHash)
+
+	return result.RowsAffected > 0, result.Error
+}
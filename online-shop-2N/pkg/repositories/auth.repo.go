@@ -2,8 +2,11 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
 	"online-shop-2N/pkg/repositories/interfaces"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -18,6 +21,21 @@ func NewAuthRepository(db *gorm.DB) interfaces.AuthRepository {
 	}
 }
 
+func (c *authDatabase) Transaction(callBack func(trxRepo interfaces.AuthRepository) error) error {
+
+	trx := c.DB.Begin()
+	transactionRepo := NewAuthRepository(trx)
+
+	err := callBack(transactionRepo)
+	if err != nil {
+		trx.Rollback()
+		return fmt.Errorf("failed to complete transaction \nerror:%w", err)
+	}
+
+	err = trx.Commit().Error
+	return err
+}
+
 // FindOtpSession implements interfaces.AuthRepository.
 func (c *authDatabase) FindOtpSession(ctx context.Context, otpID string) (otpSession models.OtpSession, err error) {
 
@@ -40,17 +58,181 @@ func (c *authDatabase) FindRefreshSessionByTokenID(ctx context.Context, tokenID
 // SaveOtpSession implements interfaces.AuthRepository.
 func (c *authDatabase) SaveOtpSession(ctx context.Context, otpSession models.OtpSession) error {
 
-	query := `INSERT INTO otp_sessions (otp_id, user_id, phone ,expire_at) 
-	VALUES ($1, $2, $3, $4)`
-	err := c.DB.Exec(query, otpSession.OtpID, otpSession.UserID, otpSession.Phone, otpSession.ExpireAt).Error
+	query := `INSERT INTO otp_sessions (otp_id, user_id, phone, code_hash, expire_at, created_at) 
+	VALUES ($1, $2, $3, $4, $5, $6)`
+	err := c.DB.Exec(query, otpSession.OtpID, otpSession.UserID, otpSession.Phone, otpSession.CodeHash,
+		otpSession.ExpireAt, otpSession.CreatedAt).Error
 	return err
 }
 
+// FindLastOtpSessionOfUser implements interfaces.AuthRepository.
+func (c *authDatabase) FindLastOtpSessionOfUser(ctx context.Context, userID uint) (otpSession models.OtpSession, err error) {
+
+	query := `SELECT * FROM otp_sessions WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1`
+
+	err = c.DB.Raw(query, userID).Scan(&otpSession).Error
+
+	return otpSession, err
+}
+
+// AddOtpAttempt implements interfaces.AuthRepository.
+// attempt is counted on the same query so concurrent requests can't exceed the max attempts
+func (c *authDatabase) AddOtpAttempt(ctx context.Context, otpID string, maxAttempts uint) (bool, error) {
+
+	query := `UPDATE otp_sessions SET attempts = attempts + 1 WHERE otp_id = $1 AND attempts < $2`
+	result := c.DB.Exec(query, otpID, maxAttempts)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// UseOtpSession implements interfaces.AuthRepository.
+func (c *authDatabase) UseOtpSession(ctx context.Context, otpID string) (bool, error) {
+
+	query := `UPDATE otp_sessions SET used_at = $1 WHERE otp_id = $2 AND used_at IS NULL`
+	result := c.DB.Exec(query, time.Now(), otpID)
+
+	return result.RowsAffected > 0, result.Error
+}
+
 // SaveRefreshSession implements interfaces.AuthRepository.
 func (c *authDatabase) SaveRefreshSession(ctx context.Context, refreshSession models.RefreshSession) error {
-	query := `INSERT INTO refresh_sessions (token_id, user_id, refresh_token, expire_at) 
-VALUES ($1, $2, $3, $4)`
-	err := c.DB.Exec(query, refreshSession.TokenID, refreshSession.UserID, refreshSession.RefreshToken, refreshSession.ExpireAt).Error
+	query := `INSERT INTO refresh_sessions (token_id, user_id, user_type, session_id, refresh_token, expire_at, 
+user_agent, ip_address, created_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	err := c.DB.Exec(query, refreshSession.TokenID, refreshSession.UserID, refreshSession.UserType, refreshSession.SessionID,
+		refreshSession.RefreshToken, refreshSession.ExpireAt, refreshSession.UserAgent, refreshSession.IPAddress,
+		refreshSession.CreatedAt).Error
+
+	return err
+}
+
+// To mark the refresh session as replaced by the new token only if it's not already replaced or blocked
+func (c *authDatabase) ReplaceRefreshSession(ctx context.Context, tokenID, replacedByTokenID string) (bool, error) {
+
+	query := `UPDATE refresh_sessions SET replaced_by_token_id = $2 
+	WHERE token_id = $1 AND replaced_by_token_id = '' AND is_blocked = false`
+	result := c.DB.Exec(query, tokenID, replacedByTokenID)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// To block all refresh tokens of the session (token family) of the user
+func (c *authDatabase) BlockRefreshSession(ctx context.Context, userType string, userID uint, sessionID string) (bool, error) {
+
+	query := `UPDATE refresh_sessions SET is_blocked = true 
+	WHERE user_type = $1 AND user_id = $2 AND session_id = $3 AND is_blocked = false`
+	result := c.DB.Exec(query, userType, userID, sessionID)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (c *authDatabase) BlockAllRefreshSessions(ctx context.Context, userType string, userID uint) error {
+
+	query := `UPDATE refresh_sessions SET is_blocked = true 
+	WHERE user_type = $1 AND user_id = $2 AND is_blocked = false`
+	err := c.DB.Exec(query, userType, userID).Error
+
+	return err
+}
+
+// To find the sessions which have a refresh token not replaced, blocked or expired
+// signed in time is the time of first refresh token of the session
+func (c *authDatabase) FindActiveSessions(ctx context.Context, userType string, userID uint) (sessions []responses.Session, err error) {
+
+	query := `SELECT rs.session_id, rs.user_agent, rs.ip_address, rs.expire_at, rs.created_at AS last_refreshed_at, 
+	(SELECT MIN(f.created_at) FROM refresh_sessions f WHERE f.session_id = rs.session_id) AS signed_in_at 
+	FROM refresh_sessions rs 
+	WHERE rs.user_type = $1 AND rs.user_id = $2 AND rs.replaced_by_token_id = '' 
+	AND rs.is_blocked = false AND rs.expire_at > NOW() 
+	ORDER BY rs.created_at DESC`
+	err = c.DB.Raw(query, userType, userID).Scan(&sessions).Error
+
+	return
+}
+
+// To find the sessions which got tokens after the given time, access tokens of these sessions may not expired yet
+func (c *authDatabase) FindSessionIDsRefreshedAfter(ctx context.Context, userType string, userID uint,
+	after time.Time) (sessionIDs []string, err error) {
+
+	query := `SELECT DISTINCT session_id FROM refresh_sessions 
+	WHERE user_type = $1 AND user_id = $2 AND created_at > $3`
+	err = c.DB.Raw(query, userType, userID, after).Scan(&sessionIDs).Error
+
+	return
+}
+
+// SaveUserToken implements interfaces.AuthRepository.
+func (c *authDatabase) SaveUserToken(ctx context.Context, userToken models.UserToken) error {
+
+	query := `INSERT INTO user_tokens (user_id, purpose, token_hash, expire_at, created_at) 
+	VALUES ($1, $2, $3, $4, $5)`
+	err := c.DB.Exec(query, userToken.UserID, userToken.Purpose, userToken.TokenHash,
+		userToken.ExpireAt, time.Now()).Error
+
+	return err
+}
+
+// UseUserToken implements interfaces.AuthRepository.
+// token is marked as used on the same query so it can't be used twice on concurrent requests
+func (c *authDatabase) UseUserToken(ctx context.Context, tokenHash, purpose string) (userID uint, err error) {
+
+	query := `UPDATE user_tokens SET used_at = $1 
+	WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expire_at > $1 
+	RETURNING user_id`
+	err = c.DB.Raw(query, time.Now(), tokenHash, purpose).Scan(&userID).Error
+
+	return userID, err
+}
+
+// ExpireUserTokens implements interfaces.AuthRepository.
+func (c *authDatabase) ExpireUserTokens(ctx context.Context, userID uint, purpose string) error {
+
+	query := `UPDATE user_tokens SET used_at = $1 WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL`
+	err := c.DB.Exec(query, time.Now(), userID, purpose).Error
+
+	return err
+}
+
+// FindLoginLockout implements interfaces.AuthRepository.
+func (c *authDatabase) FindLoginLockout(ctx context.Context, userType string,
+	userID uint) (lockout models.LoginLockout, err error) {
+
+	query := `SELECT * FROM login_lockouts WHERE user_type = $1 AND user_id = $2`
+	err = c.DB.Raw(query, userType, userID).Scan(&lockout).Error
+
+	return lockout, err
+}
+
+// AddLoginFailure implements interfaces.AuthRepository.
+// failure is counted on the same query so concurrent failures are not lost
+func (c *authDatabase) AddLoginFailure(ctx context.Context, userType string, userID uint,
+	resetBefore time.Time) (failedAttempts uint, err error) {
+
+	query := `INSERT INTO login_lockouts (user_type, user_id, failed_attempts, updated_at) VALUES ($1, $2, 1, $3) 
+	ON CONFLICT (user_type, user_id) DO UPDATE SET 
+	failed_attempts = CASE WHEN login_lockouts.updated_at < $4 THEN 1 ELSE login_lockouts.failed_attempts + 1 END, 
+	updated_at = $3 
+	RETURNING failed_attempts`
+	err = c.DB.Raw(query, userType, userID, time.Now(), resetBefore).Scan(&failedAttempts).Error
+
+	return failedAttempts, err
+}
+
+// LockLogin implements interfaces.AuthRepository.
+func (c *authDatabase) LockLogin(ctx context.Context, userType string, userID uint, lockedUntil time.Time) error {
+
+	query := `UPDATE login_lockouts SET locked_until = $1, failed_attempts = 0, updated_at = $2 
+	WHERE user_type = $3 AND user_id = $4`
+	err := c.DB.Exec(query, lockedUntil, time.Now(), userType, userID).Error
+
+	return err
+}
+
+// ClearLoginFailures implements interfaces.AuthRepository.
+func (c *authDatabase) ClearLoginFailures(ctx context.Context, userType string, userID uint) error {
+
+	query := `DELETE FROM login_lockouts WHERE user_type = $1 AND user_id = $2`
+	err := c.DB.Exec(query, userType, userID).Error
 
 	return err
 }
@@ -1,8 +1,8 @@
 package repositories
 
 import (
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 
 	"gorm.io/gorm"
@@ -40,11 +40,26 @@ func (c *brandDatabase) Update(brand models.Brand) error {
 	return c.DB.Where("id = ?", brand.ID).Updates(&brand).Error
 }
 
-func (c *brandDatabase) FindAll(pagination requests.Pagination) (brands []models.Brand, err error) {
+func (c *brandDatabase) FindAll(page pagination.Request) (brands []models.Brand, meta pagination.Meta, err error) {
 
-	err = c.DB.Limit(int(pagination.Count)).Offset(int(pagination.PageNumber) - 1).Find(&brands).Error
+	var totalCount int64
+	if err := c.DB.Model(&models.Brand{}).Count(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	return
+	after, keys := page.After("id", false, page.Cursor.ID)
+
+	err = c.DB.Where(after, keys...).Order("id").
+		Limit(int(page.Limit())).Offset(int(page.Offset())).Find(&brands).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	brands, meta = pagination.NewPage(page, brands, uint64(totalCount), func(brand models.Brand) pagination.Cursor {
+		return pagination.Cursor{ID: brand.ID}
+	})
+
+	return brands, meta, nil
 }
 
 func (c *brandDatabase) FindOne(brandID uint) (brand models.Brand, err error) {
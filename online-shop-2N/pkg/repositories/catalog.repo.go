@@ -0,0 +1,346 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"online-shop-2N/pkg/catalog"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type catalogDatabase struct {
+	DB *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) interfaces.CatalogRepository {
+	return &catalogDatabase{
+		DB: db,
+	}
+}
+
+func (c *catalogDatabase) Transactions(ctx context.Context, trxFn func(repo interfaces.CatalogRepository) error) error {
+
+	trx := c.DB.Begin()
+
+	repo := NewCatalogRepository(trx)
+
+	if err := trxFn(repo); err != nil {
+		trx.Rollback()
+		return err
+	}
+
+	if err := trx.Commit().Error; err != nil {
+		trx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// save a new import job with the data of file
+func (c *catalogDatabase) SaveImportJob(ctx context.Context,
+	job models.CatalogImportJob) (models.CatalogImportJob, error) {
+
+	job.CreatedAt = time.Now()
+	query := `INSERT INTO catalog_import_jobs (admin_id, file_name, format, status, data, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	err := c.DB.Raw(query, job.AdminID, job.FileName, job.Format, job.Status, job.Data,
+		job.CreatedAt).Scan(&job.ID).Error
+
+	return job, err
+}
+
+// find import job without the data of file
+func (c *catalogDatabase) FindImportJobByID(ctx context.Context, jobID uint) (job models.CatalogImportJob, err error) {
+
+	query := `SELECT id, admin_id, file_name, format, status, total_rows, created_rows, updated_rows, failed_rows,
+	error, created_at, started_at, finished_at
+	FROM catalog_import_jobs WHERE id = $1`
+	err = c.DB.Raw(query, jobID).Scan(&job).Error
+
+	return
+}
+
+// To take the oldest pending job and change it to running, skip locked is used so a job is only taken by one worker.
+// a running job started before the running before time is taken again as its worker is stopped without finishing it.
+// job id is zero when there is no pending job
+func (c *catalogDatabase) ClaimPendingImportJob(ctx context.Context,
+	startedAt, runningBefore time.Time) (job models.CatalogImportJob, err error) {
+
+	query := `UPDATE catalog_import_jobs SET status = $1, started_at = $2
+	WHERE id = (
+		SELECT id FROM catalog_import_jobs
+		WHERE status = $3 OR (status = $1 AND started_at < $4)
+		ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, admin_id, file_name, format, status, data, created_at, started_at`
+
+	err = c.DB.Raw(query, commonConstant.CatalogImportRunning, startedAt,
+		commonConstant.CatalogImportPending, runningBefore).Scan(&job).Error
+
+	return
+}
+
+// save the result of job and remove the data of file
+func (c *catalogDatabase) FinishImportJob(ctx context.Context, job models.CatalogImportJob) error {
+
+	query := `UPDATE catalog_import_jobs SET status = $1, total_rows = $2, created_rows = $3, updated_rows = $4,
+	failed_rows = $5, error = $6, finished_at = $7, data = NULL
+	WHERE id = $8`
+	err := c.DB.Exec(query, job.Status, job.TotalRows, job.CreatedRows, job.UpdatedRows,
+		job.FailedRows, job.Error, job.FinishedAt, job.ID).Error
+
+	return err
+}
+
+// change the running job back to pending so it is taken again from start by the next run
+func (c *catalogDatabase) ReleaseImportJob(ctx context.Context, jobID uint) error {
+
+	query := `UPDATE catalog_import_jobs SET status = $1, started_at = NULL WHERE id = $2 AND status = $3`
+	err := c.DB.Exec(query, commonConstant.CatalogImportPending, jobID, commonConstant.CatalogImportRunning).Error
+
+	return err
+}
+
+func (c *catalogDatabase) SaveImportError(ctx context.Context, importError models.CatalogImportError) error {
+
+	query := `INSERT INTO catalog_import_errors (job_id, row_number, sku, message) VALUES ($1, $2, $3, $4)`
+	err := c.DB.Exec(query, importError.JobID, importError.RowNumber, importError.SKU, importError.Message).Error
+
+	return err
+}
+
+// remove the errors of a previous run of the job
+func (c *catalogDatabase) DeleteAllImportErrors(ctx context.Context, jobID uint) error {
+
+	query := `DELETE FROM catalog_import_errors WHERE job_id = $1`
+	err := c.DB.Exec(query, jobID).Error
+
+	return err
+}
+
+// find the errors of a job in the order of rows
+func (c *catalogDatabase) FindAllImportErrors(ctx context.Context, jobID uint,
+	page pagination.Request) (importErrors []models.CatalogImportError, meta pagination.Meta, err error) {
+
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM catalog_import_errors WHERE job_id = ?`
+	if err := c.DB.Raw(query, jobID).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
+
+	after, keys := page.After("id", false, page.Cursor.ID)
+
+	query = `SELECT id, job_id, row_number, sku, message FROM catalog_import_errors
+	WHERE job_id = ? AND ` + after + `
+	ORDER BY id LIMIT ? OFFSET ?`
+
+	args := append([]interface{}{jobID}, keys...)
+	err = c.DB.Raw(query, append(args, page.Limit(), page.Offset())...).Scan(&importErrors).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	importErrors, meta = pagination.NewPage(page, importErrors, totalCount,
+		func(importError models.CatalogImportError) pagination.Cursor {
+			return pagination.Cursor{ID: importError.ID}
+		})
+
+	return importErrors, meta, nil
+}
+
+// find all sub categories with the name of their main category
+func (c *catalogDatabase) FindAllSubCategories(ctx context.Context) (categories []catalog.Category, err error) {
+
+	query := `SELECT sc.id, sc.name, mc.name AS main_category_name
+	FROM categories sc
+	INNER JOIN categories mc ON mc.id = sc.category_id`
+	err = c.DB.Raw(query).Scan(&categories).Error
+
+	return
+}
+
+func (c *catalogDatabase) FindAllBrands(ctx context.Context) (brands []catalog.Brand, err error) {
+
+	query := `SELECT id, name FROM brands`
+	err = c.DB.Raw(query).Scan(&brands).Error
+
+	return
+}
+
+// find all variations of categories with their options, variations without options are also found
+func (c *catalogDatabase) FindAllVariationOptions(ctx context.Context) (variationOptions []catalog.VariationOption, err error) {
+
+	query := `SELECT v.category_id, v.id AS variation_id, v.name AS variation_name,
+	COALESCE(vo.id, 0) AS id, COALESCE(vo.value, '') AS value
+	FROM variations v
+	LEFT JOIN variation_options vo ON vo.variation_id = v.id`
+	err = c.DB.Raw(query).Scan(&variationOptions).Error
+
+	return
+}
+
+func (c *catalogDatabase) FindProductByName(ctx context.Context, name string) (product models.Product, err error) {
+
+	query := `SELECT id, name, description, category_id, brand_id, price FROM products WHERE name = $1`
+	err = c.DB.Raw(query, name).Scan(&product).Error
+
+	return
+}
+
+// save product and return its id, image of product is empty until it's added by admin
+func (c *catalogDatabase) SaveProduct(ctx context.Context, product models.Product) (productID uint, err error) {
+
+	query := `INSERT INTO products (name, description, category_id, brand_id, price, image, created_at)
+	VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	createdAt := time.Now()
+	err = c.DB.Raw(query, product.Name, product.Description, product.CategoryID, product.BrandID,
+		product.Price, product.Image, createdAt).Scan(&productID).Error
+
+	return
+}
+
+func (c *catalogDatabase) UpdateProductDescription(ctx context.Context, productID uint, description string) error {
+
+	query := `UPDATE products SET description = $1, updated_at = $2 WHERE id = $3`
+
+	updatedAt := time.Now()
+	err := c.DB.Exec(query, description, updatedAt, productID).Error
+
+	return err
+}
+
+func (c *catalogDatabase) FindProductItemBySKU(ctx context.Context, sku string) (productItem models.ProductItem, err error) {
+
+	query := `SELECT id, product_id, qty_in_stock, price, sku FROM product_items WHERE sku = $1`
+	err = c.DB.Raw(query, sku).Scan(&productItem).Error
+
+	return
+}
+
+func (c *catalogDatabase) FindVariationOptionIDsOfProductItem(ctx context.Context,
+	productItemID uint) (variationOptionIDs []uint, err error) {
+
+	query := `SELECT variation_option_id FROM product_configurations
+	WHERE product_item_id = $1 ORDER BY variation_option_id`
+	err = c.DB.Raw(query, productItemID).Scan(&variationOptionIDs).Error
+
+	return
+}
+
+// To check a product item of the product already have all the given variation options
+func (c *catalogDatabase) IsProductItemConfigurationExist(ctx context.Context, productID uint,
+	variationOptionIDs []uint) (exist bool, err error) {
+
+	query := `SELECT EXISTS(
+		SELECT 1 FROM product_items pi
+		INNER JOIN product_configurations pc ON pc.product_item_id = pi.id
+		WHERE pi.product_id = ? AND pc.variation_option_id IN ?
+		GROUP BY pi.id HAVING COUNT(pc.variation_option_id) = ?
+	)`
+	err = c.DB.Raw(query, productID, variationOptionIDs, len(variationOptionIDs)).Scan(&exist).Error
+
+	return
+}
+
+func (c *catalogDatabase) SaveProductItem(ctx context.Context,
+	productItem models.ProductItem) (productItemID uint, err error) {
+
+	query := `INSERT INTO product_items (product_id, qty_in_stock, price, sku, created_at) VALUES($1, $2, $3, $4, $5)
+	RETURNING id`
+	createdAt := time.Now()
+	err = c.DB.Raw(query, productItem.ProductID, productItem.QtyInStock, productItem.Price, productItem.SKU, createdAt).
+		Scan(&productItemID).Error
+
+	return
+}
+
+func (c *catalogDatabase) SaveProductConfiguration(ctx context.Context, productItemID, variationOptionID uint) error {
+
+	query := `INSERT INTO product_configurations (product_item_id, variation_option_id) VALUES ($1, $2)`
+	err := c.DB.Exec(query, productItemID, variationOptionID).Error
+
+	return err
+}
+
+// To update the price and stock of product item, discount price is calculated again from the new price
+// with the offer running on the product (offer of product before the offer of category) or reset when no offer
+func (c *catalogDatabase) UpdateProductItem(ctx context.Context, productItemID, price, qtyInStock uint) error {
+
+	query := `UPDATE product_items pi SET price = $1, qty_in_stock = $2, updated_at = $3,
+	discount_price = COALESCE((
+		SELECT ($1 * (100 - o.discount_rate))/100
+		FROM (
+			SELECT op.offer_id, 1 AS priority
+			FROM offer_products op WHERE op.product_id = pi.product_id
+			UNION ALL
+			SELECT oc.offer_id, 2 AS priority
+			FROM offer_categories oc INNER JOIN products p ON p.category_id = oc.category_id
+			WHERE p.id = pi.product_id
+		) po
+		INNER JOIN offers o ON o.id = po.offer_id
+		WHERE o.start_date <= NOW() AND o.end_date > NOW()
+		ORDER BY po.priority, o.discount_rate DESC
+		LIMIT 1
+	), 0)
+	WHERE pi.id = $4`
+
+	updatedAt := time.Now()
+	err := c.DB.Exec(query, price, qtyInStock, updatedAt, productItemID).Error
+
+	return err
+}
+
+// To find all product items of catalog as rows and call the export func for each row,
+// rows are read one by one from database so the full catalog is not kept on memory
+func (c *catalogDatabase) ExportCatalog(ctx context.Context, exportFn func(row catalog.Row) error) error {
+
+	query := `SELECT pi.sku, p.name, p.description, COALESCE(mc.name, ''), sc.name, b.name,
+	pi.price, pi.qty_in_stock,
+	COALESCE((
+		SELECT json_object_agg(v.name, vo.value) FROM product_configurations pc
+		INNER JOIN variation_options vo ON vo.id = pc.variation_option_id
+		INNER JOIN variations v ON v.id = vo.variation_id
+		WHERE pc.product_item_id = pi.id
+	), '{}')
+	FROM product_items pi
+	INNER JOIN products p ON p.id = pi.product_id
+	INNER JOIN categories sc ON sc.id = p.category_id
+	LEFT JOIN categories mc ON mc.id = sc.category_id
+	INNER JOIN brands b ON b.id = p.brand_id
+	ORDER BY p.id, pi.id`
+
+	rows, err := c.DB.WithContext(ctx).Raw(query).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var (
+			row              catalog.Row
+			variationOptions []byte
+		)
+		err = rows.Scan(&row.SKU, &row.ProductName, &row.Description, &row.MainCategory, &row.Category,
+			&row.Brand, &row.Price, &row.QtyInStock, &variationOptions)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(variationOptions, &row.VariationOptions); err != nil {
+			return err
+		}
+
+		if err = exportFn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -2,8 +2,8 @@ package repositories
 
 import (
 	"context"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 
 	"gorm.io/gorm"
@@ -75,16 +75,28 @@ func (c *categoryDatabase) SaveSubCategory(ctx context.Context, categoryID uint,
 
 // Find all main category(its not have a category_id)
 func (c *categoryDatabase) FindAllMainCategories(ctx context.Context,
-	pagination requests.Pagination) (categories []responses.Category, err error) {
+	page pagination.Request) (categories []responses.Category, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM categories WHERE category_id IS NULL`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	query := `SELECT id, name FROM categories WHERE category_id IS NULL 
-	LIMIT $1 OFFSET $2`
-	err = c.DB.Raw(query, limit, offset).Scan(&categories).Error
+	after, keys := page.After("id", false, page.Cursor.ID)
 
-	return
+	query = `SELECT id, name FROM categories WHERE category_id IS NULL AND ` + after + ` 
+	ORDER BY id LIMIT ? OFFSET ?`
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&categories).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	categories, meta = pagination.NewPage(page, categories, totalCount, func(category responses.Category) pagination.Cursor {
+		return pagination.Cursor{ID: category.ID}
+	})
+
+	return categories, meta, nil
 }
 
 // Find all sub categories of a category
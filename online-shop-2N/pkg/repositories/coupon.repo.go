@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 	"time"
 
@@ -68,17 +68,28 @@ func (c *couponDatabase) FindCouponByName(ctx context.Context, couponName string
 	return coupon, nil
 }
 
-func (c *couponDatabase) FindAllCoupons(ctx context.Context, pagination requests.Pagination) (coupons []models.Coupon, err error) {
+func (c *couponDatabase) FindAllCoupons(ctx context.Context,
+	page pagination.Request) (coupons []models.Coupon, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(coupon_id) FROM coupons`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, errors.New("faild to find coupon count")
+	}
+
+	after, keys := page.After("created_at, coupon_id", true, page.Cursor.Time, page.Cursor.ID)
 
-	query := `SELECT * FROM coupons ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	err = c.DB.Raw(query, limit, offset).Scan(&coupons).Error
+	query = `SELECT * FROM coupons WHERE ` + after + ` ORDER BY created_at DESC, coupon_id DESC LIMIT ? OFFSET ?`
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&coupons).Error
 	if err != nil {
-		return coupons, errors.New("faild to find coupon")
+		return coupons, meta, errors.New("faild to find coupon")
 	}
-	return coupons, nil
+
+	coupons, meta = pagination.NewPage(page, coupons, totalCount, func(coupon models.Coupon) pagination.Cursor {
+		return pagination.Cursor{Time: coupon.CreatedAt, ID: coupon.CouponID}
+	})
+
+	return coupons, meta, nil
 }
 
 // save a new coupon
@@ -140,22 +151,40 @@ func (c *couponDatabase) SaveCouponUses(ctx context.Context, couponUses models.C
 
 // find all coupons for user
 
-func (c *couponDatabase) FindAllCouponForUser(ctx context.Context, userID uint, pagination requests.Pagination) (coupons []responses.UserCoupon, err error) {
+func (c *couponDatabase) FindAllCouponForUser(ctx context.Context, userID uint,
+	page pagination.Request) (coupons []responses.UserCoupon, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(c.coupon_id) FROM coupons c 
+	LEFT JOIN coupon_uses cu ON c.coupon_id = cu.coupon_id AND cu.user_id = $1`
+	if err := c.DB.Raw(query, userID).Scan(&totalCount).Error; err != nil {
+		return coupons, meta, fmt.Errorf("faild to find coupon count for user \n %v", err.Error())
+	}
+
+	// coupons which are not used by user first
+	after, keys := page.After("(cu.coupon_id IS NOT NULL)::int, c.coupon_id", false, page.Cursor.Number, page.Cursor.ID)
 
-	query := `SELECT c.coupon_id, c.coupon_code, c.coupon_name, c.expire_date, c.description, c.discount_rate, c.minimum_cart_price, 
-	c.image, c.block_status, c.coupon_id = cu.coupon_id AS used, cu.used_at FROM coupons c 
+	query = `SELECT c.coupon_id, c.coupon_code, c.coupon_name, c.expire_date, c.description, c.discount_rate, c.minimum_cart_price, 
+	c.image, c.block_status, cu.coupon_id IS NOT NULL AS used, cu.used_at FROM coupons c 
 	LEFT JOIN coupon_uses cu ON c.coupon_id = cu.coupon_id 
-	AND cu.user_id = $1 
-	ORDER BY used DESC LIMIT $2 OFFSET $3`
+	AND cu.user_id = ? 
+	WHERE ` + after + ` 
+	ORDER BY used, c.coupon_id LIMIT ? OFFSET ?`
 
-	err = c.DB.Raw(query, userID, limit, offset).Scan(&coupons).Error
+	values := append([]interface{}{userID}, keys...)
+	err = c.DB.Raw(query, append(values, page.Limit(), page.Offset())...).Scan(&coupons).Error
 
 	if err != nil {
-		return coupons, fmt.Errorf("faild to find coupons for user \n %v", err.Error())
+		return coupons, meta, fmt.Errorf("faild to find coupons for user \n %v", err.Error())
 	}
 
-	return coupons, nil
+	coupons, meta = pagination.NewPage(page, coupons, totalCount, func(coupon responses.UserCoupon) pagination.Cursor {
+		cursor := pagination.Cursor{ID: coupon.CouponID}
+		if coupon.Used {
+			cursor.Number = 1
+		}
+		return cursor
+	})
+
+	return coupons, meta, nil
 }
@@ -4,17 +4,62 @@ import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type AdminRepository interface {
+	Transaction(callBack func(trxRepo AdminRepository) error) error
+
+	FindAdminByID(ctx context.Context, adminID uint) (models.Admin, error)
 	FindAdminByEmail(ctx context.Context, email string) (models.Admin, error)
 	FindAdminByUserName(ctx context.Context, userName string) (models.Admin, error)
-	SaveAdmin(ctx context.Context, admin models.Admin) error
+	SaveAdmin(ctx context.Context, admin models.Admin) (adminID uint, err error)
+	FindAllAdmins(ctx context.Context, page pagination.Request) ([]responses.Admin, pagination.Meta, error)
+
+	// role
+	FindAllRoles(ctx context.Context) ([]responses.Role, error)
+	FindRoleByID(ctx context.Context, roleID uint) (models.Role, error)
+	FindRoleByName(ctx context.Context, name string) (models.Role, error)
+	FindRolesByIDs(ctx context.Context, roleIDs []uint) ([]models.Role, error)
+	SaveRole(ctx context.Context, role models.Role) (roleID uint, err error)
+	UpdateRole(ctx context.Context, role models.Role) error
+	UpdateRoleTwoFactor(ctx context.Context, roleID uint, required bool) error
+	DeleteRole(ctx context.Context, roleID uint) error
+	SaveRolePermissions(ctx context.Context, roleID uint, permissions []commonConstant.AdminPermission) error
+	DeleteRolePermissions(ctx context.Context, roleID uint) error
+
+	// roles of admin
+	FindAdminRoles(ctx context.Context, adminID uint) ([]models.Role, error)
+	FindAdminPermissions(ctx context.Context, adminID uint) ([]commonConstant.AdminPermission, error)
+	SetAdminRoles(ctx context.Context, adminID uint, roleIDs []uint) error
+	CountAdminsWithRole(ctx context.Context, roleID uint) (count uint, err error)
+
+	// two factor authentication
+	FindAdminTwoFactor(ctx context.Context, adminID uint) (models.AdminTwoFactor, error)
+	FindAdminTwoFactorStatus(ctx context.Context, adminID uint) (responses.TwoFactorStatus, error)
+	// To save a new not enabled secret of admin, the previous secret is replaced
+	SaveAdminTwoFactor(ctx context.Context, twoFactor models.AdminTwoFactor) error
+	EnableAdminTwoFactor(ctx context.Context, adminID uint, usedStep int64) error
+	// To set the step as last used step only if it's after the last used step, so a code can't be used twice
+	UseAdminTwoFactorStep(ctx context.Context, adminID uint, step int64) (used bool, err error)
+	DeleteAdminTwoFactor(ctx context.Context, adminID uint) error
+	// To replace all recovery codes of admin with the new codes
+	SaveAdminRecoveryCodes(ctx context.Context, adminID uint, codeHashes []string) error
+	UseAdminRecoveryCode(ctx context.Context, adminID uint, codeHash string) (used bool, err error)
+	DeleteAdminRecoveryCodes(ctx context.Context, adminID uint) error
+
+	// sign in challenges of two factor authentication
+	SaveAdminLoginChallenge(ctx context.Context, challenge models.AdminLoginChallenge) error
+	FindAdminLoginChallenge(ctx context.Context, tokenHash string) (models.AdminLoginChallenge, error)
+	// To count a verify attempt of the challenge only if attempts are less than max attempts
+	AddAdminLoginChallengeAttempt(ctx context.Context, tokenHash string, maxAttempts uint) (added bool, err error)
+	UseAdminLoginChallenge(ctx context.Context, tokenHash string) (used bool, err error)
 
-	FindAllUser(ctx context.Context, pagination requests.Pagination) (users []responses.User, err error)
+	FindAllUser(ctx context.Context, page pagination.Request) (users []responses.User, meta pagination.Meta, err error)
 
-	CreateFullSalesReport(ctc context.Context, reqData requests.SalesReport) (salesReport []responses.SalesReport, err error)
+	CreateFullSalesReport(ctc context.Context, reqData requests.SalesReport) (salesReport []responses.SalesReport, meta pagination.Meta, err error)
 
 	//stock side
 	FindStockBySKU(ctx context.Context, sku string) (stock responses.Stock, err error)
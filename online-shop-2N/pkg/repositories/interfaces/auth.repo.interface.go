@@ -2,13 +2,42 @@ package interfaces
 
 import (
 	"context"
+	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"time"
 )
 
 type AuthRepository interface {
+	Transaction(callBack func(trxRepo AuthRepository) error) error
+
 	SaveRefreshSession(ctx context.Context, refreshSession models.RefreshSession) error
 	FindRefreshSessionByTokenID(ctx context.Context, tokenID string) (models.RefreshSession, error)
+	ReplaceRefreshSession(ctx context.Context, tokenID, replacedByTokenID string) (replaced bool, err error)
+	BlockRefreshSession(ctx context.Context, userType string, userID uint, sessionID string) (blocked bool, err error)
+	BlockAllRefreshSessions(ctx context.Context, userType string, userID uint) error
+	FindActiveSessions(ctx context.Context, userType string, userID uint) ([]responses.Session, error)
+	FindSessionIDsRefreshedAfter(ctx context.Context, userType string, userID uint, after time.Time) ([]string, error)
 
 	SaveOtpSession(ctx context.Context, otpSession models.OtpSession) error
 	FindOtpSession(ctx context.Context, otpID string) (models.OtpSession, error)
+	FindLastOtpSessionOfUser(ctx context.Context, userID uint) (models.OtpSession, error)
+	// To count a verify attempt of the otp session only if attempts are less than max attempts
+	AddOtpAttempt(ctx context.Context, otpID string, maxAttempts uint) (added bool, err error)
+	// To mark the otp session as used only if it's not used already
+	UseOtpSession(ctx context.Context, otpID string) (used bool, err error)
+
+	// failed password attempts
+	FindLoginLockout(ctx context.Context, userType string, userID uint) (models.LoginLockout, error)
+	// To count the failed attempt and return the failed attempts count,
+	// count starts again when the last failure is before the given time
+	AddLoginFailure(ctx context.Context, userType string, userID uint, resetBefore time.Time) (failedAttempts uint, err error)
+	LockLogin(ctx context.Context, userType string, userID uint, lockedUntil time.Time) error
+	ClearLoginFailures(ctx context.Context, userType string, userID uint) error
+
+	// single use tokens sent by mail
+	SaveUserToken(ctx context.Context, userToken models.UserToken) error
+	// To mark the unused and not expired token as used and find its user, user id is zero when no such token
+	UseUserToken(ctx context.Context, tokenHash, purpose string) (userID uint, err error)
+	// To mark all the unused tokens of user for the purpose as used
+	ExpireUserTokens(ctx context.Context, userID uint, purpose string) error
 }
@@ -1,15 +1,15 @@
 package interfaces
 
 import (
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type BrandRepository interface {
 	IsExist(brand models.Brand) (bool, error)
 	Save(brand models.Brand) (models.Brand, error)
 	Update(brand models.Brand) error
-	FindAll(pagination requests.Pagination) ([]models.Brand, error)
+	FindAll(page pagination.Request) ([]models.Brand, pagination.Meta, error)
 	FindOne(brandID uint) (models.Brand, error)
 	Delete(brandID uint) error
 }
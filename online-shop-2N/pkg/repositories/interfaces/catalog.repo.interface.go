@@ -0,0 +1,43 @@
+package interfaces
+
+import (
+	"context"
+	"online-shop-2N/pkg/catalog"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"time"
+)
+
+type CatalogRepository interface {
+	Transactions(ctx context.Context, trxFn func(repo CatalogRepository) error) error
+
+	// import jobs
+	SaveImportJob(ctx context.Context, job models.CatalogImportJob) (models.CatalogImportJob, error)
+	FindImportJobByID(ctx context.Context, jobID uint) (models.CatalogImportJob, error)
+	ClaimPendingImportJob(ctx context.Context, startedAt, runningBefore time.Time) (models.CatalogImportJob, error)
+	FinishImportJob(ctx context.Context, job models.CatalogImportJob) error
+	ReleaseImportJob(ctx context.Context, jobID uint) error
+	SaveImportError(ctx context.Context, importError models.CatalogImportError) error
+	DeleteAllImportErrors(ctx context.Context, jobID uint) error
+	FindAllImportErrors(ctx context.Context, jobID uint,
+		page pagination.Request) ([]models.CatalogImportError, pagination.Meta, error)
+
+	// lookup of rows
+	FindAllSubCategories(ctx context.Context) ([]catalog.Category, error)
+	FindAllBrands(ctx context.Context) ([]catalog.Brand, error)
+	FindAllVariationOptions(ctx context.Context) ([]catalog.VariationOption, error)
+
+	// upsert of rows
+	FindProductByName(ctx context.Context, name string) (models.Product, error)
+	SaveProduct(ctx context.Context, product models.Product) (productID uint, err error)
+	UpdateProductDescription(ctx context.Context, productID uint, description string) error
+	FindProductItemBySKU(ctx context.Context, sku string) (models.ProductItem, error)
+	FindVariationOptionIDsOfProductItem(ctx context.Context, productItemID uint) ([]uint, error)
+	IsProductItemConfigurationExist(ctx context.Context, productID uint, variationOptionIDs []uint) (bool, error)
+	SaveProductItem(ctx context.Context, productItem models.ProductItem) (productItemID uint, err error)
+	SaveProductConfiguration(ctx context.Context, productItemID, variationOptionID uint) error
+	UpdateProductItem(ctx context.Context, productItemID, price, qtyInStock uint) error
+
+	// export
+	ExportCatalog(ctx context.Context, exportFn func(row catalog.Row) error) error
+}
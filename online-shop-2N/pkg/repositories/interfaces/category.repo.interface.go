@@ -2,8 +2,8 @@ package interfaces
 
 import (
 	"context"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 )
 
 type CategoryRepository interface {
@@ -11,7 +11,7 @@ type CategoryRepository interface {
 
 	// category
 	IsCategoryNameExist(ctx context.Context, categoryName string) (bool, error)
-	FindAllMainCategories(ctx context.Context, pagination requests.Pagination) ([]responses.Category, error)
+	FindAllMainCategories(ctx context.Context, page pagination.Request) ([]responses.Category, pagination.Meta, error)
 	SaveCategory(ctx context.Context, categoryName string) error
 
 	// sub category
@@ -2,9 +2,9 @@ package interfaces
 
 import (
 	"context"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type CouponRepository interface {
@@ -14,7 +14,7 @@ type CouponRepository interface {
 	FindCouponByCouponCode(ctx context.Context, couponCode string) (coupon models.Coupon, err error)
 	FindCouponByName(ctx context.Context, couponName string) (coupon models.Coupon, err error)
 
-	FindAllCoupons(ctx context.Context, pagination requests.Pagination) (coupons []models.Coupon, err error)
+	FindAllCoupons(ctx context.Context, page pagination.Request) (coupons []models.Coupon, meta pagination.Meta, err error)
 	SaveCoupon(ctx context.Context, coupon models.Coupon) error
 	UpdateCoupon(ctx context.Context, coupon models.Coupon) error
 
@@ -23,5 +23,5 @@ type CouponRepository interface {
 	SaveCouponUses(ctx context.Context, couponUses models.CouponUses) error
 
 	// find all coupon for user
-	FindAllCouponForUser(ctx context.Context, userID uint, pagination requests.Pagination) (coupons []responses.UserCoupon, err error)
+	FindAllCouponForUser(ctx context.Context, userID uint, page pagination.Request) (coupons []responses.UserCoupon, meta pagination.Meta, err error)
 }
@@ -5,6 +5,7 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type OfferRepository interface {
@@ -13,7 +14,7 @@ type OfferRepository interface {
 	// offer
 	FindOfferByID(ctx context.Context, offerID uint) (models.Offer, error)
 	FindOfferByName(ctx context.Context, offerName string) (models.Offer, error)
-	FindAllOffers(ctx context.Context, pagination requests.Pagination) ([]models.Offer, error)
+	FindAllOffers(ctx context.Context, page pagination.Request) ([]models.Offer, pagination.Meta, error)
 	SaveOffer(ctx context.Context, offer requests.Offer) error
 	DeleteOffer(ctx context.Context, offerID uint) error
 
@@ -31,7 +32,7 @@ type OfferRepository interface {
 
 	// offer category
 	FindOfferCategoryCategoryID(ctx context.Context, categoryID uint) (models.OfferCategory, error)
-	FindAllOfferCategories(ctx context.Context, pagination requests.Pagination) ([]responses.OfferCategory, error)
+	FindAllOfferCategories(ctx context.Context, page pagination.Request) ([]responses.OfferCategory, pagination.Meta, error)
 
 	SaveCategoryOffer(ctx context.Context, categoryOffer requests.OfferCategory) (categoryOfferID uint, err error)
 	DeleteCategoryOffer(ctx context.Context, categoryOfferID uint) error
@@ -39,7 +40,7 @@ type OfferRepository interface {
 
 	// offer products
 	FindOfferProductByProductID(ctx context.Context, productID uint) (models.OfferProduct, error)
-	FindAllOfferProducts(ctx context.Context, pagination requests.Pagination) ([]responses.OfferProduct, error)
+	FindAllOfferProducts(ctx context.Context, page pagination.Request) ([]responses.OfferProduct, pagination.Meta, error)
 
 	SaveOfferProduct(ctx context.Context, offerProduct models.OfferProduct) (productOfferId uint, err error)
 	DeleteOfferProduct(ctx context.Context, productOfferID uint) error
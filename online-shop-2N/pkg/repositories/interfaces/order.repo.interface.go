@@ -2,29 +2,37 @@ package interfaces
 
 import (
 	"context"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	commonConstant "online-shop-2N/pkg/common/constants"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"time"
 )
 
 type OrderRepository interface {
 	Transaction(callBack func(transactionRepo OrderRepository) error) error
 
 	SaveOrderLine(ctx context.Context, orderLine models.OrderLine) error
+	RestoreOrderLineQuantities(ctx context.Context, shopOrderID uint) error
+	FindOrderLinesByShopOrderID(ctx context.Context, shopOrderID uint) ([]models.OrderLine, error)
+	AddOrderLineReturnedQty(ctx context.Context, orderLineID, qty, refundAmount uint) error
+	AddOrderLineCancelledQty(ctx context.Context, orderLineID, qty, refundAmount uint) error
+	RestockOrderLineQty(ctx context.Context, orderLineID, qty uint) error
 
 	UpdateShopOrderOrderStatus(ctx context.Context, shopOrderID, changeStatusID uint) error
-	UpdateShopOrderStatusAndSavePaymentMethod(ctx context.Context, shopOrderID, orderStatusID, paymentID uint) error
+	UpdateShopOrderPaymentMethod(ctx context.Context, shopOrderID, paymentID uint) error
 
 	// shop order order
 	SaveShopOrder(ctx context.Context, shopOrder models.ShopOrder) (shopOrderID uint, err error)
 	FindShopOrderByShopOrderID(ctx context.Context, shopOrderID uint) (models.ShopOrder, error)
-	FindAllShopOrders(ctx context.Context, pagination requests.Pagination) (shopOrders []responses.ShopOrder, err error)
-	FindAllShopOrdersByUserID(ctx context.Context, userID uint, pagination requests.Pagination) ([]responses.ShopOrder, error)
+	FindShopOrderByShopOrderIDForUpdate(ctx context.Context, shopOrderID uint) (models.ShopOrder, error)
+	FindShopOrderIDsByStatusIDsBefore(ctx context.Context, orderStatusIDs []uint, before time.Time) ([]uint, error)
+	FindAllShopOrders(ctx context.Context, page pagination.Request) (shopOrders []responses.ShopOrder, meta pagination.Meta, err error)
+	FindAllShopOrdersByUserID(ctx context.Context, userID uint, page pagination.Request) ([]responses.ShopOrder, pagination.Meta, error)
 
 	// find shop order items
 	FindAllOrdersItemsByShopOrderID(ctx context.Context,
-		shopOrderID uint, pagination requests.Pagination) (orderItems []responses.OrderItem, err error)
+		shopOrderID uint, page pagination.Request) (orderItems []responses.OrderItem, meta pagination.Meta, err error)
 
 	// order status
 	FindOrderStatusByShopOrderID(ctx context.Context, shopOrderID uint) (models.OrderStatus, error)
@@ -32,20 +40,56 @@ type OrderRepository interface {
 	FindOrderStatusByStatus(ctx context.Context, orderStatus commonConstant.OrderStatusType) (models.OrderStatus, error)
 	FindAllOrderStatuses(ctx context.Context) ([]models.OrderStatus, error)
 
+	// order status history
+	SaveOrderStatusHistory(ctx context.Context, history models.OrderStatusHistory) error
+	FindOrderStatusHistoryByShopOrderID(ctx context.Context, shopOrderID uint) ([]responses.OrderStatusHistory, error)
+
 	//order return
 	FindOrderReturnByReturnID(ctx context.Context, orderReturnID uint) (models.OrderReturn, error)
 	FindOrderReturnByShopOrderID(ctx context.Context, shopOrderID uint) (orderReturn models.OrderReturn, err error)
-	FindAllOrderReturns(ctx context.Context, pagination requests.Pagination) ([]responses.OrderReturn, error)
-	FindAllPendingOrderReturns(ctx context.Context, pagination requests.Pagination) ([]responses.OrderReturn, error)
-	SaveOrderReturn(ctx context.Context, orderReturn models.OrderReturn) error
+	FindAllOrderReturns(ctx context.Context, page pagination.Request) ([]responses.OrderReturn, pagination.Meta, error)
+	FindAllPendingOrderReturns(ctx context.Context, page pagination.Request) ([]responses.OrderReturn, pagination.Meta, error)
+	SaveOrderReturn(ctx context.Context, orderReturn models.OrderReturn) (orderReturnID uint, err error)
 	UpdateOrderReturn(ctx context.Context, orderReturn models.OrderReturn) error
+	SaveOrderReturnLine(ctx context.Context, returnLine models.OrderReturnLine) error
+	FindOrderReturnLinesByReturnID(ctx context.Context, orderReturnID uint) ([]models.OrderReturnLine, error)
+
+	// order cancel
+	SaveOrderCancel(ctx context.Context, orderCancel models.OrderCancel) (orderCancelID uint, err error)
+	SaveOrderCancelLine(ctx context.Context, cancelLine models.OrderCancelLine) error
+
+	// order payment
+	SaveOrderPayment(ctx context.Context, orderPayment models.OrderPayment) (orderPaymentID uint, err error)
+	FindOrderPaymentByGatewayOrderID(ctx context.Context, gatewayOrderID string) (models.OrderPayment, error)
+	FindOrderPaymentByGatewayPaymentID(ctx context.Context, gatewayPaymentID string) (models.OrderPayment, error)
+	FindOrderPaymentsByShopOrderID(ctx context.Context, shopOrderID uint) ([]responses.ShopOrderPayment, error)
+	UpdateOrderPaymentStatus(ctx context.Context, orderPaymentID uint,
+		status commonConstant.PaymentStatusType, gatewayPaymentID string) error
+	CancelUnpaidOrderPayments(ctx context.Context, shopOrderID uint) error
+	SaveWebhookEvent(ctx context.Context, event models.WebhookEvent) (saved bool, err error)
+
+	// refund
+	SaveRefund(ctx context.Context, refund models.Refund) (refundID uint, err error)
+	UpdateRefund(ctx context.Context, refundID uint, status commonConstant.RefundStatusType,
+		gatewayRefundID, failureReason string) error
+	FindRefundsByShopOrderID(ctx context.Context, shopOrderID uint) ([]models.Refund, error)
+	FindRefundByIDForUpdate(ctx context.Context, refundID uint) (models.Refund, error)
+	FindRefundByGatewayRefundID(ctx context.Context, gatewayRefundID string) (models.Refund, error)
+	FindPendingGatewayRefunds(ctx context.Context, pendingBefore time.Time) ([]models.Refund, error)
+
+	// cart of the order
+	SaveAppliedCouponUses(ctx context.Context, userID uint) error
+	DeleteOrderedCartItems(ctx context.Context, userID, shopOrderID uint) error
 
 	// wallet
 	FindWalletByUserID(ctx context.Context, userID uint) (wallet models.Wallet, err error)
+	FindWalletByUserIDForUpdate(ctx context.Context, userID uint) (wallet models.Wallet, err error)
 	SaveWallet(ctx context.Context, userID uint) (walletID uint, err error)
-	UpdateWallet(ctx context.Context, walletID, updateTotalAmount uint) error
+	IncrementWalletAmount(ctx context.Context, walletID, amount uint) (totalAmount uint, err error)
+	DecrementWalletAmount(ctx context.Context, walletID, amount uint) (totalAmount uint, decremented bool, err error)
 	SaveWalletTransaction(ctx context.Context, walletTrx models.Transaction) error
 
 	FindWalletTransactions(ctx context.Context, walletID uint,
-		pagination requests.Pagination) (transaction []models.Transaction, err error)
+		page pagination.Request) (transaction []models.Transaction, meta pagination.Meta, err error)
+	FindMismatchedWallets(ctx context.Context, page pagination.Request) ([]responses.WalletReconciliation, pagination.Meta, error)
 }
@@ -5,6 +5,7 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type ProductRepository interface {
@@ -26,10 +27,18 @@ type ProductRepository interface {
 	IsProductNameExistForOtherProduct(ctx context.Context, name string, productID uint) (bool, error)
 	IsProductNameExist(ctx context.Context, productName string) (exist bool, err error)
 
-	FindAllProducts(ctx context.Context, pagination requests.Pagination) ([]responses.Product, error)
+	FindAllProducts(ctx context.Context, filter requests.ProductFilter, page pagination.Request) ([]responses.Product, pagination.Meta, error)
+	FindProductFacets(ctx context.Context, filter requests.ProductFilter, priceBuckets []uint) (responses.ProductFacets, error)
 	SaveProduct(ctx context.Context, product models.Product) error
 	UpdateProduct(ctx context.Context, product models.Product) error
 
+	// product detail
+	FindProductDetailByID(ctx context.Context, productID uint) (responses.ProductDetail, error)
+	FindAllProductItemDetails(ctx context.Context, productID uint) ([]responses.ProductItemDetail, error)
+	FindAllVariationValuesOfProduct(ctx context.Context, productID uint) ([]responses.ProductItemVariationValue, error)
+	FindAllProductItemImagesOfProduct(ctx context.Context, productID uint) ([]responses.ProductItemImage, error)
+	FindActiveOfferOfProduct(ctx context.Context, productID uint) (responses.ProductOffer, error)
+
 	// product items
 	FindProductItemByID(ctx context.Context, productItemID uint) (models.ProductItem, error)
 	FindAllProductItems(ctx context.Context, productID uint) ([]responses.ProductItems, error)
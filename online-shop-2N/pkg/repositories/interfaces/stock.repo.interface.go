@@ -4,9 +4,10 @@ import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 )
 
 type StockRepository interface {
-	FindAll(ctx context.Context, pagination requests.Pagination) (stocks []responses.Stock, err error)
+	FindAll(ctx context.Context, page pagination.Request) (stocks []responses.Stock, meta pagination.Meta, err error)
 	Update(ctx context.Context, updateValues requests.UpdateStock) error
 }
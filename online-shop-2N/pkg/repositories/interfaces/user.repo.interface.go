@@ -7,6 +7,8 @@ import (
 )
 
 type UserRepository interface {
+	Transaction(callBack func(trxRepo UserRepository) error) error
+
 	FindUserByUserID(ctx context.Context, userID uint) (user models.User, err error)
 	FindUserByEmail(ctx context.Context, email string) (user models.User, err error)
 	FindUserByUserName(ctx context.Context, userName string) (user models.User, err error)
@@ -16,8 +18,16 @@ type UserRepository interface {
 	SaveUser(ctx context.Context, user models.User) (userID uint, err error)
 	UpdateVerified(ctx context.Context, userID uint) error
 	UpdateUser(ctx context.Context, user models.User) (err error)
+	UpdatePassword(ctx context.Context, userID uint, password string) error
 	UpdateBlockStatus(ctx context.Context, userID uint, blockStatus bool) error
 
+	// identities of oauth providers
+	FindUserIdentity(ctx context.Context, provider, providerUserID string) (models.UserIdentity, error)
+	FindAllUserIdentities(ctx context.Context, userID uint) ([]responses.UserIdentity, error)
+	SaveUserIdentity(ctx context.Context, identity models.UserIdentity) error
+	DeleteUserIdentity(ctx context.Context, userID uint, provider string) (bool, error)
+	DeleteAllUserIdentities(ctx context.Context, userID uint) error
+
 	//address
 	// FindCountryByID(ctx context.Context, countryID uint) (models.Country, error)
 	FindAddressByID(ctx context.Context, addressID uint) (responses.Address, error)
@@ -5,6 +5,7 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 
 	"gorm.io/gorm"
@@ -58,16 +59,28 @@ func (c *offerDatabase) FindOfferByName(ctx context.Context, offerName string) (
 
 // findAll offers
 func (c *offerDatabase) FindAllOffers(ctx context.Context,
-	pagination requests.Pagination) (offers []models.Offer, err error) {
+	page pagination.Request) (offers []models.Offer, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM offers`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	query := `SELECT id, name, description, discount_rate, start_date, end_date 
-	 FROM offers LIMIT $1 OFFSET $2`
-	err = c.DB.Raw(query, limit, offset).Scan(&offers).Error
+	after, keys := page.After("id", false, page.Cursor.ID)
 
-	return
+	query = `SELECT id, name, description, discount_rate, start_date, end_date 
+	 FROM offers WHERE ` + after + ` ORDER BY id LIMIT ? OFFSET ?`
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&offers).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	offers, meta = pagination.NewPage(page, offers, totalCount, func(offer models.Offer) pagination.Cursor {
+		return pagination.Cursor{ID: offer.ID}
+	})
+
+	return offers, meta, nil
 }
 
 // save a new offer
@@ -131,16 +144,33 @@ func (c *offerDatabase) FindOfferCategoryCategoryID(ctx context.Context,
 
 // find all offer_category
 func (c *offerDatabase) FindAllOfferCategories(ctx context.Context,
-	pagination requests.Pagination) (offerCategories []responses.OfferCategory, err error) {
+	page pagination.Request) (offerCategories []responses.OfferCategory, meta pagination.Meta, err error) {
 
-	query := `SELECT oc.id AS offer_category_id, oc.category_id,c.name AS category_name, 
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM offer_categories`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
+
+	after, keys := page.After("oc.id", false, page.Cursor.ID)
+
+	query = `SELECT oc.id AS offer_category_id, oc.category_id,c.name AS category_name, 
 	oc.offer_id, o.name AS offer_name, o.discount_rate 
 	FROM offer_categories oc INNER JOIN categories c ON c.id = oc.category_id 
-	INNER JOIN offers o ON oc.offer_id = o.id`
+	INNER JOIN offers o ON oc.offer_id = o.id 
+	WHERE ` + after + ` ORDER BY oc.id LIMIT ? OFFSET ?`
 
-	err = c.DB.Raw(query).Scan(&offerCategories).Error
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&offerCategories).Error
+	if err != nil {
+		return nil, meta, err
+	}
 
-	return
+	offerCategories, meta = pagination.NewPage(page, offerCategories, totalCount,
+		func(offerCategory responses.OfferCategory) pagination.Cursor {
+			return pagination.Cursor{ID: offerCategory.OfferCategoryID}
+		})
+
+	return offerCategories, meta, nil
 }
 
 // save a new offer for category
@@ -183,14 +213,32 @@ func (c *offerDatabase) FindOfferProductByProductID(ctx context.Context,
 
 // find all offer_products
 func (c *offerDatabase) FindAllOfferProducts(ctx context.Context,
-	pagination requests.Pagination) (offerProducts []responses.OfferProduct, err error) {
+	page pagination.Request) (offerProducts []responses.OfferProduct, meta pagination.Meta, err error) {
+
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM offer_products`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	query := `SELECT op.id AS offer_product_id, op.product_id,p.product_name,op.offer_id,o.offer_name, o.discount_rate  
+	after, keys := page.After("op.id", false, page.Cursor.ID)
+
+	query = `SELECT op.id AS offer_product_id, op.product_id, p.name AS product_name, op.offer_id, 
+	o.name AS offer_name, o.discount_rate  
 	FROM offer_products op INNER JOIN products p ON p.id = op.product_id 
-	INNER JOIN offers o ON o.id = op.offer_id`
-	err = c.DB.Raw(query).Scan(&offerProducts).Error
+	INNER JOIN offers o ON o.id = op.offer_id 
+	WHERE ` + after + ` ORDER BY op.id LIMIT ? OFFSET ?`
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&offerProducts).Error
+	if err != nil {
+		return nil, meta, err
+	}
 
-	return
+	offerProducts, meta = pagination.NewPage(page, offerProducts, totalCount,
+		func(offerProduct responses.OfferProduct) pagination.Cursor {
+			return pagination.Cursor{ID: offerProduct.OfferProductID}
+		})
+
+	return offerProducts, meta, nil
 }
 
 // save a offer for product
@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 	"time"
 
@@ -31,7 +31,7 @@ func (c *OrderDatabase) Transaction(callBack func(trxRepo interfaces.OrderReposi
 	err := callBack(transactionRepo)
 	if err != nil {
 		trx.Rollback()
-		return fmt.Errorf("failed to complete transaction \nerror:%v", err.Error())
+		return fmt.Errorf("failed to complete transaction \nerror:%w", err)
 	}
 
 	err = trx.Commit().Error
@@ -47,63 +47,126 @@ func (c *OrderDatabase) FindShopOrderByShopOrderID(ctx context.Context, shopOrde
 	return shopOrder, err
 }
 
+// find a shop order and lock the row until the end of the transaction
+func (c *OrderDatabase) FindShopOrderByShopOrderIDForUpdate(ctx context.Context,
+	shopOrderID uint) (shopOrder models.ShopOrder, err error) {
+
+	query := `SELECT * FROM shop_orders WHERE id = $1 FOR UPDATE`
+	err = c.DB.Raw(query, shopOrderID).Scan(&shopOrder).Error
+
+	return shopOrder, err
+}
+
+// find ids of shop orders which are in any of the given statuses and ordered before the given time
+func (c *OrderDatabase) FindShopOrderIDsByStatusIDsBefore(ctx context.Context,
+	orderStatusIDs []uint, before time.Time) (shopOrderIDs []uint, err error) {
+
+	query := `SELECT id FROM shop_orders WHERE order_status_id IN ? AND order_date < ? ORDER BY order_date`
+	err = c.DB.Raw(query, orderStatusIDs, before).Scan(&shopOrderIDs).Error
+
+	return shopOrderIDs, err
+}
+
 // get all shop order of user
 func (c *OrderDatabase) FindAllShopOrdersByUserID(ctx context.Context, userID uint,
-	pagination requests.Pagination) (shopOrders []responses.ShopOrder, err error) {
+	page pagination.Request) (shopOrders []responses.ShopOrder, meta pagination.Meta, err error) {
+
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM shop_orders WHERE user_id = $1`
+	if err := c.DB.Raw(query, userID).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	after, keys := page.After("so.order_date, so.id", true, page.Cursor.Time, page.Cursor.ID)
 
-	query := `SELECT so.user_id, so.id AS shop_order_id, so.order_date, so.order_total_price, so.discount, 
+	query = `SELECT so.user_id, so.id AS shop_order_id, so.order_date, so.order_total_price, so.discount, 
 	so.order_status_id, os.status AS order_status,so.address_id, so.payment_method_id, pm.name AS payment_method_name  
 	FROM shop_orders so 
 	INNER JOIN order_statuses os ON so.order_status_id = os.id 
 	INNER JOIN payment_methods pm ON pm.id = so.payment_method_id 
-	WHERE user_id = $1 
-	ORDER BY order_date DESC LIMIT $2 OFFSET  $3`
-	err = c.DB.Raw(query, userID, limit, offset).Scan(&shopOrders).Error
+	WHERE so.user_id = ? AND ` + after + ` 
+	ORDER BY so.order_date DESC, so.id DESC LIMIT ? OFFSET ?`
+
+	values := append([]interface{}{userID}, keys...)
+	err = c.DB.Raw(query, append(values, page.Limit(), page.Offset())...).Scan(&shopOrders).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	shopOrders, meta = pagination.NewPage(page, shopOrders, totalCount, shopOrderCursor)
 
-	return shopOrders, err
+	return shopOrders, meta, nil
 }
 
 // find all shop orders with user
 func (c *OrderDatabase) FindAllShopOrders(ctx context.Context,
-	pagination requests.Pagination) (shopOrders []responses.ShopOrder, err error) {
+	page pagination.Request) (shopOrders []responses.ShopOrder, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM shop_orders`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
+
+	after, keys := page.After("so.order_date, so.id", true, page.Cursor.Time, page.Cursor.ID)
 
-	query := `SELECT so.user_id, so.id AS shop_order_id, so.order_date, so.order_total_price, so.discount, 
+	query = `SELECT so.user_id, so.id AS shop_order_id, so.order_date, so.order_total_price, so.discount, 
 	so.order_status_id, os.status AS order_status, so.address_id, so.payment_method_id, pm.name AS payment_method_name   
 	FROM shop_orders so 
 	INNER JOIN order_statuses os ON so.order_status_id = os.id 
 	INNER JOIN payment_methods pm ON so.payment_method_id = pm.id 
-	ORDER BY so.order_date DESC LIMIT $1 OFFSET $2`
+	WHERE ` + after + ` 
+	ORDER BY so.order_date DESC, so.id DESC LIMIT ? OFFSET ?`
+
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&shopOrders).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	shopOrders, meta = pagination.NewPage(page, shopOrders, totalCount, shopOrderCursor)
 
-	err = c.DB.Raw(query, limit, offset).Scan(&shopOrders).Error
+	return shopOrders, meta, nil
+}
 
-	return shopOrders, err
+// shop orders are paged by the order date
+func shopOrderCursor(shopOrder responses.ShopOrder) pagination.Cursor {
+	return pagination.Cursor{Time: shopOrder.OrderDate, ID: shopOrder.ShopOrderID}
 }
 
 // get order items of a specific order
-func (c *OrderDatabase) FindAllOrdersItemsByShopOrderID(ctx context.Context,
-	shopOrderID uint, pagination requests.Pagination) (orderItems []responses.OrderItem, err error) {
+func (c *OrderDatabase) FindAllOrdersItemsByShopOrderID(ctx context.Context, shopOrderID uint,
+	page pagination.Request) (orderItems []responses.OrderItem, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM order_lines WHERE shop_order_id = $1`
+	if err := c.DB.Raw(query, shopOrderID).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	query := `SELECT ol.product_item_id, p.name AS product_name, p.image, ol.price, so.order_date, os.status,ol.qty, 
+	after, keys := page.After("ol.qty, ol.id", true, page.Cursor.Number, page.Cursor.ID)
+
+	query = `SELECT ol.id AS order_line_id, ol.product_item_id, p.name AS product_name, p.image, ol.price, 
+	so.order_date, os.status,ol.qty, ol.returned_qty, ol.cancelled_qty, 
 	(ol.price * ol.qty) AS sub_total FROM  order_lines ol 
 	INNER JOIN shop_orders so ON ol.shop_order_id = so.id 
 	INNER JOIN product_items pi ON ol.product_item_id = pi.id
 	INNER JOIN products p ON pi.product_id = p.id 
 	INNER JOIN order_statuses os ON so.order_status_id = os.id 
-	AND ol.shop_order_id = $1 
-	ORDER BY ol.qty DESC LIMIT $2 OFFSET $3`
+	AND ol.shop_order_id = ? 
+	WHERE ` + after + ` 
+	ORDER BY ol.qty DESC, ol.id DESC LIMIT ? OFFSET ?`
+
+	values := append([]interface{}{shopOrderID}, keys...)
+	err = c.DB.Raw(query, append(values, page.Limit(), page.Offset())...).Scan(&orderItems).Error
+	if err != nil {
+		return nil, meta, err
+	}
 
-	err = c.DB.Raw(query, shopOrderID, limit, offset).Scan(&orderItems).Error
+	orderItems, meta = pagination.NewPage(page, orderItems, totalCount, func(orderItem responses.OrderItem) pagination.Cursor {
+		return pagination.Cursor{Number: int64(orderItem.Qty), ID: orderItem.OrderLineID}
+	})
 
-	return orderItems, err
+	return orderItems, meta, nil
 }
 
 // ! order place
@@ -131,6 +194,17 @@ func (c *OrderDatabase) SaveOrderLine(ctx context.Context, orderLine models.Orde
 	return err
 }
 
+// add back the quantity of all order lines of the shop order to product items stock
+func (c *OrderDatabase) RestoreOrderLineQuantities(ctx context.Context, shopOrderID uint) error {
+
+	query := `UPDATE product_items pi SET qty_in_stock = pi.qty_in_stock + ol.qty 
+	FROM order_lines ol 
+	WHERE pi.id = ol.product_item_id AND ol.shop_order_id = $1`
+	err := c.DB.Exec(query, shopOrderID).Error
+
+	return err
+}
+
 //!end
 
 func (c *OrderDatabase) FindOrderStatusByShopOrderID(ctx context.Context,
@@ -176,11 +250,10 @@ func (c *OrderDatabase) UpdateShopOrderOrderStatus(ctx context.Context, shopOrde
 	return err
 }
 
-func (c *OrderDatabase) UpdateShopOrderStatusAndSavePaymentMethod(ctx context.Context,
-	shopOrderID, orderStatusID, paymentID uint) error {
+func (c *OrderDatabase) UpdateShopOrderPaymentMethod(ctx context.Context, shopOrderID, paymentID uint) error {
 
-	query := `UPDATE shop_orders SET order_status_id = $1, payment_method_id = $2 WHERE id = $3`
-	err := c.DB.Exec(query, orderStatusID, paymentID, shopOrderID).Error
+	query := `UPDATE shop_orders SET payment_method_id = $1 WHERE id = $2`
+	err := c.DB.Exec(query, paymentID, shopOrderID).Error
 
 	return err
 }
@@ -196,64 +269,96 @@ func (c *OrderDatabase) FindOrderReturnByReturnID(ctx context.Context,
 func (c *OrderDatabase) FindOrderReturnByShopOrderID(ctx context.Context,
 	shopOrderID uint) (orderReturn models.OrderReturn, err error) {
 
-	query := `SELECT *  FROM order_returns WHERE shop_order_id = $1`
+	query := `SELECT *  FROM order_returns WHERE shop_order_id = $1 ORDER BY id DESC LIMIT 1`
 	err = c.DB.Raw(query, shopOrderID).Scan(&orderReturn).Error
 
 	return orderReturn, err
 }
 
 func (c *OrderDatabase) FindAllOrderReturns(ctx context.Context,
-	pagination requests.Pagination) (orderReturns []responses.OrderReturn, err error) {
+	page pagination.Request) (orderReturns []responses.OrderReturn, meta pagination.Meta, err error) {
+
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM order_returns`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	after, keys := page.After("ors.request_date, ors.id", false, page.Cursor.Time, page.Cursor.ID)
 
-	query := `SELECT ors.id AS order_return_id, ors.shop_order_id, ors.request_date, ors.return_reason, 
-		os.id AS order_status_id, os.status AS order_status,ors.refund_amount, 
+	query = `SELECT ors.id AS order_return_id, ors.shop_order_id, ors.request_date, ors.return_reason, 
+		os.id AS order_status_id, os.status AS order_status,ors.refund_amount, ors.refund_policy, 
 		ors.admin_comment, ors.is_approved, ors.approval_date, ors.return_date 
 		FROM order_returns ors 
 		INNER JOIN shop_orders so ON ors.shop_order_id =  so.id 
 		INNER JOIN order_statuses os ON so.order_status_id = os.id 
-		ORDER BY ors.request_date LIMIT $1 OFFSET $2`
-	err = c.DB.Raw(query, limit, offset).Scan(&orderReturns).Error
+		WHERE ` + after + ` 
+		ORDER BY ors.request_date, ors.id LIMIT ? OFFSET ?`
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&orderReturns).Error
+	if err != nil {
+		return nil, meta, err
+	}
 
-	return orderReturns, err
+	orderReturns, meta = pagination.NewPage(page, orderReturns, totalCount, orderReturnCursor)
+
+	return orderReturns, meta, nil
 }
 
 func (c *OrderDatabase) FindAllPendingOrderReturns(ctx context.Context,
-	pagination requests.Pagination) (pendingReturns []responses.OrderReturn, err error) {
-
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	page pagination.Request) (pendingReturns []responses.OrderReturn, meta pagination.Meta, err error) {
 
 	returnRequested, err1 := c.FindOrderStatusByStatus(ctx, "return requested")
 	returnApproved, err2 := c.FindOrderStatusByStatus(ctx, "return approved")
 	err = errors.Join(err1, err2)
 	if err != nil {
-		return nil, err
+		return nil, meta, err
 	}
 
-	query := `SELECT ors.id AS order_return_id, ors.shop_order_id, ors.request_date, ors.return_reason, 
-	os.id AS order_status_id, os.status AS order_status,ors.refund_amount  
+	var totalCount uint64
+	query := `SELECT COUNT(ors.id) FROM order_returns ors 
+	INNER JOIN shop_orders so ON ors.shop_order_id =  so.id 
+	WHERE so.order_status_id = $1 OR so.order_status_id = $2`
+	if err := c.DB.Raw(query, returnRequested.ID, returnApproved.ID).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
+
+	after, keys := page.After("ors.request_date, ors.id", true, page.Cursor.Time, page.Cursor.ID)
+
+	query = `SELECT ors.id AS order_return_id, ors.shop_order_id, ors.request_date, ors.return_reason, 
+	os.id AS order_status_id, os.status AS order_status,ors.refund_amount, ors.refund_policy  
 	FROM order_returns ors 
 	INNER JOIN shop_orders so ON ors.shop_order_id =  so.id 
 	INNER JOIN order_statuses os ON so.order_status_id = os.id 
-	WHERE so.order_status_id = $1 OR so.order_status_id = $2 
-	ORDER BY ors.request_date DESC LIMIT $3 OFFSET $4`
-	err = c.DB.Raw(query, returnRequested.ID, returnApproved.ID, limit, offset).Scan(&pendingReturns).Error
+	WHERE (so.order_status_id = ? OR so.order_status_id = ?) AND ` + after + ` 
+	ORDER BY ors.request_date DESC, ors.id DESC LIMIT ? OFFSET ?`
+
+	values := append([]interface{}{returnRequested.ID, returnApproved.ID}, keys...)
+	err = c.DB.Raw(query, append(values, page.Limit(), page.Offset())...).Scan(&pendingReturns).Error
+	if err != nil {
+		return nil, meta, err
+	}
 
-	return pendingReturns, err
+	pendingReturns, meta = pagination.NewPage(page, pendingReturns, totalCount, orderReturnCursor)
+
+	return pendingReturns, meta, nil
+}
+
+// order returns are paged by the request date
+func orderReturnCursor(orderReturn responses.OrderReturn) pagination.Cursor {
+	return pagination.Cursor{Time: orderReturn.RequestDate, ID: orderReturn.OrderReturnID}
 }
 
 // to save a return requests
-func (c *OrderDatabase) SaveOrderReturn(ctx context.Context, orderReturn models.OrderReturn) error {
+func (c *OrderDatabase) SaveOrderReturn(ctx context.Context, orderReturn models.OrderReturn) (orderReturnID uint, err error) {
 
-	query := `INSERT INTO order_returns (shop_order_id,return_reason,request_date,refund_amount,is_approved) 
-	VALUES($1,$2,$3,$4,$5)`
-	err := c.DB.Exec(query, orderReturn.ShopOrderID, orderReturn.ReturnReason,
-		orderReturn.RequestDate, orderReturn.RefundAmount, false).Error
+	query := `INSERT INTO order_returns (shop_order_id,return_reason,request_date,refund_amount,
+	refund_policy,wallet_refund_amount,is_approved) 
+	VALUES($1,$2,$3,$4,$5,$6,$7) RETURNING id`
+	err = c.DB.Raw(query, orderReturn.ShopOrderID, orderReturn.ReturnReason,
+		orderReturn.RequestDate, orderReturn.RefundAmount, orderReturn.RefundPolicy,
+		orderReturn.WalletRefundAmount, false).Scan(&orderReturnID).Error
 
-	return err
+	return orderReturnID, err
 }
 
 // update the order return
@@ -261,8 +366,40 @@ func (c *OrderDatabase) UpdateOrderReturn(ctx context.Context, orderReturn model
 
 	query := `UPDATE order_returns SET admin_comment = $1, return_date = $2, 
 	approval_date = $3, is_approved = $4 WHERE id = $5`
-	err := c.DB.Exec(query, orderReturn.AdminComment, orderReturn.RequestDate,
+	err := c.DB.Exec(query, orderReturn.AdminComment, orderReturn.ReturnDate,
 		orderReturn.ApprovalDate, orderReturn.IsApproved, orderReturn.ID).Error
 
 	return err
 }
+
+// save a change of order status
+func (c *OrderDatabase) SaveOrderStatusHistory(ctx context.Context, history models.OrderStatusHistory) error {
+
+	query := `INSERT INTO order_status_history (shop_order_id, from_status_id, to_status_id, 
+	actor, actor_id, comment, created_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	createdAt := time.Now()
+	err := c.DB.Exec(query, history.ShopOrderID, history.FromStatusID, history.ToStatusID,
+		history.Actor, history.ActorID, history.Comment, createdAt).Error
+
+	return err
+}
+
+// find all status changes of a shop order in the order they happened
+func (c *OrderDatabase) FindOrderStatusHistoryByShopOrderID(ctx context.Context,
+	shopOrderID uint) (histories []responses.OrderStatusHistory, err error) {
+
+	query := `SELECT osh.id AS history_id, osh.shop_order_id, 
+	COALESCE(fos.status, '') AS from_status, tos.status AS to_status, 
+	osh.actor, osh.actor_id, osh.comment, osh.created_at 
+	FROM order_status_history osh 
+	LEFT JOIN order_statuses fos ON osh.from_status_id = fos.id 
+	INNER JOIN order_statuses tos ON osh.to_status_id = tos.id 
+	WHERE osh.shop_order_id = $1 
+	ORDER BY osh.created_at, osh.id`
+
+	err = c.DB.Raw(query, shopOrderID).Scan(&histories).Error
+
+	return histories, err
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// save the coupon applied on the cart of user as used by the user, nothing is saved when no coupon applied
+func (c *OrderDatabase) SaveAppliedCouponUses(ctx context.Context, userID uint) error {
+
+	query := `INSERT INTO coupon_uses (user_id, coupon_id, used_at)
+	SELECT user_id, applied_coupon_id, $1 FROM carts WHERE user_id = $2 AND applied_coupon_id != 0`
+
+	usedAt := time.Now()
+	err := c.DB.Exec(query, usedAt, userID).Error
+
+	return err
+}
+
+// remove the product items of the shop order from the cart of user, other items added to cart are kept
+func (c *OrderDatabase) DeleteOrderedCartItems(ctx context.Context, userID, shopOrderID uint) error {
+
+	query := `DELETE FROM cart_items ci USING carts c
+	WHERE ci.cart_id = c.id AND c.user_id = $1
+	AND ci.product_item_id IN (SELECT product_item_id FROM order_lines WHERE shop_order_id = $2)`
+	err := c.DB.Exec(query, userID, shopOrderID).Error
+
+	return err
+}
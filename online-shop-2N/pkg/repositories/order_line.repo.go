@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"online-shop-2N/pkg/models"
+	"time"
+)
+
+// find all order lines of a shop order with its returned and cancelled quantities
+func (c *OrderDatabase) FindOrderLinesByShopOrderID(ctx context.Context, shopOrderID uint) (orderLines []models.OrderLine, err error) {
+
+	query := `SELECT * FROM order_lines WHERE shop_order_id = $1 ORDER BY id`
+	err = c.DB.Raw(query, shopOrderID).Scan(&orderLines).Error
+
+	return orderLines, err
+}
+
+// add the returned quantity and its refund amount to the order line
+func (c *OrderDatabase) AddOrderLineReturnedQty(ctx context.Context, orderLineID, qty, refundAmount uint) error {
+
+	query := `UPDATE order_lines SET returned_qty = returned_qty + $1, 
+	refunded_amount = refunded_amount + $2 WHERE id = $3`
+	err := c.DB.Exec(query, qty, refundAmount, orderLineID).Error
+
+	return err
+}
+
+// add the cancelled quantity and its refund amount to the order line
+func (c *OrderDatabase) AddOrderLineCancelledQty(ctx context.Context, orderLineID, qty, refundAmount uint) error {
+
+	query := `UPDATE order_lines SET cancelled_qty = cancelled_qty + $1, 
+	refunded_amount = refunded_amount + $2 WHERE id = $3`
+	err := c.DB.Exec(query, qty, refundAmount, orderLineID).Error
+
+	return err
+}
+
+// add back the given quantity of the order line to its product item stock
+func (c *OrderDatabase) RestockOrderLineQty(ctx context.Context, orderLineID, qty uint) error {
+
+	query := `UPDATE product_items pi SET qty_in_stock = pi.qty_in_stock + $1 
+	FROM order_lines ol 
+	WHERE pi.id = ol.product_item_id AND ol.id = $2`
+	err := c.DB.Exec(query, qty, orderLineID).Error
+
+	return err
+}
+
+func (c *OrderDatabase) SaveOrderReturnLine(ctx context.Context, returnLine models.OrderReturnLine) error {
+
+	query := `INSERT INTO order_return_lines (order_return_id, order_line_id, qty, refund_amount) 
+	VALUES ($1, $2, $3, $4)`
+	err := c.DB.Exec(query, returnLine.OrderReturnID, returnLine.OrderLineID,
+		returnLine.Qty, returnLine.RefundAmount).Error
+
+	return err
+}
+
+func (c *OrderDatabase) FindOrderReturnLinesByReturnID(ctx context.Context,
+	orderReturnID uint) (returnLines []models.OrderReturnLine, err error) {
+
+	query := `SELECT * FROM order_return_lines WHERE order_return_id = $1 ORDER BY id`
+	err = c.DB.Raw(query, orderReturnID).Scan(&returnLines).Error
+
+	return returnLines, err
+}
+
+// save an order cancel and return the order cancel id
+func (c *OrderDatabase) SaveOrderCancel(ctx context.Context, orderCancel models.OrderCancel) (orderCancelID uint, err error) {
+
+	createdAt := time.Now()
+	query := `INSERT INTO order_cancels (shop_order_id, cancel_reason, actor, actor_id, refund_amount, 
+	refund_policy, wallet_refund_amount, created_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	err = c.DB.Raw(query, orderCancel.ShopOrderID, orderCancel.CancelReason, orderCancel.Actor, orderCancel.ActorID,
+		orderCancel.RefundAmount, orderCancel.RefundPolicy, orderCancel.WalletRefundAmount, createdAt).Scan(&orderCancelID).Error
+
+	return orderCancelID, err
+}
+
+func (c *OrderDatabase) SaveOrderCancelLine(ctx context.Context, cancelLine models.OrderCancelLine) error {
+
+	query := `INSERT INTO order_cancel_lines (order_cancel_id, order_line_id, qty, refund_amount) 
+	VALUES ($1, $2, $3, $4)`
+	err := c.DB.Exec(query, cancelLine.OrderCancelID, cancelLine.OrderLineID,
+		cancelLine.Qty, cancelLine.RefundAmount).Error
+
+	return err
+}
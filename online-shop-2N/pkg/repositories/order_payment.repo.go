@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"time"
+)
+
+// save a payment created on payment gateway for a shop order
+func (c *OrderDatabase) SaveOrderPayment(ctx context.Context, orderPayment models.OrderPayment) (orderPaymentID uint, err error) {
+
+	createdAt := time.Now()
+	query := `INSERT INTO order_payments (shop_order_id, payment_method_id, amount, gateway_order_id,
+	gateway_payment_id, status, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	err = c.DB.Raw(query, orderPayment.ShopOrderID, orderPayment.PaymentMethodID, orderPayment.Amount,
+		orderPayment.GatewayOrderID, orderPayment.GatewayPaymentID, orderPayment.Status, createdAt).Scan(&orderPaymentID).Error
+
+	return orderPaymentID, err
+}
+
+// find order payment using the order/intent id of payment gateway
+func (c *OrderDatabase) FindOrderPaymentByGatewayOrderID(ctx context.Context,
+	gatewayOrderID string) (orderPayment models.OrderPayment, err error) {
+
+	query := `SELECT * FROM order_payments WHERE gateway_order_id = $1`
+	err = c.DB.Raw(query, gatewayOrderID).Scan(&orderPayment).Error
+
+	return orderPayment, err
+}
+
+// find order payment using the payment id of payment gateway
+func (c *OrderDatabase) FindOrderPaymentByGatewayPaymentID(ctx context.Context,
+	gatewayPaymentID string) (orderPayment models.OrderPayment, err error) {
+
+	query := `SELECT * FROM order_payments WHERE gateway_payment_id = $1`
+	err = c.DB.Raw(query, gatewayPaymentID).Scan(&orderPayment).Error
+
+	return orderPayment, err
+}
+
+// find all payments of a shop order with its payment type
+func (c *OrderDatabase) FindOrderPaymentsByShopOrderID(ctx context.Context,
+	shopOrderID uint) (orderPayments []responses.ShopOrderPayment, err error) {
+
+	query := `SELECT op.id AS order_payment_id, op.shop_order_id, op.payment_method_id, 
+	pm.name AS payment_type, op.amount, op.status, op.gateway_order_id, op.gateway_payment_id, op.created_at 
+	FROM order_payments op 
+	INNER JOIN payment_methods pm ON op.payment_method_id = pm.id 
+	WHERE op.shop_order_id = $1 
+	ORDER BY op.created_at, op.id`
+
+	err = c.DB.Raw(query, shopOrderID).Scan(&orderPayments).Error
+
+	return orderPayments, err
+}
+
+func (c *OrderDatabase) UpdateOrderPaymentStatus(ctx context.Context, orderPaymentID uint,
+	status commonConstant.PaymentStatusType, gatewayPaymentID string) error {
+
+	updatedAt := time.Now()
+	query := `UPDATE order_payments SET status = $1,
+	gateway_payment_id = COALESCE(NULLIF($2, ''), gateway_payment_id),
+	updated_at = $3 WHERE id = $4`
+	err := c.DB.Exec(query, status, gatewayPaymentID, updatedAt, orderPaymentID).Error
+
+	return err
+}
+
+// cancel the gateway payments of shop order which are created or failed, those payments are not paid yet
+func (c *OrderDatabase) CancelUnpaidOrderPayments(ctx context.Context, shopOrderID uint) error {
+
+	updatedAt := time.Now()
+	query := `UPDATE order_payments SET status = $1, updated_at = $2
+	WHERE shop_order_id = $3 AND status IN ($4, $5)`
+	err := c.DB.Exec(query, commonConstant.PaymentStatusCancelled, updatedAt, shopOrderID,
+		commonConstant.PaymentStatusCreated, commonConstant.PaymentStatusFailed).Error
+
+	return err
+}
+
+// save the webhook event and return false if the event is already saved
+func (c *OrderDatabase) SaveWebhookEvent(ctx context.Context, event models.WebhookEvent) (saved bool, err error) {
+
+	receivedAt := time.Now()
+	query := `INSERT INTO webhook_events (provider, event_id, event_type, received_at)
+	VALUES ($1, $2, $3, $4) ON CONFLICT (provider, event_id) DO NOTHING`
+
+	result := c.DB.Exec(query, event.Provider, event.EventID, event.EventType, receivedAt)
+
+	return result.RowsAffected == 1, result.Error
+}
@@ -5,7 +5,10 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -151,25 +154,315 @@ func (c *productDatabase) UpdateProduct(ctx context.Context, product models.Prod
 	return err
 }
 
-// get all products from database
-func (c *productDatabase) FindAllProducts(ctx context.Context, pagination requests.Pagination) (products []responses.Product, err error) {
+// get all products from database which match the search and filters.
+// products sorted by relevance or popularity are paged by the offset on cursor, others by the sort keys
+func (c *productDatabase) FindAllProducts(ctx context.Context, filter requests.ProductFilter,
+	page pagination.Request) (products []responses.Product, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	condition, values := productFilterCondition(filter)
 
-	query := `SELECT p.id, p.name, p.description, p.price, p.discount_price, 
+	var totalCount uint64
+	query := `SELECT COUNT(p.id) FROM products p 
+	INNER JOIN categories sc ON p.category_id = sc.id ` + condition
+	if err := c.DB.Raw(query, values...).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
+
+	query = `SELECT p.id, p.name, p.description, p.price, p.discount_price, 
 	p.image, p.image, p.category_id, sc.name AS category_name, 
 	mc.name AS main_category_name, p.brand_id, b.name AS brand_name,
 	p.created_at, p.updated_at 
 	FROM products p 
 	INNER JOIN categories sc ON p.category_id = sc.id 
 	INNER JOIN categories mc ON sc.category_id = mc.id 
-	INNER JOIN brands b ON b.id = p.brand_id 
-	ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	INNER JOIN brands b ON b.id = p.brand_id `
+
+	// sold quantity of products is only needed to sort by popularity
+	if filter.Sort == requests.ProductSortPopularity {
+		query += `LEFT JOIN (
+		SELECT pi.product_id, SUM(ol.qty - ol.cancelled_qty - ol.returned_qty) AS sold_qty 
+		FROM order_lines ol INNER JOIN product_items pi ON pi.id = ol.product_item_id 
+		GROUP BY pi.product_id
+	) s ON s.product_id = p.id `
+	}
 
-	err = c.DB.Raw(query, limit, offset).Scan(&products).Error
+	var (
+		after, orderBy string
+		keys           []interface{}
+		cursorOf       func(product responses.Product) pagination.Cursor
+	)
+
+	switch filter.Sort {
+	case requests.ProductSortRelevance:
+		after = "TRUE"
+		orderBy = `ts_rank(p.search_vector, websearch_to_tsquery('english', ?)) DESC, p.id DESC`
+	case requests.ProductSortPriceAsc, requests.ProductSortPriceDesc:
+		desc := filter.Sort == requests.ProductSortPriceDesc
+		after, keys = page.After("COALESCE(NULLIF(p.discount_price, 0), p.price), p.id", desc,
+			page.Cursor.Number, page.Cursor.ID)
+		orderBy = `COALESCE(NULLIF(p.discount_price, 0), p.price) ASC, p.id ASC`
+		if desc {
+			orderBy = `COALESCE(NULLIF(p.discount_price, 0), p.price) DESC, p.id DESC`
+		}
+		cursorOf = func(product responses.Product) pagination.Cursor {
+			price := product.Price
+			if product.DiscountPrice != 0 {
+				price = product.DiscountPrice
+			}
+			return pagination.Cursor{Number: int64(price), ID: product.ID}
+		}
+	case requests.ProductSortPopularity:
+		after = "TRUE"
+		orderBy = `COALESCE(s.sold_qty, 0) DESC, p.created_at DESC, p.id DESC`
+	default:
+		after, keys = page.After("p.created_at, p.id", true, page.Cursor.Time, page.Cursor.ID)
+		orderBy = `p.created_at DESC, p.id DESC`
+		cursorOf = func(product responses.Product) pagination.Cursor {
+			return pagination.Cursor{Time: product.CreatedAt, ID: product.ID}
+		}
+	}
 
-	return
+	if condition == "" {
+		query += `WHERE ` + after
+	} else {
+		query += condition + ` AND ` + after
+	}
+	values = append(values, keys...)
+
+	query += ` ORDER BY ` + orderBy
+	if filter.Sort == requests.ProductSortRelevance {
+		values = append(values, filter.Query)
+	}
+
+	query += ` LIMIT ? OFFSET ?`
+	values = append(values, page.Limit(), page.Offset())
+
+	err = c.DB.Raw(query, values...).Scan(&products).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	products, meta = pagination.NewPage(page, products, totalCount, cursorOf)
+
+	return products, meta, nil
+}
+
+// where condition and its values for the filters of product, category of product should be joined as sc.
+// price is filtered by the discount price when the product have it
+func productFilterCondition(filter requests.ProductFilter) (string, []interface{}) {
+
+	var (
+		conditions []string
+		values     []interface{}
+	)
+
+	if filter.Query != "" {
+		conditions = append(conditions, `p.search_vector @@ websearch_to_tsquery('english', ?)`)
+		values = append(values, filter.Query)
+	}
+	// category can be a sub category or main category
+	if filter.CategoryID != 0 {
+		conditions = append(conditions, `(p.category_id = ? OR sc.category_id = ?)`)
+		values = append(values, filter.CategoryID, filter.CategoryID)
+	}
+	if filter.BrandID != 0 {
+		conditions = append(conditions, `p.brand_id = ?`)
+		values = append(values, filter.BrandID)
+	}
+	if filter.MinPrice != 0 {
+		conditions = append(conditions, `COALESCE(NULLIF(p.discount_price, 0), p.price) >= ?`)
+		values = append(values, filter.MinPrice)
+	}
+	if filter.MaxPrice != 0 {
+		conditions = append(conditions, `COALESCE(NULLIF(p.discount_price, 0), p.price) <= ?`)
+		values = append(values, filter.MaxPrice)
+	}
+	if filter.InStock && len(filter.OptionIDs) == 0 {
+		conditions = append(conditions, `EXISTS (
+		SELECT 1 FROM product_items pi WHERE pi.product_id = p.id AND pi.qty_in_stock > 0
+	)`)
+	}
+	// an item of product (in stock when asked) should have one of the given options for each variation of the options
+	if len(filter.OptionIDs) > 0 {
+		conditions = append(conditions, `EXISTS (
+		SELECT 1 FROM product_items pi 
+		INNER JOIN product_configurations pc ON pc.product_item_id = pi.id 
+		INNER JOIN variation_options vo ON vo.id = pc.variation_option_id 
+		WHERE pi.product_id = p.id AND pc.variation_option_id IN ? `+inStockCondition(filter.InStock)+` 
+		GROUP BY pi.id 
+		HAVING COUNT(DISTINCT vo.variation_id) = (
+			SELECT COUNT(DISTINCT variation_id) FROM variation_options WHERE id IN ?
+		)
+	)`)
+		values = append(values, filter.OptionIDs, filter.OptionIDs)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), values
+}
+
+// condition on product items joined as pi to take only the items in stock
+func inStockCondition(inStock bool) string {
+	if inStock {
+		return "AND pi.qty_in_stock > 0"
+	}
+	return ""
+}
+
+// row of facets query, parent is the variation of a variation option
+type productFacetRow struct {
+	Facet      string
+	ID         int
+	Name       string
+	ParentID   uint
+	ParentName string
+	Count      uint64
+}
+
+// query of the products which match the filter for a facet with the extra condition when it's given
+func productFacetMatchedQuery(filter requests.ProductFilter, extraCondition string,
+	extraValues ...interface{}) (string, []interface{}) {
+
+	condition, values := productFilterCondition(filter)
+	if extraCondition != "" {
+		if condition == "" {
+			condition = `WHERE ` + extraCondition
+		} else {
+			condition += ` AND ` + extraCondition
+		}
+		values = append(values, extraValues...)
+	}
+
+	return `SELECT p.id, p.brand_id, p.category_id, COALESCE(NULLIF(p.discount_price, 0), p.price) AS price 
+		FROM products p 
+		INNER JOIN categories sc ON p.category_id = sc.id ` + condition, values
+}
+
+// To find the count of products for each brand, sub category, variation option and price range
+// on the products which match the search and filters in a single query.
+// each facet is counted without its own filter so the other values of it can be chosen with their counts,
+// sub categories are counted on the sub categories of the same main category of the category filter.
+// price range of the bucket i is from priceBuckets[i-1] to priceBuckets[i], the first start at zero and last have no limit
+func (c *productDatabase) FindProductFacets(ctx context.Context, filter requests.ProductFilter,
+	priceBuckets []uint) (facets responses.ProductFacets, err error) {
+
+	buckets := make([]string, len(priceBuckets))
+	for i, price := range priceBuckets {
+		buckets[i] = strconv.FormatUint(uint64(price), 10)
+	}
+
+	brandFilter := filter
+	brandFilter.BrandID = 0
+	brandQuery, values := productFacetMatchedQuery(brandFilter, "")
+
+	subCategoryFilter := filter
+	subCategoryFilter.CategoryID = 0
+	var siblingCondition string
+	if filter.CategoryID != 0 {
+		// the category filter can be a main category or one of its sub categories
+		siblingCondition = `(sc.category_id = ? OR sc.category_id = (SELECT category_id FROM categories WHERE id = ?))`
+	}
+	subCategoryQuery, subCategoryValues := productFacetMatchedQuery(subCategoryFilter, siblingCondition,
+		filter.CategoryID, filter.CategoryID)
+	values = append(values, subCategoryValues...)
+
+	optionFilter := filter
+	optionFilter.OptionIDs = nil
+	optionQuery, optionValues := productFacetMatchedQuery(optionFilter, "")
+	values = append(values, optionValues...)
+
+	priceFilter := filter
+	priceFilter.MinPrice, priceFilter.MaxPrice = 0, 0
+	priceQuery, priceValues := productFacetMatchedQuery(priceFilter, "")
+	values = append(values, priceValues...)
+
+	// the item of an option should have one of the options of each other variation on the filter
+	var otherOptionsCondition string
+	if len(filter.OptionIDs) > 0 {
+		otherOptionsCondition = `WHERE (
+		SELECT COUNT(DISTINCT svo.variation_id) FROM product_configurations spc 
+		INNER JOIN variation_options svo ON svo.id = spc.variation_option_id 
+		WHERE spc.product_item_id = pi.id AND spc.variation_option_id IN ? AND svo.variation_id <> v.id
+	) = (
+		SELECT COUNT(DISTINCT variation_id) FROM variation_options WHERE id IN ? AND variation_id <> v.id
+	) `
+		values = append(values, filter.OptionIDs, filter.OptionIDs)
+	}
+
+	query := `WITH brand_matched AS (` + brandQuery + `), 
+	sub_category_matched AS (` + subCategoryQuery + `), 
+	option_matched AS (` + optionQuery + `), 
+	price_matched AS (` + priceQuery + `)
+	SELECT 'brand' AS facet, b.id, b.name, 0 AS parent_id, '' AS parent_name, COUNT(m.id) AS count 
+	FROM brand_matched m INNER JOIN brands b ON b.id = m.brand_id 
+	GROUP BY b.id, b.name 
+	UNION ALL 
+	SELECT 'sub_category', sc.id, sc.name, 0, '', COUNT(m.id) 
+	FROM sub_category_matched m INNER JOIN categories sc ON sc.id = m.category_id 
+	GROUP BY sc.id, sc.name 
+	UNION ALL 
+	SELECT 'variation_option', vo.id, vo.value, v.id, v.name, COUNT(DISTINCT m.id) 
+	FROM option_matched m 
+	INNER JOIN product_items pi ON pi.product_id = m.id ` + inStockCondition(filter.InStock) + ` 
+	INNER JOIN product_configurations pc ON pc.product_item_id = pi.id 
+	INNER JOIN variation_options vo ON vo.id = pc.variation_option_id 
+	INNER JOIN variations v ON v.id = vo.variation_id 
+	` + otherOptionsCondition + `
+	GROUP BY vo.id, vo.value, v.id, v.name 
+	UNION ALL 
+	SELECT 'price_range', width_bucket(m.price, ARRAY[` + strings.Join(buckets, ", ") + `]::bigint[]), '', 0, '', COUNT(m.id) 
+	FROM price_matched m 
+	GROUP BY 2 
+	ORDER BY facet, parent_id, count DESC, id`
+
+	var rows []productFacetRow
+	if err := c.DB.Raw(query, values...).Scan(&rows).Error; err != nil {
+		return facets, err
+	}
+
+	// price ranges are ordered by the price instead of count
+	priceRangeCounts := make(map[int]uint64)
+
+	for _, row := range rows {
+		switch row.Facet {
+		case "brand":
+			facets.Brands = append(facets.Brands, responses.FacetCount{
+				ID: uint(row.ID), Name: row.Name, Count: row.Count,
+			})
+		case "sub_category":
+			facets.SubCategories = append(facets.SubCategories, responses.FacetCount{
+				ID: uint(row.ID), Name: row.Name, Count: row.Count,
+			})
+		case "variation_option":
+			facets.VariationOptions = append(facets.VariationOptions, responses.VariationOptionFacet{
+				VariationID: row.ParentID, VariationName: row.ParentName,
+				ID: uint(row.ID), Value: row.Name, Count: row.Count,
+			})
+		case "price_range":
+			priceRangeCounts[row.ID] = row.Count
+		}
+	}
+
+	for i := 0; i <= len(priceBuckets); i++ {
+		count, ok := priceRangeCounts[i]
+		if !ok {
+			continue
+		}
+		var priceRange responses.PriceRangeFacet
+		if i > 0 {
+			priceRange.MinPrice = priceBuckets[i-1]
+		}
+		if i < len(priceBuckets) {
+			priceRange.MaxPrice = priceBuckets[i]
+		}
+		priceRange.Count = count
+		facets.PriceRanges = append(facets.PriceRanges, priceRange)
+	}
+
+	return facets, nil
 }
 
 // to get productItem id
@@ -278,3 +571,91 @@ func (c *productDatabase) FindAllProductItemImages(ctx context.Context, productI
 
 	return
 }
+
+// To find product with its brand and categories for the product detail
+func (c *productDatabase) FindProductDetailByID(ctx context.Context,
+	productID uint) (product responses.ProductDetail, err error) {
+
+	query := `SELECT p.id, p.name, p.description, p.price, p.discount_price, p.image, 
+	p.brand_id, b.name AS brand_name, p.category_id, sc.name AS category_name, 
+	mc.id AS main_category_id, mc.name AS main_category_name, p.created_at, p.updated_at 
+	FROM products p 
+	INNER JOIN brands b ON b.id = p.brand_id 
+	INNER JOIN categories sc ON sc.id = p.category_id 
+	LEFT JOIN categories mc ON mc.id = sc.category_id 
+	WHERE p.id = $1`
+
+	err = c.DB.Raw(query, productID).Scan(&product).Error
+
+	return
+}
+
+// To find all items of a product for the product detail
+func (c *productDatabase) FindAllProductItemDetails(ctx context.Context,
+	productID uint) (productItems []responses.ProductItemDetail, err error) {
+
+	query := `SELECT id, sku, price, discount_price, qty_in_stock 
+	FROM product_items WHERE product_id = $1 ORDER BY id`
+
+	err = c.DB.Raw(query, productID).Scan(&productItems).Error
+
+	return
+}
+
+// To find variation values of all items of a product in a single query
+func (c *productDatabase) FindAllVariationValuesOfProduct(ctx context.Context,
+	productID uint) (variationValues []responses.ProductItemVariationValue, err error) {
+
+	query := `SELECT pi.id AS product_item_id, v.id AS variation_id, v.name, 
+	vo.id AS variation_option_id, vo.value 
+	FROM product_items pi 
+	INNER JOIN product_configurations pc ON pc.product_item_id = pi.id 
+	INNER JOIN variation_options vo ON vo.id = pc.variation_option_id 
+	INNER JOIN variations v ON v.id = vo.variation_id 
+	WHERE pi.product_id = $1 
+	ORDER BY pi.id, v.id`
+
+	err = c.DB.Raw(query, productID).Scan(&variationValues).Error
+
+	return
+}
+
+// To find images of all items of a product in a single query
+func (c *productDatabase) FindAllProductItemImagesOfProduct(ctx context.Context,
+	productID uint) (images []responses.ProductItemImage, err error) {
+
+	query := `SELECT pim.product_item_id, pim.image 
+	FROM product_images pim 
+	INNER JOIN product_items pi ON pi.id = pim.product_item_id 
+	WHERE pi.product_id = $1 
+	ORDER BY pim.id`
+
+	err = c.DB.Raw(query, productID).Scan(&images).Error
+
+	return
+}
+
+// To find the offer running now on the product,
+// offer of the product itself is taken before the offer of its category
+func (c *productDatabase) FindActiveOfferOfProduct(ctx context.Context,
+	productID uint) (offer responses.ProductOffer, err error) {
+
+	query := `SELECT o.id AS offer_id, o.name AS offer_name, o.description, o.discount_rate, 
+	po.offer_type, o.start_date, o.end_date 
+	FROM (
+		SELECT op.offer_id, 'product' AS offer_type, 1 AS priority 
+		FROM offer_products op WHERE op.product_id = $1 
+		UNION ALL 
+		SELECT oc.offer_id, 'category' AS offer_type, 2 AS priority 
+		FROM offer_categories oc INNER JOIN products p ON p.category_id = oc.category_id 
+		WHERE p.id = $1
+	) po 
+	INNER JOIN offers o ON o.id = po.offer_id 
+	WHERE o.start_date <= NOW() AND o.end_date > NOW() 
+	ORDER BY po.priority, o.discount_rate DESC 
+	LIMIT 1`
+
+	err = c.DB.Raw(query, productID).Scan(&offer).Error
+
+	return
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"time"
+)
+
+// save a refund of an order return or cancel and return the refund id
+func (c *OrderDatabase) SaveRefund(ctx context.Context, refund models.Refund) (refundID uint, err error) {
+
+	createdAt := time.Now()
+	query := `INSERT INTO refunds (shop_order_id, order_return_id, order_cancel_id, order_payment_id, refund_to, amount,
+	status, gateway_refund_id, failure_reason, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	err = c.DB.Raw(query, refund.ShopOrderID, refund.OrderReturnID, refund.OrderCancelID, refund.OrderPaymentID,
+		refund.RefundTo, refund.Amount, refund.Status, refund.GatewayRefundID, refund.FailureReason,
+		createdAt).Scan(&refundID).Error
+
+	return refundID, err
+}
+
+func (c *OrderDatabase) UpdateRefund(ctx context.Context, refundID uint, status commonConstant.RefundStatusType,
+	gatewayRefundID, failureReason string) error {
+
+	updatedAt := time.Now()
+	query := `UPDATE refunds SET status = $1, gateway_refund_id = $2, failure_reason = $3,
+	updated_at = $4 WHERE id = $5`
+	err := c.DB.Exec(query, status, gatewayRefundID, failureReason, updatedAt, refundID).Error
+
+	return err
+}
+
+// find all refunds of a shop order
+func (c *OrderDatabase) FindRefundsByShopOrderID(ctx context.Context, shopOrderID uint) (refunds []models.Refund, err error) {
+
+	query := `SELECT * FROM refunds WHERE shop_order_id = $1 ORDER BY created_at, id`
+	err = c.DB.Raw(query, shopOrderID).Scan(&refunds).Error
+
+	return refunds, err
+}
+
+// find the refund and lock it until the transaction end so it's not completed or failed twice
+func (c *OrderDatabase) FindRefundByIDForUpdate(ctx context.Context, refundID uint) (refund models.Refund, err error) {
+
+	query := `SELECT * FROM refunds WHERE id = $1 FOR UPDATE`
+	err = c.DB.Raw(query, refundID).Scan(&refund).Error
+
+	return refund, err
+}
+
+// find refund using the refund id of payment gateway
+func (c *OrderDatabase) FindRefundByGatewayRefundID(ctx context.Context, gatewayRefundID string) (refund models.Refund, err error) {
+
+	query := `SELECT * FROM refunds WHERE gateway_refund_id = $1`
+	err = c.DB.Raw(query, gatewayRefundID).Scan(&refund).Error
+
+	return refund, err
+}
+
+// find the gateway refunds which are pending without any change after the given time
+func (c *OrderDatabase) FindPendingGatewayRefunds(ctx context.Context, pendingBefore time.Time) (refunds []models.Refund, err error) {
+
+	query := `SELECT * FROM refunds WHERE status = $1 AND order_payment_id <> 0
+	AND COALESCE(updated_at, created_at) < $2 ORDER BY id`
+	err = c.DB.Raw(query, commonConstant.RefundStatusPending, pendingBefore).Scan(&refunds).Error
+
+	return refunds, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 
 	"gorm.io/gorm"
@@ -28,21 +29,33 @@ func (c *stockDatabase) Update(ctx context.Context, valuesToUpdate requests.Upda
 	return err
 }
 
-func (c *stockDatabase) FindAll(ctx context.Context, pagination requests.Pagination) (stocks []responses.Stock, err error) {
+func (c *stockDatabase) FindAll(ctx context.Context,
+	page pagination.Request) (stocks []responses.Stock, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(id) FROM product_items`
+	if err := c.DB.Raw(query).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
+
+	// items of low stock first
+	after, keys := page.After("pi.qty_in_stock, pi.id", false, page.Cursor.Number, page.Cursor.ID)
 
-	query := `SELECT pi.id AS product_item_id, pi.sku, pi.qty_in_stock, pi.price, p.name AS product_name
+	query = `SELECT pi.id AS product_item_id, pi.sku, pi.qty_in_stock, pi.price, p.name AS product_name
 	FROM product_items pi 
 	INNER JOIN products p ON p.id = pi.product_id
-	ORDER BY qty_in_stock LIMIT $1 OFFSET $2`
+	WHERE ` + after + ` 
+	ORDER BY pi.qty_in_stock, pi.id LIMIT ? OFFSET ?`
 
-	err = c.DB.Raw(query, limit, offset).Scan(&stocks).Error
+	err = c.DB.Raw(query, append(keys, page.Limit(), page.Offset())...).Scan(&stocks).Error
 	if err != nil {
-		return nil, err
+		return nil, meta, err
 	}
 
+	stocks, meta = pagination.NewPage(page, stocks, totalCount, func(stock responses.Stock) pagination.Cursor {
+		return pagination.Cursor{Number: int64(stock.QtyInStock), ID: stock.ProductItemID}
+	})
+
 	// insert each stocks variation full values
 	query = `SELECT vo.id, vo.value FROM variation_options vo 
 	INNER JOIN product_configurations pc ON vo.id = pc.variation_option_id 
@@ -52,10 +65,10 @@ func (c *stockDatabase) FindAll(ctx context.Context, pagination requests.Paginat
 		var variationValue []responses.VariationOption
 		err = c.DB.Raw(query, stock.ProductItemID).Scan(&variationValue).Error
 		if err != nil {
-			return nil, err
+			return nil, meta, err
 		}
 		stocks[i].VariationOptions = variationValue
 	}
 
-	return
+	return stocks, meta, nil
 }
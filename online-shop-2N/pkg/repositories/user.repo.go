@@ -20,6 +20,21 @@ func NewUserRepository(DB *gorm.DB) interfaces.UserRepository {
 	return &userDatabase{DB: DB}
 }
 
+func (c *userDatabase) Transaction(callBack func(trxRepo interfaces.UserRepository) error) error {
+
+	trx := c.DB.Begin()
+	transactionRepo := NewUserRepository(trx)
+
+	err := callBack(transactionRepo)
+	if err != nil {
+		trx.Rollback()
+		return fmt.Errorf("failed to complete transaction \nerror:%w", err)
+	}
+
+	err = trx.Commit().Error
+	return err
+}
+
 func (c *userDatabase) FindUserByUserID(ctx context.Context, userID uint) (user models.User, err error) {
 
 	query := `SELECT * FROM users WHERE id = $1`
@@ -62,14 +77,14 @@ func (c *userDatabase) FindUserByUserNameEmailOrPhoneNotID(ctx context.Context,
 
 func (c *userDatabase) SaveUser(ctx context.Context, user models.User) (userID uint, err error) {
 
-	//save the user details
+	//save the user details, empty phone of oauth users saved as null to not conflict on unique phone
 	query := `INSERT INTO users (user_name, first_name, 
 		last_name, age, email, phone, password, google_image, created_at, verified) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10 ) RETURNING id`
+	VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10 ) RETURNING id`
 
 	createdAt := time.Now()
 	err = c.DB.Raw(query, user.UserName, user.FirstName, user.LastName,
-		user.Age, user.Email, user.Phone, user.Password, user.GoogleImage, createdAt, true).Scan(&userID).Error
+		user.Age, user.Email, user.Phone, user.Password, user.GoogleImage, createdAt, user.Verified).Scan(&userID).Error
 
 	return userID, err
 }
@@ -82,6 +97,14 @@ func (c *userDatabase) UpdateVerified(ctx context.Context, userID uint) error {
 	return err
 }
 
+func (c *userDatabase) UpdatePassword(ctx context.Context, userID uint, password string) error {
+
+	query := `UPDATE users SET password = $1, updated_at = $2 WHERE id = $3`
+	err := c.DB.Exec(query, password, time.Now(), userID).Error
+
+	return err
+}
+
 func (c *userDatabase) UpdateUser(ctx context.Context, user models.User) (err error) {
 
 	updatedAt := time.Now()
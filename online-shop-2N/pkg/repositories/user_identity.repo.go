@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/models"
+	"time"
+)
+
+func (c *userDatabase) FindUserIdentity(ctx context.Context, provider,
+	providerUserID string) (identity models.UserIdentity, err error) {
+
+	query := `SELECT * FROM user_identities WHERE provider = $1 AND provider_user_id = $2`
+	err = c.DB.Raw(query, provider, providerUserID).Scan(&identity).Error
+
+	return identity, err
+}
+
+func (c *userDatabase) FindAllUserIdentities(ctx context.Context,
+	userID uint) (identities []responses.UserIdentity, err error) {
+
+	query := `SELECT provider, email, created_at AS linked_at FROM user_identities
+	WHERE user_id = $1 ORDER BY created_at`
+	err = c.DB.Raw(query, userID).Scan(&identities).Error
+
+	return identities, err
+}
+
+func (c *userDatabase) SaveUserIdentity(ctx context.Context, identity models.UserIdentity) error {
+
+	query := `INSERT INTO user_identities (user_id, provider, provider_user_id, email, created_at)
+	VALUES ($1, $2, $3, $4, $5)`
+	createdAt := time.Now()
+	err := c.DB.Exec(query, identity.UserID, identity.Provider, identity.ProviderUserID,
+		identity.Email, createdAt).Error
+
+	return err
+}
+
+func (c *userDatabase) DeleteUserIdentity(ctx context.Context, userID uint, provider string) (bool, error) {
+
+	query := `DELETE FROM user_identities WHERE user_id = $1 AND provider = $2`
+	result := c.DB.Exec(query, userID, provider)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (c *userDatabase) DeleteAllUserIdentities(ctx context.Context, userID uint) error {
+
+	query := `DELETE FROM user_identities WHERE user_id = $1`
+	err := c.DB.Exec(query, userID).Error
+
+	return err
+}
@@ -2,8 +2,9 @@ package repositories
 
 import (
 	"context"
-	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"time"
 )
 
@@ -16,28 +17,53 @@ func (c *OrderDatabase) FindWalletByUserID(ctx context.Context, userID uint) (wa
 	return
 }
 
-// create a new wallet for user
+// find wallet by userID and lock the wallet row until the transaction end
+func (c *OrderDatabase) FindWalletByUserIDForUpdate(ctx context.Context, userID uint) (wallet models.Wallet, err error) {
+
+	query := `SELECT * FROM wallets WHERE user_id = $1 FOR UPDATE`
+	err = c.DB.Raw(query, userID).Scan(&wallet).Error
+
+	return
+}
+
+// create a new wallet for user, wallet id is zero when the user already have a wallet
 func (c *OrderDatabase) SaveWallet(ctx context.Context, userID uint) (walletID uint, err error) {
 
-	query := `INSERT INTO wallets (user_id,total_amount) VALUES ($1, $2) RETURNING id`
+	query := `INSERT INTO wallets (user_id,total_amount) VALUES ($1, $2)
+	ON CONFLICT (user_id) DO NOTHING RETURNING id`
 	err = c.DB.Raw(query, userID, 0).Scan(&walletID).Error
 
 	return
 }
-func (c *OrderDatabase) UpdateWallet(ctx context.Context, walletID, upateTotalAmount uint) error {
 
-	query := `UPDATE wallets SET total_amount = $1 WHERE id = $2`
-	err := c.DB.Exec(query, upateTotalAmount, walletID).Error
+// add the amount to wallet total on database and return the new total
+func (c *OrderDatabase) IncrementWalletAmount(ctx context.Context, walletID, amount uint) (totalAmount uint, err error) {
 
-	return err
+	query := `UPDATE wallets SET total_amount = total_amount + $1 WHERE id = $2 RETURNING total_amount`
+	err = c.DB.Raw(query, amount, walletID).Scan(&totalAmount).Error
+
+	return
+}
+
+// take the amount from wallet total on database only if the total is enough and return the new total
+func (c *OrderDatabase) DecrementWalletAmount(ctx context.Context, walletID,
+	amount uint) (totalAmount uint, decremented bool, err error) {
+
+	query := `UPDATE wallets SET total_amount = total_amount - $1 
+	WHERE id = $2 AND total_amount >= $1 RETURNING total_amount`
+	result := c.DB.Raw(query, amount, walletID).Scan(&totalAmount)
+
+	return totalAmount, result.RowsAffected == 1, result.Error
 }
 
 func (c *OrderDatabase) SaveWalletTransaction(ctx context.Context, walletTrx models.Transaction) error {
 
 	trxDate := time.Now()
-	query := `INSERT INTO transactions (wallet_id, transaction_date, amount, transaction_type) 
-	VALUES ($1, $2, $3, $4)`
-	err := c.DB.Exec(query, walletTrx.WalletID, trxDate, walletTrx.Amount, walletTrx.TransactionType).Error
+	query := `INSERT INTO transactions (wallet_id, transaction_date, amount, transaction_type, 
+	source_type, source_id, balance_after, note) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	err := c.DB.Exec(query, walletTrx.WalletID, trxDate, walletTrx.Amount, walletTrx.TransactionType,
+		walletTrx.SourceType, walletTrx.SourceID, walletTrx.BalanceAfter, walletTrx.Note).Error
 
 	return err
 }
@@ -45,15 +71,65 @@ func (c *OrderDatabase) SaveWalletTransaction(ctx context.Context, walletTrx mod
 // find wallet transaction history
 
 func (c *OrderDatabase) FindWalletTransactions(ctx context.Context, walletID uint,
-	pagination requests.Pagination) (transaction []models.Transaction, err error) {
+	page pagination.Request) (transactions []models.Transaction, meta pagination.Meta, err error) {
 
-	limit := pagination.Count
-	offset := (pagination.PageNumber - 1) * limit
+	var totalCount uint64
+	query := `SELECT COUNT(transaction_id) FROM transactions WHERE wallet_id = $1`
+	if err := c.DB.Raw(query, walletID).Scan(&totalCount).Error; err != nil {
+		return nil, meta, err
+	}
 
-	query := `SELECT * FROM transactions WHERE wallet_id = $1
-	ORDER BY transaction_date DESC LIMIT $2 OFFSET $3`
+	after, keys := page.After("transaction_date, transaction_id", true, page.Cursor.Time, page.Cursor.ID)
 
-	err = c.DB.Raw(query, walletID, limit, offset).Scan(&transaction).Error
+	query = `SELECT * FROM transactions WHERE wallet_id = ? AND ` + after + ` 
+	ORDER BY transaction_date DESC, transaction_id DESC LIMIT ? OFFSET ?`
 
-	return
+	values := append([]interface{}{walletID}, keys...)
+	err = c.DB.Raw(query, append(values, page.Limit(), page.Offset())...).Scan(&transactions).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	transactions, meta = pagination.NewPage(page, transactions, totalCount, func(transaction models.Transaction) pagination.Cursor {
+		return pagination.Cursor{Time: transaction.TransactionDate, ID: transaction.TransactionID}
+	})
+
+	return transactions, meta, nil
+}
+
+// find wallets which total amount is not same as the sum of its transactions
+func (c *OrderDatabase) FindMismatchedWallets(ctx context.Context,
+	page pagination.Request) (wallets []responses.WalletReconciliation, meta pagination.Meta, err error) {
+
+	const mismatchedWallets = `SELECT w.id AS wallet_id, w.user_id, w.total_amount, 
+	COALESCE(SUM(CASE WHEN t.transaction_type = ? THEN t.amount::BIGINT ELSE -t.amount::BIGINT END), 0) AS ledger_amount 
+	FROM wallets w 
+	LEFT JOIN transactions t ON t.wallet_id = w.id `
+	const mismatchedCondition = ` GROUP BY w.id 
+	HAVING w.total_amount <> COALESCE(SUM(CASE WHEN t.transaction_type = ? THEN t.amount::BIGINT ELSE -t.amount::BIGINT END), 0)`
+
+	var totalCount uint64
+	query := `SELECT COUNT(*) FROM (` + mismatchedWallets + mismatchedCondition + `) mw`
+	err = c.DB.Raw(query, models.Credit, models.Credit).Scan(&totalCount).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	after, keys := page.After("w.id", false, page.Cursor.ID)
+
+	query = mismatchedWallets + `WHERE ` + after + mismatchedCondition + ` 
+	ORDER BY w.id LIMIT ? OFFSET ?`
+
+	values := append([]interface{}{models.Credit}, keys...)
+	values = append(values, models.Credit, page.Limit(), page.Offset())
+	err = c.DB.Raw(query, values...).Scan(&wallets).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	wallets, meta = pagination.NewPage(page, wallets, totalCount, func(wallet responses.WalletReconciliation) pagination.Cursor {
+		return pagination.Cursor{ID: wallet.WalletID}
+	})
+
+	return wallets, meta, nil
 }
@@ -0,0 +1,31 @@
+package denylist
+
+import (
+	"context"
+	"log"
+	"online-shop-2N/pkg/config"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// memory store keep the denied ids only on the running instance
+const storeMemory = "memory"
+
+// Denylist keep the ids of revoked access tokens or sessions until the access tokens of them are expired
+type Denylist interface {
+	Deny(ctx context.Context, id string, expireAt time.Time) error
+	// To check any of the given ids is denied
+	IsDenied(ctx context.Context, ids ...string) (bool, error)
+}
+
+// New denylist with the configured store, postgres is used by default so all instances share it
+func NewDenylist(cfg config.Config, db *gorm.DB) Denylist {
+
+	if cfg.TokenDenylistStore == storeMemory {
+		log.Printf("token denylist running on memory store")
+		return NewMemoryDenylist()
+	}
+
+	return NewPostgresDenylist(db)
+}
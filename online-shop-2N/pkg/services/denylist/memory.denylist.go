@@ -0,0 +1,54 @@
+package denylist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryDenylist struct {
+	mu     sync.RWMutex
+	denied map[string]time.Time
+}
+
+func NewMemoryDenylist() Denylist {
+
+	return &memoryDenylist{
+		denied: make(map[string]time.Time),
+	}
+}
+
+func (c *memoryDenylist) Deny(ctx context.Context, id string, expireAt time.Time) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// remove the expired ids so the map not grow forever
+	now := time.Now()
+	for deniedID, deniedExpireAt := range c.denied {
+		if now.After(deniedExpireAt) {
+			delete(c.denied, deniedID)
+		}
+	}
+
+	if expireAt.After(c.denied[id]) {
+		c.denied[id] = expireAt
+	}
+
+	return nil
+}
+
+func (c *memoryDenylist) IsDenied(ctx context.Context, ids ...string) (bool, error) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	for _, id := range ids {
+		if expireAt, ok := c.denied[id]; ok && now.Before(expireAt) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
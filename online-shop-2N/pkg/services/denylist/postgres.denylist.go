@@ -0,0 +1,61 @@
+package denylist
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postgresDenylist struct {
+	DB *gorm.DB
+}
+
+func NewPostgresDenylist(db *gorm.DB) Denylist {
+
+	return &postgresDenylist{
+		DB: db,
+	}
+}
+
+func (c *postgresDenylist) Deny(ctx context.Context, id string, expireAt time.Time) error {
+
+	// remove the expired ids so the table not grow forever
+	query := `DELETE FROM token_denylist WHERE expire_at < NOW()`
+	if err := c.DB.Exec(query).Error; err != nil {
+		return fmt.Errorf("failed to remove expired ids from token denylist \nerror:%w", err)
+	}
+
+	query = `INSERT INTO token_denylist (id, expire_at) VALUES ($1, $2) 
+	ON CONFLICT (id) DO UPDATE SET expire_at = GREATEST(token_denylist.expire_at, EXCLUDED.expire_at)`
+	if err := c.DB.Exec(query, id, expireAt).Error; err != nil {
+		return fmt.Errorf("failed to save id on token denylist \nerror:%w", err)
+	}
+
+	return nil
+}
+
+func (c *postgresDenylist) IsDenied(ctx context.Context, ids ...string) (denied bool, err error) {
+
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	query := `SELECT EXISTS (SELECT 1 FROM token_denylist 
+	WHERE id IN (` + strings.Join(placeholders, ", ") + `) AND expire_at > NOW())`
+	err = c.DB.Raw(query, args...).Scan(&denied).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check id on token denylist \nerror:%w", err)
+	}
+
+	return denied, nil
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (Mailer, error) {
+
+	if dir == "" {
+		dir = "mails"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mails directory \nerror:%w", err)
+	}
+
+	return &fileMailer{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+// To write the mail as an eml file which can be opened with mail clients
+func (c *fileMailer) Send(ctx context.Context, mail Mail) error {
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405"), uuid.NewString())
+
+	err := os.WriteFile(filepath.Join(c.dir, name), buildMessage(c.from, mail), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write mail file \nerror:%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/config"
+)
+
+const (
+	modeSMTP = "smtp"
+	// file mailer write the mails to files of a directory for local testing
+	modeFile = "file"
+	// memory mailer keep the mails only on the running instance
+	modeMemory = "memory"
+)
+
+var ErrMailNotConfigured = errors.New("smtp host or mail from address not configured, set MAILER_MODE to use another mailer")
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// New mailer with the configured mode, when mode not given smtp is used and it should be configured.
+// file and memory mailers don't deliver the mails so they are used only when given explicitly
+func NewMailer(cfg config.Config) (Mailer, error) {
+
+	switch cfg.MailerMode {
+	case modeSMTP, "":
+		return NewSMTPMailer(cfg)
+	case modeFile:
+		log.Printf("mailer writing mails to directory %s", cfg.MailerDir)
+		return NewFileMailer(cfg.MailerDir, cfg.MailFrom)
+	case modeMemory:
+		log.Printf("mailer keeping mails on memory")
+		return NewMemoryMailer(), nil
+	}
+
+	return nil, fmt.Errorf("invalid mailer mode %s", cfg.MailerMode)
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+type MemoryMailer struct {
+	mu    sync.RWMutex
+	mails []Mail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (c *MemoryMailer) Send(ctx context.Context, mail Mail) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.mails = append(c.mails, mail)
+	return nil
+}
+
+// To find all the mails sent to the address
+func (c *MemoryMailer) MailsTo(to string) []Mail {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var mails []Mail
+	for _, mail := range c.mails {
+		if mail.To == to {
+			mails = append(mails, mail)
+		}
+	}
+	return mails
+}
@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"online-shop-2N/pkg/config"
+	"strings"
+)
+
+type smtpMailer struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+func NewSMTPMailer(cfg config.Config) (Mailer, error) {
+
+	if cfg.SMTPHost == "" || cfg.MailFrom == "" {
+		return nil, ErrMailNotConfigured
+	}
+
+	port := cfg.SMTPPort
+	if port == "" {
+		port = "587"
+	}
+
+	var auth smtp.Auth
+	if cfg.SMTPUserName != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUserName, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	return &smtpMailer{
+		address: net.JoinHostPort(cfg.SMTPHost, port),
+		auth:    auth,
+		from:    cfg.MailFrom,
+	}, nil
+}
+
+func (c *smtpMailer) Send(ctx context.Context, mail Mail) error {
+
+	// smtp package upgrade the connection to tls when the server support STARTTLS
+	err := smtp.SendMail(c.address, c.auth, c.from, []string{mail.To}, buildMessage(c.from, mail))
+	if err != nil {
+		return fmt.Errorf("failed to send mail through smtp \nerror:%w", err)
+	}
+
+	return nil
+}
+
+// To build the plain text message with headers, new lines are removed from header values
+func buildMessage(from string, mail Mail) []byte {
+
+	headerValue := strings.NewReplacer("\r", "", "\n", "")
+
+	var message strings.Builder
+	message.WriteString("From: " + headerValue.Replace(from) + "\r\n")
+	message.WriteString("To: " + headerValue.Replace(mail.To) + "\r\n")
+	message.WriteString("Subject: " + headerValue.Replace(mail.Subject) + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+
+	return []byte(message.String())
+}
@@ -0,0 +1,119 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+)
+
+const (
+	appleIssuer   = "https://appleid.apple.com"
+	appleAuthURL  = "https://appleid.apple.com/auth/authorize"
+	appleTokenURL = "https://appleid.apple.com/auth/token"
+
+	appleClientSecretDuration = time.Minute * 5
+)
+
+type appleProvider struct {
+	config     oauth2.Config
+	teamID     string
+	keyID      string
+	privateKey *ecdsa.PrivateKey
+}
+
+// client id of apple is the service id, the client secret is signed with the private key (.p8 file) of the key id
+func NewAppleProvider(clientID, teamID, keyID, privateKeyFile, redirectURL string) (Provider, error) {
+
+	if teamID == "" || keyID == "" || privateKeyFile == "" {
+		return nil, errors.New("team id, key id and private key file are required for apple")
+	}
+
+	keyPEM, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return &appleProvider{
+		config: oauth2.Config{
+			ClientID:    clientID,
+			RedirectURL: redirectURL,
+			Scopes:      []string{"name", "email"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   appleAuthURL,
+				TokenURL:  appleTokenURL,
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+		},
+		teamID:     teamID,
+		keyID:      keyID,
+		privateKey: privateKey,
+	}, nil
+}
+
+func (c *appleProvider) Name() string {
+	return ProviderApple
+}
+
+// apple not support pkce so only the nonce is used,
+// callback is posted as form when the name or email scope is requested
+func (c *appleProvider) AuthCodeURL(flow Flow) string {
+
+	return c.config.AuthCodeURL(flow.State,
+		oauth2.SetAuthURLParam("nonce", flow.Nonce),
+		oauth2.SetAuthURLParam("response_mode", "form_post"),
+	)
+}
+
+// apple have no user info endpoint, the user details are taken from id token
+func (c *appleProvider) FetchUser(ctx context.Context, code string, flow Flow) (User, error) {
+
+	clientSecret, err := c.clientSecret()
+	if err != nil {
+		return User{}, fmt.Errorf("failed to sign client secret: %w", err)
+	}
+	config := c.config
+	config.ClientSecret = clientSecret
+
+	token, err := config.Exchange(ctx, code)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to exchange code for token: %w", err)
+	}
+
+	claims, err := parseIDToken(token, appleIssuer, c.config.ClientID, flow.Nonce)
+	if err != nil {
+		return User{}, err
+	}
+
+	return User{
+		Provider:       ProviderApple,
+		ProviderUserID: claims.Subject,
+		Email:          claims.Email,
+		EmailVerified:  bool(claims.EmailVerified),
+	}, nil
+}
+
+// client secret of apple is a short lived token signed with the private key of team
+func (c *appleProvider) clientSecret() (string, error) {
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    c.teamID,
+		Subject:   c.config.ClientID,
+		Audience:  jwt.ClaimStrings{appleIssuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(appleClientSecretDuration)),
+	})
+	token.Header["kid"] = c.keyID
+
+	return token.SignedString(c.privateKey)
+}
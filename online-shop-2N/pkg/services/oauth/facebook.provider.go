@@ -0,0 +1,76 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	facebookAuthURL  = "https://www.facebook.com/v18.0/dialog/oauth"
+	facebookTokenURL = "https://graph.facebook.com/v18.0/oauth/access_token"
+	facebookUserURL  = "https://graph.facebook.com/v18.0/me?fields=id,first_name,last_name,email,picture.type(large)"
+)
+
+type facebookProvider struct {
+	config oauth2.Config
+}
+
+type facebookUser struct {
+	ID        string `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Picture   struct {
+		Data struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	} `json:"picture"`
+}
+
+func NewFacebookProvider(clientID, clientSecret, redirectURL string) Provider {
+	return &facebookProvider{
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"email", "public_profile"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  facebookAuthURL,
+				TokenURL: facebookTokenURL,
+			},
+		},
+	}
+}
+
+func (c *facebookProvider) Name() string {
+	return ProviderFacebook
+}
+
+func (c *facebookProvider) AuthCodeURL(flow Flow) string {
+	return c.config.AuthCodeURL(flow.State, flow.pkceAuthOptions()...)
+}
+
+// facebook not tell the email is verified, so it's not used to find the existing accounts
+func (c *facebookProvider) FetchUser(ctx context.Context, code string, flow Flow) (User, error) {
+
+	token, err := c.config.Exchange(ctx, code, flow.pkceExchangeOptions()...)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to exchange code for token: %w", err)
+	}
+
+	var profile facebookUser
+	if err := getJSON(c.config.Client(ctx, token), facebookUserURL, &profile); err != nil {
+		return User{}, fmt.Errorf("failed to get user profile: %w", err)
+	}
+
+	return User{
+		Provider:       ProviderFacebook,
+		ProviderUserID: profile.ID,
+		Email:          profile.Email,
+		FirstName:      profile.FirstName,
+		LastName:       profile.LastName,
+		AvatarURL:      profile.Picture.Data.URL,
+	}, nil
+}
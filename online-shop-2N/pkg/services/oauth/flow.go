@@ -0,0 +1,123 @@
+package oauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// time to complete the sign in on provider
+const FlowDuration = time.Minute * 10
+
+var ErrInvalidFlow = errors.New("invalid or expired oauth flow")
+
+// flow of oauth from the redirect to provider until the callback, it's kept by the client as a signed value
+// so no state is saved on server
+type Flow struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	// nonce is checked on the id token and the code verifier is sent on code exchange for pkce
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	// id of the signed in user when the provider is linking to the user
+	LinkUserID uint      `json:"link_user_id,omitempty"`
+	ExpireAt   time.Time `json:"expire_at"`
+}
+
+func NewFlow(provider string, linkUserID uint) (Flow, error) {
+
+	state, err := randomString()
+	if err != nil {
+		return Flow{}, err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return Flow{}, err
+	}
+
+	return Flow{
+		Provider:     provider,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		LinkUserID:   linkUserID,
+		ExpireAt:     time.Now().Add(FlowDuration),
+	}, nil
+}
+
+// To sign the flow with the key, the signed value is the json of flow with its hmac
+func (f Flow) Sign(key []byte) (string, error) {
+
+	payload, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signFlow(encodedPayload, key)), nil
+}
+
+// To parse the signed value of flow, the signature and expire time are verified
+func ParseFlow(signedFlow string, key []byte) (Flow, error) {
+
+	encodedPayload, encodedSignature, found := strings.Cut(signedFlow, ".")
+	if !found {
+		return Flow{}, ErrInvalidFlow
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signFlow(encodedPayload, key)) {
+		return Flow{}, ErrInvalidFlow
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return Flow{}, ErrInvalidFlow
+	}
+
+	var flow Flow
+	if err := json.Unmarshal(payload, &flow); err != nil {
+		return Flow{}, ErrInvalidFlow
+	}
+	if time.Now().After(flow.ExpireAt) {
+		return Flow{}, ErrInvalidFlow
+	}
+
+	return flow, nil
+}
+
+// options of pkce to send with the auth code url
+func (f Flow) pkceAuthOptions() []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(f.CodeVerifier)}
+}
+
+// options of pkce to send on code exchange
+func (f Flow) pkceExchangeOptions() []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{oauth2.VerifierOption(f.CodeVerifier)}
+}
+
+func signFlow(encodedPayload string, key []byte) []byte {
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encodedPayload))
+
+	return mac.Sum(nil)
+}
+
+func randomString() (string, error) {
+
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
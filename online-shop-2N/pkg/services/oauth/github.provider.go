@@ -0,0 +1,95 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	githubAuthURL   = "https://github.com/login/oauth/authorize"
+	githubTokenURL  = "https://github.com/login/oauth/access_token"
+	githubUserURL   = "https://api.github.com/user"
+	githubEmailsURL = "https://api.github.com/user/emails"
+)
+
+type githubProvider struct {
+	config oauth2.Config
+}
+
+type githubUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func NewGithubProvider(clientID, clientSecret, redirectURL string) Provider {
+	return &githubProvider{
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  githubAuthURL,
+				TokenURL: githubTokenURL,
+			},
+		},
+	}
+}
+
+func (c *githubProvider) Name() string {
+	return ProviderGithub
+}
+
+func (c *githubProvider) AuthCodeURL(flow Flow) string {
+	return c.config.AuthCodeURL(flow.State, flow.pkceAuthOptions()...)
+}
+
+// email of profile can be private, so the primary email with its verified status is taken from the emails api
+func (c *githubProvider) FetchUser(ctx context.Context, code string, flow Flow) (User, error) {
+
+	token, err := c.config.Exchange(ctx, code, flow.pkceExchangeOptions()...)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to exchange code for token: %w", err)
+	}
+	client := c.config.Client(ctx, token)
+
+	var profile githubUser
+	if err := getJSON(client, githubUserURL, &profile); err != nil {
+		return User{}, fmt.Errorf("failed to get user profile: %w", err)
+	}
+
+	var emails []githubEmail
+	if err := getJSON(client, githubEmailsURL, &emails); err != nil {
+		return User{}, fmt.Errorf("failed to get user emails: %w", err)
+	}
+
+	user := User{
+		Provider:       ProviderGithub,
+		ProviderUserID: strconv.FormatInt(profile.ID, 10),
+		AvatarURL:      profile.AvatarURL,
+	}
+	user.FirstName, user.LastName = splitName(profile.Name)
+	if user.FirstName == "" {
+		user.FirstName = profile.Login
+	}
+
+	for _, email := range emails {
+		if email.Primary {
+			user.Email, user.EmailVerified = email.Email, email.Verified
+			break
+		}
+	}
+
+	return user, nil
+}
@@ -0,0 +1,26 @@
+package oauth
+
+import "golang.org/x/oauth2"
+
+const (
+	googleIssuer      = "https://accounts.google.com"
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+// google is an openid connect provider with known endpoints, so the discovery is not needed
+func NewGoogleProvider(clientID, clientSecret, redirectURL string) Provider {
+
+	return newOIDCProvider(ProviderGoogle, googleIssuer, oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   googleAuthURL,
+			TokenURL:  googleTokenURL,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}, googleUserInfoURL)
+}
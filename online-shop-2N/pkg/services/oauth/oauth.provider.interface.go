@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Provider interface {
+	Name() string
+	// AuthCodeURL give the url of provider which the user is redirected to sign in with the state of flow
+	AuthCodeURL(flow Flow) string
+	// FetchUser exchange the code of callback for token and fetch the user details from provider,
+	// the code verifier and nonce of the same flow are used when the provider support them
+	FetchUser(ctx context.Context, code string, flow Flow) (User, error)
+}
+
+var (
+	ErrProviderNotRegistered = errors.New("oauth provider not registered")
+	ErrInvalidIDToken        = errors.New("invalid id token of oauth provider")
+)
+
+// user details given by provider, email is empty when the user not shared it
+type User struct {
+	Provider       string
+	ProviderUserID string
+	Email          string
+	// email is verified by provider, only verified emails are used to find the existing accounts
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+	AvatarURL     string
+}
+
+// To get a json api of provider with the client of oauth token
+func getJSON(client *http.Client, url string, value any) error {
+
+	res, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed with status %s", url, res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(value)
+}
+
+// split the full name which some providers give to first and last name
+func splitName(name string) (firstName, lastName string) {
+
+	names := strings.Fields(name)
+	if len(names) == 0 {
+		return "", ""
+	}
+
+	return names[0], strings.Join(names[1:], " ")
+}
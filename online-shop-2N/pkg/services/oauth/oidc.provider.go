@@ -0,0 +1,190 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+)
+
+// generic openid connect provider, google and apple are also using the same claims on id token
+type oidcProvider struct {
+	name        string
+	issuer      string
+	config      oauth2.Config
+	userInfoURL string
+}
+
+// claims of id token and user info endpoint
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string    `json:"email"`
+	EmailVerified boolClaim `json:"email_verified"`
+	GivenName     string    `json:"given_name"`
+	FamilyName    string    `json:"family_name"`
+	Name          string    `json:"name"`
+	Picture       string    `json:"picture"`
+	Nonce         string    `json:"nonce"`
+}
+
+// some providers like apple give the boolean claims as string
+type boolClaim bool
+
+func (c *boolClaim) UnmarshalJSON(data []byte) error {
+	*c = boolClaim(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+// discovery document of the issuer
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// To create an openid connect provider with the endpoints found on discovery of issuer
+func NewOIDCProvider(ctx context.Context, name, issuer, clientID, clientSecret,
+	redirectURL string) (Provider, error) {
+
+	issuer = strings.TrimRight(issuer, "/")
+	discoveryURL := issuer + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discovery document: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get discovery document with status %s", res.Status)
+	}
+
+	var discovery oidcDiscovery
+	if err := json.NewDecoder(res.Body).Decode(&discovery); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery document: %w", err)
+	}
+	if discovery.Issuer != issuer {
+		return nil, fmt.Errorf("issuer '%s' of discovery document not match with '%s'", discovery.Issuer, issuer)
+	}
+
+	return newOIDCProvider(name, issuer, oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, discovery.UserInfoEndpoint), nil
+}
+
+func newOIDCProvider(name, issuer string, config oauth2.Config, userInfoURL string) *oidcProvider {
+	return &oidcProvider{
+		name:        name,
+		issuer:      issuer,
+		config:      config,
+		userInfoURL: userInfoURL,
+	}
+}
+
+func (c *oidcProvider) Name() string {
+	return c.name
+}
+
+func (c *oidcProvider) AuthCodeURL(flow Flow) string {
+
+	opts := append(flow.pkceAuthOptions(), oauth2.SetAuthURLParam("nonce", flow.Nonce))
+	return c.config.AuthCodeURL(flow.State, opts...)
+}
+
+// user details are taken from the user info endpoint when the provider have it otherwise from the id token
+func (c *oidcProvider) FetchUser(ctx context.Context, code string, flow Flow) (User, error) {
+
+	token, err := c.config.Exchange(ctx, code, flow.pkceExchangeOptions()...)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to exchange code for token: %w", err)
+	}
+
+	claims, err := parseIDToken(token, c.issuer, c.config.ClientID, flow.Nonce)
+	if err != nil {
+		return User{}, err
+	}
+
+	if c.userInfoURL != "" {
+		var userInfo idTokenClaims
+		err = getJSON(c.config.Client(ctx, token), c.userInfoURL, &userInfo)
+		if err != nil {
+			return User{}, fmt.Errorf("failed to get user info: %w", err)
+		}
+		// user info must be of the same user of id token
+		if userInfo.Subject != claims.Subject {
+			return User{}, fmt.Errorf("%w: subject of user info not match with id token", ErrInvalidIDToken)
+		}
+		claims = userInfo
+	}
+
+	return c.userFromClaims(claims), nil
+}
+
+func (c *oidcProvider) userFromClaims(claims idTokenClaims) User {
+
+	firstName, lastName := claims.GivenName, claims.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName = splitName(claims.Name)
+	}
+
+	return User{
+		Provider:       c.name,
+		ProviderUserID: claims.Subject,
+		Email:          claims.Email,
+		EmailVerified:  bool(claims.EmailVerified),
+		FirstName:      firstName,
+		LastName:       lastName,
+		AvatarURL:      claims.Picture,
+	}
+}
+
+// id token is received directly from the token endpoint of provider over tls,
+// so the signature is not verified but the issuer, audience, expire time and nonce of flow are checked
+func parseIDToken(token *oauth2.Token, issuer, clientID, nonce string) (idTokenClaims, error) {
+
+	var claims idTokenClaims
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return claims, fmt.Errorf("%w: id token not found on token response", ErrInvalidIDToken)
+	}
+
+	_, _, err := jwt.NewParser().ParseUnverified(rawIDToken, &claims)
+	if err != nil {
+		return claims, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	now := time.Now()
+	switch {
+	case !claims.VerifyIssuer(issuer, true):
+		return claims, fmt.Errorf("%w: invalid issuer", ErrInvalidIDToken)
+	case !claims.VerifyAudience(clientID, true):
+		return claims, fmt.Errorf("%w: invalid audience", ErrInvalidIDToken)
+	case !claims.VerifyExpiresAt(now, true):
+		return claims, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	case subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1:
+		return claims, fmt.Errorf("%w: invalid nonce", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return claims, fmt.Errorf("%w: subject not found", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
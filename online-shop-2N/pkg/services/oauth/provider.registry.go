@@ -0,0 +1,131 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/config"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderGoogle   = "google"
+	ProviderGithub   = "github"
+	ProviderFacebook = "facebook"
+	ProviderApple    = "apple"
+	// name of the generic openid connect provider when it's not configured
+	defaultOIDCProviderName = "oidc"
+
+	discoveryTimeout = time.Second * 10
+)
+
+type Registry interface {
+	Get(name string) (Provider, error)
+	// Names of the registered providers on sorted order
+	Names() []string
+}
+
+type registry struct {
+	providers map[string]Provider
+}
+
+// To create a registry with the given providers, the later one override the same name
+func NewRegistry(providers ...Provider) Registry {
+
+	registered := make(map[string]Provider, len(providers))
+	for _, provider := range providers {
+		registered[provider.Name()] = provider
+	}
+
+	return &registry{
+		providers: registered,
+	}
+}
+
+// New registry with the providers which are configured, a provider is enabled when its client id is configured
+func NewProviderRegistry(cfg config.Config) (Registry, error) {
+
+	var providers []Provider
+
+	if cfg.GoathClientID != "" {
+		redirectURL := cfg.GoauthCallbackUrl
+		if redirectURL == "" {
+			redirectURL = callbackURL(cfg, ProviderGoogle)
+		}
+		providers = append(providers, NewGoogleProvider(cfg.GoathClientID, cfg.GoauthClientSecret, redirectURL))
+	}
+
+	if cfg.GithubClientID != "" {
+		providers = append(providers, NewGithubProvider(cfg.GithubClientID, cfg.GithubClientSecret,
+			callbackURL(cfg, ProviderGithub)))
+	}
+
+	if cfg.FacebookClientID != "" {
+		providers = append(providers, NewFacebookProvider(cfg.FacebookClientID, cfg.FacebookClientSecret,
+			callbackURL(cfg, ProviderFacebook)))
+	}
+
+	if cfg.AppleClientID != "" {
+		provider, err := NewAppleProvider(cfg.AppleClientID, cfg.AppleTeamID, cfg.AppleKeyID,
+			cfg.ApplePrivateKeyFile, callbackURL(cfg, ProviderApple))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create apple oauth provider: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	if cfg.OIDCClientID != "" {
+		name := cfg.OIDCProviderName
+		if name == "" {
+			name = defaultOIDCProviderName
+		}
+		for _, provider := range providers {
+			if provider.Name() == name {
+				return nil, fmt.Errorf("oidc provider name '%s' is already used by another oauth provider", name)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+		defer cancel()
+
+		provider, err := NewOIDCProvider(ctx, name, cfg.OIDCIssuer, cfg.OIDCClientID, cfg.OIDCClientSecret,
+			callbackURL(cfg, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create oidc provider: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	registry := NewRegistry(providers...)
+	log.Printf("oauth providers enabled: %v", registry.Names())
+
+	return registry, nil
+}
+
+func (c *registry) Get(name string) (Provider, error) {
+
+	provider, ok := c.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w with name '%s'", ErrProviderNotRegistered, name)
+	}
+
+	return provider, nil
+}
+
+func (c *registry) Names() []string {
+
+	names := make([]string, 0, len(c.providers))
+	for name := range c.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// callback url of a provider is the redirect base url with the provider name
+func callbackURL(cfg config.Config, name string) string {
+	return strings.TrimRight(cfg.OAuthRedirectBaseURL, "/") + "/" + name + "/callback"
+}
@@ -0,0 +1,62 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/config"
+)
+
+const (
+	// twilio verify generate, send and verify the codes
+	providerTwilio = "twilio"
+	// local provider generate the codes and send them with the sms sender
+	providerLocal = "local"
+)
+
+var (
+	ErrOtpProviderNotConfigured = errors.New("otp provider not configured, set OTP_PROVIDER to twilio or local")
+	ErrOtpHashKeyNotConfigured  = errors.New("otp hash key not configured or same as the user auth key")
+	ErrTwilioNotConfigured      = errors.New("twilio account sid, auth token and service sid required for twilio otp")
+)
+
+type OtpAuth interface {
+	// To send otp to the phone number on E.164 format.
+	// the hash of code is returned when the code is verified by the service itself
+	// and it's empty when the code is verified by the provider
+	SendOtp(ctx context.Context, phoneNumber string) (codeHash string, err error)
+	// To verify the code, the code hash is the one returned on send
+	VerifyOtp(ctx context.Context, phoneNumber, code, codeHash string) (valid bool, err error)
+}
+
+// New otp auth with the configured provider, the provider should be given explicitly
+// so a missing config fails on start instead of sending the codes with a local sender
+func NewOtpAuth(cfg config.Config) (OtpAuth, error) {
+
+	switch cfg.OtpProvider {
+	case providerTwilio:
+		if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" || cfg.TwilioServiceID == "" {
+			return nil, ErrTwilioNotConfigured
+		}
+		return NewTwilioOtpAuth(cfg), nil
+	case providerLocal:
+	case "":
+		return nil, ErrOtpProviderNotConfigured
+	default:
+		return nil, fmt.Errorf("invalid otp provider %s", cfg.OtpProvider)
+	}
+
+	// codes are hashed with a separate key so a leaked key of tokens not reveal the codes
+	if cfg.OtpHashKey == "" || cfg.OtpHashKey == cfg.UserAuthKey {
+		return nil, ErrOtpHashKeyNotConfigured
+	}
+
+	sender, err := NewSMSSender(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("otp codes are generated locally and sent with the %s sms sender", cfg.SMSSender)
+	return NewLocalOtpAuth(sender, cfg.OtpHashKey), nil
+}
@@ -1,6 +0,0 @@
-package otp
-
-type OtpAuth interface {
-	SentOtp(phoneNumber string) (string, error)
-	VerifyOtp(phoneNumber string, code string) (valid bool, err error)
-}
@@ -0,0 +1,103 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrSMSSenderNotConfigured = errors.New("sms sender not configured, set SMS_SENDER to console or file")
+
+const (
+	// console sender log only the recipient of messages, the message is never logged as it have the code
+	senderConsole = "console"
+	// file sender append the messages to a file for local testing
+	senderFile = "file"
+)
+
+// To send the sms of otp, implement it for the sms gateway to use
+type SMSSender interface {
+	SendSMS(ctx context.Context, phoneNumber, message string) error
+}
+
+// New sms sender with the configured sender, the sender should be given explicitly
+func NewSMSSender(cfg config.Config) (SMSSender, error) {
+
+	switch cfg.SMSSender {
+	case senderConsole:
+		return NewConsoleSMSSender(), nil
+	case senderFile:
+		return NewFileSMSSender(cfg.SMSSenderFile)
+	case "":
+		return nil, ErrSMSSenderNotConfigured
+	}
+
+	return nil, fmt.Errorf("invalid sms sender %s", cfg.SMSSender)
+}
+
+type consoleSMSSender struct{}
+
+func NewConsoleSMSSender() SMSSender {
+	return &consoleSMSSender{}
+}
+
+func (c *consoleSMSSender) SendSMS(ctx context.Context, phoneNumber, message string) error {
+
+	log.Printf("sms of %d characters to %s not sent by console sender", len(message), maskPhoneNumber(phoneNumber))
+	return nil
+}
+
+// To show only the last four digits of phone number on logs
+func maskPhoneNumber(phoneNumber string) string {
+
+	if len(phoneNumber) <= 4 {
+		return strings.Repeat("*", len(phoneNumber))
+	}
+	return strings.Repeat("*", len(phoneNumber)-4) + phoneNumber[len(phoneNumber)-4:]
+}
+
+type fileSMSSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSMSSender(path string) (SMSSender, error) {
+
+	if path == "" {
+		path = "sms.log"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory of sms file \nerror:%w", err)
+	}
+
+	return &fileSMSSender{
+		path: path,
+	}, nil
+}
+
+// To append the message as a line to the file
+func (c *fileSMSSender) SendSMS(ctx context.Context, phoneNumber, message string) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open sms file \nerror:%w", err)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), phoneNumber, message)
+	if err != nil {
+		return fmt.Errorf("failed to write sms file \nerror:%w", err)
+	}
+
+	return nil
+}
@@ -1,8 +1,7 @@
 package otp
 
 import (
-	"fmt"
-	"log"
+	"context"
 	"online-shop-2N/pkg/config"
 
 	"github.com/twilio/twilio-go"
@@ -14,7 +13,7 @@ type twilioOtp struct {
 	client    twilio.RestClient
 }
 
-func NewOtpAuth(cfg config.Config) OtpAuth {
+func NewTwilioOtpAuth(cfg config.Config) OtpAuth {
 	client := *twilio.NewRestClientWithParams(twilio.ClientParams{
 		Username: cfg.TwilioAccountSID,
 		Password: cfg.TwilioAuthToken,
@@ -26,35 +25,31 @@ func NewOtpAuth(cfg config.Config) OtpAuth {
 	}
 }
 
-func (c *twilioOtp) SentOtp(phoneNumber string) (string, error) {
+// twilio keeps the code, so there is no code hash to store
+func (c *twilioOtp) SendOtp(ctx context.Context, phoneNumber string) (string, error) {
 
 	params := &twilioApi.CreateVerificationParams{}
 	params.SetTo(phoneNumber)
 	params.SetChannel("sms")
-	log.Print(phoneNumber)
 
-	resp, err := c.client.VerifyV2.CreateVerification(c.serviceID, params)
+	_, err := c.client.VerifyV2.CreateVerification(c.serviceID, params)
 	if err != nil {
 		return "", err
 	}
 
-	return *resp.Sid, nil
+	return "", nil
 }
 
-func (c *twilioOtp) VerifyOtp(phoneNumber string, code string) (valid bool, err error) {
+func (c *twilioOtp) VerifyOtp(ctx context.Context, phoneNumber, code, codeHash string) (valid bool, err error) {
 
 	params := &twilioApi.CreateVerificationCheckParams{}
 	params.SetTo(phoneNumber)
 	params.SetCode(code)
 
 	resp, err := c.client.VerifyV2.CreateVerificationCheck(c.serviceID, params)
-
 	if err != nil {
 		return false, err
 	}
-	if resp != nil && *resp.Status != "approved" {
-		return false, fmt.Errorf("invalid otp")
-	}
 
-	return true, nil
+	return resp != nil && resp.Status != nil && *resp.Status == "approved", nil
 }
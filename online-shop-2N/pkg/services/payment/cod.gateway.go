@@ -0,0 +1,43 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+)
+
+// cash on delivery have nothing to create or verify on a gateway, the amount is collected on delivery
+type codGateway struct{}
+
+func NewCodGateway() PaymentGateway {
+	return &codGateway{}
+}
+
+func (c *codGateway) PaymentType() commonConstant.PaymentType {
+	return commonConstant.CodPayment
+}
+
+func (c *codGateway) CreateIntent(ctx context.Context, req CreateIntentRequest) (CreateIntentResponse, error) {
+
+	return CreateIntentResponse{
+		GatewayAmount: req.Amount,
+	}, nil
+}
+
+func (c *codGateway) Verify(ctx context.Context, req VerifyRequest) (VerifyResponse, error) {
+	return VerifyResponse{}, nil
+}
+
+// cod amount can't send back through a gateway
+func (c *codGateway) Refund(ctx context.Context, req RefundRequest) (RefundResponse, error) {
+	return RefundResponse{}, ErrNotSupported
+}
+
+func (c *codGateway) FindRefund(ctx context.Context, gatewayRefundID string) (RefundResponse, error) {
+	return RefundResponse{}, ErrNotSupported
+}
+
+func (c *codGateway) ParseWebhook(ctx context.Context, payload []byte, header http.Header) (WebhookEvent, error) {
+	return WebhookEvent{}, ErrNotSupported
+}
@@ -0,0 +1,156 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"online-shop-2N/pkg/utils"
+	"sync"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// verify with this payment id to get a not approved payment from fake gateway
+	FakeFailedPaymentID = "fake_payment_failed"
+
+	// fake webhook events
+	FakeEventPaymentSucceeded = "payment.succeeded"
+	FakeEventPaymentFailed    = "payment.failed"
+	FakeEventPaymentRefunded  = "payment.refunded"
+	FakeEventRefundFailed     = "refund.failed"
+)
+
+// fake gateway keep the intents on memory and approve every payment of a created intent
+// used to run the checkout end to end without any network call, its webhooks have no signature
+// so it's registered only on development or test environment
+type fakeGateway struct {
+	paymentType commonConstant.PaymentType
+	mu          sync.Mutex
+	intents     map[string]CreateIntentRequest
+}
+
+func NewFakeGateway(paymentType commonConstant.PaymentType) PaymentGateway {
+
+	return &fakeGateway{
+		paymentType: paymentType,
+		intents:     make(map[string]CreateIntentRequest),
+	}
+}
+
+func (c *fakeGateway) PaymentType() commonConstant.PaymentType {
+	return c.paymentType
+}
+
+func (c *fakeGateway) CreateIntent(ctx context.Context, req CreateIntentRequest) (CreateIntentResponse, error) {
+
+	gatewayOrderID := "fake_order_" + uuid.NewString()
+
+	c.mu.Lock()
+	c.intents[gatewayOrderID] = req
+	c.mu.Unlock()
+
+	return CreateIntentResponse{
+		GatewayOrderID: gatewayOrderID,
+		GatewayAmount:  req.Amount,
+		ClientSecret:   gatewayOrderID + "_secret",
+		PublicKey:      "fake_public_key",
+	}, nil
+}
+
+func (c *fakeGateway) Verify(ctx context.Context, req VerifyRequest) (VerifyResponse, error) {
+
+	if req.GatewayPaymentID == FakeFailedPaymentID {
+		return VerifyResponse{}, ErrPaymentNotApproved
+	}
+
+	// payment id can be the intent id like stripe
+	gatewayOrderID := req.GatewayOrderID
+	if gatewayOrderID == "" {
+		gatewayOrderID = req.GatewayPaymentID
+	}
+
+	c.mu.Lock()
+	intent, ok := c.intents[gatewayOrderID]
+	c.mu.Unlock()
+
+	if !ok {
+		return VerifyResponse{}, utils.AppendMessageToError(ErrPaymentNotApproved,
+			fmt.Sprintf("fake intent %s not exist", gatewayOrderID))
+	}
+
+	return VerifyResponse{
+		GatewayOrderID:   gatewayOrderID,
+		GatewayPaymentID: req.GatewayPaymentID,
+		Amount:           intent.Amount,
+	}, nil
+}
+
+func (c *fakeGateway) Refund(ctx context.Context, req RefundRequest) (RefundResponse, error) {
+
+	return RefundResponse{
+		GatewayRefundID: "fake_refund_" + uuid.NewString(),
+		Status:          RefundStatusProcessed,
+	}, nil
+}
+
+// refunds of fake gateway are processed at once
+func (c *fakeGateway) FindRefund(ctx context.Context, gatewayRefundID string) (RefundResponse, error) {
+
+	return RefundResponse{
+		GatewayRefundID: gatewayRefundID,
+		Status:          RefundStatusProcessed,
+	}, nil
+}
+
+// fake webhook body, there is no signature on fake gateway
+type fakeWebhookEvent struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	OrderID   string `json:"order_id"`
+	PaymentID string `json:"payment_id"`
+	RefundID  string `json:"refund_id"`
+	Amount    uint   `json:"amount"`
+	Reason    string `json:"reason"`
+	Partial   bool   `json:"partial"`
+}
+
+func (c *fakeGateway) ParseWebhook(ctx context.Context, payload []byte, header http.Header) (WebhookEvent, error) {
+
+	var event fakeWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return WebhookEvent{}, utils.PrependMessageToError(err, "failed to parse fake webhook event")
+	}
+	// duplicates of events are found by the id, so an event without id can be processed any number of times
+	if event.ID == "" {
+		return WebhookEvent{}, utils.AppendMessageToError(ErrInvalidWebhookEvent, "fake webhook event id is empty")
+	}
+
+	webhookEvent := WebhookEvent{
+		EventID:          event.ID,
+		GatewayEventType: event.Type,
+		Type:             WebhookUnhandled,
+		GatewayOrderID:   event.OrderID,
+		GatewayPaymentID: event.PaymentID,
+		GatewayRefundID:  event.RefundID,
+		Amount:           event.Amount,
+		FailureReason:    event.Reason,
+		FullyRefunded:    !event.Partial,
+	}
+
+	switch event.Type {
+	case FakeEventPaymentSucceeded:
+		webhookEvent.Type = WebhookPaymentSucceeded
+	case FakeEventPaymentFailed:
+		webhookEvent.Type = WebhookPaymentFailed
+	case FakeEventPaymentRefunded:
+		webhookEvent.Type = WebhookPaymentRefunded
+	case FakeEventRefundFailed:
+		webhookEvent.Type = WebhookRefundFailed
+	}
+
+	return webhookEvent, nil
+}
@@ -0,0 +1,164 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"online-shop-2N/pkg/config"
+	"testing"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+)
+
+func TestNewPaymentGatewayRegistryFakeMode(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		appEnv  string
+		wantErr error
+	}{
+		{name: "development", appEnv: config.EnvDevelopment},
+		{name: "test", appEnv: config.EnvTest},
+		{name: "production", appEnv: config.EnvProduction, wantErr: ErrFakeGatewayNotAllowed},
+		{name: "not configured", appEnv: "", wantErr: ErrFakeGatewayNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			_, err := NewPaymentGatewayRegistry(config.Config{
+				AppEnv:             test.appEnv,
+				PaymentGatewayMode: gatewayModeFake,
+			})
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// To run the checkout of an online payment with the fake gateway from the intent to the webhook and refund
+func TestFakeGatewayCheckout(t *testing.T) {
+
+	ctx := context.Background()
+
+	registry, err := NewPaymentGatewayRegistry(config.Config{
+		AppEnv:             config.EnvTest,
+		PaymentGatewayMode: gatewayModeFake,
+	})
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	for _, paymentType := range []commonConstant.PaymentType{
+		commonConstant.RazopayPayment, commonConstant.StripePayment,
+	} {
+		t.Run(string(paymentType), func(t *testing.T) {
+
+			gateway, err := registry.Get(paymentType)
+			if err != nil {
+				t.Fatalf("failed to get gateway: %v", err)
+			}
+
+			intent, err := gateway.CreateIntent(ctx, CreateIntentRequest{ShopOrderID: 1, Amount: 500})
+			if err != nil {
+				t.Fatalf("failed to create intent: %v", err)
+			}
+			if intent.GatewayOrderID == "" || intent.GatewayAmount != 500 {
+				t.Fatalf("got intent %+v, want order id with amount 500", intent)
+			}
+
+			verified, err := gateway.Verify(ctx, VerifyRequest{
+				GatewayOrderID:   intent.GatewayOrderID,
+				GatewayPaymentID: "fake_payment",
+			})
+			if err != nil {
+				t.Fatalf("failed to verify payment: %v", err)
+			}
+			if verified.GatewayOrderID != intent.GatewayOrderID || verified.Amount != 500 {
+				t.Fatalf("got verified %+v, want intent %s with amount 500", verified, intent.GatewayOrderID)
+			}
+
+			// payment id is the intent id like stripe
+			verified, err = gateway.Verify(ctx, VerifyRequest{GatewayPaymentID: intent.GatewayOrderID})
+			if err != nil || verified.GatewayOrderID != intent.GatewayOrderID {
+				t.Fatalf("got verified %+v with error %v, want intent %s", verified, err, intent.GatewayOrderID)
+			}
+
+			_, err = gateway.Verify(ctx, VerifyRequest{
+				GatewayOrderID:   intent.GatewayOrderID,
+				GatewayPaymentID: FakeFailedPaymentID,
+			})
+			if !errors.Is(err, ErrPaymentNotApproved) {
+				t.Fatalf("got error %v on failed payment, want %v", err, ErrPaymentNotApproved)
+			}
+
+			_, err = gateway.Verify(ctx, VerifyRequest{GatewayOrderID: "fake_order_not_exist"})
+			if !errors.Is(err, ErrPaymentNotApproved) {
+				t.Fatalf("got error %v on unknown intent, want %v", err, ErrPaymentNotApproved)
+			}
+
+			payload, _ := json.Marshal(fakeWebhookEvent{
+				ID:        "evt_1",
+				Type:      FakeEventPaymentSucceeded,
+				OrderID:   intent.GatewayOrderID,
+				PaymentID: "fake_payment",
+				Amount:    500,
+			})
+			event, err := gateway.ParseWebhook(ctx, payload, nil)
+			if err != nil {
+				t.Fatalf("failed to parse webhook: %v", err)
+			}
+			if event.Type != WebhookPaymentSucceeded || event.EventID != "evt_1" ||
+				event.GatewayOrderID != intent.GatewayOrderID || event.Amount != 500 {
+				t.Fatalf("got webhook event %+v, want payment succeeded of intent with amount 500", event)
+			}
+
+			refund, err := gateway.Refund(ctx, RefundRequest{GatewayPaymentID: "fake_payment", Amount: 500})
+			if err != nil || refund.Status != RefundStatusProcessed {
+				t.Fatalf("got refund %+v with error %v, want processed", refund, err)
+			}
+			found, err := gateway.FindRefund(ctx, refund.GatewayRefundID)
+			if err != nil || found.Status != RefundStatusProcessed {
+				t.Fatalf("got refund %+v with error %v, want processed", found, err)
+			}
+		})
+	}
+}
+
+func TestFakeGatewayParseWebhook(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		payload  string
+		wantType WebhookEventType
+		wantErr  error
+	}{
+		{name: "payment succeeded", payload: `{"id":"evt_1","type":"payment.succeeded"}`,
+			wantType: WebhookPaymentSucceeded},
+		{name: "payment failed", payload: `{"id":"evt_2","type":"payment.failed"}`,
+			wantType: WebhookPaymentFailed},
+		{name: "payment refunded", payload: `{"id":"evt_3","type":"payment.refunded"}`,
+			wantType: WebhookPaymentRefunded},
+		{name: "refund failed", payload: `{"id":"evt_4","type":"refund.failed"}`,
+			wantType: WebhookRefundFailed},
+		{name: "unknown type", payload: `{"id":"evt_5","type":"payment.disputed"}`,
+			wantType: WebhookUnhandled},
+		{name: "empty event id", payload: `{"type":"payment.succeeded"}`, wantErr: ErrInvalidWebhookEvent},
+	}
+
+	gateway := NewFakeGateway(commonConstant.RazopayPayment)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			event, err := gateway.ParseWebhook(context.Background(), []byte(test.payload), nil)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err == nil && event.Type != test.wantType {
+				t.Fatalf("got event type %v, want %v", event.Type, test.wantType)
+			}
+		})
+	}
+}
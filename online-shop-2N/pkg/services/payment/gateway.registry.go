@@ -0,0 +1,69 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/config"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+)
+
+// fake mode register the fake gateway in place of online payment gateways
+const gatewayModeFake = "fake"
+
+var ErrFakeGatewayNotAllowed = errors.New("fake payment gateway is allowed only on development or test environment")
+
+type Registry interface {
+	Get(paymentType commonConstant.PaymentType) (PaymentGateway, error)
+}
+
+type registry struct {
+	gateways map[commonConstant.PaymentType]PaymentGateway
+}
+
+// To create a registry with the given gateways, the later one override the same payment type
+func NewRegistry(gateways ...PaymentGateway) Registry {
+
+	registered := make(map[commonConstant.PaymentType]PaymentGateway, len(gateways))
+	for _, gateway := range gateways {
+		registered[gateway.PaymentType()] = gateway
+	}
+
+	return &registry{
+		gateways: registered,
+	}
+}
+
+// New registry with all the payment gateways of the application,
+// fake mode should be given with the development or test environment
+func NewPaymentGatewayRegistry(cfg config.Config) (Registry, error) {
+
+	if cfg.PaymentGatewayMode == gatewayModeFake {
+		if cfg.AppEnv != config.EnvDevelopment && cfg.AppEnv != config.EnvTest {
+			return nil, ErrFakeGatewayNotAllowed
+		}
+		log.Printf("payment gateways running on fake mode on %s environment", cfg.AppEnv)
+		return NewRegistry(
+			NewCodGateway(),
+			NewFakeGateway(commonConstant.RazopayPayment),
+			NewFakeGateway(commonConstant.StripePayment),
+		), nil
+	}
+
+	return NewRegistry(
+		NewCodGateway(),
+		NewRazorpayGateway(cfg),
+		NewStripeGateway(cfg),
+	), nil
+}
+
+func (c *registry) Get(paymentType commonConstant.PaymentType) (PaymentGateway, error) {
+
+	gateway, ok := c.gateways[paymentType]
+	if !ok {
+		return nil, fmt.Errorf("%w for payment type '%s'", ErrGatewayNotRegistered, paymentType)
+	}
+
+	return gateway, nil
+}
@@ -0,0 +1,114 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+)
+
+type PaymentGateway interface {
+	PaymentType() commonConstant.PaymentType
+	// CreateIntent create the order/intent on gateway which the user pay for
+	CreateIntent(ctx context.Context, req CreateIntentRequest) (CreateIntentResponse, error)
+	// Verify check the payment of an intent is completed on gateway and return the intent and amount paid
+	Verify(ctx context.Context, req VerifyRequest) (VerifyResponse, error)
+	Refund(ctx context.Context, req RefundRequest) (RefundResponse, error)
+	// FindRefund find the current status of a refund made on gateway
+	FindRefund(ctx context.Context, gatewayRefundID string) (RefundResponse, error)
+	// ParseWebhook verify the signature of a webhook request and parse it to a gateway independent event
+	ParseWebhook(ctx context.Context, payload []byte, header http.Header) (WebhookEvent, error)
+}
+
+var (
+	ErrGatewayNotRegistered = errors.New("payment gateway not registered")
+	ErrPaymentNotApproved   = errors.New("payment not approved")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrInvalidWebhookEvent  = errors.New("invalid webhook event")
+	ErrNotSupported         = errors.New("not supported by payment gateway")
+	// refund is declined by gateway so nothing is refunded, other refund errors may be of a refund made on gateway
+	ErrRefundRejected = errors.New("refund rejected by payment gateway")
+)
+
+type CreateIntentRequest struct {
+	ShopOrderID uint
+	// amount in actual price of the order
+	Amount   uint
+	Currency string
+	Email    string
+	Phone    string
+}
+
+type CreateIntentResponse struct {
+	GatewayOrderID string
+	// amount in the unit which gateway used
+	GatewayAmount uint
+	ClientSecret  string
+	PublicKey     string
+}
+
+type VerifyRequest struct {
+	GatewayOrderID   string
+	GatewayPaymentID string
+	Signature        string
+}
+
+type VerifyResponse struct {
+	GatewayOrderID   string
+	GatewayPaymentID string
+	// amount paid in actual price
+	Amount uint
+}
+
+type RefundRequest struct {
+	GatewayOrderID   string
+	GatewayPaymentID string
+	// amount in actual price, zero for a full refund
+	Amount uint
+	// our id of the refund, a refund made before with the same reference is returned instead of refunding again
+	Reference string
+}
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusProcessed RefundStatus = "processed"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+type RefundResponse struct {
+	GatewayRefundID string
+	Status          RefundStatus
+}
+
+type WebhookEventType string
+
+const (
+	WebhookPaymentSucceeded WebhookEventType = "payment succeeded"
+	WebhookPaymentFailed    WebhookEventType = "payment failed"
+	WebhookPaymentRefunded  WebhookEventType = "payment refunded"
+	WebhookRefundFailed     WebhookEventType = "refund failed"
+	// any other event which is not processed
+	WebhookUnhandled WebhookEventType = "unhandled"
+)
+
+type WebhookEvent struct {
+	EventID string
+	// event name given by gateway
+	GatewayEventType string
+	Type             WebhookEventType
+
+	GatewayOrderID   string
+	GatewayPaymentID string
+	// refund id of gateway for the refund events if it's send by gateway
+	GatewayRefundID string
+	// shop order id if it's send back by gateway
+	ShopOrderID uint
+	// amount of the payment in actual price
+	Amount        uint
+	FailureReason string
+	// false for a partial refund
+	FullyRefunded bool
+}
@@ -0,0 +1,304 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/utils"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+
+	"github.com/razorpay/razorpay-go"
+	rzperrors "github.com/razorpay/razorpay-go/errors"
+)
+
+const (
+	// razorpay webhook events
+	razorpayEventPaymentCaptured = "payment.captured"
+	razorpayEventPaymentFailed   = "payment.failed"
+	razorpayEventRefundProcessed = "refund.processed"
+	razorpayEventRefundFailed    = "refund.failed"
+
+	razorpayRefundReferenceNoteKey = "reference"
+	// a payment can't have more refunds than the max count of a page
+	razorpayMaxRefundsCount = 100
+
+	razorpayPaymentCaptured = "captured"
+	razorpayRefundProcessed = "processed"
+	razorpayRefundFailed    = "failed"
+)
+
+type razorpayGateway struct {
+	key           string
+	secret        string
+	webhookSecret string
+	client        *razorpay.Client
+}
+
+func NewRazorpayGateway(cfg config.Config) PaymentGateway {
+
+	return &razorpayGateway{
+		key:           cfg.RazorPayKey,
+		secret:        cfg.RazorPaySecret,
+		webhookSecret: cfg.RazorPayWebhookSecret,
+		client:        razorpay.NewClient(cfg.RazorPayKey, cfg.RazorPaySecret),
+	}
+}
+
+func (c *razorpayGateway) PaymentType() commonConstant.PaymentType {
+	return commonConstant.RazopayPayment
+}
+
+func (c *razorpayGateway) CreateIntent(ctx context.Context, req CreateIntentRequest) (CreateIntentResponse, error) {
+
+	//razorpay amount is calculate on pisa for india so make the actual price into paisa
+	razorPayAmount := req.Amount * 100
+
+	// razor pay data for order
+	data := map[string]interface{}{
+		"amount":   razorPayAmount,
+		"currency": req.Currency,
+		"receipt":  "ecommerce purchase completed",
+	}
+
+	razorpayRes, err := c.client.Order.Create(data, nil)
+	if err != nil {
+		return CreateIntentResponse{}, utils.PrependMessageToError(err, "failed to create razorpay order")
+	}
+
+	razorpayOrderID, ok := razorpayRes["id"].(string)
+	if !ok {
+		return CreateIntentResponse{}, errors.New("failed to get razorpay order id from response")
+	}
+
+	return CreateIntentResponse{
+		GatewayOrderID: razorpayOrderID,
+		GatewayAmount:  razorPayAmount,
+		PublicKey:      c.key,
+	}, nil
+}
+
+func (c *razorpayGateway) Verify(ctx context.Context, req VerifyRequest) (VerifyResponse, error) {
+
+	data := req.GatewayOrderID + "|" + req.GatewayPaymentID
+	if err := verifyHmacSha256(c.secret, []byte(data), req.Signature); err != nil {
+		return VerifyResponse{}, utils.AppendMessageToError(ErrInvalidSignature, "razorpay signature not match")
+	}
+
+	// fetch payment and verify
+	payment, err := c.client.Payment.Fetch(req.GatewayPaymentID, nil, nil)
+	if err != nil {
+		return VerifyResponse{}, utils.PrependMessageToError(err, "failed to fetch razorpay payment")
+	}
+
+	// check payment status and the payment is of the same order
+	if payment["status"] != razorpayPaymentCaptured || payment["order_id"] != req.GatewayOrderID {
+		return VerifyResponse{}, ErrPaymentNotApproved
+	}
+
+	// amount is on paisa, json numbers are decoded as float64
+	paisa, ok := payment["amount"].(float64)
+	if !ok {
+		return VerifyResponse{}, errors.New("failed to get amount from razorpay payment")
+	}
+
+	return VerifyResponse{
+		GatewayOrderID:   req.GatewayOrderID,
+		GatewayPaymentID: req.GatewayPaymentID,
+		Amount:           uint(paisa) / 100,
+	}, nil
+}
+
+// razorpay not deduplicate refunds, so the refunds of payment are checked for the reference before refunding
+func (c *razorpayGateway) Refund(ctx context.Context, req RefundRequest) (RefundResponse, error) {
+
+	if req.Reference != "" {
+		refundRes, found, err := c.findRefundByReference(req.GatewayPaymentID, req.Reference)
+		if err != nil {
+			return RefundResponse{}, err
+		}
+		if found {
+			return refundRes, nil
+		}
+	}
+
+	// zero amount refund the full payment on razorpay
+	data := map[string]interface{}{
+		"receipt": req.Reference,
+		"notes": map[string]interface{}{
+			razorpayRefundReferenceNoteKey: req.Reference,
+		},
+	}
+	refundRes, err := c.client.Payment.Refund(req.GatewayPaymentID, int(req.Amount*100), data, nil)
+	if err != nil {
+		if isRazorpayRefundRejected(err) {
+			err = utils.AppendMessageToError(ErrRefundRejected, err.Error())
+		}
+		return RefundResponse{}, utils.PrependMessageToError(err, "failed to create razorpay refund")
+	}
+
+	return newRazorpayRefundResponse(refundRes), nil
+}
+
+// To find the refund of payment which is made with the reference on receipt or notes
+func (c *razorpayGateway) findRefundByReference(gatewayPaymentID, reference string) (RefundResponse, bool, error) {
+
+	refundsRes, err := c.client.Payment.FetchMultipleRefund(gatewayPaymentID,
+		map[string]interface{}{"count": razorpayMaxRefundsCount}, nil)
+	if err != nil {
+		return RefundResponse{}, false, utils.PrependMessageToError(err, "failed to fetch razorpay refunds of payment")
+	}
+
+	items, _ := refundsRes["items"].([]interface{})
+	for _, item := range items {
+		refundRes, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		notes, _ := refundRes["notes"].(map[string]interface{})
+		if refundRes["receipt"] == reference || notes[razorpayRefundReferenceNoteKey] == reference {
+			return newRazorpayRefundResponse(refundRes), true, nil
+		}
+	}
+
+	return RefundResponse{}, false, nil
+}
+
+func (c *razorpayGateway) FindRefund(ctx context.Context, gatewayRefundID string) (RefundResponse, error) {
+
+	refundRes, err := c.client.Refund.Fetch(gatewayRefundID, nil, nil)
+	if err != nil {
+		return RefundResponse{}, utils.PrependMessageToError(err, "failed to fetch razorpay refund")
+	}
+
+	return newRazorpayRefundResponse(refundRes), nil
+}
+
+// razorpay client return a bad request error for any response without a known error code,
+// so only the ones with the description of razorpay are taken as a declined refund
+func isRazorpayRefundRejected(err error) bool {
+
+	var badRequestErr *rzperrors.BadRequestError
+	return errors.As(err, &badRequestErr) && badRequestErr.Message != ""
+}
+
+func newRazorpayRefundResponse(refundRes map[string]interface{}) RefundResponse {
+
+	refundID, _ := refundRes["id"].(string)
+	status := RefundStatusPending
+	switch refundRes["status"] {
+	case razorpayRefundProcessed:
+		status = RefundStatusProcessed
+	case razorpayRefundFailed:
+		status = RefundStatusFailed
+	}
+
+	return RefundResponse{
+		GatewayRefundID: refundID,
+		Status:          status,
+	}
+}
+
+// razorpay webhook body with only the used fields
+type razorpayWebhookEvent struct {
+	Event   string `json:"event"`
+	Payload struct {
+		Payment struct {
+			Entity razorpayPaymentEntity `json:"entity"`
+		} `json:"payment"`
+		Refund struct {
+			Entity razorpayRefundEntity `json:"entity"`
+		} `json:"refund"`
+	} `json:"payload"`
+}
+
+type razorpayPaymentEntity struct {
+	ID               string `json:"id"`
+	OrderID          string `json:"order_id"`
+	Amount           uint   `json:"amount"`
+	AmountRefunded   uint   `json:"amount_refunded"`
+	RefundStatus     string `json:"refund_status"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type razorpayRefundEntity struct {
+	ID        string `json:"id"`
+	PaymentID string `json:"payment_id"`
+}
+
+// event id is sent on header by razorpay, if it's not there the payload hash is used to find duplicates
+func (c *razorpayGateway) ParseWebhook(ctx context.Context, payload []byte, header http.Header) (WebhookEvent, error) {
+
+	if c.webhookSecret == "" {
+		return WebhookEvent{}, utils.AppendMessageToError(ErrInvalidSignature, "razorpay webhook secret not configured")
+	}
+	if err := verifyHmacSha256(c.webhookSecret, payload, header.Get("X-Razorpay-Signature")); err != nil {
+		return WebhookEvent{}, utils.AppendMessageToError(ErrInvalidSignature, "razorpay webhook signature not match")
+	}
+
+	var event razorpayWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return WebhookEvent{}, utils.PrependMessageToError(err, "failed to parse razorpay webhook event")
+	}
+
+	eventID := header.Get("X-Razorpay-Event-Id")
+	if eventID == "" {
+		hash := sha256.Sum256(payload)
+		eventID = hex.EncodeToString(hash[:])
+	}
+
+	payment := event.Payload.Payment.Entity
+	webhookEvent := WebhookEvent{
+		EventID:          eventID,
+		GatewayEventType: event.Event,
+		Type:             WebhookUnhandled,
+		GatewayOrderID:   payment.OrderID,
+		GatewayPaymentID: payment.ID,
+		Amount:           payment.Amount / 100,
+	}
+
+	switch event.Event {
+	case razorpayEventPaymentCaptured:
+		webhookEvent.Type = WebhookPaymentSucceeded
+	case razorpayEventPaymentFailed:
+		webhookEvent.Type = WebhookPaymentFailed
+		webhookEvent.FailureReason = payment.ErrorDescription
+	case razorpayEventRefundProcessed:
+		// refund event may not contain the payment entity
+		refund := event.Payload.Refund.Entity
+		webhookEvent.Type = WebhookPaymentRefunded
+		webhookEvent.GatewayPaymentID = refund.PaymentID
+		webhookEvent.GatewayRefundID = refund.ID
+		webhookEvent.FullyRefunded = payment.RefundStatus == "full" ||
+			(payment.Amount > 0 && payment.AmountRefunded >= payment.Amount)
+	case razorpayEventRefundFailed:
+		refund := event.Payload.Refund.Entity
+		webhookEvent.Type = WebhookRefundFailed
+		webhookEvent.GatewayPaymentID = refund.PaymentID
+		webhookEvent.GatewayRefundID = refund.ID
+	}
+
+	return webhookEvent, nil
+}
+
+// To verify the signature which is hex of HMAC SHA256 of the data with secret
+func verifyHmacSha256(secret string, data []byte, signature string) error {
+
+	h := hmac.New(sha256.New, []byte(secret))
+	if _, err := h.Write(data); err != nil {
+		return err
+	}
+
+	expected := hex.EncodeToString(h.Sum(nil))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return errors.New("signature not match")
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"online-shop-2N/pkg/config"
+	"strings"
+	"testing"
+
+	rzperrors "github.com/razorpay/razorpay-go/errors"
+)
+
+func razorpayTestSignature(secret string, payload []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestVerifyHmacSha256(t *testing.T) {
+
+	data := []byte("order_1|pay_1")
+	signature := razorpayTestSignature("secret", data)
+
+	tests := []struct {
+		name      string
+		secret    string
+		data      []byte
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid signature", secret: "secret", data: data, signature: signature},
+		{name: "wrong secret", secret: "other", data: data, signature: signature, wantErr: true},
+		{name: "changed data", secret: "secret", data: []byte("order_1|pay_2"), signature: signature, wantErr: true},
+		{name: "upper case signature", secret: "secret", data: data, signature: strings.ToUpper(signature), wantErr: true},
+		{name: "empty signature", secret: "secret", data: data, signature: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			err := verifyHmacSha256(test.secret, test.data, test.signature)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRazorpayGatewayParseWebhook(t *testing.T) {
+
+	payload := []byte(`{"event":"payment.captured","payload":{"payment":{"entity":` +
+		`{"id":"pay_1","order_id":"order_1","amount":50000}}}}`)
+
+	tests := []struct {
+		name          string
+		webhookSecret string
+		signature     string
+		eventID       string
+		wantEventID   string
+		wantErr       error
+	}{
+		{name: "valid signature with event id", webhookSecret: "secret",
+			signature: razorpayTestSignature("secret", payload), eventID: "evt_1", wantEventID: "evt_1"},
+		{name: "valid signature without event id", webhookSecret: "secret",
+			signature: razorpayTestSignature("secret", payload), wantEventID: razorpayTestPayloadHash(payload)},
+		{name: "invalid signature", webhookSecret: "secret",
+			signature: razorpayTestSignature("other", payload), wantErr: ErrInvalidSignature},
+		{name: "missing signature", webhookSecret: "secret", wantErr: ErrInvalidSignature},
+		{name: "secret not configured", signature: razorpayTestSignature("", payload), wantErr: ErrInvalidSignature},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			gateway := NewRazorpayGateway(config.Config{RazorPayWebhookSecret: test.webhookSecret})
+
+			header := http.Header{}
+			header.Set("X-Razorpay-Signature", test.signature)
+			if test.eventID != "" {
+				header.Set("X-Razorpay-Event-Id", test.eventID)
+			}
+
+			event, err := gateway.ParseWebhook(context.Background(), payload, header)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if event.EventID != test.wantEventID || event.Type != WebhookPaymentSucceeded ||
+				event.GatewayOrderID != "order_1" || event.Amount != 500 {
+				t.Fatalf("got event %+v, want payment succeeded of order_1 with amount 500", event)
+			}
+		})
+	}
+}
+
+func razorpayTestPayloadHash(payload []byte) string {
+	hash := sha256.Sum256(payload)
+	return hex.EncodeToString(hash[:])
+}
+
+func TestIsRazorpayRefundRejected(t *testing.T) {
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "bad request", err: &rzperrors.BadRequestError{Message: "The refund amount is invalid"}, want: true},
+		{name: "response without error", err: &rzperrors.BadRequestError{}},
+		{name: "server error", err: &rzperrors.ServerError{Message: "server error"}},
+		{name: "gateway error", err: &rzperrors.GatewayError{Message: "gateway error"}},
+		{name: "connection error", err: errors.New("i/o timeout")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			if got := isRazorpayRefundRejected(test.err); got != test.want {
+				t.Fatalf("got rejected %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/utils"
+	"strconv"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/client"
+	"github.com/stripe/stripe-go/v72/webhook"
+)
+
+const (
+	stripeShopOrderIDMetadataKey     = "shop_order_id"
+	stripeRefundReferenceMetadataKey = "reference"
+
+	// stripe webhook events
+	stripeEventPaymentSucceeded = "payment_intent.succeeded"
+	stripeEventPaymentFailed    = "payment_intent.payment_failed"
+	stripeEventChargeRefunded   = "charge.refunded"
+	stripeEventRefundUpdated    = "charge.refund.updated"
+)
+
+type stripeGateway struct {
+	publishKey    string
+	webhookSecret string
+	client        *client.API
+}
+
+func NewStripeGateway(cfg config.Config) PaymentGateway {
+
+	return &stripeGateway{
+		publishKey:    cfg.StripPublishKey,
+		webhookSecret: cfg.StripeWebhookSecret,
+		client:        client.New(cfg.StripSecretKey, nil),
+	}
+}
+
+func (c *stripeGateway) PaymentType() commonConstant.PaymentType {
+	return commonConstant.StripePayment
+}
+
+func (c *stripeGateway) CreateIntent(ctx context.Context, req CreateIntentRequest) (CreateIntentResponse, error) {
+
+	// create a payment param
+	params := &stripe.PaymentIntentParams{
+
+		Amount:       stripe.Int64(int64(req.Amount)),
+		ReceiptEmail: stripe.String(req.Email),
+
+		Currency: stripe.String(req.Currency),
+		AutomaticPaymentMethods: &stripe.PaymentIntentAutomaticPaymentMethodsParams{
+			Enabled: stripe.Bool(true),
+		},
+	}
+	// shop order id on metadata to find the order back from stripe webhook events
+	params.AddMetadata(stripeShopOrderIDMetadataKey, strconv.FormatUint(uint64(req.ShopOrderID), 10))
+
+	// create new payment intent with this param
+	paymentIntent, err := c.client.PaymentIntents.New(params)
+	if err != nil {
+		return CreateIntentResponse{}, utils.PrependMessageToError(err, "failed to create new stripe payment intent")
+	}
+
+	return CreateIntentResponse{
+		GatewayOrderID: paymentIntent.ID,
+		GatewayAmount:  req.Amount,
+		ClientSecret:   paymentIntent.ClientSecret,
+		PublicKey:      c.publishKey,
+	}, nil
+}
+
+// stripe payment is verified with the payment intent id which is the gateway order id or the payment id given by client
+func (c *stripeGateway) Verify(ctx context.Context, req VerifyRequest) (VerifyResponse, error) {
+
+	paymentIntentID := req.GatewayPaymentID
+	if paymentIntentID == "" {
+		paymentIntentID = req.GatewayOrderID
+	}
+
+	// get payment by payment_id
+	paymentIntent, err := c.client.PaymentIntents.Get(paymentIntentID, nil)
+	if err != nil {
+		return VerifyResponse{}, utils.PrependMessageToError(err, "failed to get payment intent from stripe")
+	}
+
+	// verify the payment intent
+	if paymentIntent.Status != stripe.PaymentIntentStatusSucceeded && paymentIntent.Status != stripe.PaymentIntentStatusRequiresCapture {
+		return VerifyResponse{}, ErrPaymentNotApproved
+	}
+
+	// intent is created with the actual price so the amount is not converted
+	return VerifyResponse{
+		GatewayOrderID:   paymentIntent.ID,
+		GatewayPaymentID: paymentIntent.ID,
+		Amount:           uint(paymentIntent.Amount),
+	}, nil
+}
+
+// idempotency key of stripe expire after a day, so the refunds of payment are also checked for the reference
+func (c *stripeGateway) Refund(ctx context.Context, req RefundRequest) (RefundResponse, error) {
+
+	if req.Reference != "" {
+		refund, err := c.findRefundByReference(req.GatewayOrderID, req.Reference)
+		if err != nil {
+			return RefundResponse{}, err
+		}
+		if refund != nil {
+			return newStripeRefundResponse(refund), nil
+		}
+	}
+
+	params := &stripe.RefundParams{
+		PaymentIntent: stripe.String(req.GatewayOrderID),
+	}
+	// without amount stripe refund the full payment
+	if req.Amount > 0 {
+		params.Amount = stripe.Int64(int64(req.Amount))
+	}
+	if req.Reference != "" {
+		params.SetIdempotencyKey(req.Reference)
+		params.AddMetadata(stripeRefundReferenceMetadataKey, req.Reference)
+	}
+
+	refund, err := c.client.Refunds.New(params)
+	if err != nil {
+		if isStripeRefundRejected(err) {
+			err = utils.AppendMessageToError(ErrRefundRejected, err.Error())
+		}
+		return RefundResponse{}, utils.PrependMessageToError(err, "failed to create stripe refund")
+	}
+
+	return newStripeRefundResponse(refund), nil
+}
+
+// To find the refund of payment intent which is made with the reference on metadata
+func (c *stripeGateway) findRefundByReference(paymentIntentID, reference string) (*stripe.Refund, error) {
+
+	iter := c.client.Refunds.List(&stripe.RefundListParams{
+		PaymentIntent: stripe.String(paymentIntentID),
+	})
+	for iter.Next() {
+		if refund := iter.Refund(); refund.Metadata[stripeRefundReferenceMetadataKey] == reference {
+			return refund, nil
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to list stripe refunds of payment intent")
+	}
+
+	return nil, nil
+}
+
+func (c *stripeGateway) FindRefund(ctx context.Context, gatewayRefundID string) (RefundResponse, error) {
+
+	refund, err := c.client.Refunds.Get(gatewayRefundID, nil)
+	if err != nil {
+		return RefundResponse{}, utils.PrependMessageToError(err, "failed to get stripe refund")
+	}
+
+	return newStripeRefundResponse(refund), nil
+}
+
+// a request error of stripe is a declined refund, conflicts and rate limits may succeed on retry
+func isStripeRefundRejected(err error) bool {
+
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return false
+	}
+
+	switch stripeErr.HTTPStatusCode {
+	case http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return stripeErr.HTTPStatusCode >= http.StatusBadRequest && stripeErr.HTTPStatusCode < http.StatusInternalServerError
+}
+
+func newStripeRefundResponse(refund *stripe.Refund) RefundResponse {
+
+	status := RefundStatusPending
+	switch refund.Status {
+	case stripe.RefundStatusSucceeded:
+		status = RefundStatusProcessed
+	case stripe.RefundStatusFailed, stripe.RefundStatusCanceled:
+		status = RefundStatusFailed
+	}
+
+	return RefundResponse{
+		GatewayRefundID: refund.ID,
+		Status:          status,
+	}
+}
+
+func (c *stripeGateway) ParseWebhook(ctx context.Context, payload []byte, header http.Header) (WebhookEvent, error) {
+
+	event, err := webhook.ConstructEvent(payload, header.Get("Stripe-Signature"), c.webhookSecret)
+	if err != nil {
+		return WebhookEvent{}, utils.AppendMessageToError(ErrInvalidSignature, err.Error())
+	}
+
+	webhookEvent := WebhookEvent{
+		EventID:          event.ID,
+		GatewayEventType: event.Type,
+		Type:             WebhookUnhandled,
+	}
+
+	switch event.Type {
+	case stripeEventPaymentSucceeded, stripeEventPaymentFailed:
+		var paymentIntent stripe.PaymentIntent
+		if err := json.Unmarshal(event.Data.Raw, &paymentIntent); err != nil {
+			return WebhookEvent{}, utils.PrependMessageToError(err, "failed to parse stripe payment intent from event")
+		}
+
+		webhookEvent.GatewayOrderID = paymentIntent.ID
+		webhookEvent.ShopOrderID = stripeMetadataShopOrderID(paymentIntent)
+		webhookEvent.Amount = uint(paymentIntent.Amount)
+
+		webhookEvent.Type = WebhookPaymentSucceeded
+		if event.Type == stripeEventPaymentFailed {
+			webhookEvent.Type = WebhookPaymentFailed
+			if paymentIntent.LastPaymentError != nil {
+				webhookEvent.FailureReason = paymentIntent.LastPaymentError.Msg
+			}
+		}
+
+	case stripeEventChargeRefunded:
+		var charge stripe.Charge
+		if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+			return WebhookEvent{}, utils.PrependMessageToError(err, "failed to parse stripe charge from event")
+		}
+
+		webhookEvent.Type = WebhookPaymentRefunded
+		webhookEvent.GatewayPaymentID = charge.ID
+		webhookEvent.FullyRefunded = charge.Refunded
+		if charge.PaymentIntent != nil {
+			webhookEvent.GatewayOrderID = charge.PaymentIntent.ID
+		}
+		// refunds of charge are sorted from the latest, the older ones are completed by their own update events
+		if charge.Refunds != nil && len(charge.Refunds.Data) > 0 {
+			webhookEvent.GatewayRefundID = charge.Refunds.Data[0].ID
+		}
+
+	case stripeEventRefundUpdated:
+		var refund stripe.Refund
+		if err := json.Unmarshal(event.Data.Raw, &refund); err != nil {
+			return WebhookEvent{}, utils.PrependMessageToError(err, "failed to parse stripe refund from event")
+		}
+
+		// charge refunded event may not have the refunds of charge, so success of each refund is taken from here too
+		switch newStripeRefundResponse(&refund).Status {
+		case RefundStatusProcessed:
+			webhookEvent.Type = WebhookPaymentRefunded
+		case RefundStatusFailed:
+			webhookEvent.Type = WebhookRefundFailed
+		default:
+			return webhookEvent, nil
+		}
+		webhookEvent.GatewayRefundID = refund.ID
+		if refund.PaymentIntent != nil {
+			webhookEvent.GatewayOrderID = refund.PaymentIntent.ID
+		}
+	}
+
+	return webhookEvent, nil
+}
+
+// To get the shop order id saved on payment intent metadata
+func stripeMetadataShopOrderID(paymentIntent stripe.PaymentIntent) uint {
+
+	id, err := strconv.ParseUint(paymentIntent.Metadata[stripeShopOrderIDMetadataKey], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
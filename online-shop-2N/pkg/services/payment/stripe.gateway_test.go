@@ -0,0 +1,155 @@
+package payment
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"online-shop-2N/pkg/config"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/webhook"
+)
+
+func stripeTestSignature(secret string, payload []byte, signedAt time.Time) string {
+	signature := webhook.ComputeSignature(signedAt, payload, secret)
+	return fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), hex.EncodeToString(signature))
+}
+
+func TestStripeGatewayParseWebhook(t *testing.T) {
+
+	payload := []byte(`{"id":"evt_1","object":"event","type":"payment_intent.succeeded","data":{"object":` +
+		`{"id":"pi_1","object":"payment_intent","amount":500,"metadata":{"shop_order_id":"7"}}}}`)
+
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   error
+	}{
+		{name: "valid signature", signature: stripeTestSignature("secret", payload, now)},
+		{name: "invalid signature", signature: stripeTestSignature("other", payload, now),
+			wantErr: ErrInvalidSignature},
+		{name: "expired signature", signature: stripeTestSignature("secret", payload, now.Add(-time.Hour)),
+			wantErr: ErrInvalidSignature},
+		{name: "missing signature", wantErr: ErrInvalidSignature},
+	}
+
+	gateway := NewStripeGateway(config.Config{StripeWebhookSecret: "secret"})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			header := http.Header{}
+			header.Set("Stripe-Signature", test.signature)
+
+			event, err := gateway.ParseWebhook(context.Background(), payload, header)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if event.EventID != "evt_1" || event.Type != WebhookPaymentSucceeded ||
+				event.GatewayOrderID != "pi_1" || event.ShopOrderID != 7 || event.Amount != 500 {
+				t.Fatalf("got event %+v, want payment succeeded of pi_1 for shop order 7 with amount 500", event)
+			}
+		})
+	}
+}
+
+func TestIsStripeRefundRejected(t *testing.T) {
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "invalid request", err: &stripe.Error{HTTPStatusCode: http.StatusBadRequest}, want: true},
+		{name: "payment not found", err: &stripe.Error{HTTPStatusCode: http.StatusNotFound}, want: true},
+		{name: "idempotency conflict", err: &stripe.Error{HTTPStatusCode: http.StatusConflict}},
+		{name: "rate limited", err: &stripe.Error{HTTPStatusCode: http.StatusTooManyRequests}},
+		{name: "server error", err: &stripe.Error{HTTPStatusCode: http.StatusInternalServerError}},
+		{name: "connection error", err: errors.New("connection reset by peer")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			if got := isStripeRefundRejected(test.err); got != test.want {
+				t.Fatalf("got rejected %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStripeGatewayParseRefundWebhook(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		payload        string
+		wantType       WebhookEventType
+		wantRefundID   string
+		wantFullRefund bool
+		wantPaymentID  string
+		wantIntentID   string
+	}{
+		{
+			name: "charge refunded",
+			payload: `{"id":"evt_1","type":"charge.refunded","data":{"object":{"id":"ch_1","object":"charge",` +
+				`"refunded":true,"payment_intent":"pi_1","refunds":{"object":"list","data":[{"id":"re_2"},{"id":"re_1"}]}}}}`,
+			wantType: WebhookPaymentRefunded, wantRefundID: "re_2", wantFullRefund: true,
+			wantPaymentID: "ch_1", wantIntentID: "pi_1",
+		},
+		{
+			name: "charge refunded without refunds",
+			payload: `{"id":"evt_2","type":"charge.refunded","data":{"object":{"id":"ch_1","object":"charge",` +
+				`"refunded":false,"payment_intent":"pi_1"}}}`,
+			wantType: WebhookPaymentRefunded, wantPaymentID: "ch_1", wantIntentID: "pi_1",
+		},
+		{
+			name: "refund succeeded",
+			payload: `{"id":"evt_3","type":"charge.refund.updated","data":{"object":{"id":"re_1","object":"refund",` +
+				`"status":"succeeded","payment_intent":"pi_1"}}}`,
+			wantType: WebhookPaymentRefunded, wantRefundID: "re_1", wantIntentID: "pi_1",
+		},
+		{
+			name: "refund failed",
+			payload: `{"id":"evt_4","type":"charge.refund.updated","data":{"object":{"id":"re_1","object":"refund",` +
+				`"status":"failed","payment_intent":"pi_1"}}}`,
+			wantType: WebhookRefundFailed, wantRefundID: "re_1", wantIntentID: "pi_1",
+		},
+		{
+			name: "refund pending",
+			payload: `{"id":"evt_5","type":"charge.refund.updated","data":{"object":{"id":"re_1","object":"refund",` +
+				`"status":"pending","payment_intent":"pi_1"}}}`,
+			wantType: WebhookUnhandled,
+		},
+	}
+
+	gateway := NewStripeGateway(config.Config{StripeWebhookSecret: "secret"})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			payload := []byte(test.payload)
+			header := http.Header{}
+			header.Set("Stripe-Signature", stripeTestSignature("secret", payload, time.Now()))
+
+			event, err := gateway.ParseWebhook(context.Background(), payload, header)
+			if err != nil {
+				t.Fatalf("failed to parse webhook: %v", err)
+			}
+			if event.Type != test.wantType || event.GatewayRefundID != test.wantRefundID ||
+				event.FullyRefunded != test.wantFullRefund || event.GatewayPaymentID != test.wantPaymentID ||
+				event.GatewayOrderID != test.wantIntentID {
+				t.Fatalf("got event %+v, want type %v with refund %q of payment %q and intent %q",
+					event, test.wantType, test.wantRefundID, test.wantPaymentID, test.wantIntentID)
+			}
+		})
+	}
+}
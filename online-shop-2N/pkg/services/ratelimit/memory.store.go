@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// buckets which are full after this are removed on cleanup
+const cleanupInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// time when the bucket is full again, so it can be removed
+	fullAt time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	cleanedAt time.Time
+}
+
+func NewMemoryStore() Store {
+
+	return &memoryStore{
+		buckets:   make(map[string]*bucket),
+		cleanedAt: time.Now(),
+	}
+}
+
+func (c *memoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+
+	if limit.Requests <= 0 || limit.Period <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.cleanup(now)
+
+	capacity := float64(limit.Requests)
+	// tokens refilled on a second
+	refillRate := capacity / limit.Period.Seconds()
+
+	b, ok := c.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updatedAt: now}
+		c.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updatedAt).Seconds()*refillRate)
+	b.updatedAt = now
+
+	if b.tokens < 1 {
+		retryAfter := time.Duration((1 - b.tokens) / refillRate * float64(time.Second))
+		return Result{Allowed: false, RetryAfter: retryAfter}, nil
+	}
+
+	b.tokens--
+	b.fullAt = now.Add(time.Duration((capacity - b.tokens) / refillRate * float64(time.Second)))
+
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// To remove the full buckets so the map not grow forever, a full bucket is same as no bucket
+func (c *memoryStore) cleanup(now time.Time) {
+
+	if now.Sub(c.cleanedAt) < cleanupInterval {
+		return
+	}
+	c.cleanedAt = now
+
+	for key, b := range c.buckets {
+		if now.After(b.fullAt) {
+			delete(c.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"online-shop-2N/pkg/config"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+
+	limit := Limit{Requests: 3, Period: time.Hour}
+
+	tests := []struct {
+		name          string
+		key           string
+		limit         Limit
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{name: "first request", key: "ip:1", limit: limit, wantAllowed: true, wantRemaining: 2},
+		{name: "second request", key: "ip:1", limit: limit, wantAllowed: true, wantRemaining: 1},
+		{name: "last request", key: "ip:1", limit: limit, wantAllowed: true, wantRemaining: 0},
+		{name: "over limit", key: "ip:1", limit: limit, wantAllowed: false},
+		{name: "another key", key: "ip:2", limit: limit, wantAllowed: true, wantRemaining: 2},
+		{name: "no limit", key: "ip:1", limit: Limit{}, wantAllowed: true},
+	}
+
+	store := NewMemoryStore()
+
+	// the cases run in order on the same store
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			result, err := store.Take(context.Background(), test.key, test.limit)
+			if err != nil {
+				t.Fatalf("failed to take token: %v", err)
+			}
+			if result.Allowed != test.wantAllowed || result.Remaining != test.wantRemaining {
+				t.Fatalf("got result %+v, want allowed %v with %d remaining",
+					result, test.wantAllowed, test.wantRemaining)
+			}
+			if !result.Allowed && (result.RetryAfter <= 0 || result.RetryAfter > limit.Period/3) {
+				t.Fatalf("got retry after %v, want up to %v", result.RetryAfter, limit.Period/3)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: 20 * time.Millisecond}
+
+	for i, wantAllowed := range []bool{true, false} {
+		result, err := store.Take(context.Background(), "ip:1", limit)
+		if err != nil || result.Allowed != wantAllowed {
+			t.Fatalf("got result %+v with error %v on request %d, want allowed %v", result, err, i+1, wantAllowed)
+		}
+	}
+
+	time.Sleep(2 * limit.Period)
+
+	result, err := store.Take(context.Background(), "ip:1", limit)
+	if err != nil || !result.Allowed {
+		t.Fatalf("got result %+v with error %v after the period, want allowed", result, err)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		store   string
+		wantErr bool
+	}{
+		{name: "default", store: ""},
+		{name: "memory", store: storeMemory},
+		{name: "unknown", store: "redis", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			_, err := NewStore(config.Config{RateLimitStore: test.store})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/config"
+	"time"
+)
+
+// memory store keep the buckets only on the running instance
+const storeMemory = "memory"
+
+// Limit allow the number of requests on the period, requests are refilled evenly over the period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// time to wait until the next request is allowed, zero when it's allowed
+	RetryAfter time.Duration
+}
+
+// Store keep a token bucket for each key, implement it to share the limits between instances
+type Store interface {
+	// To take a token from the bucket of key, request is not allowed when the bucket is empty
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// New rate limit store with the configured store, memory is used by default
+func NewStore(cfg config.Config) (Store, error) {
+
+	switch cfg.RateLimitStore {
+	case "", storeMemory:
+		log.Printf("rate limit running on memory store")
+		return NewMemoryStore(), nil
+	}
+
+	return nil, fmt.Errorf("invalid rate limit store %s", cfg.RateLimitStore)
+}
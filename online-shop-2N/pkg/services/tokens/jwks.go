@@ -0,0 +1,107 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// public key as json web key which is used by other services to verify the tokens
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// rsa
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+var errUnsupportedPublicKey = errors.New("unsupported public key for jwk")
+
+// To find the public keys of all active keys, hmac keys are secret so they are never published
+func (s keySet) jwks() JWKSet {
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range s.keys {
+		if key.publicKey == nil {
+			continue
+		}
+		jwk, err := newJWK(key.publicKey)
+		if err != nil {
+			continue
+		}
+		jwk.KeyID = key.id
+		jwk.Use = "sig"
+		jwk.Algorithm = key.method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// To create jwk with only the required members of the key type
+func newJWK(publicKey crypto.PublicKey) (JWK, error) {
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KeyType: "RSA",
+			N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			KeyType: "OKP",
+			Curve:   "Ed25519",
+			X:       base64.RawURLEncoding.EncodeToString(key),
+		}, nil
+	default:
+		return JWK{}, errUnsupportedPublicKey
+	}
+}
+
+// To find the RFC 7638 thumbprint of the public key which is used as key id
+func thumbprint(publicKey crypto.PublicKey) (string, error) {
+
+	jwk, err := newJWK(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	// required members in lexicographic order
+	var members interface{}
+	if jwk.KeyType == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
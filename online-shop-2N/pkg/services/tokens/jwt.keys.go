@@ -0,0 +1,196 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrInvalidSigningAlg = errors.New("invalid token signing algorithm")
+	ErrInvalidKeyFile    = errors.New("invalid token key file")
+	ErrUnknownKeyID      = errors.New("unknown token key id")
+)
+
+// key to sign or verify tokens which is found on token header by its id
+type signingKey struct {
+	id     string
+	method jwt.SigningMethod
+	// nil for the keys which only verify tokens
+	signKey   interface{}
+	verifyKey interface{}
+	// public key to publish on jwks, nil for hmac keys
+	publicKey crypto.PublicKey
+}
+
+// all the active keys of tokens, new tokens are signed with the current key
+// and the other keys only verify tokens so keys can be rotated without invalidating the issued tokens
+type keySet struct {
+	current *signingKey
+	keys    map[string]*signingKey
+}
+
+func newKeySet(current *signingKey, verifyKeys []*signingKey) keySet {
+
+	set := keySet{
+		current: current,
+		keys:    make(map[string]*signingKey, len(verifyKeys)+1),
+	}
+	for _, key := range verifyKeys {
+		set.keys[key.id] = key
+	}
+	set.keys[current.id] = current
+
+	return set
+}
+
+// To create key set of hmac secret and the comma separated secrets which only verify tokens
+func newHMACKeySet(secret, verifySecrets string) keySet {
+
+	var verifyKeys []*signingKey
+	for _, verifySecret := range splitList(verifySecrets) {
+		verifyKeys = append(verifyKeys, newHMACKey(verifySecret))
+	}
+
+	return newKeySet(newHMACKey(secret), verifyKeys)
+}
+
+func newHMACKey(secret string) *signingKey {
+
+	// id of the key is from the hash of secret so it's same on all instances without configuring it
+	hash := sha256.Sum256([]byte("kid:" + secret))
+
+	return &signingKey{
+		id:        base64.RawURLEncoding.EncodeToString(hash[:12]),
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// To create key set of RS256 or EdDSA from the private key file and the comma separated key files which only verify tokens
+func newAsymmetricKeySet(alg, signingKeyFile, verifyKeyFiles string) (keySet, error) {
+
+	if signingKeyFile == "" {
+		return keySet{}, fmt.Errorf("%w: signing key file required for %s", ErrInvalidKeyFile, alg)
+	}
+
+	current, err := loadKeyFile(alg, signingKeyFile)
+	if err != nil {
+		return keySet{}, err
+	}
+	if current.signKey == nil {
+		return keySet{}, fmt.Errorf("%w: signing key file %s has no private key", ErrInvalidKeyFile, signingKeyFile)
+	}
+
+	var verifyKeys []*signingKey
+	for _, file := range splitList(verifyKeyFiles) {
+		key, err := loadKeyFile(alg, file)
+		if err != nil {
+			return keySet{}, err
+		}
+		// only used to verify even it's a private key
+		key.signKey = nil
+		verifyKeys = append(verifyKeys, key)
+	}
+
+	return newKeySet(current, verifyKeys), nil
+}
+
+// To load the private or public key of the algorithm from the pem file
+func loadKeyFile(alg, file string) (*signingKey, error) {
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token key file %s: %w", file, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s is not a pem file", ErrInvalidKeyFile, file)
+	}
+
+	var (
+		privateKey interface{}
+		publicKey  interface{}
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported pem block type %s", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidKeyFile, file, err)
+	}
+
+	if signer, ok := privateKey.(crypto.Signer); ok {
+		publicKey = signer.Public()
+	}
+
+	key := &signingKey{publicKey: publicKey}
+	switch alg {
+	case AlgRS256:
+		rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a rsa key", ErrInvalidKeyFile, file)
+		}
+		key.method = jwt.SigningMethodRS256
+		key.verifyKey = rsaPublicKey
+		if privateKey != nil {
+			key.signKey = privateKey
+		}
+	case AlgEdDSA:
+		edPublicKey, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an ed25519 key", ErrInvalidKeyFile, file)
+		}
+		key.method = jwt.SigningMethodEdDSA
+		key.verifyKey = edPublicKey
+		if privateKey != nil {
+			key.signKey = privateKey
+		}
+	default:
+		return nil, ErrInvalidSigningAlg
+	}
+
+	key.id, err = thumbprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func splitList(value string) []string {
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -5,23 +5,49 @@ import (
 	"fmt"
 	"online-shop-2N/pkg/config"
 	"online-shop-2N/pkg/utils"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// issuer of tokens when it's not configured
+const defaultIssuer = "online-shop-2N"
+
 type jwtAuth struct {
-	adminSecretKey string
-	userSecretKey  string
+	issuer    string
+	adminKeys keySet
+	userKeys  keySet
 }
 
 // New TokenAuth
-func NewTokenService(cfg config.Config) TokenService {
+// HS256 tokens are signed with separate secrets for admin and user,
+// RS256 and EdDSA tokens of both are signed with the same key and separated by the audience
+func NewTokenService(cfg config.Config) (TokenService, error) {
+
+	service := &jwtAuth{
+		issuer: cfg.TokenIssuer,
+	}
+	if service.issuer == "" {
+		service.issuer = defaultIssuer
+	}
 
-	return &jwtAuth{
-		adminSecretKey: cfg.AdminAuthKey,
-		userSecretKey:  cfg.UserAuthKey,
+	switch cfg.TokenSigningAlg {
+	case "", AlgHS256:
+		service.adminKeys = newHMACKeySet(cfg.AdminAuthKey, cfg.AdminAuthVerifyKeys)
+		service.userKeys = newHMACKeySet(cfg.UserAuthKey, cfg.UserAuthVerifyKeys)
+	case AlgRS256, AlgEdDSA:
+		keys, err := newAsymmetricKeySet(cfg.TokenSigningAlg, cfg.TokenSigningKeyFile, cfg.TokenVerifyKeyFiles)
+		if err != nil {
+			return nil, err
+		}
+		service.adminKeys = keys
+		service.userKeys = keys
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSigningAlg, cfg.TokenSigningAlg)
 	}
+
+	return service, nil
 }
 
 var (
@@ -29,46 +55,48 @@ var (
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrFailedToParseToken = errors.New("failed to parse token to claims")
 	ErrExpiredToken       = errors.New("token expired")
+	ErrInvalidTokenUse    = errors.New("invalid token use")
 )
 
+// registered claims with id of the session of token and what the token is used for
 type jwtClaims struct {
-	TokenID   string
-	UserID    uint
-	ExpiresAt time.Time
-	// jwt.RegisteredClaims
+	SessionID string   `json:"sid,omitempty"`
+	TokenUse  TokenUse `json:"token_use"`
+	jwt.RegisteredClaims
 }
 
 // Generate a new JWT token string from token request
 func (c *jwtAuth) GenerateToken(req GenerateTokenRequest) (GenerateTokenResponse, error) {
 
-	if req.UsedFor != Admin && req.UsedFor != User {
-
-		return GenerateTokenResponse{}, ErrInvalidUserType
+	keys, err := c.keysOf(req.UsedFor)
+	if err != nil {
+		return GenerateTokenResponse{}, err
+	}
+	if !isValidTokenUse(req.TokenUse) {
+		return GenerateTokenResponse{}, ErrInvalidTokenUse
 	}
 
 	tokenID := utils.GenerateUniqueString()
+	now := time.Now()
 	claims := &jwtClaims{
-		TokenID: tokenID,
-		UserID:  req.UserID,
-		// RegisteredClaims: jwt.RegisteredClaims{
-		// 	ExpiresAt: jwt.NewNumericDate(req.ExpirationDate),
-		// },
-		ExpiresAt: req.ExpireAt,
+		SessionID: req.SessionID,
+		TokenUse:  req.TokenUse,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   strconv.FormatUint(uint64(req.UserID), 10),
+			Issuer:    c.issuer,
+			Audience:  jwt.ClaimStrings{string(req.UsedFor)},
+			ExpiresAt: jwt.NewNumericDate(req.ExpireAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	var (
-		tokenString string
-		err         error
-	)
-	// sign the token by user type
-	if req.UsedFor == Admin {
-		tokenString, err = token.SignedString([]byte(c.adminSecretKey))
-	} else {
-		tokenString, err = token.SignedString([]byte(c.userSecretKey))
-	}
+	token := jwt.NewWithClaims(keys.current.method, claims)
+	// key id to find the key on verify, the previous keys still verify their tokens after rotation
+	token.Header["kid"] = keys.current.id
 
+	tokenString, err := token.SignedString(keys.current.signKey)
 	if err != nil {
 		return GenerateTokenResponse{}, fmt.Errorf("failed to sign the token \nerror:%w", err)
 	}
@@ -84,44 +112,88 @@ func (c *jwtAuth) GenerateToken(req GenerateTokenRequest) (GenerateTokenResponse
 // Verify JWT token string and return TokenResponse
 func (c *jwtAuth) VerifyToken(req VerifyTokenRequest) (VerifyTokenResponse, error) {
 
-	if req.UsedFor != Admin && req.UsedFor != User {
-		return VerifyTokenResponse{}, ErrInvalidUserType
+	keys, err := c.keysOf(req.UsedFor)
+	if err != nil {
+		return VerifyTokenResponse{}, err
 	}
 
-	token, err := jwt.ParseWithClaims(req.TokenString, &jwtClaims{}, func(t *jwt.Token) (interface{}, error) {
+	claims := &jwtClaims{}
+	_, err = jwt.ParseWithClaims(req.TokenString, claims, func(t *jwt.Token) (interface{}, error) {
 
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
+		keyID, _ := t.Header["kid"].(string)
+		key, ok := keys.keys[keyID]
+		if !ok {
+			return nil, ErrUnknownKeyID
 		}
-		if req.UsedFor == Admin {
-			return []byte(c.adminSecretKey), nil
+		// algorithm of token should be the algorithm of its key
+		if t.Method.Alg() != key.method.Alg() {
+			return nil, ErrInvalidToken
 		}
-		return []byte(c.userSecretKey), nil
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
-		if errors.Is(err, ErrExpiredToken) {
+		if errors.Is(err, jwt.ErrTokenExpired) {
 			return VerifyTokenResponse{}, ErrExpiredToken
 		}
 		return VerifyTokenResponse{}, ErrInvalidToken
 	}
 
-	claims, ok := token.Claims.(*jwtClaims)
-	if !ok {
+	if claims.ExpiresAt == nil || !claims.VerifyIssuer(c.issuer, true) ||
+		!claims.VerifyAudience(string(req.UsedFor), true) {
+		return VerifyTokenResponse{}, ErrInvalidToken
+	}
+	// tokens without a known use are not accepted, the caller check the use it expects
+	if !isValidTokenUse(claims.TokenUse) {
+		return VerifyTokenResponse{}, ErrInvalidTokenUse
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
 		return VerifyTokenResponse{}, ErrFailedToParseToken
 	}
 
 	response := VerifyTokenResponse{
-		TokenID: claims.TokenID,
-		UserID:  claims.UserID,
+		TokenID:   claims.ID,
+		UserID:    uint(userID),
+		TokenUse:  claims.TokenUse,
+		SessionID: claims.SessionID,
 	}
 	return response, nil
 }
 
-// Validate claims
-func (c *jwtClaims) Valid() error {
-	if time.Since(c.ExpiresAt) > 0 {
-		return ErrExpiredToken
+// JWKS implements TokenService.
+func (c *jwtAuth) JWKS() JWKSet {
+
+	set := c.adminKeys.jwks()
+
+	// admin and user have the same keys when signed with public keys
+	added := make(map[string]bool, len(set.Keys))
+	for _, key := range set.Keys {
+		added[key.KeyID] = true
+	}
+	for _, key := range c.userKeys.jwks().Keys {
+		if !added[key.KeyID] {
+			set.Keys = append(set.Keys, key)
+		}
 	}
-	return nil
+
+	return set
+}
+
+// To find the keys of user type
+func (c *jwtAuth) keysOf(userType UserType) (keySet, error) {
+
+	switch userType {
+	case Admin:
+		return c.adminKeys, nil
+	case User:
+		return c.userKeys, nil
+	default:
+		return keySet{}, ErrInvalidUserType
+	}
+}
+
+func isValidTokenUse(tokenUse TokenUse) bool {
+	return tokenUse == AccessToken || tokenUse == RefreshToken
 }
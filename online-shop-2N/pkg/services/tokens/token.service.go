@@ -5,6 +5,8 @@ import "time"
 type TokenService interface {
 	GenerateToken(req GenerateTokenRequest) (GenerateTokenResponse, error)
 	VerifyToken(req VerifyTokenRequest) (VerifyTokenResponse, error)
+	// public keys to verify the tokens which is empty for HS256
+	JWKS() JWKSet
 }
 
 type UserType string
@@ -14,10 +16,20 @@ const (
 	User  UserType = "user"
 )
 
+// what the token is used for, a refresh token can't be used as an access token
+type TokenUse string
+
+const (
+	AccessToken  TokenUse = "access"
+	RefreshToken TokenUse = "refresh"
+)
+
 type GenerateTokenRequest struct {
-	UserID   uint
-	UsedFor  UserType
-	ExpireAt time.Time
+	UserID    uint
+	UsedFor   UserType
+	TokenUse  TokenUse
+	SessionID string
+	ExpireAt  time.Time
 }
 
 type GenerateTokenResponse struct {
@@ -31,6 +43,8 @@ type VerifyTokenRequest struct {
 }
 
 type VerifyTokenResponse struct {
-	TokenID string
-	UserID  uint
+	TokenID   string
+	UserID    uint
+	TokenUse  TokenUse
+	SessionID string
 }
@@ -0,0 +1,189 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"online-shop-2N/pkg/config"
+	"strings"
+	"time"
+)
+
+const (
+	// defaults of authenticator apps, other values are ignored by many of them
+	digits     = 6
+	period     = 30
+	secretSize = 20
+	// codes of previous and next steps are accepted for the clock drift of devices
+	skewSteps = 1
+
+	defaultIssuer = "online-shop-2N"
+)
+
+var (
+	ErrEncryptionKeyNotConfigured = errors.New("totp encryption key not configured or same as the admin auth key")
+	ErrInvalidEncryptedSecret     = errors.New("invalid encrypted totp secret")
+)
+
+// secret of a new enrollment, key and uri are shown to the admin only once
+type Secret struct {
+	Key             string
+	EncryptedKey    string
+	ProvisioningURI string
+}
+
+type TotpService interface {
+	// To generate a new secret for the account with the uri to show as qr code on authenticator apps
+	GenerateSecret(accountName string) (Secret, error)
+	// To validate the code with the encrypted secret, returns the time step of the matched code
+	// so the same code can't be used again
+	Validate(encryptedKey, code string) (step int64, valid bool, err error)
+}
+
+type totpService struct {
+	issuer string
+	aead   cipher.AEAD
+}
+
+// New totp service, secrets are stored encrypted with the totp encryption key
+// which should be separate from the keys of tokens so rotating them not lose the secrets
+func NewTotpService(cfg config.Config) (TotpService, error) {
+
+	key := cfg.TotpEncryptionKey
+	if key == "" || key == cfg.AdminAuthKey {
+		return nil, ErrEncryptionKeyNotConfigured
+	}
+
+	// aes-256 key from the configured key of any length
+	aesKey := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(aesKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher of totp secrets \nerror:%w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher of totp secrets \nerror:%w", err)
+	}
+
+	issuer := cfg.TotpIssuer
+	if issuer == "" {
+		issuer = defaultIssuer
+	}
+
+	return &totpService{
+		issuer: issuer,
+		aead:   aead,
+	}, nil
+}
+
+func (c *totpService) GenerateSecret(accountName string) (Secret, error) {
+
+	secretBytes := make([]byte, secretSize)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return Secret{}, fmt.Errorf("failed to generate totp secret \nerror:%w", err)
+	}
+	key := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secretBytes)
+
+	encryptedKey, err := c.encrypt(key)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	return Secret{
+		Key:             key,
+		EncryptedKey:    encryptedKey,
+		ProvisioningURI: c.provisioningURI(accountName, key),
+	}, nil
+}
+
+func (c *totpService) Validate(encryptedKey, code string) (int64, bool, error) {
+
+	key, err := c.decrypt(encryptedKey)
+	if err != nil {
+		return 0, false, err
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(key)
+	if err != nil {
+		return 0, false, ErrInvalidEncryptedSecret
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) != digits {
+		return 0, false, nil
+	}
+
+	currentStep := time.Now().Unix() / period
+	for step := currentStep - skewSteps; step <= currentStep+skewSteps; step++ {
+		if hmac.Equal([]byte(generateCode(secret, step)), []byte(code)) {
+			return step, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// key uri format of google authenticator which is supported by the authenticator apps
+func (c *totpService) provisioningURI(accountName, key string) string {
+
+	label := url.PathEscape(c.issuer + ":" + accountName)
+	params := url.Values{}
+	params.Set("secret", key)
+	params.Set("issuer", c.issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(digits))
+	params.Set("period", fmt.Sprint(period))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// code of the time step from RFC 6238 with the truncate of RFC 4226
+func generateCode(secret []byte, step int64) string {
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", digits, value%1000000)
+}
+
+func (c *totpService) encrypt(plainText string) (string, error) {
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce \nerror:%w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plainText), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *totpService) decrypt(cipherText string) (string, error) {
+
+	sealed, err := base64.RawStdEncoding.DecodeString(cipherText)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidEncryptedSecret
+	}
+
+	nonceSize := c.aead.NonceSize()
+	plainText, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", ErrInvalidEncryptedSecret
+	}
+
+	return string(plainText), nil
+}
@@ -0,0 +1,127 @@
+package totp
+
+import (
+	"encoding/base32"
+	"errors"
+	"net/url"
+	"online-shop-2N/pkg/config"
+	"strings"
+	"testing"
+	"time"
+)
+
+// test vectors of RFC 6238 with sha1, the last 6 digits of the 8 digit codes
+func TestGenerateCode(t *testing.T) {
+
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		unixTime int64
+		want     string
+	}{
+		{unixTime: 59, want: "287082"},
+		{unixTime: 1111111109, want: "081804"},
+		{unixTime: 1111111111, want: "050471"},
+		{unixTime: 1234567890, want: "005924"},
+		{unixTime: 2000000000, want: "279037"},
+		{unixTime: 20000000000, want: "353130"},
+	}
+
+	for _, test := range tests {
+		t.Run(time.Unix(test.unixTime, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+
+			got := generateCode(secret, test.unixTime/period)
+			if got != test.want {
+				t.Fatalf("got code %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewTotpService(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		wantErr error
+	}{
+		{name: "configured", cfg: config.Config{TotpEncryptionKey: "totp key", AdminAuthKey: "admin key"}},
+		{name: "not configured", cfg: config.Config{AdminAuthKey: "admin key"}, wantErr: ErrEncryptionKeyNotConfigured},
+		{name: "same as admin auth key", cfg: config.Config{TotpEncryptionKey: "admin key", AdminAuthKey: "admin key"},
+			wantErr: ErrEncryptionKeyNotConfigured},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			_, err := NewTotpService(test.cfg)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestTotpServiceValidate(t *testing.T) {
+
+	service, err := NewTotpService(config.Config{TotpEncryptionKey: "totp key", TotpIssuer: "shop"})
+	if err != nil {
+		t.Fatalf("failed to create totp service: %v", err)
+	}
+
+	secret, err := service.GenerateSecret("admin@shop.com")
+	if err != nil {
+		t.Fatalf("failed to generate secret: %v", err)
+	}
+	if secret.EncryptedKey == "" || strings.Contains(secret.EncryptedKey, secret.Key) {
+		t.Fatalf("got encrypted key %s, want the key encrypted", secret.EncryptedKey)
+	}
+
+	uri, err := url.Parse(secret.ProvisioningURI)
+	if err != nil || uri.Scheme != "otpauth" || uri.Query().Get("secret") != secret.Key ||
+		uri.Query().Get("issuer") != "shop" {
+		t.Fatalf("got provisioning uri %s, want otpauth uri with the key and issuer", secret.ProvisioningURI)
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret.Key)
+	if err != nil {
+		t.Fatalf("failed to decode key: %v", err)
+	}
+	currentStep := time.Now().Unix() / period
+
+	tests := []struct {
+		name         string
+		encryptedKey string
+		code         string
+		wantValid    bool
+		wantErr      error
+	}{
+		{name: "current code", encryptedKey: secret.EncryptedKey,
+			code: generateCode(key, currentStep), wantValid: true},
+		{name: "previous code on skew", encryptedKey: secret.EncryptedKey,
+			code: generateCode(key, currentStep-1), wantValid: true},
+		{name: "code with spaces", encryptedKey: secret.EncryptedKey,
+			code: " " + generateCode(key, currentStep) + " ", wantValid: true},
+		{name: "expired code", encryptedKey: secret.EncryptedKey, code: generateCode(key, currentStep-3)},
+		{name: "short code", encryptedKey: secret.EncryptedKey, code: "123"},
+		{name: "invalid encrypted key", encryptedKey: "not encrypted", code: "123456",
+			wantErr: ErrInvalidEncryptedSecret},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			step, valid, err := service.Validate(test.encryptedKey, test.code)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			// the code of the step before the clock drift can match by chance
+			if test.name == "expired code" && valid && step < currentStep-skewSteps {
+				t.Fatalf("got valid code of step %d, want invalid", step)
+			}
+			if test.name != "expired code" && valid != test.wantValid {
+				t.Fatalf("got valid %v, want %v", valid, test.wantValid)
+			}
+		})
+	}
+}
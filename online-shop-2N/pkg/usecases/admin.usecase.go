@@ -8,58 +8,90 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/denylist"
 	service "online-shop-2N/pkg/usecases/interfaces"
+	"online-shop-2N/pkg/utils"
+
+	token "online-shop-2N/pkg/services/tokens"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type adminUseCase struct {
-	adminRepo interfaces.AdminRepository
-	userRepo  interfaces.UserRepository
+	adminRepo     interfaces.AdminRepository
+	userRepo      interfaces.UserRepository
+	authRepo      interfaces.AuthRepository
+	tokenDenylist denylist.Denylist
 }
 
-func NewAdminUseCase(repo interfaces.AdminRepository, userRepo interfaces.UserRepository) service.AdminUseCase {
+func NewAdminUseCase(repo interfaces.AdminRepository, userRepo interfaces.UserRepository,
+	authRepo interfaces.AuthRepository, tokenDenylist denylist.Denylist) service.AdminUseCase {
 
 	return &adminUseCase{
-		adminRepo: repo,
-		userRepo:  userRepo,
+		adminRepo:     repo,
+		userRepo:      userRepo,
+		authRepo:      authRepo,
+		tokenDenylist: tokenDenylist,
 	}
 }
 
-func (c *adminUseCase) SignUp(ctx context.Context, loginDetails models.Admin) error {
+func (c *adminUseCase) SignUp(ctx context.Context, signUpDetails requests.AdminSignUp) error {
 
-	existAdmin, err := c.adminRepo.FindAdminByEmail(ctx, loginDetails.Email)
+	existAdmin, err := c.adminRepo.FindAdminByEmail(ctx, signUpDetails.Email)
 	if err != nil {
 		return err
 	} else if existAdmin.ID != 0 {
-		return errors.New("can't save admin \nan admin already exist with this email")
+		return utils.AppendMessageToError(ErrAdminAlreadyExist, "an admin already exist with this email")
 	}
 
-	existAdmin, err = c.adminRepo.FindAdminByUserName(ctx, loginDetails.UserName)
+	existAdmin, err = c.adminRepo.FindAdminByUserName(ctx, signUpDetails.UserName)
 	if err != nil {
 		return err
 	} else if existAdmin.ID != 0 {
-		return errors.New("can't save admin \nan admin already exist with this user_name")
+		return utils.AppendMessageToError(ErrAdminAlreadyExist, "an admin already exist with this user_name")
 	}
 
 	// generate a hashed password for admin
-	hashPass, err := bcrypt.GenerateFromPassword([]byte(loginDetails.Password), 10)
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(signUpDetails.Password), 10)
 
 	if err != nil {
 		return errors.New("failed to generate hashed password for admin")
 	}
-	// set the hashed password on the admin
-	loginDetails.Password = string(hashPass)
 
-	return c.adminRepo.SaveAdmin(ctx, loginDetails)
+	err = c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+
+		if err := validateRoleIDs(ctx, trxRepo, signUpDetails.RoleIDs); err != nil {
+			return err
+		}
+
+		adminID, err := trxRepo.SaveAdmin(ctx, models.Admin{
+			UserName: signUpDetails.UserName,
+			Email:    signUpDetails.Email,
+			// set the hashed password on the admin
+			Password: string(hashPass),
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save admin")
+		}
+
+		err = trxRepo.SetAdminRoles(ctx, adminID, signUpDetails.RoleIDs)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save roles of admin")
+		}
+		return nil
+	})
+
+	return err
 }
 
-func (c *adminUseCase) FindAllUser(ctx context.Context, pagination requests.Pagination) (users []responses.User, err error) {
+func (c *adminUseCase) FindAllUser(ctx context.Context,
+	page pagination.Request) (users []responses.User, meta pagination.Meta, err error) {
 
-	users, err = c.adminRepo.FindAllUser(ctx, pagination)
+	users, meta, err = c.adminRepo.FindAllUser(ctx, page)
 
-	return users, err
+	return users, meta, err
 }
 
 // Block User
@@ -78,18 +110,27 @@ func (c *adminUseCase) BlockOrUnBlockUser(ctx context.Context, blockDetails requ
 	if err != nil {
 		return fmt.Errorf("failed to update user block status \nerror:%v", err.Error())
 	}
+
+	// logout the blocked user from all devices immediately
+	if blockDetails.Block {
+		err = revokeAllSessions(ctx, c.authRepo, c.tokenDenylist, token.User, blockDetails.UserID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to revoke sessions of blocked user")
+		}
+	}
 	return nil
 }
 
-func (c *adminUseCase) GetFullSalesReport(ctx context.Context, requestData requests.SalesReport) (salesReport []responses.SalesReport, err error) {
-	salesReport, err = c.adminRepo.CreateFullSalesReport(ctx, requestData)
+func (c *adminUseCase) GetFullSalesReport(ctx context.Context,
+	requestData requests.SalesReport) (salesReport []responses.SalesReport, meta pagination.Meta, err error) {
+	salesReport, meta, err = c.adminRepo.CreateFullSalesReport(ctx, requestData)
 
 	if err != nil {
-		return salesReport, err
+		return salesReport, meta, err
 	}
 
 	log.Printf("successfully got sales report from %v to %v of limit %v",
-		requestData.StartDate, requestData.EndDate, requestData.Pagination.Count)
+		requestData.StartDate, requestData.EndDate, requestData.Pagination.PageSize)
 
-	return salesReport, nil
+	return salesReport, meta, nil
 }
@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/utils"
+)
+
+func (c *adminUseCase) FindAllAdmins(ctx context.Context, page pagination.Request) ([]responses.Admin, pagination.Meta, error) {
+
+	admins, meta, err := c.adminRepo.FindAllAdmins(ctx, page)
+	if err != nil {
+		return nil, meta, utils.PrependMessageToError(err, "failed to find all admins")
+	}
+
+	return admins, meta, nil
+}
+
+// To replace the roles of admin, the super admin role can't be removed from the last super admin
+func (c *adminUseCase) UpdateAdminRoles(ctx context.Context, adminID uint, roleIDs []uint) error {
+
+	return c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+
+		admin, err := trxRepo.FindAdminByID(ctx, adminID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find admin")
+		}
+		if admin.ID == 0 {
+			return ErrAdminNotExist
+		}
+
+		if err := validateRoleIDs(ctx, trxRepo, roleIDs); err != nil {
+			return err
+		}
+
+		superAdminRole, err := trxRepo.FindRoleByName(ctx, commonConstant.RoleSuperAdmin)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find super admin role")
+		}
+
+		currentRoles, err := trxRepo.FindAdminRoles(ctx, adminID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find roles of admin")
+		}
+
+		if hasRole(currentRoles, superAdminRole.ID) && !containsID(roleIDs, superAdminRole.ID) {
+			superAdminsCount, err := trxRepo.CountAdminsWithRole(ctx, superAdminRole.ID)
+			if err != nil {
+				return utils.PrependMessageToError(err, "failed to count super admins")
+			}
+			if superAdminsCount <= 1 {
+				return ErrLastSuperAdmin
+			}
+		}
+
+		err = trxRepo.SetAdminRoles(ctx, adminID, roleIDs)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to update roles of admin")
+		}
+		return nil
+	})
+}
+
+func (c *adminUseCase) FindAllRoles(ctx context.Context) ([]responses.Role, error) {
+
+	roles, err := c.adminRepo.FindAllRoles(ctx)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find all roles")
+	}
+
+	return roles, nil
+}
+
+func (c *adminUseCase) SaveRole(ctx context.Context, role requests.Role) (roleID uint, err error) {
+
+	permissions, err := validatePermissions(role.Permissions)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+
+		existRole, err := trxRepo.FindRoleByName(ctx, role.Name)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to check role already exist")
+		}
+		if existRole.ID != 0 {
+			return ErrRoleAlreadyExist
+		}
+
+		roleID, err = trxRepo.SaveRole(ctx, models.Role{
+			Name:        role.Name,
+			Description: role.Description,
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save role")
+		}
+
+		err = trxRepo.SaveRolePermissions(ctx, roleID, permissions)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save permissions of role")
+		}
+		return nil
+	})
+
+	return roleID, err
+}
+
+// To update the name, description and replace the permissions of role
+func (c *adminUseCase) UpdateRole(ctx context.Context, roleID uint, role requests.Role) error {
+
+	permissions, err := validatePermissions(role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	return c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+
+		if err := checkRoleChangeable(ctx, trxRepo, roleID); err != nil {
+			return err
+		}
+
+		existRole, err := trxRepo.FindRoleByName(ctx, role.Name)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to check role already exist")
+		}
+		if existRole.ID != 0 && existRole.ID != roleID {
+			return ErrRoleAlreadyExist
+		}
+
+		err = trxRepo.UpdateRole(ctx, models.Role{
+			ID:          roleID,
+			Name:        role.Name,
+			Description: role.Description,
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to update role")
+		}
+
+		err = trxRepo.DeleteRolePermissions(ctx, roleID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to remove old permissions of role")
+		}
+
+		err = trxRepo.SaveRolePermissions(ctx, roleID, permissions)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save permissions of role")
+		}
+		return nil
+	})
+}
+
+// To require two factor authentication from the admins of role, it can be changed for super admin role too
+func (c *adminUseCase) UpdateRoleTwoFactor(ctx context.Context, roleID uint, required bool) error {
+
+	role, err := c.adminRepo.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find role")
+	}
+	if role.ID == 0 {
+		return ErrRoleNotExist
+	}
+
+	err = c.adminRepo.UpdateRoleTwoFactor(ctx, roleID, required)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update two factor requirement of role")
+	}
+
+	return nil
+}
+
+// To remove the two factor and recovery codes of admin when the admin lost them,
+// admin should set up the two factor again
+func (c *adminUseCase) ResetAdminTwoFactor(ctx context.Context, adminID uint) error {
+
+	return c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+
+		admin, err := trxRepo.FindAdminByID(ctx, adminID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find admin")
+		}
+		if admin.ID == 0 {
+			return ErrAdminNotExist
+		}
+
+		return removeAdminTwoFactor(ctx, trxRepo, adminID)
+	})
+}
+
+// role can only be deleted when it's not assigned to any admin
+func (c *adminUseCase) DeleteRole(ctx context.Context, roleID uint) error {
+
+	return c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+
+		if err := checkRoleChangeable(ctx, trxRepo, roleID); err != nil {
+			return err
+		}
+
+		adminsCount, err := trxRepo.CountAdminsWithRole(ctx, roleID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to count admins of role")
+		}
+		if adminsCount > 0 {
+			return ErrRoleAssigned
+		}
+
+		err = trxRepo.DeleteRole(ctx, roleID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to delete role")
+		}
+		return nil
+	})
+}
+
+// To check the role exist and it's not the super admin role
+func checkRoleChangeable(ctx context.Context, adminRepo interfaces.AdminRepository, roleID uint) error {
+
+	role, err := adminRepo.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find role")
+	}
+	if role.ID == 0 {
+		return ErrRoleNotExist
+	}
+	if role.Name == commonConstant.RoleSuperAdmin {
+		return ErrSuperAdminRoleChange
+	}
+
+	return nil
+}
+
+// To check all the given roles exist
+func validateRoleIDs(ctx context.Context, adminRepo interfaces.AdminRepository, roleIDs []uint) error {
+
+	roles, err := adminRepo.FindRolesByIDs(ctx, roleIDs)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find roles")
+	}
+
+	for _, roleID := range roleIDs {
+		if !hasRole(roles, roleID) {
+			return ErrRoleNotExist
+		}
+	}
+
+	return nil
+}
+
+// To check the permissions are known and remove the duplicates
+func validatePermissions(permissions []commonConstant.AdminPermission) ([]commonConstant.AdminPermission, error) {
+
+	known := make(map[commonConstant.AdminPermission]bool, len(commonConstant.AdminPermissions))
+	for _, permission := range commonConstant.AdminPermissions {
+		known[permission] = true
+	}
+
+	var (
+		added     = make(map[commonConstant.AdminPermission]bool, len(permissions))
+		validated []commonConstant.AdminPermission
+	)
+	for _, permission := range permissions {
+		if !known[permission] {
+			return nil, utils.AppendMessageToError(ErrInvalidPermission, string(permission))
+		}
+		if !added[permission] {
+			added[permission] = true
+			validated = append(validated, permission)
+		}
+	}
+
+	return validated, nil
+}
+
+func hasRole(roles []models.Role, roleID uint) bool {
+	for _, role := range roles {
+		if role.ID == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, value := range ids {
+		if value == id {
+			return true
+		}
+	}
+	return false
+}
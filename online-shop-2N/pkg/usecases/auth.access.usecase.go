@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/utils"
+	"sync"
+	"time"
+
+	token "online-shop-2N/pkg/services/tokens"
+)
+
+// block status of user is cached for this duration so each request not need to find the user
+// a block is applied immediately through the denylist, the cache only delay the unblock
+const userBlockedCacheTTL = time.Second * 30
+
+// To verify the access token and check it's not revoked, and for user the user is not blocked
+func (c *authUseCase) VerifyAccessToken(ctx context.Context, accessToken string,
+	usedFor token.UserType) (token.VerifyTokenResponse, error) {
+
+	verifyRes, err := c.tokenService.VerifyToken(token.VerifyTokenRequest{
+		TokenString: accessToken,
+		UsedFor:     usedFor,
+	})
+	if err != nil {
+		return token.VerifyTokenResponse{}, utils.AppendMessageToError(ErrInvalidAccessToken, err.Error())
+	}
+	// refresh token live longer than the deny of its session so it's never accepted as a bearer token
+	if verifyRes.TokenUse != token.AccessToken {
+		return token.VerifyTokenResponse{}, utils.AppendMessageToError(ErrInvalidAccessToken, "not an access token")
+	}
+
+	ids := []string{verifyRes.TokenID}
+	if verifyRes.SessionID != "" {
+		ids = append(ids, verifyRes.SessionID)
+	}
+	denied, err := c.tokenDenylist.IsDenied(ctx, ids...)
+	if err != nil {
+		return token.VerifyTokenResponse{}, utils.PrependMessageToError(err, "failed to check access token on denylist")
+	}
+	if denied {
+		return token.VerifyTokenResponse{}, ErrAccessTokenRevoked
+	}
+
+	if usedFor == token.User {
+		blocked, err := c.isUserBlocked(ctx, verifyRes.UserID)
+		if err != nil {
+			return token.VerifyTokenResponse{}, err
+		}
+		if blocked {
+			return token.VerifyTokenResponse{}, ErrUserBlocked
+		}
+	}
+
+	return verifyRes, nil
+}
+
+// To find the public keys which verify the tokens
+func (c *authUseCase) FindJWKS(ctx context.Context) token.JWKSet {
+	return c.tokenService.JWKS()
+}
+
+// To check the admin has all the given permissions through any of its roles
+func (c *authUseCase) VerifyAdminPermissions(ctx context.Context, adminID uint,
+	permissions ...commonConstant.AdminPermission) error {
+
+	adminPermissions, err := c.adminRepo.FindAdminPermissions(ctx, adminID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find permissions of admin")
+	}
+
+	// admins of roles which require two factor can't do anything until they enable it
+	status, err := c.adminRepo.FindAdminTwoFactorStatus(ctx, adminID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find two factor status of admin")
+	}
+	if status.Required && !status.Enabled {
+		return ErrTwoFactorRequired
+	}
+
+	granted := make(map[commonConstant.AdminPermission]bool, len(adminPermissions))
+	for _, permission := range adminPermissions {
+		granted[permission] = true
+	}
+	if granted[commonConstant.PermissionAll] {
+		return nil
+	}
+
+	for _, permission := range permissions {
+		if !granted[permission] {
+			return utils.AppendMessageToError(ErrPermissionDenied, "required permission "+string(permission))
+		}
+	}
+
+	return nil
+}
+
+// To find the user block status from cache or from database when it's not cached
+func (c *authUseCase) isUserBlocked(ctx context.Context, userID uint) (bool, error) {
+
+	if blocked, ok := c.userBlockedCache.get(userID); ok {
+		return blocked, nil
+	}
+
+	user, err := c.userRepo.FindUserByUserID(ctx, userID)
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to find user block status")
+	}
+
+	c.userBlockedCache.set(userID, user.BlockStatus)
+	return user.BlockStatus, nil
+}
+
+type userBlockedCache struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	entries   map[uint]userBlockedEntry
+	lastSweep time.Time
+}
+
+type userBlockedEntry struct {
+	blocked  bool
+	expireAt time.Time
+}
+
+func newUserBlockedCache(ttl time.Duration) *userBlockedCache {
+	return &userBlockedCache{
+		ttl:     ttl,
+		entries: make(map[uint]userBlockedEntry),
+	}
+}
+
+func (c *userBlockedCache) get(userID uint) (blocked, ok bool) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[userID]
+	if !ok || time.Now().After(entry.expireAt) {
+		return false, false
+	}
+	return entry.blocked, true
+}
+
+func (c *userBlockedCache) set(userID uint, blocked bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// remove the expired entries once in a ttl so the map not grow forever
+	now := time.Now()
+	if now.Sub(c.lastSweep) > c.ttl {
+		for id, entry := range c.entries {
+			if now.After(entry.expireAt) {
+				delete(c.entries, id)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	c.entries[userID] = userBlockedEntry{
+		blocked:  blocked,
+		expireAt: now.Add(c.ttl),
+	}
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	token "online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/utils"
+	"time"
+)
+
+// used when the login lock is not configured
+const (
+	defaultLoginMaxFailedAttempts = 5
+	defaultLoginLockDuration      = time.Minute * 15
+)
+
+// To check the account is locked by the failed password attempts
+func (c *authUseCase) checkLoginLock(ctx context.Context, userType token.UserType, userID uint) error {
+
+	lockout, err := c.authRepo.FindLoginLockout(ctx, string(userType), userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find login lock of account")
+	}
+
+	if lockout.LockedUntil != nil {
+		if retryAfter := time.Until(*lockout.LockedUntil); retryAfter > 0 {
+			return utils.WithRetryAfter(ErrAccountLocked, retryAfter)
+		}
+	}
+
+	return nil
+}
+
+// To count the failed password attempt, account is locked when the failed attempts reach the max attempts
+// failures older than the lock duration are not counted
+func (c *authUseCase) addLoginFailure(ctx context.Context, userType token.UserType, userID uint) error {
+
+	now := time.Now()
+	failedAttempts, err := c.authRepo.AddLoginFailure(ctx, string(userType), userID, now.Add(-c.loginLockDuration))
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to count failed login attempt")
+	}
+
+	if failedAttempts < c.loginMaxFailedAttempts {
+		return ErrWrongPassword
+	}
+
+	err = c.authRepo.LockLogin(ctx, string(userType), userID, now.Add(c.loginLockDuration))
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to lock login of account")
+	}
+
+	return utils.WithRetryAfter(ErrAccountLocked, c.loginLockDuration)
+}
+
+func (c *authUseCase) clearLoginFailures(ctx context.Context, userType token.UserType, userID uint) error {
+
+	err := c.authRepo.ClearLoginFailures(ctx, string(userType), userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to clear failed login attempts")
+	}
+
+	return nil
+}
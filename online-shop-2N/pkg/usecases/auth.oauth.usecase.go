@@ -0,0 +1,266 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/oauth"
+	"online-shop-2N/pkg/utils"
+	"strings"
+	"time"
+)
+
+func (c *authUseCase) FindOAuthProviders(ctx context.Context) responses.OAuthProviders {
+	return responses.OAuthProviders{
+		Providers: c.oauthProviders.Names(),
+	}
+}
+
+// one time code of oauth login is exchanged for tokens by frontend right after the redirect
+const oauthLoginCodeDuration = time.Minute
+
+// To get the url of provider which the user is redirected to sign in and the signed flow,
+// the signed flow is kept by client until the callback, link user id is zero when it's not linking to a user
+func (c *authUseCase) GetOAuthURL(ctx context.Context, providerName string,
+	linkUserID uint) (authURL, signedFlow string, err error) {
+
+	provider, err := c.oauthProviders.Get(providerName)
+	if err != nil {
+		return "", "", ErrOAuthProviderNotExist
+	}
+
+	flow, err := oauth.NewFlow(providerName, linkUserID)
+	if err != nil {
+		return "", "", utils.PrependMessageToError(err, "failed to create oauth flow")
+	}
+
+	signedFlow, err = flow.Sign(c.oauthStateKey)
+	if err != nil {
+		return "", "", utils.PrependMessageToError(err, "failed to sign oauth flow")
+	}
+
+	return provider.AuthCodeURL(flow), signedFlow, nil
+}
+
+// To verify the signed flow which is kept by client is of the provider and state of callback
+func (c *authUseCase) VerifyOAuthFlow(ctx context.Context, providerName, signedFlow,
+	state string) (oauth.Flow, error) {
+
+	flow, err := oauth.ParseFlow(signedFlow, c.oauthStateKey)
+	if err != nil {
+		return oauth.Flow{}, ErrInvalidOAuthState
+	}
+
+	if flow.Provider != providerName || subtle.ConstantTimeCompare([]byte(flow.State), []byte(state)) != 1 {
+		return oauth.Flow{}, ErrInvalidOAuthState
+	}
+
+	return flow, nil
+}
+
+// To sign in the user of provider, a new user is created when there is no user with the identity or email.
+// user is created or linked only with an email verified by provider, so an account can't be taken before its owner
+// and the user of the same email is linked only when it's verified on the account also
+func (c *authUseCase) OAuthLogin(ctx context.Context, flow oauth.Flow, code string) (uint, error) {
+
+	oauthUser, err := c.fetchOAuthUser(ctx, flow, code)
+	if err != nil {
+		return 0, err
+	}
+
+	identity, err := c.userRepo.FindUserIdentity(ctx, oauthUser.Provider, oauthUser.ProviderUserID)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to find user identity")
+	}
+
+	if identity.ID != 0 {
+		user, err := c.userRepo.FindUserByUserID(ctx, identity.UserID)
+		if err != nil {
+			return 0, utils.PrependMessageToError(err, "failed to find user of identity")
+		}
+		if user.BlockStatus {
+			return 0, ErrUserBlocked
+		}
+		return user.ID, nil
+	}
+
+	if oauthUser.Email == "" {
+		return 0, ErrOAuthEmailNotShared
+	}
+	if !oauthUser.EmailVerified {
+		return 0, ErrOAuthEmailNotVerified
+	}
+
+	existUser, err := c.userRepo.FindUserByEmail(ctx, oauthUser.Email)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to find user with email")
+	}
+
+	if existUser.ID != 0 {
+		// owner of the email can't be confirmed to merge when the account not verified it
+		if !existUser.Verified {
+			return 0, ErrOAuthAccountNotLinked
+		}
+		if existUser.BlockStatus {
+			return 0, ErrUserBlocked
+		}
+
+		err = c.userRepo.SaveUserIdentity(ctx, newUserIdentity(existUser.ID, oauthUser))
+		if err != nil {
+			return 0, utils.PrependMessageToError(err, "failed to link identity to user")
+		}
+		return existUser.ID, nil
+	}
+
+	return c.saveOAuthUser(ctx, oauthUser)
+}
+
+func (c *authUseCase) FindAllUserIdentities(ctx context.Context, userID uint) ([]responses.UserIdentity, error) {
+
+	identities, err := c.userRepo.FindAllUserIdentities(ctx, userID)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find identities of user")
+	}
+
+	return identities, nil
+}
+
+// To create the one time code which the frontend exchange for the tokens of user after oauth login
+func (c *authUseCase) NewOAuthLoginCode(ctx context.Context, userID uint) (string, error) {
+	return c.newUserToken(ctx, userID, userTokenOAuthLogin, oauthLoginCodeDuration)
+}
+
+func (c *authUseCase) UseOAuthLoginCode(ctx context.Context, loginCode string) (uint, error) {
+
+	userID, err := c.authRepo.UseUserToken(ctx, hashUserToken(loginCode), userTokenOAuthLogin)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to use oauth login code")
+	}
+	if userID == 0 {
+		return 0, ErrInvalidUserToken
+	}
+
+	return userID, nil
+}
+
+// To link the user of provider to the signed in user
+func (c *authUseCase) LinkUserIdentity(ctx context.Context, userID uint, flow oauth.Flow, code string) error {
+
+	oauthUser, err := c.fetchOAuthUser(ctx, flow, code)
+	if err != nil {
+		return err
+	}
+
+	identity, err := c.userRepo.FindUserIdentity(ctx, oauthUser.Provider, oauthUser.ProviderUserID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find user identity")
+	}
+	if identity.ID != 0 {
+		return ErrIdentityAlreadyLinked
+	}
+
+	identities, err := c.userRepo.FindAllUserIdentities(ctx, userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find identities of user")
+	}
+	for _, identity := range identities {
+		if identity.Provider == oauthUser.Provider {
+			return ErrProviderAlreadyLinked
+		}
+	}
+
+	err = c.userRepo.SaveUserIdentity(ctx, newUserIdentity(userID, oauthUser))
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to link identity to user")
+	}
+
+	return nil
+}
+
+// To unlink the provider from user, the last identity can't be unlinked when the user have no password
+func (c *authUseCase) UnlinkUserIdentity(ctx context.Context, userID uint, providerName string) error {
+
+	user, err := c.userRepo.FindUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find user")
+	}
+
+	identities, err := c.userRepo.FindAllUserIdentities(ctx, userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find identities of user")
+	}
+	if user.Password == "" && len(identities) <= 1 {
+		return ErrLastSignInMethod
+	}
+
+	deleted, err := c.userRepo.DeleteUserIdentity(ctx, userID, providerName)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to unlink identity from user")
+	}
+	if !deleted {
+		return ErrIdentityNotLinked
+	}
+
+	return nil
+}
+
+func (c *authUseCase) fetchOAuthUser(ctx context.Context, flow oauth.Flow, code string) (oauth.User, error) {
+
+	provider, err := c.oauthProviders.Get(flow.Provider)
+	if err != nil {
+		return oauth.User{}, ErrOAuthProviderNotExist
+	}
+
+	oauthUser, err := provider.FetchUser(ctx, code, flow)
+	if err != nil {
+		return oauth.User{}, utils.AppendMessageToError(ErrOAuthFailed, err.Error())
+	}
+
+	return oauthUser, nil
+}
+
+// To create a new user with the identity of provider, email of the user should be verified by provider
+func (c *authUseCase) saveOAuthUser(ctx context.Context, oauthUser oauth.User) (userID uint, err error) {
+
+	user := models.User{
+		FirstName:   oauthUser.FirstName,
+		LastName:    oauthUser.LastName,
+		Email:       oauthUser.Email,
+		GoogleImage: oauthUser.AvatarURL,
+		Verified:    true,
+	}
+
+	// create a random user name for user based on first name or the name of email
+	name := user.FirstName
+	if name == "" {
+		name, _, _ = strings.Cut(user.Email, "@")
+	}
+	user.UserName = utils.GenerateRandomUserName(name)
+
+	err = c.userRepo.Transaction(func(trxRepo interfaces.UserRepository) error {
+
+		userID, err = trxRepo.SaveUser(ctx, user)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save user")
+		}
+
+		err = trxRepo.SaveUserIdentity(ctx, newUserIdentity(userID, oauthUser))
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save user identity")
+		}
+		return nil
+	})
+
+	return userID, err
+}
+
+func newUserIdentity(userID uint, oauthUser oauth.User) models.UserIdentity {
+	return models.UserIdentity{
+		UserID:         userID,
+		Provider:       oauthUser.Provider,
+		ProviderUserID: oauthUser.ProviderUserID,
+		Email:          oauthUser.Email,
+	}
+}
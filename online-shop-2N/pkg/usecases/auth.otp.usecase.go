@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	otpExpireDuration = time.Minute * 2
+	otpResendCooldown = time.Minute
+	otpMaxAttempts    = 5
+)
+
+// To send otp to the phone of user and save the otp session, returns the otp id of session
+func (c *authUseCase) sendOtp(ctx context.Context, user models.User) (string, error) {
+
+	phone, err := utils.NormalizePhoneNumber(user.Phone, c.countryCode)
+	if err != nil {
+		return "", ErrInvalidPhoneNumber
+	}
+
+	lastSession, err := c.authRepo.FindLastOtpSessionOfUser(ctx, user.ID)
+	if err != nil {
+		return "", utils.PrependMessageToError(err, "failed to find last otp session of user")
+	}
+	if lastSession.ID != 0 {
+		if wait := otpResendCooldown - time.Since(lastSession.CreatedAt); wait > 0 {
+			return "", utils.WithRetryAfter(ErrOtpResendCooldown, wait)
+		}
+	}
+
+	codeHash, err := c.otpAuth.SendOtp(ctx, phone)
+	if err != nil {
+		return "", utils.PrependMessageToError(err, "failed to send otp")
+	}
+
+	now := time.Now()
+	otpSession := models.OtpSession{
+		OtpID:     uuid.NewString(),
+		UserID:    user.ID,
+		Phone:     phone,
+		CodeHash:  codeHash,
+		ExpireAt:  now.Add(otpExpireDuration),
+		CreatedAt: now,
+	}
+	err = c.authRepo.SaveOtpSession(ctx, otpSession)
+	if err != nil {
+		return "", utils.PrependMessageToError(err, "failed to save otp session")
+	}
+
+	return otpSession.OtpID, nil
+}
+
+// To verify the otp of session, the session can be verified only once and with limited attempts
+func (c *authUseCase) verifyOtp(ctx context.Context, otpVerifyDetails requests.OTPVerify) (models.OtpSession, error) {
+
+	otpSession, err := c.authRepo.FindOtpSession(ctx, otpVerifyDetails.OtpID)
+	if err != nil {
+		return otpSession, utils.PrependMessageToError(err, "failed to find otp session from database")
+	}
+
+	if otpSession.ID == 0 {
+		return otpSession, ErrInvalidOtp
+	}
+	if otpSession.UsedAt != nil {
+		return otpSession, ErrOtpAlreadyUsed
+	}
+	if time.Since(otpSession.ExpireAt) > 0 {
+		return otpSession, ErrOtpExpired
+	}
+
+	// count the attempt before verify so concurrent wrong codes can't exceed the max attempts
+	added, err := c.authRepo.AddOtpAttempt(ctx, otpSession.OtpID, otpMaxAttempts)
+	if err != nil {
+		return otpSession, utils.PrependMessageToError(err, "failed to add attempt of otp session")
+	}
+	if !added {
+		return otpSession, ErrOtpAttemptsExceeded
+	}
+
+	valid, err := c.otpAuth.VerifyOtp(ctx, otpSession.Phone, otpVerifyDetails.Otp, otpSession.CodeHash)
+	if err != nil {
+		return otpSession, utils.PrependMessageToError(err, "failed to verify otp")
+	}
+	if !valid {
+		return otpSession, ErrInvalidOtp
+	}
+
+	used, err := c.authRepo.UseOtpSession(ctx, otpSession.OtpID)
+	if err != nil {
+		return otpSession, utils.PrependMessageToError(err, "failed to mark otp session as used")
+	}
+	if !used {
+		return otpSession, ErrOtpAlreadyUsed
+	}
+
+	return otpSession, nil
+}
@@ -0,0 +1,200 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/services/mailer"
+	token "online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/utils"
+	"time"
+)
+
+// purposes of the single use tokens of user, the oauth login code is given on redirect and others sent by mail
+const (
+	userTokenPasswordReset     = "password_reset"
+	userTokenEmailVerification = "email_verification"
+	userTokenOAuthLogin        = "oauth_login"
+)
+
+const (
+	passwordResetTokenDuration     = time.Minute * 30
+	emailVerificationTokenDuration = time.Hour * 24
+)
+
+// To send password reset link to the email, nothing is sent when no user with the email
+// so the response is same and not show the email is registered or not
+func (c *authUseCase) ForgotPassword(ctx context.Context, email string) error {
+
+	user, err := c.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find user")
+	}
+
+	if user.ID == 0 || user.BlockStatus {
+		log.Printf("password reset requested for not existing or blocked user")
+		return nil
+	}
+
+	resetToken, err := c.newUserToken(ctx, user.ID, userTokenPasswordReset, passwordResetTokenDuration)
+	if err != nil {
+		return err
+	}
+
+	err = c.mailer.Send(ctx, mailer.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password. "+
+			"The link expires in %v and can be used only once.\n\n%s\n\n"+
+			"If you didn't request it you can ignore this mail.",
+			user.FirstName, passwordResetTokenDuration, c.frontendLink("/reset-password", resetToken)),
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to send password reset mail")
+	}
+
+	return nil
+}
+
+// To reset the password with the token and logout the user from all devices
+func (c *authUseCase) ResetPassword(ctx context.Context, resetDetails requests.ResetPassword) error {
+
+	hashPass, err := utils.GenerateHashFromPassword(resetDetails.Password)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to hash the password")
+	}
+
+	userID, err := c.authRepo.UseUserToken(ctx, hashUserToken(resetDetails.Token), userTokenPasswordReset)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to use password reset token")
+	}
+	if userID == 0 {
+		return ErrInvalidUserToken
+	}
+
+	err = c.userRepo.UpdatePassword(ctx, userID, hashPass)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update password")
+	}
+
+	err = revokeAllSessions(ctx, c.authRepo, c.tokenDenylist, token.User, userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to revoke sessions of user")
+	}
+
+	return nil
+}
+
+// To send the email verification link again, nothing is sent when no user with the email
+func (c *authUseCase) ResendEmailVerification(ctx context.Context, email string) error {
+
+	user, err := c.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find user")
+	}
+
+	if user.ID == 0 {
+		log.Printf("email verification requested for not existing user")
+		return nil
+	}
+	if user.Verified {
+		return ErrEmailAlreadyVerified
+	}
+
+	return c.sendEmailVerification(ctx, user)
+}
+
+// To verify the email of user with the token sent on sign up
+func (c *authUseCase) VerifyEmail(ctx context.Context, verificationToken string) error {
+
+	userID, err := c.authRepo.UseUserToken(ctx, hashUserToken(verificationToken), userTokenEmailVerification)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to use email verification token")
+	}
+	if userID == 0 {
+		return ErrInvalidUserToken
+	}
+
+	err = c.userRepo.UpdateVerified(ctx, userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update user verified on database")
+	}
+
+	return nil
+}
+
+func (c *authUseCase) sendEmailVerification(ctx context.Context, user models.User) error {
+
+	verificationToken, err := c.newUserToken(ctx, user.ID, userTokenEmailVerification, emailVerificationTokenDuration)
+	if err != nil {
+		return err
+	}
+
+	err = c.mailer.Send(ctx, mailer.Mail{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to verify your email. The link expires in %v.\n\n%s",
+			user.FirstName, emailVerificationTokenDuration, c.frontendLink("/verify-email", verificationToken)),
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to send email verification mail")
+	}
+
+	return nil
+}
+
+// To create a new single use token for the purpose, the previous tokens of the purpose are expired
+// so only the token on the last mail is valid
+func (c *authUseCase) newUserToken(ctx context.Context, userID uint, purpose string,
+	duration time.Duration) (string, error) {
+
+	userToken, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.authRepo.ExpireUserTokens(ctx, userID, purpose)
+	if err != nil {
+		return "", utils.PrependMessageToError(err, "failed to expire previous tokens")
+	}
+
+	err = c.authRepo.SaveUserToken(ctx, models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashUserToken(userToken),
+		ExpireAt:  time.Now().Add(duration),
+	})
+	if err != nil {
+		return "", utils.PrependMessageToError(err, "failed to save token")
+	}
+
+	return userToken, nil
+}
+
+func (c *authUseCase) frontendLink(path, userToken string) string {
+	return c.frontendURL + path + "?token=" + url.QueryEscape(userToken)
+}
+
+// To generate a random url safe token with 32 bytes of randomness
+func generateRandomToken() (string, error) {
+
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", utils.PrependMessageToError(err, "failed to generate random token")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// token has enough randomness so sha256 is enough to not expose it from database
+func hashUserToken(userToken string) string {
+	hash := sha256.Sum256([]byte(userToken))
+	return hex.EncodeToString(hash[:])
+}
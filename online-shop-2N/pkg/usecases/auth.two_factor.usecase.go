@@ -0,0 +1,309 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/utils"
+	"strings"
+	"time"
+)
+
+const (
+	adminLoginChallengeDuration    = time.Minute * 5
+	adminLoginChallengeMaxAttempts = 5
+
+	recoveryCodesCount = 10
+	recoveryCodeLength = 10
+	// letters and numbers which are not confused with each other on reading
+	recoveryCodeLetters = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// To start the sign in challenge of admin which is completed with the two factor code
+func (c *authUseCase) newAdminLoginChallenge(ctx context.Context,
+	adminID uint) (responses.TwoFactorChallenge, error) {
+
+	challengeToken, err := generateRandomToken()
+	if err != nil {
+		return responses.TwoFactorChallenge{}, err
+	}
+
+	expireAt := time.Now().Add(adminLoginChallengeDuration)
+	err = c.adminRepo.SaveAdminLoginChallenge(ctx, models.AdminLoginChallenge{
+		AdminID:   adminID,
+		TokenHash: hashUserToken(challengeToken),
+		ExpireAt:  expireAt,
+	})
+	if err != nil {
+		return responses.TwoFactorChallenge{}, utils.PrependMessageToError(err, "failed to save sign in challenge")
+	}
+
+	return responses.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+		ExpireAt:          expireAt,
+	}, nil
+}
+
+// To complete the sign in challenge with the code of authenticator or a recovery code
+func (c *authUseCase) AdminLoginTwoFactor(ctx context.Context,
+	loginDetails requests.AdminTwoFactorLogin) (adminID uint, err error) {
+
+	tokenHash := hashUserToken(loginDetails.ChallengeToken)
+
+	challenge, err := c.adminRepo.FindAdminLoginChallenge(ctx, tokenHash)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to find sign in challenge")
+	}
+	if challenge.ID == 0 || challenge.UsedAt != nil || time.Since(challenge.ExpireAt) > 0 {
+		return 0, ErrInvalidChallengeToken
+	}
+
+	// count the attempt before verify so concurrent wrong codes can't exceed the max attempts
+	added, err := c.adminRepo.AddAdminLoginChallengeAttempt(ctx, tokenHash, adminLoginChallengeMaxAttempts)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to add attempt of sign in challenge")
+	}
+	if !added {
+		return 0, ErrChallengeAttemptsExceed
+	}
+
+	if err := c.verifyTwoFactorCode(ctx, challenge.AdminID, loginDetails.Code); err != nil {
+		return 0, err
+	}
+
+	used, err := c.adminRepo.UseAdminLoginChallenge(ctx, tokenHash)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to mark sign in challenge as used")
+	}
+	if !used {
+		return 0, ErrInvalidChallengeToken
+	}
+
+	return challenge.AdminID, nil
+}
+
+func (c *authUseCase) FindAdminTwoFactorStatus(ctx context.Context,
+	adminID uint) (responses.TwoFactorStatus, error) {
+
+	status, err := c.adminRepo.FindAdminTwoFactorStatus(ctx, adminID)
+	if err != nil {
+		return status, utils.PrependMessageToError(err, "failed to find two factor status of admin")
+	}
+
+	return status, nil
+}
+
+// To generate a new secret for admin which is enabled only after a code of it verified
+func (c *authUseCase) SetupAdminTwoFactor(ctx context.Context, adminID uint) (responses.TwoFactorSetup, error) {
+
+	admin, err := c.adminRepo.FindAdminByID(ctx, adminID)
+	if err != nil {
+		return responses.TwoFactorSetup{}, utils.PrependMessageToError(err, "failed to find admin")
+	}
+	if admin.ID == 0 {
+		return responses.TwoFactorSetup{}, ErrAdminNotExist
+	}
+
+	twoFactor, err := c.adminRepo.FindAdminTwoFactor(ctx, adminID)
+	if err != nil {
+		return responses.TwoFactorSetup{}, utils.PrependMessageToError(err, "failed to find two factor of admin")
+	}
+	if twoFactor.EnabledAt != nil {
+		return responses.TwoFactorSetup{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := c.totpService.GenerateSecret(admin.Email)
+	if err != nil {
+		return responses.TwoFactorSetup{}, utils.PrependMessageToError(err, "failed to generate two factor secret")
+	}
+
+	err = c.adminRepo.SaveAdminTwoFactor(ctx, models.AdminTwoFactor{
+		AdminID: adminID,
+		Secret:  secret.EncryptedKey,
+	})
+	if err != nil {
+		return responses.TwoFactorSetup{}, utils.PrependMessageToError(err, "failed to save two factor secret")
+	}
+
+	return responses.TwoFactorSetup{
+		Secret:          secret.Key,
+		ProvisioningURI: secret.ProvisioningURI,
+	}, nil
+}
+
+// To enable the two factor after the first code of authenticator verified and return the recovery codes
+func (c *authUseCase) EnableAdminTwoFactor(ctx context.Context, adminID uint,
+	code string) (responses.RecoveryCodes, error) {
+
+	twoFactor, err := c.adminRepo.FindAdminTwoFactor(ctx, adminID)
+	if err != nil {
+		return responses.RecoveryCodes{}, utils.PrependMessageToError(err, "failed to find two factor of admin")
+	}
+	if twoFactor.AdminID == 0 {
+		return responses.RecoveryCodes{}, ErrTwoFactorNotSetup
+	}
+	if twoFactor.EnabledAt != nil {
+		return responses.RecoveryCodes{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, valid, err := c.totpService.Validate(twoFactor.Secret, code)
+	if err != nil {
+		return responses.RecoveryCodes{}, utils.PrependMessageToError(err, "failed to validate two factor code")
+	}
+	if !valid {
+		return responses.RecoveryCodes{}, ErrInvalidTwoFactorCode
+	}
+
+	var recoveryCodes responses.RecoveryCodes
+	err = c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+
+		err := trxRepo.EnableAdminTwoFactor(ctx, adminID, step)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to enable two factor")
+		}
+
+		recoveryCodes, err = saveNewRecoveryCodes(ctx, trxRepo, adminID)
+		return err
+	})
+
+	return recoveryCodes, err
+}
+
+// To disable the two factor of admin with a valid code, it can't be disabled when it's required by role
+func (c *authUseCase) DisableAdminTwoFactor(ctx context.Context, adminID uint, code string) error {
+
+	status, err := c.adminRepo.FindAdminTwoFactorStatus(ctx, adminID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find two factor status of admin")
+	}
+	if status.Required {
+		return ErrTwoFactorRequired
+	}
+
+	if err := c.verifyTwoFactorCode(ctx, adminID, code); err != nil {
+		return err
+	}
+
+	return c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) error {
+		return removeAdminTwoFactor(ctx, trxRepo, adminID)
+	})
+}
+
+// To replace the recovery codes of admin with new codes after a valid code verified
+func (c *authUseCase) RegenerateAdminRecoveryCodes(ctx context.Context, adminID uint,
+	code string) (responses.RecoveryCodes, error) {
+
+	if err := c.verifyTwoFactorCode(ctx, adminID, code); err != nil {
+		return responses.RecoveryCodes{}, err
+	}
+
+	var recoveryCodes responses.RecoveryCodes
+	err := c.adminRepo.Transaction(func(trxRepo interfaces.AdminRepository) (err error) {
+		recoveryCodes, err = saveNewRecoveryCodes(ctx, trxRepo, adminID)
+		return err
+	})
+
+	return recoveryCodes, err
+}
+
+// To verify the code of authenticator or a recovery code of admin, both can be used only once
+func (c *authUseCase) verifyTwoFactorCode(ctx context.Context, adminID uint, code string) error {
+
+	twoFactor, err := c.adminRepo.FindAdminTwoFactor(ctx, adminID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find two factor of admin")
+	}
+	if twoFactor.AdminID == 0 || twoFactor.EnabledAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeRecoveryCode(code)
+
+	// codes of authenticator are digits only and recovery codes have letters
+	step, valid, err := c.totpService.Validate(twoFactor.Secret, code)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to validate two factor code")
+	}
+
+	var used bool
+	if valid {
+		used, err = c.adminRepo.UseAdminTwoFactorStep(ctx, adminID, step)
+	} else {
+		used, err = c.adminRepo.UseAdminRecoveryCode(ctx, adminID, hashUserToken(code))
+	}
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to mark two factor code as used")
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// To remove the two factor and recovery codes of admin
+func removeAdminTwoFactor(ctx context.Context, adminRepo interfaces.AdminRepository, adminID uint) error {
+
+	err := adminRepo.DeleteAdminTwoFactor(ctx, adminID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to remove two factor of admin")
+	}
+
+	err = adminRepo.DeleteAdminRecoveryCodes(ctx, adminID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to remove recovery codes of admin")
+	}
+
+	return nil
+}
+
+// To generate new recovery codes and replace the previous codes of admin with them
+func saveNewRecoveryCodes(ctx context.Context, adminRepo interfaces.AdminRepository,
+	adminID uint) (responses.RecoveryCodes, error) {
+
+	codes := make([]string, recoveryCodesCount)
+	codeHashes := make([]string, recoveryCodesCount)
+
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return responses.RecoveryCodes{}, utils.PrependMessageToError(err, "failed to generate recovery code")
+		}
+		// shown with a separator to read easily
+		codes[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+		codeHashes[i] = hashUserToken(code)
+	}
+
+	err := adminRepo.SaveAdminRecoveryCodes(ctx, adminID, codeHashes)
+	if err != nil {
+		return responses.RecoveryCodes{}, utils.PrependMessageToError(err, "failed to save recovery codes")
+	}
+
+	return responses.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+func generateRecoveryCode() (string, error) {
+
+	code := make([]byte, recoveryCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeLetters))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = recoveryCodeLetters[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// recovery codes are accepted with or without the separator and in any case
+func normalizeRecoveryCode(code string) string {
+
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
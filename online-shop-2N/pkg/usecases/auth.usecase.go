@@ -2,45 +2,88 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/config"
 	"online-shop-2N/pkg/models"
 	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/denylist"
+	"online-shop-2N/pkg/services/mailer"
+	"online-shop-2N/pkg/services/oauth"
 	"online-shop-2N/pkg/services/otp"
 	token "online-shop-2N/pkg/services/tokens"
+	"online-shop-2N/pkg/services/totp"
 	service "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
-	"sync"
+	"strings"
 	"time"
-
-	"github.com/google/uuid"
-)
-
-const (
-	countryCode       = "+84"
-	otpExpireDuration = time.Minute * 2
 )
 
 type authUseCase struct {
 	authRepo interfaces.AuthRepository
 
-	userRepo     interfaces.UserRepository
-	adminRepo    interfaces.AdminRepository
-	tokenService token.TokenService
-	optAuth      otp.OtpAuth
+	userRepo       interfaces.UserRepository
+	adminRepo      interfaces.AdminRepository
+	tokenService   token.TokenService
+	tokenDenylist  denylist.Denylist
+	otpAuth        otp.OtpAuth
+	totpService    totp.TotpService
+	mailer         mailer.Mailer
+	oauthProviders oauth.Registry
+	oauthStateKey  []byte
+	frontendURL    string
+	countryCode    string
+
+	loginMaxFailedAttempts uint
+	loginLockDuration      time.Duration
+	userBlockedCache       *userBlockedCache
 }
 
 func NewAuthUseCase(authRepo interfaces.AuthRepository, tokenService token.TokenService,
 	userRepo interfaces.UserRepository, adminRepo interfaces.AdminRepository,
-	optAuth otp.OtpAuth) service.AuthUseCase {
+	otpAuth otp.OtpAuth, totpService totp.TotpService, tokenDenylist denylist.Denylist, mailer mailer.Mailer,
+	oauthProviders oauth.Registry, cfg config.Config) service.AuthUseCase {
+
+	defaultCountryCode := cfg.DefaultCountryCode
+	if defaultCountryCode == "" {
+		defaultCountryCode = utils.DefaultCountryCode
+	}
+
+	loginMaxFailedAttempts := cfg.LoginMaxFailedAttempts
+	if loginMaxFailedAttempts == 0 {
+		loginMaxFailedAttempts = defaultLoginMaxFailedAttempts
+	}
+	// flow of oauth is signed with the user auth key when the state key not configured
+	oauthStateKey := cfg.OAuthStateKey
+	if oauthStateKey == "" {
+		oauthStateKey = cfg.UserAuthKey
+	}
+
+	loginLockDuration := cfg.LoginLockDuration
+	if loginLockDuration == 0 {
+		loginLockDuration = defaultLoginLockDuration
+	}
 
 	return &authUseCase{
-		userRepo:     userRepo,
-		adminRepo:    adminRepo,
-		tokenService: tokenService,
-		authRepo:     authRepo,
-		optAuth:      optAuth,
+		userRepo:         userRepo,
+		adminRepo:        adminRepo,
+		tokenService:     tokenService,
+		tokenDenylist:    tokenDenylist,
+		authRepo:         authRepo,
+		otpAuth:          otpAuth,
+		totpService:      totpService,
+		mailer:           mailer,
+		oauthProviders:   oauthProviders,
+		oauthStateKey:    []byte(oauthStateKey),
+		frontendURL:      strings.TrimRight(cfg.FrontendURL, "/"),
+		countryCode:      defaultCountryCode,
+		userBlockedCache: newUserBlockedCache(userBlockedCacheTTL),
+
+		loginMaxFailedAttempts: loginMaxFailedAttempts,
+		loginLockDuration:      loginLockDuration,
 	}
 }
 
@@ -82,9 +125,17 @@ func (c *authUseCase) UserLogin(ctx context.Context, loginDetails requests.Login
 		return 0, ErrUserBlocked
 	}
 
+	if err := c.checkLoginLock(ctx, token.User, user.ID); err != nil {
+		return 0, err
+	}
+
 	err = utils.ComparePasswordWithHashedPassword(loginDetails.Password, user.Password)
 	if err != nil {
-		return 0, ErrWrongPassword
+		return 0, c.addLoginFailure(ctx, token.User, user.ID)
+	}
+
+	if err := c.clearLoginFailures(ctx, token.User, user.ID); err != nil {
+		return 0, err
 	}
 
 	return user.ID, nil
@@ -121,68 +172,23 @@ func (c *authUseCase) UserLoginOtpSend(ctx context.Context, loginDetails request
 		return "", ErrUserBlocked
 	}
 
-	errChan := make(chan error, 2)
-	wait := sync.WaitGroup{}
-	wait.Add(2)
-
-	go func() {
-		defer wait.Done()
-		_, err := c.optAuth.SentOtp(countryCode + user.Phone)
-		if err != nil {
-			errChan <- fmt.Errorf("failed to send otp \nerrors:%v", err.Error())
-		}
-	}()
-	otpID := uuid.NewString()
-
-	go func() {
-		defer wait.Done()
-		otpSession := models.OtpSession{
-			OtpID:    otpID,
-			UserID:   user.ID,
-			Phone:    user.Phone,
-			ExpireAt: time.Now().Add(otpExpireDuration), // 2 minutes expire for otp
-		}
-		err := c.authRepo.SaveOtpSession(ctx, otpSession)
-		if err != nil {
-			errChan <- fmt.Errorf("failed to save otp session \nerror:%v", err.Error())
-		}
-	}()
-
-	wait.Wait()
-	close(errChan)
-
-	for err := range errChan {
-		if err != nil {
-			return "", err
-		}
-	}
-
-	return otpID, nil
+	return c.sendOtp(ctx, user)
 }
 
 func (c *authUseCase) LoginOtpVerify(ctx context.Context, otpVerifyDetails requests.OTPVerify) (uint, error) {
 
-	otpSession, err := c.authRepo.FindOtpSession(ctx, otpVerifyDetails.OtpID)
+	otpSession, err := c.verifyOtp(ctx, otpVerifyDetails)
 	if err != nil {
-		return 0, utils.PrependMessageToError(err, "failed to find otp session from database")
-	}
-
-	if time.Since(otpSession.ExpireAt) > 0 {
-		return 0, ErrOtpExpired
-	}
-
-	valid, err := c.optAuth.VerifyOtp(countryCode+otpSession.Phone, otpVerifyDetails.Otp)
-	if err != nil {
-		return 0, utils.PrependMessageToError(err, "failed to verify otp")
-	}
-	if !valid {
-		return 0, ErrInvalidOtp
+		return 0, err
 	}
 
 	return otpSession.UserID, nil
 }
 
-func (c *authUseCase) AdminLogin(ctx context.Context, loginDetails requests.Login) (uint, error) {
+// To verify the password of admin, a sign in challenge is returned instead of admin id
+// when the two factor authentication is enabled for admin
+func (c *authUseCase) AdminLogin(ctx context.Context,
+	loginDetails requests.Login) (uint, responses.TwoFactorChallenge, error) {
 
 	var (
 		admin models.Admin
@@ -194,61 +200,109 @@ func (c *authUseCase) AdminLogin(ctx context.Context, loginDetails requests.Logi
 	case loginDetails.UserName != "":
 		admin, err = c.adminRepo.FindAdminByUserName(ctx, loginDetails.UserName)
 	default:
-		return 0, ErrEmptyLoginCredentials
+		return 0, responses.TwoFactorChallenge{}, ErrEmptyLoginCredentials
 	}
 
 	if err != nil {
-		return 0, utils.PrependMessageToError(err, "failed to find admin")
+		return 0, responses.TwoFactorChallenge{}, utils.PrependMessageToError(err, "failed to find admin")
 	}
 
 	if admin.ID == 0 {
-		return 0, ErrUserNotExist
+		return 0, responses.TwoFactorChallenge{}, ErrUserNotExist
+	}
+
+	if err := c.checkLoginLock(ctx, token.Admin, admin.ID); err != nil {
+		return 0, responses.TwoFactorChallenge{}, err
 	}
 
 	err = utils.ComparePasswordWithHashedPassword(loginDetails.Password, admin.Password)
 	if err != nil {
-		return 0, ErrWrongPassword
+		return 0, responses.TwoFactorChallenge{}, c.addLoginFailure(ctx, token.Admin, admin.ID)
+	}
+
+	if err := c.clearLoginFailures(ctx, token.Admin, admin.ID); err != nil {
+		return 0, responses.TwoFactorChallenge{}, err
+	}
+
+	status, err := c.adminRepo.FindAdminTwoFactorStatus(ctx, admin.ID)
+	if err != nil {
+		return 0, responses.TwoFactorChallenge{}, utils.PrependMessageToError(err,
+			"failed to find two factor status of admin")
+	}
+	if !status.Enabled {
+		return admin.ID, responses.TwoFactorChallenge{}, nil
+	}
+
+	challenge, err := c.newAdminLoginChallenge(ctx, admin.ID)
+	if err != nil {
+		return 0, responses.TwoFactorChallenge{}, err
 	}
 
-	return admin.ID, nil
+	return 0, challenge, nil
 }
 
 func (c *authUseCase) GenerateAccessToken(ctx context.Context, tokenParams service.GenerateTokenParams) (string, error) {
 
 	tokenReq := token.GenerateTokenRequest{
-		UserID:   tokenParams.UserID,
-		UsedFor:  tokenParams.UserType,
-		ExpireAt: time.Now().Add(AccessTokenDuration),
+		UserID:    tokenParams.UserID,
+		UsedFor:   tokenParams.UserType,
+		TokenUse:  token.AccessToken,
+		SessionID: tokenParams.SessionID,
+		ExpireAt:  time.Now().Add(AccessTokenDuration),
 	}
 
 	tokenRes, err := c.tokenService.GenerateToken(tokenReq)
 
 	return tokenRes.TokenString, err
 }
-func (c *authUseCase) GenerateRefreshToken(ctx context.Context, tokenParams service.GenerateTokenParams) (string, error) {
+
+// To generate a refresh token and save its session, a new session is created when session id not given
+func (c *authUseCase) GenerateRefreshToken(ctx context.Context, tokenParams service.GenerateTokenParams) (models.RefreshSession, error) {
+
+	if tokenParams.SessionID == "" {
+		tokenParams.SessionID = utils.GenerateUniqueString()
+	}
+
+	refreshSession, err := c.newRefreshSession(tokenParams)
+	if err != nil {
+		return models.RefreshSession{}, err
+	}
+
+	err = c.authRepo.SaveRefreshSession(ctx, refreshSession)
+	if err != nil {
+		return models.RefreshSession{}, err
+	}
+	log.Printf("successfully refresh token created and refresh session stored in database")
+	return refreshSession, nil
+}
+
+// To sign a new refresh token for the session
+func (c *authUseCase) newRefreshSession(tokenParams service.GenerateTokenParams) (models.RefreshSession, error) {
 
 	expireAt := time.Now().Add(RefreshTokenDuration)
 	tokenReq := token.GenerateTokenRequest{
-		UserID:   tokenParams.UserID,
-		UsedFor:  tokenParams.UserType,
-		ExpireAt: expireAt,
+		UserID:    tokenParams.UserID,
+		UsedFor:   tokenParams.UserType,
+		TokenUse:  token.RefreshToken,
+		SessionID: tokenParams.SessionID,
+		ExpireAt:  expireAt,
 	}
 	tokenRes, err := c.tokenService.GenerateToken(tokenReq)
 	if err != nil {
-		return "", err
+		return models.RefreshSession{}, err
 	}
 
-	err = c.authRepo.SaveRefreshSession(ctx, models.RefreshSession{
-		UserID:       tokenParams.UserID,
+	return models.RefreshSession{
 		TokenID:      tokenRes.TokenID,
+		UserID:       tokenParams.UserID,
+		UserType:     string(tokenParams.UserType),
+		SessionID:    tokenParams.SessionID,
 		RefreshToken: tokenRes.TokenString,
 		ExpireAt:     expireAt,
-	})
-	if err != nil {
-		return "", err
-	}
-	log.Printf("successfully refresh token created and refresh session stored in database")
-	return tokenRes.TokenString, nil
+		UserAgent:    tokenParams.UserAgent,
+		IPAddress:    tokenParams.IPAddress,
+		CreatedAt:    time.Now(),
+	}, nil
 }
 
 func (c *authUseCase) VerifyAndGetRefreshTokenSession(ctx context.Context, refreshToken string, usedFor token.UserType) (models.RefreshSession, error) {
@@ -261,14 +315,17 @@ func (c *authUseCase) VerifyAndGetRefreshTokenSession(ctx context.Context, refre
 	if err != nil {
 		return models.RefreshSession{}, utils.PrependMessageToError(ErrInvalidRefreshToken, err.Error())
 	}
+	if verifyRes.TokenUse != token.RefreshToken {
+		return models.RefreshSession{}, utils.PrependMessageToError(ErrInvalidRefreshToken, "not a refresh token")
+	}
 
 	refreshSession, err := c.authRepo.FindRefreshSessionByTokenID(ctx, verifyRes.TokenID)
 	if err != nil {
 		return refreshSession, err
 	}
 
-	if refreshSession.TokenID == "" {
-		return refreshSession, ErrRefreshSessionNotExist
+	if refreshSession.TokenID == "" || refreshSession.UserType != string(usedFor) {
+		return models.RefreshSession{}, ErrRefreshSessionNotExist
 	}
 
 	if time.Since(refreshSession.ExpireAt) > 0 {
@@ -279,126 +336,183 @@ func (c *authUseCase) VerifyAndGetRefreshTokenSession(ctx context.Context, refre
 		return models.RefreshSession{}, ErrRefreshSessionBlocked
 	}
 
+	// an already rotated token is used again means it's stolen, so revoke the whole session
+	if refreshSession.ReplacedByTokenID != "" {
+		return models.RefreshSession{}, c.revokeReusedSession(ctx, refreshSession)
+	}
+
 	return refreshSession, nil
 }
 
-func (c *authUseCase) UserSignUp(ctx context.Context, signUpDetails models.User) error {
+// To replace the refresh token with a new refresh token of the same session
+func (c *authUseCase) RotateRefreshToken(ctx context.Context, refreshToken string,
+	tokenParams service.GenerateTokenParams) (models.RefreshSession, error) {
 
-	existUser, err := c.userRepo.FindUserByUserNameEmailOrPhoneNotID(ctx, signUpDetails)
+	refreshSession, err := c.VerifyAndGetRefreshTokenSession(ctx, refreshToken, tokenParams.UserType)
 	if err != nil {
-		return utils.PrependMessageToError(err, "failed to check user details already exist")
+		return models.RefreshSession{}, err
 	}
 
-	// if user crdentials already exiest and  verified then return it as errors
-	if existUser.ID != 0 && existUser.Verified {
-		err = utils.CompareUserExistingDetails(existUser, signUpDetails)
-		err = utils.AppendMessageToError(ErrUserAlreadyExit, err.Error())
-		return err
-	}
+	tokenParams.UserID = refreshSession.UserID
+	tokenParams.SessionID = refreshSession.SessionID
 
-	// errChan := make(chan error, 2)
-	// wait := sync.WaitGroup{}
-	// wait.Add(2)
-
-	// go func() {
-	// 	defer wait.Done()
-	// 	_, err := c.optAuth.SentOtp(countryCode + signUpDetails.Phone)
-	// 	if err != nil {
-	// 		errChan <- fmt.Errorf("failed to send otp \nerrors:%v", err.Error())
-	// 	}
-	// }()
+	newRefreshSession, err := c.newRefreshSession(tokenParams)
+	if err != nil {
+		return models.RefreshSession{}, err
+	}
 
-	userID := existUser.ID
+	err = c.authRepo.Transaction(func(trxRepo interfaces.AuthRepository) error {
 
-	if userID == 0 { // if user not exist then save user on database
-		hashPass, err := utils.GenerateHashFromPassword(signUpDetails.Password)
+		// the token may be rotated by another request after it's verified
+		replaced, err := trxRepo.ReplaceRefreshSession(ctx, refreshSession.TokenID, newRefreshSession.TokenID)
 		if err != nil {
-			return utils.PrependMessageToError(err, "failed to hash the password")
+			return utils.PrependMessageToError(err, "failed to replace refresh session")
+		}
+		if !replaced {
+			return ErrRefreshTokenReused
 		}
 
-		signUpDetails.Password = string(hashPass)
-		userID, err = c.userRepo.SaveUser(ctx, signUpDetails)
-
+		err = trxRepo.SaveRefreshSession(ctx, newRefreshSession)
 		if err != nil {
-			return utils.PrependMessageToError(err, "failed to save user details")
+			return utils.PrependMessageToError(err, "failed to save refresh session")
 		}
+		return nil
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return models.RefreshSession{}, c.revokeReusedSession(ctx, refreshSession)
+	}
+	if err != nil {
+		return models.RefreshSession{}, err
 	}
 
-	// otpID := uuid.NewString()
-
-	// go func() {
-	// 	defer wait.Done()
-	// 	otpSession := models.OtpSession{
-	// 		OtpID:    otpID,
-	// 		UserID:   userID,
-	// 		Phone:    signUpDetails.Phone,
-	// 		ExpireAt: time.Now().Add(otpExpireDuration), // 2 minutes expire for otp
-	// 	}
-	// 	err := c.authRepo.SaveOtpSession(ctx, otpSession)
-	// 	if err != nil {
-	// 		errChan <- fmt.Errorf("failed to save otp session \nerror:%v", err.Error())
-	// 	}
-	// }()
+	return newRefreshSession, nil
+}
 
-	// wait.Wait()
-	// close(errChan)
+// To block all refresh tokens of the session of the reused token, deny its access tokens and return the reused error
+func (c *authUseCase) revokeReusedSession(ctx context.Context, refreshSession models.RefreshSession) error {
 
-	// for err := range errChan {
-	// 	if err != nil {
-	// 		return "", err
-	// 	}
-	// }
+	_, err := revokeSession(ctx, c.authRepo, c.tokenDenylist, token.UserType(refreshSession.UserType),
+		refreshSession.UserID, refreshSession.SessionID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to revoke session of reused refresh token")
+	}
+	log.Printf("refresh token reused on session %s, all tokens of the session revoked", refreshSession.SessionID)
 
-	return nil
+	return ErrRefreshTokenReused
 }
 
-func (c *authUseCase) SingUpOtpVerify(ctx context.Context,
-	otpVerifyDetails requests.OTPVerify) (userID uint, err error) {
+func (c *authUseCase) FindAllSessions(ctx context.Context, userType token.UserType, userID uint,
+	currentSessionID string) ([]responses.Session, error) {
 
-	otpSession, err := c.authRepo.FindOtpSession(ctx, otpVerifyDetails.OtpID)
+	sessions, err := c.authRepo.FindActiveSessions(ctx, string(userType), userID)
 	if err != nil {
-		return 0, utils.PrependMessageToError(err, "failed to find otp session from database")
+		return nil, utils.PrependMessageToError(err, "failed to find active sessions")
 	}
 
-	if time.Since(otpSession.ExpireAt) > 0 {
-		return 0, ErrOtpExpired
+	for i := range sessions {
+		sessions[i].Current = sessions[i].SessionID == currentSessionID
 	}
 
-	valid, err := c.optAuth.VerifyOtp(countryCode+otpSession.Phone, otpVerifyDetails.Otp)
-	if err != nil {
-		return 0, utils.PrependMessageToError(err, "failed to verify otp")
+	return sessions, nil
+}
+
+// To logout the session of the access token and deny the access token itself
+// access tokens before sessions have no session id so its token id is denied
+func (c *authUseCase) Logout(ctx context.Context, userType token.UserType, userID uint, sessionID, tokenID string) error {
+
+	if sessionID != "" {
+		if _, err := revokeSession(ctx, c.authRepo, c.tokenDenylist, userType, userID, sessionID); err != nil {
+			return err
+		}
 	}
-	if !valid {
-		return 0, ErrInvalidOtp
+
+	err := c.tokenDenylist.Deny(ctx, tokenID, time.Now().Add(AccessTokenDuration))
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to deny access token")
 	}
 
-	err = c.userRepo.UpdateVerified(ctx, otpSession.UserID)
+	return nil
+}
+
+func (c *authUseCase) RevokeSession(ctx context.Context, userType token.UserType, userID uint, sessionID string) error {
+
+	revoked, err := revokeSession(ctx, c.authRepo, c.tokenDenylist, userType, userID, sessionID)
 	if err != nil {
-		return 0, utils.PrependMessageToError(err, "failed to update user verified on database")
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotExist
 	}
 
-	return otpSession.UserID, nil
+	return nil
 }
 
-// google login
-func (c *authUseCase) GoogleLogin(ctx context.Context, user models.User) (userID uint, err error) {
+func (c *authUseCase) RevokeAllSessions(ctx context.Context, userType token.UserType, userID uint) error {
+	return revokeAllSessions(ctx, c.authRepo, c.tokenDenylist, userType, userID)
+}
+
+func (c *authUseCase) UserSignUp(ctx context.Context, signUpDetails models.User) error {
 
-	existUser, err := c.userRepo.FindUserByEmail(ctx, user.Email)
+	existUser, err := c.userRepo.FindUserByUserNameEmailOrPhoneNotID(ctx, signUpDetails)
 	if err != nil {
-		return userID, fmt.Errorf("failed to get user details with given email \nerror:%v", err.Error())
+		return utils.PrependMessageToError(err, "failed to check user details already exist")
 	}
 
-	if existUser.ID != 0 {
-		return existUser.ID, nil
+	// if user crdentials already exiest and  verified then return it as errors
+	if existUser.ID != 0 && existUser.Verified {
+		err = utils.CompareUserExistingDetails(existUser, signUpDetails)
+		err = utils.AppendMessageToError(ErrUserAlreadyExit, err.Error())
+		return err
 	}
 
-	// create a random user name for user based on user name
-	user.UserName = utils.GenerateRandomUserName(user.FirstName)
+	hashPass, err := utils.GenerateHashFromPassword(signUpDetails.Password)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to hash the password")
+	}
+	signUpDetails.Password = string(hashPass)
+	// user is verified after verifying the email
+	signUpDetails.Verified = false
 
-	userID, err = c.userRepo.SaveUser(ctx, user)
+	if existUser.ID == 0 { // if user not exist then save user on database
+		signUpDetails.ID, err = c.userRepo.SaveUser(ctx, signUpDetails)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save user details")
+		}
+	} else { // not verified user is replaced with the new details, only the owner of email can verify it
+		signUpDetails.ID = existUser.ID
+		err = c.userRepo.Transaction(func(trxRepo interfaces.UserRepository) error {
+
+			err = trxRepo.UpdateUser(ctx, signUpDetails)
+			if err != nil {
+				return utils.PrependMessageToError(err, "failed to update not verified user details")
+			}
+			// identities linked by the previous sign up should not sign in to the replaced user
+			err = trxRepo.DeleteAllUserIdentities(ctx, signUpDetails.ID)
+			if err != nil {
+				return utils.PrependMessageToError(err, "failed to delete identities of not verified user")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.sendEmailVerification(ctx, signUpDetails)
+}
+
+func (c *authUseCase) SingUpOtpVerify(ctx context.Context,
+	otpVerifyDetails requests.OTPVerify) (userID uint, err error) {
+
+	otpSession, err := c.verifyOtp(ctx, otpVerifyDetails)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.userRepo.UpdateVerified(ctx, otpSession.UserID)
 	if err != nil {
-		return userID, fmt.Errorf("failed to save user details \nerror:%v", err.Error())
+		return 0, utils.PrependMessageToError(err, "failed to update user verified on database")
 	}
 
-	return userID, nil
+	return otpSession.UserID, nil
 }
@@ -1,8 +1,8 @@
 package usecases
 
 import (
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	repoInterface "online-shop-2N/pkg/repositories/interfaces"
 	"online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
@@ -47,15 +47,15 @@ func (b *brandUseCase) Update(brand models.Brand) error {
 	return nil
 }
 
-func (b *brandUseCase) FindAll(pagination requests.Pagination) ([]models.Brand, error) {
+func (b *brandUseCase) FindAll(page pagination.Request) ([]models.Brand, pagination.Meta, error) {
 
-	brands, err := b.brandRepo.FindAll(pagination)
+	brands, meta, err := b.brandRepo.FindAll(page)
 
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to find all brands from db")
+		return nil, meta, utils.PrependMessageToError(err, "failed to find all brands from db")
 	}
 
-	return brands, nil
+	return brands, meta, nil
 }
 
 func (b *brandUseCase) FindOne(brandID uint) (models.Brand, error) {
@@ -0,0 +1,345 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"online-shop-2N/pkg/catalog"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"online-shop-2N/pkg/repositories/interfaces"
+	service "online-shop-2N/pkg/usecases/interfaces"
+	"online-shop-2N/pkg/utils"
+	"time"
+)
+
+type catalogUseCase struct {
+	catalogRepo interfaces.CatalogRepository
+}
+
+func NewCatalogUseCase(catalogRepo interfaces.CatalogRepository) service.CatalogUseCase {
+	return &catalogUseCase{
+		catalogRepo: catalogRepo,
+	}
+}
+
+// To save the file as a pending import job which is run later by the import worker,
+// header of csv is checked before saving so a wrong file is rejected at once
+func (c *catalogUseCase) SaveImportJob(ctx context.Context, adminID uint, fileName string, format catalog.Format,
+	data []byte) (models.CatalogImportJob, error) {
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return models.CatalogImportJob{}, ErrEmptyCatalogImportFile
+	}
+
+	if _, err := catalog.NewReader(format, bytes.NewReader(data)); err != nil {
+		return models.CatalogImportJob{}, err
+	}
+
+	job, err := c.catalogRepo.SaveImportJob(ctx, models.CatalogImportJob{
+		AdminID:  adminID,
+		FileName: fileName,
+		Format:   string(format),
+		Status:   commonConstant.CatalogImportPending,
+		Data:     data,
+	})
+	if err != nil {
+		return job, utils.PrependMessageToError(err, "failed to save catalog import job")
+	}
+
+	log.Printf("successfully saved catalog import job %v of file %s", job.ID, fileName)
+	return job, nil
+}
+
+func (c *catalogUseCase) FindImportJob(ctx context.Context, jobID uint) (models.CatalogImportJob, error) {
+
+	job, err := c.catalogRepo.FindImportJobByID(ctx, jobID)
+	if err != nil {
+		return job, utils.PrependMessageToError(err, "failed to find catalog import job from database")
+	}
+	if job.ID == 0 {
+		return job, ErrCatalogImportJobNotExist
+	}
+
+	return job, nil
+}
+
+func (c *catalogUseCase) FindAllImportErrors(ctx context.Context, jobID uint,
+	page pagination.Request) ([]models.CatalogImportError, pagination.Meta, error) {
+
+	if _, err := c.FindImportJob(ctx, jobID); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+
+	importErrors, meta, err := c.catalogRepo.FindAllImportErrors(ctx, jobID, page)
+	if err != nil {
+		return nil, meta, utils.PrependMessageToError(err, "failed to find catalog import errors from database")
+	}
+
+	return importErrors, meta, nil
+}
+
+// To run the oldest pending import job, job id is zero when there is no pending job.
+// a job which is running from before the running before time is run again from start, its rows are upserted by sku.
+// a job which is stopped by an error is saved as failed with the error, the rows imported before it are kept.
+// a job which is stopped by cancel of context on shutdown is changed back to pending to run it again on next start
+func (c *catalogUseCase) RunNextImportJob(ctx context.Context, runningBefore time.Time) (uint, error) {
+
+	job, err := c.catalogRepo.ClaimPendingImportJob(ctx, time.Now(), runningBefore)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to claim pending catalog import job")
+	}
+	if job.ID == 0 {
+		return 0, nil
+	}
+
+	// errors of rows are saved again on this run
+	if err := c.catalogRepo.DeleteAllImportErrors(ctx, job.ID); err != nil {
+		return job.ID, utils.PrependMessageToError(err, "failed to remove previous errors of catalog import job")
+	}
+
+	job.Status = commonConstant.CatalogImportCompleted
+	if err := c.runImportJob(ctx, &job); err != nil {
+		// the job is not failed when it is stopped by shutdown, only the timeout of job fails it
+		if errors.Is(ctx.Err(), context.Canceled) {
+			if err := c.catalogRepo.ReleaseImportJob(ctx, job.ID); err != nil {
+				return job.ID, utils.PrependMessageToError(err, "failed to change stopped catalog import job to pending")
+			}
+			log.Printf("catalog import job %v is stopped before finishing and changed to pending", job.ID)
+			return job.ID, nil
+		}
+		log.Printf("catalog import job %v failed \nerror:%v", job.ID, err)
+		job.Status = commonConstant.CatalogImportFailed
+		job.Error = err.Error()
+	}
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err := c.catalogRepo.FinishImportJob(ctx, job); err != nil {
+		return job.ID, utils.PrependMessageToError(err, "failed to save result of catalog import job")
+	}
+
+	log.Printf("successfully finished catalog import job %v with %v created, %v updated and %v failed rows",
+		job.ID, job.CreatedRows, job.UpdatedRows, job.FailedRows)
+	return job.ID, nil
+}
+
+// read each row of the file and import it, an invalid row is saved as an error of job and the next rows are continued
+func (c *catalogUseCase) runImportJob(ctx context.Context, job *models.CatalogImportJob) error {
+
+	reader, err := catalog.NewReader(catalog.Format(job.Format), bytes.NewReader(job.Data))
+	if err != nil {
+		return err
+	}
+
+	lookup, err := c.findCatalogLookup(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row, line, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		job.TotalRows++
+
+		if err == nil {
+			var created bool
+			created, err = c.importRow(ctx, lookup, row)
+			if err == nil {
+				if created {
+					job.CreatedRows++
+				} else {
+					job.UpdatedRows++
+				}
+				continue
+			}
+		}
+
+		if !errors.Is(err, catalog.ErrInvalidRow) {
+			return utils.PrependMessageToError(err, fmt.Sprintf("failed to import row %d", line))
+		}
+
+		job.FailedRows++
+		err = c.catalogRepo.SaveImportError(ctx, models.CatalogImportError{
+			JobID:     job.ID,
+			RowNumber: uint(line),
+			SKU:       row.SKU,
+			Message:   err.Error(),
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save error of row")
+		}
+	}
+}
+
+// categories, brands and variation options are found once for a job
+func (c *catalogUseCase) findCatalogLookup(ctx context.Context) (*catalog.Lookup, error) {
+
+	categories, err := c.catalogRepo.FindAllSubCategories(ctx)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find categories from database")
+	}
+
+	brands, err := c.catalogRepo.FindAllBrands(ctx)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find brands from database")
+	}
+
+	variationOptions, err := c.catalogRepo.FindAllVariationOptions(ctx)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find variation options from database")
+	}
+
+	return catalog.NewLookup(categories, brands, variationOptions), nil
+}
+
+// To validate the row and save it in a transaction, product item is created when the sku not exist otherwise updated
+func (c *catalogUseCase) importRow(ctx context.Context, lookup *catalog.Lookup, row catalog.Row) (created bool, err error) {
+
+	resolved, err := lookup.Resolve(row)
+	if err != nil {
+		return false, err
+	}
+
+	err = c.catalogRepo.Transactions(ctx, func(trxRepo interfaces.CatalogRepository) error {
+		created, err = c.upsertProductItem(ctx, trxRepo, resolved)
+		return err
+	})
+
+	return created, err
+}
+
+// step 1 : find the product by name, create it when not exist or check its category and brand are same as row
+// step 2 : find the product item by sku, it should be of the same product and same variation options
+// step 3 : update the price and stock of existing product item
+// step 4 : or check the variation options not used by another item of product and create the product item
+func (c *catalogUseCase) upsertProductItem(ctx context.Context, trxRepo interfaces.CatalogRepository,
+	row catalog.ResolvedRow) (created bool, err error) {
+
+	productItem, err := trxRepo.FindProductItemBySKU(ctx, row.SKU)
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to find product item by sku")
+	}
+
+	product, err := trxRepo.FindProductByName(ctx, row.ProductName)
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to find product by name")
+	}
+
+	if product.ID == 0 {
+		if productItem.ID != 0 {
+			return false, fmt.Errorf("%w: sku already exist for another product", catalog.ErrInvalidRow)
+		}
+		product.ID, err = trxRepo.SaveProduct(ctx, models.Product{
+			Name:        row.ProductName,
+			Description: row.Description,
+			CategoryID:  row.CategoryID,
+			BrandID:     row.BrandID,
+			Price:       row.Price,
+		})
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to save product")
+		}
+	} else {
+		if product.CategoryID != row.CategoryID || product.BrandID != row.BrandID {
+			return false, fmt.Errorf("%w: product '%s' already exist with another category or brand",
+				catalog.ErrInvalidRow, row.ProductName)
+		}
+		if product.Description != row.Description {
+			err = trxRepo.UpdateProductDescription(ctx, product.ID, row.Description)
+			if err != nil {
+				return false, utils.PrependMessageToError(err, "failed to update product description")
+			}
+		}
+	}
+
+	if productItem.ID != 0 {
+		if productItem.ProductID != product.ID {
+			return false, fmt.Errorf("%w: sku already exist for another product", catalog.ErrInvalidRow)
+		}
+
+		variationOptionIDs, err := trxRepo.FindVariationOptionIDsOfProductItem(ctx, productItem.ID)
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to find variation options of product item")
+		}
+		if !isSameUintSlice(variationOptionIDs, row.VariationOptionIDs) {
+			return false, fmt.Errorf("%w: variation options of an existing sku can't be changed", catalog.ErrInvalidRow)
+		}
+
+		err = trxRepo.UpdateProductItem(ctx, productItem.ID, row.Price, row.QtyInStock)
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to update product item")
+		}
+		return false, nil
+	}
+
+	// same as on adding product item, categories without variations can have any number of items
+	if len(row.VariationOptionIDs) > 0 {
+		exist, err := trxRepo.IsProductItemConfigurationExist(ctx, product.ID, row.VariationOptionIDs)
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to check product item configuration exist")
+		}
+		if exist {
+			return false, fmt.Errorf("%w: %v", catalog.ErrInvalidRow, ErrProductItemAlreadyExist)
+		}
+	}
+
+	productItemID, err := trxRepo.SaveProductItem(ctx, models.ProductItem{
+		ProductID:  product.ID,
+		QtyInStock: row.QtyInStock,
+		Price:      row.Price,
+		SKU:        row.SKU,
+	})
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to save product item")
+	}
+
+	for _, variationOptionID := range row.VariationOptionIDs {
+		err = trxRepo.SaveProductConfiguration(ctx, productItemID, variationOptionID)
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to save product item configuration")
+		}
+	}
+
+	return true, nil
+}
+
+// To write all product items of catalog on the writer in the format, rows are written while reading from database
+func (c *catalogUseCase) ExportCatalog(ctx context.Context, format catalog.Format, w io.Writer) error {
+
+	writer, err := catalog.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	err = c.catalogRepo.ExportCatalog(ctx, writer.Write)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to export catalog")
+	}
+
+	return writer.Flush()
+}
+
+// both slices should be sorted
+func isSameUintSlice(a, b []uint) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
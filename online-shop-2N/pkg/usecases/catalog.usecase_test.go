@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+	"online-shop-2N/pkg/catalog"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"testing"
+	"time"
+)
+
+// catalog repository of a claimed import job, methods not used on the run of job are not implemented
+type testCatalogRepository struct {
+	interfaces.CatalogRepository
+	job         models.CatalogImportJob
+	finishedJob *models.CatalogImportJob
+	released    bool
+}
+
+func (c *testCatalogRepository) ClaimPendingImportJob(ctx context.Context,
+	startedAt, runningBefore time.Time) (models.CatalogImportJob, error) {
+	return c.job, nil
+}
+
+func (c *testCatalogRepository) DeleteAllImportErrors(ctx context.Context, jobID uint) error {
+	return nil
+}
+
+func (c *testCatalogRepository) FindAllSubCategories(ctx context.Context) ([]catalog.Category, error) {
+	return nil, nil
+}
+
+func (c *testCatalogRepository) FindAllBrands(ctx context.Context) ([]catalog.Brand, error) {
+	return nil, nil
+}
+
+func (c *testCatalogRepository) FindAllVariationOptions(ctx context.Context) ([]catalog.VariationOption, error) {
+	return nil, nil
+}
+
+func (c *testCatalogRepository) FinishImportJob(ctx context.Context, job models.CatalogImportJob) error {
+	c.finishedJob = &job
+	return nil
+}
+
+func (c *testCatalogRepository) ReleaseImportJob(ctx context.Context, jobID uint) error {
+	c.released = true
+	return nil
+}
+
+func TestRunNextImportJob(t *testing.T) {
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	timedOutCtx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantReleased bool
+		wantStatus   commonConstant.CatalogImportStatusType
+	}{
+		{name: "completed", ctx: context.Background(), wantStatus: commonConstant.CatalogImportCompleted},
+		{name: "stopped by timeout of job", ctx: timedOutCtx, wantStatus: commonConstant.CatalogImportFailed},
+		{name: "stopped by shutdown", ctx: canceledCtx, wantReleased: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			catalogRepo := &testCatalogRepository{
+				job: models.CatalogImportJob{ID: 1, Format: string(catalog.FormatJSONL)},
+			}
+			catalogUseCase := NewCatalogUseCase(catalogRepo)
+
+			jobID, err := catalogUseCase.RunNextImportJob(test.ctx, time.Now())
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if jobID != 1 {
+				t.Fatalf("got job id %v, want 1", jobID)
+			}
+			if catalogRepo.released != test.wantReleased {
+				t.Fatalf("got job released %v, want %v", catalogRepo.released, test.wantReleased)
+			}
+
+			if test.wantReleased {
+				if catalogRepo.finishedJob != nil {
+					t.Fatalf("got job finished with status %s, want job not finished", catalogRepo.finishedJob.Status)
+				}
+				return
+			}
+			if catalogRepo.finishedJob == nil || catalogRepo.finishedJob.Status != test.wantStatus {
+				t.Fatalf("got finished job %+v, want status %s", catalogRepo.finishedJob, test.wantStatus)
+			}
+		})
+	}
+}
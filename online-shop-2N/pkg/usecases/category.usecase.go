@@ -4,6 +4,7 @@ import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 	service "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
@@ -20,23 +21,24 @@ func NewCategoryUseCase(categoryRepo interfaces.CategoryRepository) service.Cate
 	}
 }
 
-func (c *categoryUseCase) FindAllCategories(ctx context.Context, pagination requests.Pagination) ([]responses.Category, error) {
+func (c *categoryUseCase) FindAllCategories(ctx context.Context,
+	page pagination.Request) ([]responses.Category, pagination.Meta, error) {
 
-	categories, err := c.categoryRepo.FindAllMainCategories(ctx, pagination)
+	categories, meta, err := c.categoryRepo.FindAllMainCategories(ctx, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed find all main categories")
+		return nil, meta, utils.PrependMessageToError(err, "failed find all main categories")
 	}
 
 	for i, category := range categories {
 
 		subCategory, err := c.categoryRepo.FindAllSubCategories(ctx, category.ID)
 		if err != nil {
-			return nil, utils.PrependMessageToError(err, "failed to find sub categories")
+			return nil, meta, utils.PrependMessageToError(err, "failed to find sub categories")
 		}
 		categories[i].SubCategory = subCategory
 	}
 
-	return categories, nil
+	return categories, meta, nil
 }
 
 // Save category
@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 	service "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
@@ -56,29 +56,31 @@ func (c *couponUseCase) AddCoupon(ctx context.Context, coupon models.Coupon) err
 
 	return nil
 }
-func (c *couponUseCase) GetAllCoupons(ctx context.Context, pagination requests.Pagination) (coupons []models.Coupon, err error) {
+func (c *couponUseCase) GetAllCoupons(ctx context.Context,
+	page pagination.Request) (coupons []models.Coupon, meta pagination.Meta, err error) {
 
-	coupons, err = c.couponRepo.FindAllCoupons(ctx, pagination)
+	coupons, meta, err = c.couponRepo.FindAllCoupons(ctx, page)
 	if err != nil {
-		return coupons, err
+		return coupons, meta, err
 	}
 
 	log.Printf("successfully got all coupons \n\n")
-	return coupons, nil
+	return coupons, meta, nil
 }
 
 // get all coupon for user
-func (c *couponUseCase) GetCouponsForUser(ctx context.Context, userID uint, pagination requests.Pagination) (coupons []responses.UserCoupon, err error) {
+func (c *couponUseCase) GetCouponsForUser(ctx context.Context, userID uint,
+	page pagination.Request) (coupons []responses.UserCoupon, meta pagination.Meta, err error) {
 
-	coupons, err = c.couponRepo.FindAllCouponForUser(ctx, userID, pagination)
+	coupons, meta, err = c.couponRepo.FindAllCouponForUser(ctx, userID, page)
 
 	if err != nil {
-		return coupons, err
+		return coupons, meta, err
 	}
 
 	log.Printf("successfully go coupons for user of user_id %v", userID)
 
-	return coupons, nil
+	return coupons, meta, nil
 }
 
 func (c *couponUseCase) GetCouponByCouponCode(ctx context.Context, couponCode string) (coupon models.Coupon, err error) {
@@ -9,19 +9,46 @@ var (
 	ErrUserNotVerified       = errors.New("user not verified")
 	ErrUserBlocked           = errors.New("user blocked by admin")
 	ErrWrongPassword         = errors.New("password doesn't match")
+	ErrAccountLocked         = errors.New("account locked temporarily by too many failed password attempts")
 	// otp
-	ErrOtpExpired = errors.New("otp session expired")
-	ErrInvalidOtp = errors.New("invalid otp")
+	ErrOtpExpired          = errors.New("otp session expired")
+	ErrInvalidOtp          = errors.New("invalid otp")
+	ErrOtpAlreadyUsed      = errors.New("otp already used")
+	ErrOtpAttemptsExceeded = errors.New("too many wrong attempts on otp session, request a new otp")
+	ErrOtpResendCooldown   = errors.New("otp already sent, wait before request a new otp")
+	ErrInvalidPhoneNumber  = errors.New("invalid phone number")
+
+	// oauth
+	ErrOAuthProviderNotExist = errors.New("oauth provider not exist or not enabled")
+	ErrOAuthFailed           = errors.New("failed to get user from oauth provider")
+	ErrInvalidOAuthState     = errors.New("invalid or expired state of oauth, sign in again")
+	ErrOAuthEmailNotShared   = errors.New("email is not shared by oauth provider")
+	ErrOAuthEmailNotVerified = errors.New("email is not verified by oauth provider")
+	ErrOAuthAccountNotLinked = errors.New("an account already exist with the email, sign in to it and link the provider")
+	ErrIdentityAlreadyLinked = errors.New("account of provider already linked to a user")
+	ErrProviderAlreadyLinked = errors.New("an account of this provider already linked to the user")
+	ErrIdentityNotLinked     = errors.New("provider not linked to the user")
+	ErrLastSignInMethod      = errors.New("the only sign in method of user can't be unlinked, reset the password first")
 
 	// refresh token
 	ErrInvalidRefreshToken    = errors.New("invalid refresh token")
 	ErrRefreshSessionNotExist = errors.New("there is no refresh token session for this token")
 	ErrRefreshSessionExpired  = errors.New("refresh token expired in session")
 	ErrRefreshSessionBlocked  = errors.New("refresh token blocked in session")
+	ErrRefreshTokenReused     = errors.New("refresh token already used so all tokens of the session are revoked")
+	ErrSessionNotExist        = errors.New("there is no active session with this id")
+
+	// access token
+	ErrInvalidAccessToken = errors.New("invalid access token")
+	ErrAccessTokenRevoked = errors.New("access token revoked")
 
 	// signup
 	ErrUserAlreadyExit = errors.New("user already exist")
 
+	// password reset and email verification
+	ErrInvalidUserToken     = errors.New("invalid, expired or already used token")
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+
 	// cart
 	ErrProductItemOutOfStock = errors.New("product is now out of stock")
 	ErrCartItemAlreadyExist  = errors.New("product_item already exist on the cart")
@@ -32,7 +59,27 @@ var (
 	ErrInvalidCartItemUpdateQty  = errors.New("update cart item qty reached max limit")
 
 	// admin
-	ErrSameBlockStatus = errors.New("user block status already in given status")
+	ErrSameBlockStatus   = errors.New("user block status already in given status")
+	ErrAdminNotExist     = errors.New("admin not exist")
+	ErrAdminAlreadyExist = errors.New("admin already exist with this email or user_name")
+	ErrPermissionDenied  = errors.New("admin have no permission for this action")
+
+	// role
+	ErrRoleNotExist         = errors.New("role not exist")
+	ErrRoleAlreadyExist     = errors.New("role already exist with this name")
+	ErrInvalidPermission    = errors.New("invalid permission")
+	ErrSuperAdminRoleChange = errors.New("super admin role can't be changed or removed")
+	ErrRoleAssigned         = errors.New("role is assigned to admins")
+	ErrLastSuperAdmin       = errors.New("can't remove super admin role from the last super admin")
+
+	// two factor authentication
+	ErrTwoFactorRequired       = errors.New("two factor authentication is required by role of admin, enable it to continue")
+	ErrTwoFactorNotSetup       = errors.New("two factor authentication is not set up")
+	ErrTwoFactorNotEnabled     = errors.New("two factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two factor authentication already enabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid or already used two factor code")
+	ErrInvalidChallengeToken   = errors.New("invalid, expired or already used challenge token")
+	ErrChallengeAttemptsExceed = errors.New("too many wrong attempts on sign in challenge, sign in again")
 
 	//category
 	ErrCategoryAlreadyExist = errors.New("category already exist")
@@ -43,6 +90,7 @@ var (
 
 	// product
 	ErrProductAlreadyExist = errors.New("product already exist with this name")
+	ErrProductNotExist     = errors.New("product not exist")
 
 	// product item
 	ErrProductItemAlreadyExist = errors.New("product item already exist with this configuration")
@@ -57,7 +105,14 @@ var (
 	ErrProductOfferAlreadyExist  = errors.New("an offer already exist for this product")
 
 	// order
-	ErrOutOfStockOnCart = errors.New("cart is not valid for order out of stock is in cart")
+	ErrOutOfStockOnCart            = errors.New("cart is not valid for order out of stock is in cart")
+	ErrShopOrderNotExist           = errors.New("shop order not exist")
+	ErrOrderStatusChangeNotAllowed = errors.New("order status change not allowed")
+	ErrInvalidRefundSplit          = errors.New("wallet refund amount of split refund should be less than refund amount")
+	ErrInvalidOrderLine            = errors.New("order item not exist on this order")
+	ErrInvalidOrderLineQty         = errors.New("quantity is more than the quantity of order item left")
+	ErrNoOrderItemsLeft            = errors.New("all the items of order are already returned or cancelled")
+	ErrOrderReturnClosed           = errors.New("order return is already closed")
 
 	// wish list
 	ErrExistWishListProductItem = errors.New("product item already exist on wish list")
@@ -66,7 +121,20 @@ var (
 	ErrBlockedPayment          = errors.New("selected payment is blocked by admin")
 	ErrPaymentAmountReachedMax = errors.New("order total price reached payment method maximum amount")
 	ErrPaymentNotApproved      = errors.New("payment not approved")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrInvalidWebhookEvent     = errors.New("invalid webhook event")
+	ErrOrderNotWaitingPayment  = errors.New("order is not waiting for payment")
+	ErrInvalidOrderPayment     = errors.New("payment not found for this order")
+	ErrPaymentAmountMismatch   = errors.New("amount paid is not same as the amount due of order")
+
+	// wallet
+	ErrEmptyWallet               = errors.New("wallet have no balance to pay")
+	ErrInsufficientWalletBalance = errors.New("wallet balance is not enough")
 
 	// brand
 	ErrBrandAlreadyExist = errors.New("brand name already exist")
+
+	// catalog import
+	ErrCatalogImportJobNotExist = errors.New("catalog import job not exist")
+	ErrEmptyCatalogImportFile   = errors.New("catalog import file is empty")
 )
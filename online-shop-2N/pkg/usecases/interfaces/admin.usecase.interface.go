@@ -4,14 +4,24 @@ import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
-	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type AdminUseCase interface {
-	SignUp(ctx context.Context, admin models.Admin) error
+	SignUp(ctx context.Context, signUpDetails requests.AdminSignUp) error
+	FindAllAdmins(ctx context.Context, page pagination.Request) ([]responses.Admin, pagination.Meta, error)
+	UpdateAdminRoles(ctx context.Context, adminID uint, roleIDs []uint) error
+	ResetAdminTwoFactor(ctx context.Context, adminID uint) error
 
-	FindAllUser(ctx context.Context, pagination requests.Pagination) (users []responses.User, err error)
+	// role
+	FindAllRoles(ctx context.Context) ([]responses.Role, error)
+	SaveRole(ctx context.Context, role requests.Role) (roleID uint, err error)
+	UpdateRole(ctx context.Context, roleID uint, role requests.Role) error
+	DeleteRole(ctx context.Context, roleID uint) error
+	UpdateRoleTwoFactor(ctx context.Context, roleID uint, required bool) error
+
+	FindAllUser(ctx context.Context, page pagination.Request) (users []responses.User, meta pagination.Meta, err error)
 	BlockOrUnBlockUser(ctx context.Context, blockDetails requests.BlockUser) error
 
-	GetFullSalesReport(ctx context.Context, requestData requests.SalesReport) (salesReport []responses.SalesReport, err error)
+	GetFullSalesReport(ctx context.Context, requestData requests.SalesReport) (salesReport []responses.SalesReport, meta pagination.Meta, err error)
 }
@@ -3,7 +3,10 @@ package interfaces
 import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	commonConstant "online-shop-2N/pkg/common/constants"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/services/oauth"
 	"online-shop-2N/pkg/services/tokens"
 )
 
@@ -11,20 +14,60 @@ type AuthUseCase interface {
 	//user
 	UserSignUp(ctx context.Context, signUpDetails models.User) (err error)
 	SingUpOtpVerify(ctx context.Context, otpVerifyDetails requests.OTPVerify) (userID uint, err error)
-	GoogleLogin(ctx context.Context, user models.User) (userID uint, err error)
 	UserLogin(ctx context.Context, loginDetails requests.Login) (userID uint, err error)
 	UserLoginOtpSend(ctx context.Context, loginDetails requests.OTPLogin) (otpID string, err error)
 	LoginOtpVerify(ctx context.Context, otpVerifyDetails requests.OTPVerify) (userID uint, err error)
 
+	// oauth
+	FindOAuthProviders(ctx context.Context) responses.OAuthProviders
+	GetOAuthURL(ctx context.Context, provider string, linkUserID uint) (authURL, signedFlow string, err error)
+	VerifyOAuthFlow(ctx context.Context, provider, signedFlow, state string) (oauth.Flow, error)
+	OAuthLogin(ctx context.Context, flow oauth.Flow, code string) (userID uint, err error)
+	NewOAuthLoginCode(ctx context.Context, userID uint) (loginCode string, err error)
+	UseOAuthLoginCode(ctx context.Context, loginCode string) (userID uint, err error)
+	FindAllUserIdentities(ctx context.Context, userID uint) ([]responses.UserIdentity, error)
+	LinkUserIdentity(ctx context.Context, userID uint, flow oauth.Flow, code string) error
+	UnlinkUserIdentity(ctx context.Context, userID uint, provider string) error
+
+	// password reset and email verification
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, resetDetails requests.ResetPassword) error
+	ResendEmailVerification(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, verificationToken string) error
+
 	// admin
-	AdminLogin(ctx context.Context, loginDetails requests.Login) (adminID uint, err error)
+	// admin id is zero and challenge is returned when two factor is enabled for admin
+	AdminLogin(ctx context.Context, loginDetails requests.Login) (adminID uint, challenge responses.TwoFactorChallenge, err error)
+	AdminLoginTwoFactor(ctx context.Context, loginDetails requests.AdminTwoFactorLogin) (adminID uint, err error)
+
+	// two factor authentication of admin
+	FindAdminTwoFactorStatus(ctx context.Context, adminID uint) (responses.TwoFactorStatus, error)
+	SetupAdminTwoFactor(ctx context.Context, adminID uint) (responses.TwoFactorSetup, error)
+	EnableAdminTwoFactor(ctx context.Context, adminID uint, code string) (responses.RecoveryCodes, error)
+	DisableAdminTwoFactor(ctx context.Context, adminID uint, code string) error
+	RegenerateAdminRecoveryCodes(ctx context.Context, adminID uint, code string) (responses.RecoveryCodes, error)
+
 	// token
 	GenerateAccessToken(ctx context.Context, tokenParams GenerateTokenParams) (tokenString string, err error)
-	GenerateRefreshToken(ctx context.Context, tokenParams GenerateTokenParams) (tokenString string, err error)
+	GenerateRefreshToken(ctx context.Context, tokenParams GenerateTokenParams) (models.RefreshSession, error)
 	VerifyAndGetRefreshTokenSession(ctx context.Context, refreshToken string, usedFor tokens.UserType) (models.RefreshSession, error)
+	RotateRefreshToken(ctx context.Context, refreshToken string, tokenParams GenerateTokenParams) (models.RefreshSession, error)
+	VerifyAccessToken(ctx context.Context, accessToken string, usedFor tokens.UserType) (tokens.VerifyTokenResponse, error)
+	VerifyAdminPermissions(ctx context.Context, adminID uint, permissions ...commonConstant.AdminPermission) error
+	FindJWKS(ctx context.Context) tokens.JWKSet
+
+	// session
+	FindAllSessions(ctx context.Context, userType tokens.UserType, userID uint, currentSessionID string) ([]responses.Session, error)
+	Logout(ctx context.Context, userType tokens.UserType, userID uint, sessionID, tokenID string) error
+	RevokeSession(ctx context.Context, userType tokens.UserType, userID uint, sessionID string) error
+	RevokeAllSessions(ctx context.Context, userType tokens.UserType, userID uint) error
 }
 
+// session id is empty for a new sign in, user agent and ip address are of the device which requested the tokens
 type GenerateTokenParams struct {
-	UserID   uint
-	UserType tokens.UserType
+	UserID    uint
+	UserType  tokens.UserType
+	SessionID string
+	UserAgent string
+	IPAddress string
 }
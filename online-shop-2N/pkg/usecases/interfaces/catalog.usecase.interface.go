@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+	"online-shop-2N/pkg/catalog"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"time"
+)
+
+type CatalogUseCase interface {
+	SaveImportJob(ctx context.Context, adminID uint, fileName string, format catalog.Format,
+		data []byte) (models.CatalogImportJob, error)
+	FindImportJob(ctx context.Context, jobID uint) (models.CatalogImportJob, error)
+	FindAllImportErrors(ctx context.Context, jobID uint,
+		page pagination.Request) ([]models.CatalogImportError, pagination.Meta, error)
+	RunNextImportJob(ctx context.Context, runningBefore time.Time) (jobID uint, err error)
+
+	ExportCatalog(ctx context.Context, format catalog.Format, w io.Writer) error
+}
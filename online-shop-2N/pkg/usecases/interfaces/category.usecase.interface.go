@@ -4,10 +4,11 @@ import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 )
 
 type CategoryUseCase interface {
-	FindAllCategories(ctx context.Context, pagination requests.Pagination) ([]responses.Category, error)
+	FindAllCategories(ctx context.Context, page pagination.Request) ([]responses.Category, pagination.Meta, error)
 	SaveCategory(ctx context.Context, categoryName string) error
 	SaveSubCategory(ctx context.Context, subCategory requests.SubCategory) error
 }
@@ -2,19 +2,19 @@ package interfaces
 
 import (
 	"context"
-	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type CouponUseCase interface {
 	// coupon
 	AddCoupon(ctx context.Context, coupon models.Coupon) error
-	GetAllCoupons(ctx context.Context, pagination requests.Pagination) (coupons []models.Coupon, err error)
+	GetAllCoupons(ctx context.Context, page pagination.Request) (coupons []models.Coupon, meta pagination.Meta, err error)
 	UpdateCoupon(ctx context.Context, coupon models.Coupon) error
 
 	//user side coupons
-	GetCouponsForUser(ctx context.Context, userID uint, pagination requests.Pagination) (coupons []responses.UserCoupon, err error)
+	GetCouponsForUser(ctx context.Context, userID uint, page pagination.Request) (coupons []responses.UserCoupon, meta pagination.Meta, err error)
 
 	GetCouponByCouponCode(ctx context.Context, couponCode string) (coupon models.Coupon, err error)
 	ApplyCouponToCart(ctx context.Context, userID uint, couponCode string) (discountPrice uint, err error)
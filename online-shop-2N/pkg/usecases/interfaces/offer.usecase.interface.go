@@ -5,6 +5,7 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type OfferUseCase interface {
@@ -12,17 +13,17 @@ type OfferUseCase interface {
 	// offer
 	SaveOffer(ctx context.Context, offer requests.Offer) error
 	RemoveOffer(ctx context.Context, offerID uint) error
-	FindAllOffers(ctx context.Context, pagination requests.Pagination) ([]models.Offer, error)
+	FindAllOffers(ctx context.Context, page pagination.Request) ([]models.Offer, pagination.Meta, error)
 
 	// offer category
 	SaveCategoryOffer(ctx context.Context, offerCategory requests.OfferCategory) error
-	FindAllCategoryOffers(ctx context.Context, pagination requests.Pagination) ([]responses.OfferCategory, error)
+	FindAllCategoryOffers(ctx context.Context, page pagination.Request) ([]responses.OfferCategory, pagination.Meta, error)
 	RemoveCategoryOffer(ctx context.Context, categoryOfferID uint) error
 	ChangeCategoryOffer(ctx context.Context, categoryOfferID, offerID uint) error
 
 	// offer product
 	SaveProductOffer(ctx context.Context, offerProduct models.OfferProduct) error
-	FindAllProductOffers(ctx context.Context, pagination requests.Pagination) ([]responses.OfferProduct, error)
+	FindAllProductOffers(ctx context.Context, page pagination.Request) ([]responses.OfferProduct, pagination.Meta, error)
 	RemoveProductOffer(ctx context.Context, productOfferID uint) error
 	ChangeProductOffer(ctx context.Context, productOfferID, offerID uint) error
 }
@@ -5,6 +5,8 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"time"
 )
 
 type OrderUseCase interface {
@@ -13,22 +15,35 @@ type OrderUseCase interface {
 	SaveOrder(ctx context.Context, userID, addressID uint) (shopOrderID uint, err error)
 
 	// Find order and order items
-	FindAllShopOrders(ctx context.Context, pagination requests.Pagination) (shopOrders []responses.ShopOrder, err error)
-	FindUserShopOrder(ctx context.Context, userID uint, pagination requests.Pagination) ([]responses.ShopOrder, error)
-	FindOrderItems(ctx context.Context, shopOrderID uint, pagination requests.Pagination) ([]responses.OrderItem, error)
+	FindAllShopOrders(ctx context.Context, page pagination.Request) (shopOrders []responses.ShopOrder, meta pagination.Meta, err error)
+	FindUserShopOrder(ctx context.Context, userID uint, page pagination.Request) ([]responses.ShopOrder, pagination.Meta, error)
+	FindOrderItems(ctx context.Context, shopOrderID uint, page pagination.Request) ([]responses.OrderItem, pagination.Meta, error)
 
 	// cancel order and change order status
 	FindAllOrderStatuses(ctx context.Context) (orderStatuses []models.OrderStatus, err error)
-	UpdateOrderStatus(ctx context.Context, shopOrderID, changeStatusID uint) error
-	CancelOrder(ctx context.Context, shopOrderID uint) error
+	UpdateOrderStatus(ctx context.Context, adminID uint, updateDetails requests.UpdateOrder) error
+	CancelOrder(ctx context.Context, userID, shopOrderID uint, cancelDetails requests.CancelOrder) error
+	ExpirePendingPaymentOrders(ctx context.Context, expireBefore time.Time) (expiredOrderIDs []uint, err error)
+	ReconcilePendingRefunds(ctx context.Context, pendingBefore time.Time) (reconciledRefundIDs []uint, err error)
+
+	// order status history
+	FindOrderStatusHistory(ctx context.Context, shopOrderID uint) ([]responses.OrderStatusHistory, error)
+	FindUserOrderStatusHistory(ctx context.Context, userID, shopOrderID uint) ([]responses.OrderStatusHistory, error)
+
+	// order payments
+	FindOrderPayments(ctx context.Context, shopOrderID uint) ([]responses.ShopOrderPayment, error)
+	FindUserOrderPayments(ctx context.Context, userID, shopOrderID uint) ([]responses.ShopOrderPayment, error)
+	FindOrderRefunds(ctx context.Context, shopOrderID uint) ([]models.Refund, error)
+	FindUserOrderRefunds(ctx context.Context, userID, shopOrderID uint) ([]models.Refund, error)
 
 	// return and update
-	SubmitReturnRequest(ctx context.Context, returnDetails requests.Return) error
-	FindAllPendingOrderReturns(ctx context.Context, pagination requests.Pagination) ([]responses.OrderReturn, error)
-	FindAllOrderReturns(ctx context.Context, pagination requests.Pagination) ([]responses.OrderReturn, error)
-	UpdateReturnDetails(ctx context.Context, updateDetails requests.UpdateOrderReturn) error
+	SubmitReturnRequest(ctx context.Context, userID uint, returnDetails requests.Return) error
+	FindAllPendingOrderReturns(ctx context.Context, page pagination.Request) ([]responses.OrderReturn, pagination.Meta, error)
+	FindAllOrderReturns(ctx context.Context, page pagination.Request) ([]responses.OrderReturn, pagination.Meta, error)
+	UpdateReturnDetails(ctx context.Context, adminID uint, updateDetails requests.UpdateOrderReturn) error
 
 	// wallet
 	FindUserWallet(ctx context.Context, userID uint) (wallet models.Wallet, err error)
-	FindUserWalletTransactions(ctx context.Context, userID uint, pagination requests.Pagination) (transactions []models.Transaction, err error)
+	FindUserWalletTransactions(ctx context.Context, userID uint, page pagination.Request) (transactions []models.Transaction, meta pagination.Meta, err error)
+	FindMismatchedWallets(ctx context.Context, page pagination.Request) ([]responses.WalletReconciliation, pagination.Meta, error)
 }
@@ -2,9 +2,12 @@ package interfaces
 
 import (
 	"context"
+	"net/http"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
 )
 
 type PaymentUseCase interface {
@@ -14,10 +17,14 @@ type PaymentUseCase interface {
 
 	// razorpay
 	MakeRazorpayOrder(ctx context.Context, userID, shopOrderID uint) (razorpayOrder responses.RazorpayOrder, err error)
-	VerifyRazorPay(ctx context.Context, verifyReq requests.RazorpayVerify) error
+	VerifyRazorPay(ctx context.Context, userID, shopOrderID uint, verifyReq requests.RazorpayVerify) error
 	// stipe
 	MakeStripeOrder(ctx context.Context, userID, shopOrderID uint) (stipeOrder responses.StripeOrder, err error)
-	VerifyStripOrder(ctx context.Context, stripePaymentID string) error
+	VerifyStripOrder(ctx context.Context, userID, shopOrderID uint, stripePaymentID string) error
+	// wallet
+	PayWithWallet(ctx context.Context, userID, shopOrderID uint) (walletOrder responses.WalletOrder, err error)
+
+	HandlePaymentWebhook(ctx context.Context, paymentType commonConstant.PaymentType, payload []byte, header http.Header) error
 
 	ApproveShopOrderAndClearCart(ctx context.Context, userID uint, approveDetails requests.ApproveOrder) error
 }
@@ -5,6 +5,7 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 )
 
 type ProductUseCase interface {
@@ -15,7 +16,10 @@ type ProductUseCase interface {
 	FindAllVariationsAndItsValues(ctx context.Context, categoryID uint) ([]responses.Variation, error)
 
 	// products
-	FindAllProducts(ctx context.Context, pagination requests.Pagination) (products []responses.Product, err error)
+	FindAllProducts(ctx context.Context, filter requests.ProductFilter,
+		page pagination.Request) (products []responses.Product, meta pagination.Meta, err error)
+	FindProductFacets(ctx context.Context, filter requests.ProductFilter) (responses.ProductFacets, error)
+	FindProductDetail(ctx context.Context, productID uint) (responses.ProductDetail, error)
 	SaveProduct(ctx context.Context, product requests.Product) error
 	UpdateProduct(ctx context.Context, product models.Product) error
 
@@ -4,9 +4,10 @@ import (
 	"context"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 )
 
 type StockUseCase interface {
-	GetAllStockDetails(ctx context.Context, pagination requests.Pagination) (stocks []responses.Stock, err error)
+	GetAllStockDetails(ctx context.Context, page pagination.Request) (stocks []responses.Stock, meta pagination.Meta, err error)
 	UpdateStockBySKU(ctx context.Context, updateDetails requests.UpdateStock) error
 }
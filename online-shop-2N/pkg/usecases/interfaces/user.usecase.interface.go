@@ -10,6 +10,7 @@ import (
 type UserUseCase interface {
 	FindProfile(ctx context.Context, userId uint) (models.User, error)
 	UpdateProfile(ctx context.Context, user models.User) error
+	ChangePassword(ctx context.Context, userID uint, passwordDetails requests.ChangePassword) error
 
 	// profile side
 
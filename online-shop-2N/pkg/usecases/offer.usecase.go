@@ -5,6 +5,7 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	repo "online-shop-2N/pkg/repositories/interfaces"
 	"online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
@@ -73,13 +74,13 @@ func (c *offerUseCase) RemoveOffer(ctx context.Context, offerID uint) error {
 	return nil
 }
 
-func (c *offerUseCase) FindAllOffers(ctx context.Context, pagination requests.Pagination) ([]models.Offer, error) {
+func (c *offerUseCase) FindAllOffers(ctx context.Context, page pagination.Request) ([]models.Offer, pagination.Meta, error) {
 
-	offers, err := c.offerRepo.FindAllOffers(ctx, pagination)
+	offers, meta, err := c.offerRepo.FindAllOffers(ctx, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to find all offers")
+		return nil, meta, utils.PrependMessageToError(err, "failed to find all offers")
 	}
-	return offers, nil
+	return offers, meta, nil
 }
 
 func (c *offerUseCase) SaveCategoryOffer(ctx context.Context, offerCategory requests.OfferCategory) error {
@@ -130,14 +131,15 @@ func (c *offerUseCase) SaveCategoryOffer(ctx context.Context, offerCategory requ
 }
 
 // get all offer_category
-func (c *offerUseCase) FindAllCategoryOffers(ctx context.Context, pagination requests.Pagination) ([]responses.OfferCategory, error) {
+func (c *offerUseCase) FindAllCategoryOffers(ctx context.Context,
+	page pagination.Request) ([]responses.OfferCategory, pagination.Meta, error) {
 
-	categoryOffers, err := c.offerRepo.FindAllOfferCategories(ctx, pagination)
+	categoryOffers, meta, err := c.offerRepo.FindAllOfferCategories(ctx, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to find all category offers")
+		return nil, meta, utils.PrependMessageToError(err, "failed to find all category offers")
 	}
 
-	return categoryOffers, nil
+	return categoryOffers, meta, nil
 }
 
 // remove offer from category
@@ -235,12 +237,13 @@ func (c *offerUseCase) SaveProductOffer(ctx context.Context, offerProduct models
 }
 
 // get all offers for products
-func (c *offerUseCase) FindAllProductOffers(ctx context.Context, pagination requests.Pagination) ([]responses.OfferProduct, error) {
-	productOffers, err := c.offerRepo.FindAllOfferProducts(ctx, pagination)
+func (c *offerUseCase) FindAllProductOffers(ctx context.Context,
+	page pagination.Request) ([]responses.OfferProduct, pagination.Meta, error) {
+	productOffers, meta, err := c.offerRepo.FindAllOfferProducts(ctx, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to find product offers")
+		return nil, meta, utils.PrependMessageToError(err, "failed to find product offers")
 	}
-	return productOffers, nil
+	return productOffers, meta, nil
 }
 
 // remove offer form products
@@ -2,14 +2,15 @@ package usecases
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	commonConstant "online-shop-2N/pkg/common/constants"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/payment"
 	service "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
 	"time"
@@ -19,15 +20,18 @@ type OrderUseCase struct {
 	orderRepo interfaces.OrderRepository
 	cartRepo  interfaces.CartRepository
 	userRepo  interfaces.UserRepository
+
+	paymentGateways payment.Registry
 }
 
 func NewOrderUseCase(orderRepo interfaces.OrderRepository, cartRepo interfaces.CartRepository,
 	userRepo interfaces.UserRepository,
-	paymentRepo interfaces.PaymentRepository) service.OrderUseCase {
+	paymentRepo interfaces.PaymentRepository, paymentGateways payment.Registry) service.OrderUseCase {
 	return &OrderUseCase{
-		orderRepo: orderRepo,
-		cartRepo:  cartRepo,
-		userRepo:  userRepo,
+		orderRepo:       orderRepo,
+		cartRepo:        cartRepo,
+		userRepo:        userRepo,
+		paymentGateways: paymentGateways,
 	}
 }
 
@@ -86,6 +90,17 @@ func (c *OrderUseCase) SaveOrder(ctx context.Context, userID, addressID uint) (u
 			return utils.PrependMessageToError(err, "failed to save shop order on database")
 		}
 
+		err = trxRepo.SaveOrderStatusHistory(ctx, models.OrderStatusHistory{
+			ShopOrderID: shopOrder.ID,
+			ToStatusID:  pendingOrderStatus.ID,
+			Actor:       commonConstant.ActorUser,
+			ActorID:     userID,
+			Comment:     "order created",
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save order status history")
+		}
+
 		cartItems, err := c.cartRepo.FindAllCartItemsByCartID(ctx, cart.ID)
 		if err != nil {
 			return utils.PrependMessageToError(err, "failed to find all cart items")
@@ -123,191 +138,387 @@ func (c *OrderUseCase) SaveOrder(ctx context.Context, userID, addressID uint) (u
 
 // Find all orders of a user
 func (c *OrderUseCase) FindUserShopOrder(ctx context.Context, userID uint,
-	pagination requests.Pagination) ([]responses.ShopOrder, error) {
+	page pagination.Request) ([]responses.ShopOrder, pagination.Meta, error) {
 
-	shopOrders, err := c.orderRepo.FindAllShopOrdersByUserID(ctx, userID, pagination)
+	shopOrders, meta, err := c.orderRepo.FindAllShopOrdersByUserID(ctx, userID, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to find all shop orders by user id")
+		return nil, meta, utils.PrependMessageToError(err, "failed to find all shop orders by user id")
 	}
 
 	for i, order := range shopOrders {
 
 		address, err := c.userRepo.FindAddressByID(ctx, order.AddressID)
 		if err != nil {
-			return nil, utils.PrependMessageToError(err, "failed to get order address")
+			return nil, meta, utils.PrependMessageToError(err, "failed to get order address")
 		}
 		shopOrders[i].Address = address
 	}
 
-	return shopOrders, nil
+	return shopOrders, meta, nil
 }
 
 // func to Find all shop order
-func (c *OrderUseCase) FindAllShopOrders(ctx context.Context, pagination requests.Pagination) ([]responses.ShopOrder, error) {
+func (c *OrderUseCase) FindAllShopOrders(ctx context.Context,
+	page pagination.Request) ([]responses.ShopOrder, pagination.Meta, error) {
 
-	shopOrders, err := c.orderRepo.FindAllShopOrders(ctx, pagination)
+	shopOrders, meta, err := c.orderRepo.FindAllShopOrders(ctx, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to find all shop orders")
+		return nil, meta, utils.PrependMessageToError(err, "failed to find all shop orders")
 	}
 
 	for i, order := range shopOrders {
 
 		address, err := c.userRepo.FindAddressByID(ctx, order.AddressID)
 		if err != nil {
-			return nil, utils.PrependMessageToError(err, "failed to get order address")
+			return nil, meta, utils.PrependMessageToError(err, "failed to get order address")
 		}
 		shopOrders[i].Address = address
 	}
 
-	return shopOrders, nil
+	return shopOrders, meta, nil
 }
 
 func (c *OrderUseCase) FindOrderItems(ctx context.Context, shopOrderID uint,
-	pagination requests.Pagination) (orderItems []responses.OrderItem, err error) {
+	page pagination.Request) (orderItems []responses.OrderItem, meta pagination.Meta, err error) {
 
-	orderItems, err = c.orderRepo.FindAllOrdersItemsByShopOrderID(ctx, shopOrderID, pagination)
+	orderItems, meta, err = c.orderRepo.FindAllOrdersItemsByShopOrderID(ctx, shopOrderID, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to find order items using shop order id")
+		return nil, meta, utils.PrependMessageToError(err, "failed to find order items using shop order id")
 	}
 
-	return orderItems, nil
+	return orderItems, meta, nil
 }
 
-func (c *OrderUseCase) CancelOrder(ctx context.Context, shopOrderID uint) error {
+func (c *OrderUseCase) CancelOrder(ctx context.Context, userID, shopOrderID uint, cancelDetails requests.CancelOrder) error {
 
 	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
 	if err != nil {
-		return err
+		return utils.PrependMessageToError(err, "failed to find shop order")
+	}
+	if shopOrder.ID == 0 || shopOrder.UserID != userID {
+		return ErrShopOrderNotExist
+	}
+
+	if cancelDetails.CancelReason == "" {
+		cancelDetails.CancelReason = "order cancelled by user"
 	}
 
-	currentOrderStatus, err := c.orderRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+	err = c.cancelOrderItems(ctx, shopOrder.ID, cancelDetails, commonConstant.ActorUser, userID)
 	if err != nil {
-		return err
+		return utils.PrependMessageToError(err, "failed to cancel the order")
 	}
 
-	if currentOrderStatus.Status != commonConstant.StatusOrderPlaced {
-		return fmt.Errorf("order is ' %s ' \ncan't cancel the order", currentOrderStatus.Status)
+	return nil
+}
+
+// To cancel the given quantities of order items, the cancelled quantities are restocked and the amount paid for them is refunded
+// the order change to cancelled when all of its items are cancelled
+func (c *OrderUseCase) cancelOrderItems(ctx context.Context, shopOrderID uint, cancelDetails requests.CancelOrder,
+	actor commonConstant.OrderActorType, actorID uint) error {
+
+	refundPolicy, walletRefundAmount, err := findRefundPolicy(cancelDetails.RefundPolicy, cancelDetails.WalletRefundAmount)
+	if err != nil {
+		return err
 	}
 
-	// if its not then find the cacel orderStatusID
-	cancelOrderStatus, err := c.orderRepo.FindOrderStatusByStatus(ctx, commonConstant.StatusOrderCancelled)
+	var (
+		refund         orderRefund
+		gatewayRefunds []gatewayRefund
+	)
+	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+
+		shopOrder, err := trxRepo.FindShopOrderByShopOrderIDForUpdate(ctx, shopOrderID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find shop order")
+		}
+
+		// items can cancel only when the whole order is allowed to cancel
+		currentStatus, err := trxRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find current order status")
+		}
+		if !isOrderStatusChangeAllowed(currentStatus.Status, commonConstant.StatusOrderCancelled, actor) {
+			return utils.PrependMessageToError(ErrOrderStatusChangeNotAllowed,
+				fmt.Sprintf("items of order on status '%s' can't cancel by %s", currentStatus.Status, actor))
+		}
+
+		orderLines, err := trxRepo.FindOrderLinesByShopOrderID(ctx, shopOrder.ID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find order lines")
+		}
+
+		lineRefunds, err := findOrderLineRefunds(shopOrder, orderLines, cancelDetails.Lines)
+		if err != nil {
+			return err
+		}
+
+		// refund only the amount paid, cod is not paid until the delivery
+		refundableAmount, err := findRefundableAmount(ctx, trxRepo, shopOrder.ID)
+		if err != nil {
+			return err
+		}
+		refundAmount := lineRefunds.Amount
+		if refundAmount > refundableAmount {
+			refundAmount = refundableAmount
+		}
+
+		orderCancel := models.OrderCancel{
+			ShopOrderID:        shopOrder.ID,
+			CancelReason:       cancelDetails.CancelReason,
+			Actor:              actor,
+			ActorID:            actorID,
+			RefundAmount:       refundAmount,
+			RefundPolicy:       refundPolicy,
+			WalletRefundAmount: walletRefundAmount,
+		}
+		orderCancel.ID, err = trxRepo.SaveOrderCancel(ctx, orderCancel)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save order cancel")
+		}
+
+		err = cancelOrderLines(ctx, trxRepo, orderCancel.ID, lineRefunds.Lines)
+		if err != nil {
+			return err
+		}
+
+		if lineRefunds.AllItems {
+			_, err = changeOrderStatus(ctx, trxRepo, orderStatusChange{
+				ShopOrderID: shopOrder.ID,
+				ChangeTo:    commonConstant.StatusOrderCancelled,
+				Actor:       actor,
+				ActorID:     actorID,
+				Comment:     cancelDetails.CancelReason,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if refundAmount == 0 {
+			return nil
+		}
+		refund = orderRefund{
+			ShopOrder:     shopOrder,
+			OrderCancelID: orderCancel.ID,
+			Amount:        refundAmount,
+			Policy:        refundPolicy,
+			WalletAmount:  walletRefundAmount,
+		}
+		gatewayRefunds, err = saveOrderRefunds(ctx, trxRepo, refund)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to refund cancelled items amount")
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	err = c.orderRepo.UpdateShopOrderOrderStatus(ctx, shopOrder.ID, cancelOrderStatus.ID)
+	err = c.processGatewayRefunds(ctx, gatewayRefunds)
 	if err != nil {
-		return fmt.Errorf("failed to cancel the order %v", err.Error())
+		return utils.PrependMessageToError(err, "order items cancelled but failed to process refunds")
 	}
 
 	return nil
 }
 
 // update order
-func (c *OrderUseCase) UpdateOrderStatus(ctx context.Context, shopOrderID, changeStatusID uint) error {
+func (c *OrderUseCase) UpdateOrderStatus(ctx context.Context, adminID uint, updateDetails requests.UpdateOrder) error {
 
-	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
+	orderStatusChangeTo, err := c.orderRepo.FindOrderStatusByID(ctx, updateDetails.OrderStatusID)
 	if err != nil {
-		return utils.PrependMessageToError(err, "failed to find shop order")
+		return utils.PrependMessageToError(err, "failed to find order status")
+	}
+	if orderStatusChangeTo.ID == 0 {
+		return fmt.Errorf("invalid order_status_id %v", updateDetails.OrderStatusID)
 	}
 
-	currentOrderStatus, err := c.orderRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
-	if err != nil {
+	// return statuses have their own details to update on order return
+	if orderReturnStatuses[orderStatusChangeTo.Status] {
+		return fmt.Errorf("order status '%s' only can change through order return", orderStatusChangeTo.Status)
+	}
+
+	// cancel by admin restock all the items left and refund them to the original payment method
+	if orderStatusChangeTo.Status == commonConstant.StatusOrderCancelled {
+		err = c.cancelOrderItems(ctx, updateDetails.ShopOrderID, requests.CancelOrder{
+			CancelReason: updateDetails.Comment,
+			RefundPolicy: commonConstant.RefundToOriginalMethod,
+		}, commonConstant.ActorAdmin, adminID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to cancel the order")
+		}
+		return nil
+	}
+
+	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+
+		_, err := changeOrderStatus(ctx, trxRepo, orderStatusChange{
+			ShopOrderID: updateDetails.ShopOrderID,
+			ChangeTo:    orderStatusChangeTo.Status,
+			Actor:       commonConstant.ActorAdmin,
+			ActorID:     adminID,
+			Comment:     updateDetails.Comment,
+		})
 		return err
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to change order status")
 	}
+	return nil
+}
+
+// Find all status changes of a shop order
+func (c *OrderUseCase) FindOrderStatusHistory(ctx context.Context,
+	shopOrderID uint) ([]responses.OrderStatusHistory, error) {
 
-	orderStatusChangeTo, err := c.orderRepo.FindOrderStatusByID(ctx, changeStatusID)
+	histories, err := c.orderRepo.FindOrderStatusHistoryByShopOrderID(ctx, shopOrderID)
 	if err != nil {
-		return err
+		return nil, utils.PrependMessageToError(err, "failed to find order status history")
 	}
 
-	switch currentOrderStatus.Status {
+	return histories, nil
+}
 
-	case commonConstant.StatusOrderPlaced: // if order status is placed then change status should be order delivered
-		if orderStatusChangeTo.Status != commonConstant.StatusOrderDelivered {
-			return fmt.Errorf("order status is 'order placed' \nchange status should be 'order delivered'")
-		}
-	default:
-		return fmt.Errorf("order status %s can't change to %s ", currentOrderStatus.Status, orderStatusChangeTo.Status)
+// Find all status changes of a shop order which belongs to the user
+func (c *OrderUseCase) FindUserOrderStatusHistory(ctx context.Context,
+	userID, shopOrderID uint) ([]responses.OrderStatusHistory, error) {
+
+	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find shop order")
 	}
+	if shopOrder.ID == 0 || shopOrder.UserID != userID {
+		return nil, ErrShopOrderNotExist
+	}
+
+	return c.FindOrderStatusHistory(ctx, shopOrderID)
+}
+
+// Find all payments of a shop order with amount paid by each payment method
+func (c *OrderUseCase) FindOrderPayments(ctx context.Context,
+	shopOrderID uint) ([]responses.ShopOrderPayment, error) {
 
-	err = c.orderRepo.UpdateShopOrderOrderStatus(ctx, shopOrder.ID, changeStatusID)
+	orderPayments, err := c.orderRepo.FindOrderPaymentsByShopOrderID(ctx, shopOrderID)
 	if err != nil {
-		return fmt.Errorf("failed to change order status %v", err.Error())
+		return nil, utils.PrependMessageToError(err, "failed to find order payments")
 	}
-	return nil
+
+	return orderPayments, nil
+}
+
+// Find all payments of a shop order which belongs to the user
+func (c *OrderUseCase) FindUserOrderPayments(ctx context.Context,
+	userID, shopOrderID uint) ([]responses.ShopOrderPayment, error) {
+
+	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find shop order")
+	}
+	if shopOrder.ID == 0 || shopOrder.UserID != userID {
+		return nil, ErrShopOrderNotExist
+	}
+
+	return c.FindOrderPayments(ctx, shopOrderID)
 }
 
 // to get pending order returns
-func (c *OrderUseCase) FindAllPendingOrderReturns(ctx context.Context, pagination requests.Pagination) ([]responses.OrderReturn, error) {
+func (c *OrderUseCase) FindAllPendingOrderReturns(ctx context.Context,
+	page pagination.Request) ([]responses.OrderReturn, pagination.Meta, error) {
 
-	pendingOrderReturns, err := c.orderRepo.FindAllPendingOrderReturns(ctx, pagination)
+	pendingOrderReturns, meta, err := c.orderRepo.FindAllPendingOrderReturns(ctx, page)
 	if err != nil {
-		return pendingOrderReturns, fmt.Errorf("failed to Find pendin order returns \nerror:%v", err.Error())
+		return pendingOrderReturns, meta, fmt.Errorf("failed to Find pendin order returns \nerror:%v", err.Error())
 	}
-	return pendingOrderReturns, nil
+	return pendingOrderReturns, meta, nil
 }
 
 // to get all order return
-func (c *OrderUseCase) FindAllOrderReturns(ctx context.Context, pagination requests.Pagination) ([]responses.OrderReturn, error) {
+func (c *OrderUseCase) FindAllOrderReturns(ctx context.Context,
+	page pagination.Request) ([]responses.OrderReturn, pagination.Meta, error) {
 
-	orderReturns, err := c.orderRepo.FindAllOrderReturns(ctx, pagination)
+	orderReturns, meta, err := c.orderRepo.FindAllOrderReturns(ctx, page)
 	if err != nil {
-		return orderReturns, fmt.Errorf("failed to Find all order returns \nerror:%v", err.Error())
+		return orderReturns, meta, fmt.Errorf("failed to Find all order returns \nerror:%v", err.Error())
 	}
-	return orderReturns, nil
+	return orderReturns, meta, nil
 }
 
-func (c *OrderUseCase) SubmitReturnRequest(ctx context.Context, returnDetails requests.Return) error {
+func (c *OrderUseCase) SubmitReturnRequest(ctx context.Context, userID uint, returnDetails requests.Return) error {
 
 	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, returnDetails.ShopOrderID)
 	if err != nil {
 		return err
 	}
+	if shopOrder.ID == 0 || shopOrder.UserID != userID {
+		return ErrShopOrderNotExist
+	}
 
-	currentOrderStatus, err := c.orderRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+	refundPolicy, walletRefundAmount, err := findRefundPolicy(returnDetails.RefundPolicy, returnDetails.WalletRefundAmount)
 	if err != nil {
 		return err
 	}
 
-	if currentOrderStatus.Status != commonConstant.StatusOrderDelivered {
-		return fmt.Errorf("order is ' %s '\ncan't a make return requests for this order", currentOrderStatus.Status)
-	}
-
-	orderReturn := models.OrderReturn{
-		ShopOrderID:  returnDetails.ShopOrderID,
-		ReturnReason: returnDetails.ReturnReason,
-		RequestDate:  time.Now(),
-		RefundAmount: shopOrder.OrderTotalPrice,
-	}
-
 	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
 
-		err := trxRepo.SaveOrderReturn(ctx, orderReturn)
+		_, err := changeOrderStatus(ctx, trxRepo, orderStatusChange{
+			ShopOrderID: shopOrder.ID,
+			ChangeTo:    commonConstant.StatusReturnRequested,
+			Actor:       commonConstant.ActorUser,
+			ActorID:     userID,
+			Comment:     returnDetails.ReturnReason,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to submit order return \nerror:%v", err.Error())
+			return err
 		}
 
-		statusToChange, err := trxRepo.FindOrderStatusByStatus(ctx, commonConstant.StatusReturnRequested)
+		orderLines, err := trxRepo.FindOrderLinesByShopOrderID(ctx, shopOrder.ID)
 		if err != nil {
-			return fmt.Errorf("failed to find return requests status \nerror:%v", err.Error())
+			return utils.PrependMessageToError(err, "failed to find order lines")
 		}
 
-		err = trxRepo.UpdateShopOrderOrderStatus(ctx, shopOrder.ID, statusToChange.ID)
+		lineRefunds, err := findOrderLineRefunds(shopOrder, orderLines, returnDetails.Lines)
 		if err != nil {
-			return fmt.Errorf("failed to update order status \n error:%v", err.Error())
+			return err
+		}
+		if refundPolicy == commonConstant.RefundSplit && walletRefundAmount >= lineRefunds.Amount {
+			return ErrInvalidRefundSplit
+		}
+
+		orderReturn := models.OrderReturn{
+			ShopOrderID:        returnDetails.ShopOrderID,
+			ReturnReason:       returnDetails.ReturnReason,
+			RequestDate:        time.Now(),
+			RefundAmount:       lineRefunds.Amount,
+			RefundPolicy:       refundPolicy,
+			WalletRefundAmount: walletRefundAmount,
+		}
+		orderReturn.ID, err = trxRepo.SaveOrderReturn(ctx, orderReturn)
+		if err != nil {
+			return fmt.Errorf("failed to submit order return \nerror:%v", err.Error())
+		}
+
+		for _, lineRefund := range lineRefunds.Lines {
+			err = trxRepo.SaveOrderReturnLine(ctx, models.OrderReturnLine{
+				OrderReturnID: orderReturn.ID,
+				OrderLineID:   lineRefund.OrderLineID,
+				Qty:           lineRefund.Qty,
+				RefundAmount:  lineRefund.RefundAmount,
+			})
+			if err != nil {
+				return utils.PrependMessageToError(err, "failed to save order return line")
+			}
 		}
 		return nil
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to save order return \nerror:%v", err.Error())
+		return utils.PrependMessageToError(err, "failed to save order return")
 	}
 	log.Println("successfully order return requests submitted")
 	return nil
 }
 
-func (c *OrderUseCase) UpdateReturnDetails(ctx context.Context, updateDetails requests.UpdateOrderReturn) error {
+func (c *OrderUseCase) UpdateReturnDetails(ctx context.Context, adminID uint, updateDetails requests.UpdateOrderReturn) error {
 
 	orderReturn, err := c.orderRepo.FindOrderReturnByReturnID(ctx, updateDetails.OrderReturnID)
 	if err != nil {
@@ -319,9 +530,13 @@ func (c *OrderUseCase) UpdateReturnDetails(ctx context.Context, updateDetails re
 		return fmt.Errorf("failed to Find order details \nerror:%v", err.Error())
 	}
 
-	currentOrderStatus, err := c.orderRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+	// only the last return of the order can update, the older returns are closed
+	lastOrderReturn, err := c.orderRepo.FindOrderReturnByShopOrderID(ctx, shopOrder.ID)
 	if err != nil {
-		return err
+		return utils.PrependMessageToError(err, "failed to find last order return")
+	}
+	if lastOrderReturn.ID != orderReturn.ID {
+		return ErrOrderReturnClosed
 	}
 
 	returnStatusChangeTo, err := c.orderRepo.FindOrderStatusByID(ctx, updateDetails.OrderStatusID)
@@ -329,82 +544,87 @@ func (c *OrderUseCase) UpdateReturnDetails(ctx context.Context, updateDetails re
 		return err
 	}
 
-	switch currentOrderStatus.Status {
-
-	case commonConstant.StatusReturnRequested:
-		if returnStatusChangeTo.Status == commonConstant.StatusReturnApproved {
-			if time.Since(updateDetails.ReturnDate) > 0 {
-				return fmt.Errorf("given return date is invalid \nto update 'return approved' return date should be greater than cuurent time")
-			}
-			orderReturn.ApprovalDate = time.Now()
-			orderReturn.IsApproved = true
-			orderReturn.ReturnDate = updateDetails.ReturnDate
-		} else if returnStatusChangeTo.Status == commonConstant.StatusReturnCancelled {
-			// nothing extra update on order return may be in future when adding new statuses
-		} else {
-			return errors.New("order staus is return requested \nchange status must be return approved or return cancelled")
-		}
+	// the order state machine validate the change from current status
+	// in here only update the details of return for the status changing to
+	switch returnStatusChangeTo.Status {
 
 	case commonConstant.StatusReturnApproved:
-		if returnStatusChangeTo.Status != commonConstant.StatusOrderReturned {
-			return errors.New(" change status must be order returned")
+		if time.Since(updateDetails.ReturnDate) > 0 {
+			return fmt.Errorf("given return date is invalid \nto update 'return approved' return date should be greater than cuurent time")
 		}
+		orderReturn.ApprovalDate = time.Now()
+		orderReturn.IsApproved = true
+		orderReturn.ReturnDate = updateDetails.ReturnDate
+
+	case commonConstant.StatusReturnCancelled:
+		// nothing extra update on order return may be in future when adding new statuses
+
+	case commonConstant.StatusOrderReturned, commonConstant.StatusPartiallyReturned:
 		if time.Since(updateDetails.ReturnDate) <= 0 {
 			return fmt.Errorf("given return date is invalid \nto update 'order returned' return should be less than current time")
-		} else {
-			orderReturn.ReturnDate = updateDetails.ReturnDate
 		}
+		orderReturn.ReturnDate = updateDetails.ReturnDate
 
 	default:
-		return fmt.Errorf("order status %s can't change to %s ", currentOrderStatus.Status, returnStatusChangeTo.Status)
+		return fmt.Errorf("order return can't change to %s", returnStatusChangeTo.Status)
 	}
 
 	orderReturn.AdminComment = updateDetails.AdminComment
+
+	var (
+		refund         orderRefund
+		gatewayRefunds []gatewayRefund
+	)
 	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
 
-		err := trxRepo.UpdateOrderReturn(ctx, orderReturn)
-		if err != nil {
-			return fmt.Errorf("failed to update orders return \nerror:%v", err.Error())
-		}
+		changeTo := returnStatusChangeTo.Status
+		itemsReturned := changeTo == commonConstant.StatusOrderReturned || changeTo == commonConstant.StatusPartiallyReturned
 
-		err = trxRepo.UpdateShopOrderOrderStatus(ctx, shopOrder.ID, returnStatusChangeTo.ID)
-		if err != nil {
-			return fmt.Errorf("failed to update order status \nerror:%v", err.Error())
-		}
-
-		// if order changing to order return then return the order amount to use wallet
-		if returnStatusChangeTo.Status == commonConstant.StatusOrderReturned {
-			// get user wallet
-			wallet, err := trxRepo.FindWalletByUserID(ctx, shopOrder.UserID)
+		// restock the returned items and change the order to partially returned if some items are left
+		if itemsReturned {
+			shopOrder, err := trxRepo.FindShopOrderByShopOrderIDForUpdate(ctx, shopOrder.ID)
 			if err != nil {
-				return fmt.Errorf("failed to get user wallet for refund amount \nerror:%v", err.Error())
-			}
-			// if user have no wallet then create a new wallet for user
-			if wallet.ID == 0 {
-				wallet.ID, err = c.orderRepo.SaveWallet(ctx, shopOrder.UserID)
-				if err != nil {
-					return fmt.Errorf("failed to create a wallet for user")
-				}
+				return utils.PrependMessageToError(err, "failed to find shop order")
 			}
 
-			// calculate wallet amount and update
-			newWalletTotal := wallet.TotalAmount + shopOrder.OrderTotalPrice
-			err = trxRepo.UpdateWallet(ctx, wallet.ID, newWalletTotal)
+			allItemsReturned, err := returnOrderLines(ctx, trxRepo, shopOrder, orderReturn)
 			if err != nil {
-				return fmt.Errorf("failed to update return amount to user wallet \nerror:%v", err.Error())
+				return err
 			}
-
-			// wallet transaction
-			transaction := models.Transaction{
-				WalletID:        wallet.ID,
-				TransactionDate: time.Now(),
-				TransactionType: models.Credit,
-				Amount:          shopOrder.OrderTotalPrice,
+			changeTo = commonConstant.StatusOrderReturned
+			if !allItemsReturned {
+				changeTo = commonConstant.StatusPartiallyReturned
 			}
-			err = trxRepo.SaveWalletTransaction(ctx, transaction)
+		}
+
+		_, err := changeOrderStatus(ctx, trxRepo, orderStatusChange{
+			ShopOrderID: shopOrder.ID,
+			ChangeTo:    changeTo,
+			Actor:       commonConstant.ActorAdmin,
+			ActorID:     adminID,
+			Comment:     updateDetails.AdminComment,
+		})
+		if err != nil {
+			return err
+		}
 
+		err = trxRepo.UpdateOrderReturn(ctx, orderReturn)
+		if err != nil {
+			return fmt.Errorf("failed to update orders return \nerror:%v", err.Error())
+		}
+
+		// if items are returned then refund the return amount as per the refund policy
+		if itemsReturned {
+			refund = orderRefund{
+				ShopOrder:     shopOrder,
+				OrderReturnID: orderReturn.ID,
+				Amount:        orderReturn.RefundAmount,
+				Policy:        orderReturn.RefundPolicy,
+				WalletAmount:  orderReturn.WalletRefundAmount,
+			}
+			gatewayRefunds, err = saveOrderRefunds(ctx, trxRepo, refund)
 			if err != nil {
-				return fmt.Errorf("failed to save wallet transaction \nerror:%v", err.Error())
+				return utils.PrependMessageToError(err, "failed to refund order return amount")
 			}
 		}
 		return nil
@@ -412,7 +632,13 @@ func (c *OrderUseCase) UpdateReturnDetails(ctx context.Context, updateDetails re
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to update order return \nerror:%v", err.Error())
+		return utils.PrependMessageToError(err, "failed to update order return")
+	}
+
+	// gateway refunds are made after the order return saved so a network call not hold the transaction
+	err = c.processGatewayRefunds(ctx, gatewayRefunds)
+	if err != nil {
+		return utils.PrependMessageToError(err, "order returned but failed to process refunds")
 	}
 
 	log.Printf("successfully updated order return requests for shop_order_id %v", shopOrder.ID)
@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/utils"
+)
+
+// quantity of an order line to return or cancel with its refund amount
+type orderLineRefund struct {
+	OrderLineID  uint
+	Qty          uint
+	RefundAmount uint
+}
+
+type orderLineRefunds struct {
+	Lines  []orderLineRefund
+	Amount uint
+	// all the items left on the order are taken
+	AllItems bool
+}
+
+// To find the refund amount of the given order line quantities, each line amount is prorated with the order discount
+// if no lines given then all the quantities which are not returned or cancelled are taken
+func findOrderLineRefunds(shopOrder models.ShopOrder, orderLines []models.OrderLine,
+	requestedLines []requests.OrderLineQty) (orderLineRefunds, error) {
+
+	var (
+		grossTotal, refundedAmount, itemsLeft uint
+		linesByID                             = make(map[uint]models.OrderLine, len(orderLines))
+	)
+	for _, orderLine := range orderLines {
+		grossTotal += orderLine.Price * orderLine.Qty
+		refundedAmount += orderLine.RefundedAmount
+		itemsLeft += orderLineQtyLeft(orderLine)
+		linesByID[orderLine.ID] = orderLine
+	}
+	if itemsLeft == 0 {
+		return orderLineRefunds{}, ErrNoOrderItemsLeft
+	}
+
+	if len(requestedLines) == 0 {
+		for _, orderLine := range orderLines {
+			if qtyLeft := orderLineQtyLeft(orderLine); qtyLeft > 0 {
+				requestedLines = append(requestedLines, requests.OrderLineQty{OrderLineID: orderLine.ID, Qty: qtyLeft})
+			}
+		}
+	}
+
+	var (
+		lineRefunds  orderLineRefunds
+		requestedQty = make(map[uint]uint, len(requestedLines))
+		totalQty     uint
+	)
+	for _, requestedLine := range requestedLines {
+
+		orderLine, ok := linesByID[requestedLine.OrderLineID]
+		if !ok {
+			return orderLineRefunds{}, ErrInvalidOrderLine
+		}
+
+		requestedQty[orderLine.ID] += requestedLine.Qty
+		if requestedLine.Qty == 0 || requestedQty[orderLine.ID] > orderLineQtyLeft(orderLine) {
+			return orderLineRefunds{}, ErrInvalidOrderLineQty
+		}
+
+		amount := proratedAmount(orderLine.Price*requestedLine.Qty, shopOrder.OrderTotalPrice, grossTotal)
+		lineRefunds.Lines = append(lineRefunds.Lines, orderLineRefund{
+			OrderLineID:  orderLine.ID,
+			Qty:          requestedLine.Qty,
+			RefundAmount: amount,
+		})
+		lineRefunds.Amount += amount
+		totalQty += requestedLine.Qty
+	}
+
+	// the last items of the order take the rounding difference so the refunds of the order sum to its total price
+	lineRefunds.AllItems = totalQty == itemsLeft
+	if lineRefunds.AllItems && shopOrder.OrderTotalPrice > refundedAmount+lineRefunds.Amount {
+		difference := shopOrder.OrderTotalPrice - refundedAmount - lineRefunds.Amount
+		lineRefunds.Lines[len(lineRefunds.Lines)-1].RefundAmount += difference
+		lineRefunds.Amount += difference
+	}
+
+	return lineRefunds, nil
+}
+
+// To find the quantity of order line which is not returned or cancelled
+func orderLineQtyLeft(orderLine models.OrderLine) uint {
+	taken := orderLine.ReturnedQty + orderLine.CancelledQty
+	if taken >= orderLine.Qty {
+		return 0
+	}
+	return orderLine.Qty - taken
+}
+
+// To find the share of the amount from order total price which is after the order discount
+func proratedAmount(amount, orderTotalPrice, grossTotal uint) uint {
+	if grossTotal == 0 {
+		return 0
+	}
+	return uint(uint64(amount) * uint64(orderTotalPrice) / uint64(grossTotal))
+}
+
+// To add the returned quantities of an order return to its order lines and restock only those quantities
+// order returns before item level returns have no lines so all the items left are returned
+func returnOrderLines(ctx context.Context, trxRepo interfaces.OrderRepository,
+	shopOrder models.ShopOrder, orderReturn models.OrderReturn) (allItemsReturned bool, err error) {
+
+	orderLines, err := trxRepo.FindOrderLinesByShopOrderID(ctx, shopOrder.ID)
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to find order lines")
+	}
+
+	returnLines, err := trxRepo.FindOrderReturnLinesByReturnID(ctx, orderReturn.ID)
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to find order return lines")
+	}
+
+	var lineRefunds []orderLineRefund
+	if len(returnLines) == 0 {
+		allLineRefunds, err := findOrderLineRefunds(shopOrder, orderLines, nil)
+		if err != nil {
+			return false, err
+		}
+		lineRefunds = allLineRefunds.Lines
+	}
+	for _, returnLine := range returnLines {
+		lineRefunds = append(lineRefunds, orderLineRefund{
+			OrderLineID:  returnLine.OrderLineID,
+			Qty:          returnLine.Qty,
+			RefundAmount: returnLine.RefundAmount,
+		})
+	}
+
+	returnedQty := make(map[uint]uint, len(lineRefunds))
+	for _, lineRefund := range lineRefunds {
+
+		err = trxRepo.AddOrderLineReturnedQty(ctx, lineRefund.OrderLineID, lineRefund.Qty, lineRefund.RefundAmount)
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to update order line returned quantity")
+		}
+
+		err = trxRepo.RestockOrderLineQty(ctx, lineRefund.OrderLineID, lineRefund.Qty)
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to restock returned quantity")
+		}
+		returnedQty[lineRefund.OrderLineID] += lineRefund.Qty
+	}
+
+	allItemsReturned = true
+	for _, orderLine := range orderLines {
+		if orderLineQtyLeft(orderLine) > returnedQty[orderLine.ID] {
+			allItemsReturned = false
+		}
+	}
+
+	return allItemsReturned, nil
+}
+
+// To save the cancelled quantities of order lines and restock only those quantities
+func cancelOrderLines(ctx context.Context, trxRepo interfaces.OrderRepository,
+	orderCancelID uint, lineRefunds []orderLineRefund) error {
+
+	for _, lineRefund := range lineRefunds {
+
+		err := trxRepo.SaveOrderCancelLine(ctx, models.OrderCancelLine{
+			OrderCancelID: orderCancelID,
+			OrderLineID:   lineRefund.OrderLineID,
+			Qty:           lineRefund.Qty,
+			RefundAmount:  lineRefund.RefundAmount,
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save order cancel line")
+		}
+
+		err = trxRepo.AddOrderLineCancelledQty(ctx, lineRefund.OrderLineID, lineRefund.Qty, lineRefund.RefundAmount)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to update order line cancelled quantity")
+		}
+
+		err = trxRepo.RestockOrderLineQty(ctx, lineRefund.OrderLineID, lineRefund.Qty)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to restock cancelled quantity")
+		}
+	}
+
+	return nil
+}
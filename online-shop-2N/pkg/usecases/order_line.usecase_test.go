@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"errors"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/models"
+	"reflect"
+	"testing"
+)
+
+func TestProratedAmount(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		amount          uint
+		orderTotalPrice uint
+		grossTotal      uint
+		want            uint
+	}{
+		{name: "no discount", amount: 300, orderTotalPrice: 1000, grossTotal: 1000, want: 300},
+		{name: "discount on order", amount: 300, orderTotalPrice: 900, grossTotal: 1000, want: 270},
+		{name: "rounded down", amount: 100, orderTotalPrice: 200, grossTotal: 300, want: 66},
+		{name: "full amount", amount: 1000, orderTotalPrice: 900, grossTotal: 1000, want: 900},
+		{name: "zero gross total", amount: 300, orderTotalPrice: 900, grossTotal: 0, want: 0},
+		{name: "large amounts not overflow", amount: 4_000_000_000, orderTotalPrice: 3_000_000_000,
+			grossTotal: 4_000_000_000, want: 3_000_000_000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got := proratedAmount(test.amount, test.orderTotalPrice, test.grossTotal)
+			if got != test.want {
+				t.Fatalf("got prorated amount %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFindOrderLineRefunds(t *testing.T) {
+
+	// 10 percent discount on the order
+	discountedOrder := models.ShopOrder{OrderTotalPrice: 900}
+	discountedLines := []models.OrderLine{
+		{ID: 1, Price: 300, Qty: 2},
+		{ID: 2, Price: 400, Qty: 1},
+	}
+
+	tests := []struct {
+		name           string
+		shopOrder      models.ShopOrder
+		orderLines     []models.OrderLine
+		requestedLines []requests.OrderLineQty
+		want           orderLineRefunds
+		wantErr        error
+	}{
+		{
+			name:           "one item of line",
+			shopOrder:      discountedOrder,
+			orderLines:     discountedLines,
+			requestedLines: []requests.OrderLineQty{{OrderLineID: 1, Qty: 1}},
+			want: orderLineRefunds{
+				Lines:  []orderLineRefund{{OrderLineID: 1, Qty: 1, RefundAmount: 270}},
+				Amount: 270,
+			},
+		},
+		{
+			name:       "all items when no lines given",
+			shopOrder:  discountedOrder,
+			orderLines: discountedLines,
+			want: orderLineRefunds{
+				Lines: []orderLineRefund{
+					{OrderLineID: 1, Qty: 2, RefundAmount: 540},
+					{OrderLineID: 2, Qty: 1, RefundAmount: 360},
+				},
+				Amount:   900,
+				AllItems: true,
+			},
+		},
+		{
+			name:      "last items take rounding difference",
+			shopOrder: models.ShopOrder{OrderTotalPrice: 200},
+			orderLines: []models.OrderLine{
+				{ID: 1, Price: 100, Qty: 1},
+				{ID: 2, Price: 100, Qty: 1},
+				{ID: 3, Price: 100, Qty: 1},
+			},
+			want: orderLineRefunds{
+				Lines: []orderLineRefund{
+					{OrderLineID: 1, Qty: 1, RefundAmount: 66},
+					{OrderLineID: 2, Qty: 1, RefundAmount: 66},
+					{OrderLineID: 3, Qty: 1, RefundAmount: 68},
+				},
+				Amount:   200,
+				AllItems: true,
+			},
+		},
+		{
+			name:      "items left after a return",
+			shopOrder: discountedOrder,
+			orderLines: []models.OrderLine{
+				{ID: 1, Price: 300, Qty: 2, ReturnedQty: 1, RefundedAmount: 270},
+				{ID: 2, Price: 400, Qty: 1},
+			},
+			want: orderLineRefunds{
+				Lines: []orderLineRefund{
+					{OrderLineID: 1, Qty: 1, RefundAmount: 270},
+					{OrderLineID: 2, Qty: 1, RefundAmount: 360},
+				},
+				Amount:   630,
+				AllItems: true,
+			},
+		},
+		{
+			name:           "line not of order",
+			shopOrder:      discountedOrder,
+			orderLines:     discountedLines,
+			requestedLines: []requests.OrderLineQty{{OrderLineID: 3, Qty: 1}},
+			wantErr:        ErrInvalidOrderLine,
+		},
+		{
+			name:           "zero quantity",
+			shopOrder:      discountedOrder,
+			orderLines:     discountedLines,
+			requestedLines: []requests.OrderLineQty{{OrderLineID: 1, Qty: 0}},
+			wantErr:        ErrInvalidOrderLineQty,
+		},
+		{
+			name:           "more than quantity left",
+			shopOrder:      discountedOrder,
+			orderLines:     discountedLines,
+			requestedLines: []requests.OrderLineQty{{OrderLineID: 2, Qty: 2}},
+			wantErr:        ErrInvalidOrderLineQty,
+		},
+		{
+			name:       "repeated line more than quantity left",
+			shopOrder:  discountedOrder,
+			orderLines: discountedLines,
+			requestedLines: []requests.OrderLineQty{
+				{OrderLineID: 1, Qty: 2},
+				{OrderLineID: 1, Qty: 1},
+			},
+			wantErr: ErrInvalidOrderLineQty,
+		},
+		{
+			name:      "no items left",
+			shopOrder: discountedOrder,
+			orderLines: []models.OrderLine{
+				{ID: 1, Price: 300, Qty: 2, ReturnedQty: 1, CancelledQty: 1},
+			},
+			wantErr: ErrNoOrderItemsLeft,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := findOrderLineRefunds(test.shopOrder, test.orderLines, test.requestedLines)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got refunds %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,497 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/payment"
+	"online-shop-2N/pkg/utils"
+	"time"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+)
+
+// a refund saved as pending which should refund on payment gateway
+type gatewayRefund struct {
+	refund       models.Refund
+	orderPayment responses.ShopOrderPayment
+}
+
+// amount of an order return or cancel to refund as per the refund policy chosen by user
+type orderRefund struct {
+	ShopOrder     models.ShopOrder
+	OrderReturnID uint
+	OrderCancelID uint
+	Amount        uint
+	Policy        commonConstant.RefundPolicyType
+	// amount to wallet for a split refund
+	WalletAmount uint
+}
+
+// To get the wallet entry for the refund amount with the order return or cancel as its source
+func (r orderRefund) walletEntry(amount uint, note string) walletEntry {
+
+	entry := walletEntry{
+		UserID:     r.ShopOrder.UserID,
+		Amount:     amount,
+		SourceType: models.SourceOrderReturn,
+		SourceID:   r.OrderReturnID,
+		Note:       "order return refund",
+	}
+	if r.OrderCancelID != 0 {
+		entry.SourceType = models.SourceOrderCancel
+		entry.SourceID = r.OrderCancelID
+		entry.Note = "order cancel refund"
+	}
+	if note != "" {
+		entry.Note = note
+	}
+
+	return entry
+}
+
+// To find the refund policy and wallet amount of a return or cancel request, policy default to wallet
+func findRefundPolicy(policy commonConstant.RefundPolicyType, walletAmount uint) (commonConstant.RefundPolicyType, uint, error) {
+
+	switch policy {
+	case "":
+		return commonConstant.RefundToWallet, 0, nil
+	case commonConstant.RefundSplit:
+		// split refund need an amount to wallet and the rest to original payment method
+		if walletAmount == 0 {
+			return "", 0, ErrInvalidRefundSplit
+		}
+		return policy, walletAmount, nil
+	default:
+		return policy, 0, nil
+	}
+}
+
+// To find the refunds of an order return or cancel as per the refund policy chosen by user
+// the amount which can't refund to a gateway payment is refunded to wallet
+func planOrderRefunds(ctx context.Context, repo interfaces.OrderRepository,
+	orderRefund orderRefund) (walletAmount uint, gatewayRefunds []gatewayRefund, err error) {
+
+	switch orderRefund.Policy {
+	case commonConstant.RefundToOriginalMethod:
+		walletAmount = 0
+	case commonConstant.RefundSplit:
+		walletAmount = orderRefund.WalletAmount
+		if walletAmount > orderRefund.Amount {
+			walletAmount = orderRefund.Amount
+		}
+	default:
+		return orderRefund.Amount, nil, nil
+	}
+
+	remainingAmount := orderRefund.Amount - walletAmount
+	if remainingAmount == 0 {
+		return walletAmount, nil, nil
+	}
+
+	shopOrderID := orderRefund.ShopOrder.ID
+	orderPayments, err := repo.FindOrderPaymentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return 0, nil, utils.PrependMessageToError(err, "failed to find order payments")
+	}
+
+	refunds, err := repo.FindRefundsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return 0, nil, utils.PrependMessageToError(err, "failed to find order refunds")
+	}
+
+	// amount already refunded from each order payment
+	refundedAmounts := make(map[uint]uint)
+	for _, refund := range refunds {
+		if refund.OrderPaymentID != 0 && refund.Status != commonConstant.RefundStatusFailed {
+			refundedAmounts[refund.OrderPaymentID] += refund.Amount
+		}
+	}
+
+	for _, orderPayment := range orderPayments {
+		if remainingAmount == 0 {
+			break
+		}
+		// wallet and cod payments have no gateway to refund, so they refund to wallet
+		paymentType := commonConstant.PaymentType(orderPayment.PaymentType)
+		if orderPayment.Status != string(commonConstant.PaymentStatusSucceeded) ||
+			paymentType == commonConstant.WalletPayment || paymentType == commonConstant.CodPayment {
+			continue
+		}
+		if refundedAmounts[orderPayment.OrderPaymentID] >= orderPayment.Amount {
+			continue
+		}
+
+		amount := orderPayment.Amount - refundedAmounts[orderPayment.OrderPaymentID]
+		if amount > remainingAmount {
+			amount = remainingAmount
+		}
+		remainingAmount -= amount
+
+		gatewayRefunds = append(gatewayRefunds, gatewayRefund{
+			refund: models.Refund{
+				ShopOrderID:    shopOrderID,
+				OrderReturnID:  orderRefund.OrderReturnID,
+				OrderCancelID:  orderRefund.OrderCancelID,
+				OrderPaymentID: orderPayment.OrderPaymentID,
+				RefundTo:       paymentType,
+				Amount:         amount,
+				Status:         commonConstant.RefundStatusPending,
+			},
+			orderPayment: orderPayment,
+		})
+	}
+
+	return walletAmount + remainingAmount, gatewayRefunds, nil
+}
+
+// To save the refunds of an order return or cancel, the wallet refund is credited on the same transaction
+// and the gateway refunds are saved as pending to process after the transaction
+func saveOrderRefunds(ctx context.Context, trxRepo interfaces.OrderRepository,
+	orderRefund orderRefund) ([]gatewayRefund, error) {
+
+	walletAmount, gatewayRefunds, err := planOrderRefunds(ctx, trxRepo, orderRefund)
+	if err != nil {
+		return nil, err
+	}
+
+	if walletAmount > 0 {
+		err = refundToWallet(ctx, trxRepo, orderRefund, walletAmount, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range gatewayRefunds {
+		gatewayRefunds[i].refund.ID, err = trxRepo.SaveRefund(ctx, gatewayRefunds[i].refund)
+		if err != nil {
+			return nil, utils.PrependMessageToError(err, "failed to save gateway refund")
+		}
+	}
+
+	return gatewayRefunds, nil
+}
+
+// To credit the refund amount to user wallet and save it as a processed refund
+func refundToWallet(ctx context.Context, trxRepo interfaces.OrderRepository, orderRefund orderRefund,
+	amount uint, note string) error {
+
+	err := creditUserWallet(ctx, trxRepo, orderRefund.walletEntry(amount, note))
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to credit refund amount to user wallet")
+	}
+
+	_, err = trxRepo.SaveRefund(ctx, models.Refund{
+		ShopOrderID:   orderRefund.ShopOrder.ID,
+		OrderReturnID: orderRefund.OrderReturnID,
+		OrderCancelID: orderRefund.OrderCancelID,
+		RefundTo:      commonConstant.WalletPayment,
+		Amount:        amount,
+		Status:        commonConstant.RefundStatusProcessed,
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to save wallet refund")
+	}
+
+	return nil
+}
+
+// To find the amount paid for an order which is not refunded yet
+// cod payment is not counted until it's collected
+func findRefundableAmount(ctx context.Context, repo interfaces.OrderRepository, shopOrderID uint) (uint, error) {
+
+	orderPayments, err := repo.FindOrderPaymentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to find order payments")
+	}
+
+	refunds, err := repo.FindRefundsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to find order refunds")
+	}
+
+	var paidAmount, refundedAmount uint
+	for _, orderPayment := range orderPayments {
+		if orderPayment.Status == string(commonConstant.PaymentStatusSucceeded) ||
+			orderPayment.Status == string(commonConstant.PaymentStatusRefunded) {
+			paidAmount += orderPayment.Amount
+		}
+	}
+	for _, refund := range refunds {
+		if refund.Status != commonConstant.RefundStatusFailed {
+			refundedAmount += refund.Amount
+		}
+	}
+
+	if refundedAmount >= paidAmount {
+		return 0, nil
+	}
+	return paidAmount - refundedAmount, nil
+}
+
+// To refund the pending refunds on its payment gateway
+func (c *OrderUseCase) processGatewayRefunds(ctx context.Context, gatewayRefunds []gatewayRefund) error {
+
+	for _, pendingRefund := range gatewayRefunds {
+		if _, err := c.processGatewayRefund(ctx, pendingRefund); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// To refund the pending refund on its payment gateway, sent is false when the result of gateway is not known.
+// a refund rejected by gateway is marked as failed and the amount is refunded to user wallet,
+// on other errors the refund may be made on gateway so it's kept pending for the webhook or reconcile
+func (c *OrderUseCase) processGatewayRefund(ctx context.Context, pendingRefund gatewayRefund) (sent bool, err error) {
+
+	refund := pendingRefund.refund
+	refundRes, err := c.refundOnGateway(ctx, pendingRefund)
+	if err == nil {
+		return true, c.updateGatewayRefund(ctx, refund, refundRes)
+	}
+
+	if !isGatewayRefundRejected(err) {
+		log.Printf("failed to refund %v on %s for refund_id %v, keeping it pending to reconcile: %v",
+			refund.Amount, refund.RefundTo, refund.ID, err)
+		return false, nil
+	}
+
+	log.Printf("refund %v on %s rejected for refund_id %v, refunding to wallet: %v",
+		refund.Amount, refund.RefundTo, refund.ID, err)
+
+	failureReason := err.Error()
+	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+		return failGatewayRefund(ctx, trxRepo, refund.ID, failureReason)
+	})
+	if err != nil {
+		return true, utils.PrependMessageToError(err, fmt.Sprintf("failed to refund to wallet for refund_id %v", refund.ID))
+	}
+
+	return true, nil
+}
+
+// To check the refund is surely not made on gateway, so it can be refunded to wallet
+func isGatewayRefundRejected(err error) bool {
+	return errors.Is(err, payment.ErrRefundRejected) ||
+		errors.Is(err, payment.ErrNotSupported) ||
+		errors.Is(err, payment.ErrGatewayNotRegistered)
+}
+
+func (c *OrderUseCase) refundOnGateway(ctx context.Context, pendingRefund gatewayRefund) (payment.RefundResponse, error) {
+
+	gateway, err := c.paymentGateways.Get(pendingRefund.refund.RefundTo)
+	if err != nil {
+		return payment.RefundResponse{}, err
+	}
+
+	return gateway.Refund(ctx, payment.RefundRequest{
+		GatewayOrderID:   pendingRefund.orderPayment.GatewayOrderID,
+		GatewayPaymentID: pendingRefund.orderPayment.GatewayPaymentID,
+		Amount:           pendingRefund.refund.Amount,
+		Reference:        fmt.Sprintf("refund_%d", pendingRefund.refund.ID),
+	})
+}
+
+// To update the refund as per the status of gateway, a refund still pending on gateway is reconciled later
+func (c *OrderUseCase) updateGatewayRefund(ctx context.Context, refund models.Refund,
+	refundRes payment.RefundResponse) error {
+
+	err := c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+
+		switch refundRes.Status {
+		case payment.RefundStatusProcessed:
+			return completeGatewayRefund(ctx, trxRepo, refund.ID, refundRes.GatewayRefundID)
+		case payment.RefundStatusFailed:
+			return failGatewayRefund(ctx, trxRepo, refund.ID, "refund failed on gateway")
+		}
+
+		err := trxRepo.UpdateRefund(ctx, refund.ID, commonConstant.RefundStatusPending, refundRes.GatewayRefundID, "")
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to update refund status")
+		}
+		return nil
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, fmt.Sprintf("failed to update gateway refund for refund_id %v", refund.ID))
+	}
+
+	log.Printf("successfully refunded %v on %s for shop order %v with status %s",
+		refund.Amount, refund.RefundTo, refund.ShopOrderID, refundRes.Status)
+	return nil
+}
+
+// To mark the pending refund as processed and mark the order payment as refunded when it's fully refunded
+// should call with a transaction repository
+func completeGatewayRefund(ctx context.Context, trxRepo interfaces.OrderRepository,
+	refundID uint, gatewayRefundID string) error {
+
+	refund, err := trxRepo.FindRefundByIDForUpdate(ctx, refundID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find refund")
+	}
+	// already completed or failed by webhook or reconcile
+	if refund.Status != commonConstant.RefundStatusPending {
+		return nil
+	}
+	if gatewayRefundID == "" {
+		gatewayRefundID = refund.GatewayRefundID
+	}
+
+	err = trxRepo.UpdateRefund(ctx, refund.ID, commonConstant.RefundStatusProcessed, gatewayRefundID, "")
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update refund status")
+	}
+
+	orderPayments, err := trxRepo.FindOrderPaymentsByShopOrderID(ctx, refund.ShopOrderID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find order payments")
+	}
+	refunds, err := trxRepo.FindRefundsByShopOrderID(ctx, refund.ShopOrderID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find order refunds")
+	}
+
+	var refundedAmount uint
+	for _, orderRefund := range refunds {
+		if orderRefund.OrderPaymentID == refund.OrderPaymentID && orderRefund.Status == commonConstant.RefundStatusProcessed {
+			refundedAmount += orderRefund.Amount
+		}
+	}
+	for _, orderPayment := range orderPayments {
+		if orderPayment.OrderPaymentID != refund.OrderPaymentID || refundedAmount < orderPayment.Amount {
+			continue
+		}
+		err = trxRepo.UpdateOrderPaymentStatus(ctx, refund.OrderPaymentID, commonConstant.PaymentStatusRefunded, "")
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to update order payment status")
+		}
+	}
+
+	return nil
+}
+
+// To mark the pending refund as failed and refund the amount to user wallet
+// should call with a transaction repository
+func failGatewayRefund(ctx context.Context, trxRepo interfaces.OrderRepository,
+	refundID uint, failureReason string) error {
+
+	refund, err := trxRepo.FindRefundByIDForUpdate(ctx, refundID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find refund")
+	}
+	// already completed or failed by webhook or reconcile
+	if refund.Status != commonConstant.RefundStatusPending {
+		return nil
+	}
+
+	err = trxRepo.UpdateRefund(ctx, refund.ID, commonConstant.RefundStatusFailed, refund.GatewayRefundID, failureReason)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update refund status")
+	}
+
+	shopOrder, err := trxRepo.FindShopOrderByShopOrderID(ctx, refund.ShopOrderID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find shop order")
+	}
+
+	orderRefund := orderRefund{
+		ShopOrder:     shopOrder,
+		OrderReturnID: refund.OrderReturnID,
+		OrderCancelID: refund.OrderCancelID,
+	}
+	note := fmt.Sprintf("refund of failed %s refund", refund.RefundTo)
+
+	return refundToWallet(ctx, trxRepo, orderRefund, refund.Amount, note)
+}
+
+// To reconcile the gateway refunds which are pending from before the given time
+// refund without gateway refund id is sent again with the same reference, gateway return the refund when it's made already.
+// others are completed or failed as per the status on gateway
+func (c *OrderUseCase) ReconcilePendingRefunds(ctx context.Context, pendingBefore time.Time) ([]uint, error) {
+
+	refunds, err := c.orderRepo.FindPendingGatewayRefunds(ctx, pendingBefore)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find pending gateway refunds")
+	}
+
+	var reconciledRefundIDs []uint
+	for _, refund := range refunds {
+		if err := ctx.Err(); err != nil {
+			return reconciledRefundIDs, err
+		}
+
+		reconciled, err := c.reconcileGatewayRefund(ctx, refund)
+		if err != nil {
+			log.Printf("failed to reconcile refund_id %v \nerror:%v", refund.ID, err)
+			continue
+		}
+		if reconciled {
+			reconciledRefundIDs = append(reconciledRefundIDs, refund.ID)
+		}
+	}
+
+	return reconciledRefundIDs, nil
+}
+
+func (c *OrderUseCase) reconcileGatewayRefund(ctx context.Context, refund models.Refund) (bool, error) {
+
+	// refund is saved but not sent to gateway
+	if refund.GatewayRefundID == "" {
+		orderPayments, err := c.orderRepo.FindOrderPaymentsByShopOrderID(ctx, refund.ShopOrderID)
+		if err != nil {
+			return false, utils.PrependMessageToError(err, "failed to find order payments")
+		}
+		for _, orderPayment := range orderPayments {
+			if orderPayment.OrderPaymentID == refund.OrderPaymentID {
+				sent, err := c.processGatewayRefund(ctx, gatewayRefund{refund: refund, orderPayment: orderPayment})
+				return sent && err == nil, err
+			}
+		}
+		return false, fmt.Errorf("order payment %v of refund not exist", refund.OrderPaymentID)
+	}
+
+	gateway, err := c.paymentGateways.Get(refund.RefundTo)
+	if err != nil {
+		return false, err
+	}
+	refundRes, err := gateway.FindRefund(ctx, refund.GatewayRefundID)
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to find refund on gateway")
+	}
+	if refundRes.Status == payment.RefundStatusPending {
+		return false, nil
+	}
+
+	return true, c.updateGatewayRefund(ctx, refund, refundRes)
+}
+
+// Find all refunds of a shop order
+func (c *OrderUseCase) FindOrderRefunds(ctx context.Context, shopOrderID uint) ([]models.Refund, error) {
+
+	refunds, err := c.orderRepo.FindRefundsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find order refunds")
+	}
+
+	return refunds, nil
+}
+
+// Find all refunds of a shop order which belongs to the user
+func (c *OrderUseCase) FindUserOrderRefunds(ctx context.Context, userID, shopOrderID uint) ([]models.Refund, error) {
+
+	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find shop order")
+	}
+	if shopOrder.ID == 0 || shopOrder.UserID != userID {
+		return nil, ErrShopOrderNotExist
+	}
+
+	return c.FindOrderRefunds(ctx, shopOrderID)
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"online-shop-2N/pkg/services/payment"
+	"online-shop-2N/pkg/utils"
+	"testing"
+)
+
+func TestIsGatewayRefundRejected(t *testing.T) {
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "rejected by gateway", err: utils.PrependMessageToError(payment.ErrRefundRejected, "failed to create refund"),
+			want: true},
+		{name: "not supported by gateway", err: payment.ErrNotSupported, want: true},
+		{name: "gateway not registered", err: fmt.Errorf("%w for payment type 'x'", payment.ErrGatewayNotRegistered),
+			want: true},
+		{name: "timeout", err: errors.New("context deadline exceeded")},
+		{name: "unknown error of gateway", err: utils.PrependMessageToError(errors.New("502 bad gateway"),
+			"failed to create refund")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			if got := isGatewayRefundRejected(test.err); got != test.want {
+				t.Fatalf("got rejected %v, want %v", got, test.want)
+			}
+		})
+	}
+}
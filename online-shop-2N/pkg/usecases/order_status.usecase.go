@@ -0,0 +1,190 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/utils"
+	"time"
+)
+
+// all allowed order status transitions and the actors who can make each of them
+// any status change which is not in this table is rejected
+var orderStatusTransitions = map[commonConstant.OrderStatusType]map[commonConstant.OrderStatusType][]commonConstant.OrderActorType{
+	commonConstant.StatusPaymentPending: {
+		commonConstant.StatusOrderPlaced:    {commonConstant.ActorUser, commonConstant.ActorSystem},
+		commonConstant.StatusPaymentFailed:  {commonConstant.ActorSystem},
+		commonConstant.StatusPaymentExpired: {commonConstant.ActorSystem},
+	},
+	commonConstant.StatusPaymentFailed: {
+		commonConstant.StatusOrderPlaced:    {commonConstant.ActorUser, commonConstant.ActorSystem},
+		commonConstant.StatusPaymentExpired: {commonConstant.ActorSystem},
+	},
+	commonConstant.StatusOrderPlaced: {
+		commonConstant.StatusOrderShipped:   {commonConstant.ActorAdmin},
+		commonConstant.StatusOrderDelivered: {commonConstant.ActorAdmin},
+		commonConstant.StatusOrderCancelled: {commonConstant.ActorUser, commonConstant.ActorAdmin},
+	},
+	commonConstant.StatusOrderShipped: {
+		commonConstant.StatusOutForDelivery: {commonConstant.ActorAdmin},
+		commonConstant.StatusOrderDelivered: {commonConstant.ActorAdmin},
+	},
+	commonConstant.StatusOutForDelivery: {
+		commonConstant.StatusOrderDelivered: {commonConstant.ActorAdmin},
+	},
+	commonConstant.StatusOrderDelivered: {
+		commonConstant.StatusReturnRequested: {commonConstant.ActorUser},
+	},
+	commonConstant.StatusReturnRequested: {
+		commonConstant.StatusReturnApproved:  {commonConstant.ActorAdmin},
+		commonConstant.StatusReturnCancelled: {commonConstant.ActorAdmin},
+	},
+	commonConstant.StatusReturnApproved: {
+		commonConstant.StatusOrderReturned:     {commonConstant.ActorAdmin},
+		commonConstant.StatusPartiallyReturned: {commonConstant.ActorAdmin},
+	},
+	// the items left on a partially returned order can return later
+	commonConstant.StatusPartiallyReturned: {
+		commonConstant.StatusReturnRequested: {commonConstant.ActorUser},
+	},
+}
+
+// statuses which only can change through the order return flow
+var orderReturnStatuses = map[commonConstant.OrderStatusType]bool{
+	commonConstant.StatusReturnRequested:   true,
+	commonConstant.StatusReturnApproved:    true,
+	commonConstant.StatusReturnCancelled:   true,
+	commonConstant.StatusOrderReturned:     true,
+	commonConstant.StatusPartiallyReturned: true,
+}
+
+// To check the actor is allowed to change the order status from one to another
+func isOrderStatusChangeAllowed(from, to commonConstant.OrderStatusType, actor commonConstant.OrderActorType) bool {
+
+	for _, allowedActor := range orderStatusTransitions[from][to] {
+		if allowedActor == actor {
+			return true
+		}
+	}
+	return false
+}
+
+type orderStatusChange struct {
+	ShopOrderID uint
+	ChangeTo    commonConstant.OrderStatusType
+	Actor       commonConstant.OrderActorType
+	ActorID     uint
+	Comment     string
+}
+
+// To change the status of a shop order through the order state machine and save it on history
+// should call with a transaction repository so the shop order row stay locked until the change is saved
+func changeOrderStatus(ctx context.Context, trxRepo interfaces.OrderRepository,
+	change orderStatusChange) (models.OrderStatus, error) {
+
+	shopOrder, err := trxRepo.FindShopOrderByShopOrderIDForUpdate(ctx, change.ShopOrderID)
+	if err != nil {
+		return models.OrderStatus{}, utils.PrependMessageToError(err, "failed to find shop order")
+	}
+	if shopOrder.ID == 0 {
+		return models.OrderStatus{}, ErrShopOrderNotExist
+	}
+
+	currentStatus, err := trxRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+	if err != nil {
+		return models.OrderStatus{}, utils.PrependMessageToError(err, "failed to find current order status")
+	}
+
+	if !isOrderStatusChangeAllowed(currentStatus.Status, change.ChangeTo, change.Actor) {
+		return models.OrderStatus{}, utils.PrependMessageToError(ErrOrderStatusChangeNotAllowed,
+			fmt.Sprintf("order status '%s' can't change to '%s' by %s", currentStatus.Status, change.ChangeTo, change.Actor))
+	}
+
+	statusToChange, err := trxRepo.FindOrderStatusByStatus(ctx, change.ChangeTo)
+	if err != nil {
+		return models.OrderStatus{}, utils.PrependMessageToError(err, "failed to find order status to change")
+	}
+
+	err = trxRepo.UpdateShopOrderOrderStatus(ctx, shopOrder.ID, statusToChange.ID)
+	if err != nil {
+		return models.OrderStatus{}, utils.PrependMessageToError(err, "failed to update order status")
+	}
+
+	err = trxRepo.SaveOrderStatusHistory(ctx, models.OrderStatusHistory{
+		ShopOrderID:  shopOrder.ID,
+		FromStatusID: currentStatus.ID,
+		ToStatusID:   statusToChange.ID,
+		Actor:        change.Actor,
+		ActorID:      change.ActorID,
+		Comment:      change.Comment,
+	})
+	if err != nil {
+		return models.OrderStatus{}, utils.PrependMessageToError(err, "failed to save order status history")
+	}
+
+	return statusToChange, nil
+}
+
+// To expire all orders which are waiting for payment from before the given time
+// each order change to payment expired, its items quantity restored to stock and
+// the wallet amount paid returned on the same transaction
+func (c *OrderUseCase) ExpirePendingPaymentOrders(ctx context.Context, expireBefore time.Time) (expiredOrderIDs []uint, err error) {
+
+	pendingStatus, err1 := c.orderRepo.FindOrderStatusByStatus(ctx, commonConstant.StatusPaymentPending)
+	failedStatus, err2 := c.orderRepo.FindOrderStatusByStatus(ctx, commonConstant.StatusPaymentFailed)
+	err = errors.Join(err1, err2)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find payment waiting order statuses")
+	}
+
+	shopOrderIDs, err := c.orderRepo.FindShopOrderIDsByStatusIDsBefore(ctx,
+		[]uint{pendingStatus.ID, failedStatus.ID}, expireBefore)
+	if err != nil {
+		return nil, utils.PrependMessageToError(err, "failed to find payment waiting orders")
+	}
+
+	var expireErrs error
+	for _, shopOrderID := range shopOrderIDs {
+
+		err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+
+			_, err := changeOrderStatus(ctx, trxRepo, orderStatusChange{
+				ShopOrderID: shopOrderID,
+				ChangeTo:    commonConstant.StatusPaymentExpired,
+				Actor:       commonConstant.ActorSystem,
+				Comment:     "payment not completed in time",
+			})
+			if err != nil {
+				return err
+			}
+
+			err = trxRepo.RestoreOrderLineQuantities(ctx, shopOrderID)
+			if err != nil {
+				return utils.PrependMessageToError(err, "failed to restore order items quantity")
+			}
+
+			// wallet amount paid for a split payment is returned to user wallet
+			shopOrder, err := trxRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
+			if err != nil {
+				return utils.PrependMessageToError(err, "failed to find shop order")
+			}
+			return refundOrderWalletPayments(ctx, trxRepo, shopOrder)
+		})
+
+		// order may paid after it's selected so the state machine not allow to expire it
+		if errors.Is(err, ErrOrderStatusChangeNotAllowed) {
+			continue
+		}
+		if err != nil {
+			expireErrs = errors.Join(expireErrs,
+				utils.PrependMessageToError(err, fmt.Sprintf("failed to expire shop order %v", shopOrderID)))
+			continue
+		}
+		expiredOrderIDs = append(expiredOrderIDs, shopOrderID)
+	}
+
+	return expiredOrderIDs, expireErrs
+}
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	commonConstant "online-shop-2N/pkg/common/constants"
+	"testing"
+)
+
+func TestIsOrderStatusChangeAllowed(t *testing.T) {
+
+	tests := []struct {
+		from  commonConstant.OrderStatusType
+		to    commonConstant.OrderStatusType
+		actor commonConstant.OrderActorType
+		want  bool
+	}{
+		// payment
+		{from: commonConstant.StatusPaymentPending, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorUser, want: true},
+		{from: commonConstant.StatusPaymentPending, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorSystem, want: true},
+		{from: commonConstant.StatusPaymentPending, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorAdmin, want: false},
+		{from: commonConstant.StatusPaymentPending, to: commonConstant.StatusPaymentExpired, actor: commonConstant.ActorSystem, want: true},
+		{from: commonConstant.StatusPaymentPending, to: commonConstant.StatusPaymentExpired, actor: commonConstant.ActorUser, want: false},
+		{from: commonConstant.StatusPaymentFailed, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorUser, want: true},
+		{from: commonConstant.StatusPaymentExpired, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorSystem, want: false},
+		{from: commonConstant.StatusPaymentPending, to: commonConstant.StatusOrderShipped, actor: commonConstant.ActorAdmin, want: false},
+
+		// delivery
+		{from: commonConstant.StatusOrderPlaced, to: commonConstant.StatusOrderShipped, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusOrderPlaced, to: commonConstant.StatusOrderShipped, actor: commonConstant.ActorUser, want: false},
+		{from: commonConstant.StatusOrderShipped, to: commonConstant.StatusOutForDelivery, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusOutForDelivery, to: commonConstant.StatusOrderDelivered, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusOrderDelivered, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorAdmin, want: false},
+		{from: commonConstant.StatusOrderShipped, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorAdmin, want: false},
+
+		// cancel
+		{from: commonConstant.StatusOrderPlaced, to: commonConstant.StatusOrderCancelled, actor: commonConstant.ActorUser, want: true},
+		{from: commonConstant.StatusOrderPlaced, to: commonConstant.StatusOrderCancelled, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusOrderPlaced, to: commonConstant.StatusOrderCancelled, actor: commonConstant.ActorSystem, want: false},
+		{from: commonConstant.StatusOrderShipped, to: commonConstant.StatusOrderCancelled, actor: commonConstant.ActorUser, want: false},
+		{from: commonConstant.StatusOrderCancelled, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorAdmin, want: false},
+
+		// return
+		{from: commonConstant.StatusOrderDelivered, to: commonConstant.StatusReturnRequested, actor: commonConstant.ActorUser, want: true},
+		{from: commonConstant.StatusOrderDelivered, to: commonConstant.StatusReturnRequested, actor: commonConstant.ActorAdmin, want: false},
+		{from: commonConstant.StatusOrderPlaced, to: commonConstant.StatusReturnRequested, actor: commonConstant.ActorUser, want: false},
+		{from: commonConstant.StatusReturnRequested, to: commonConstant.StatusReturnApproved, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusReturnRequested, to: commonConstant.StatusReturnApproved, actor: commonConstant.ActorUser, want: false},
+		{from: commonConstant.StatusReturnRequested, to: commonConstant.StatusReturnCancelled, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusReturnApproved, to: commonConstant.StatusOrderReturned, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusReturnApproved, to: commonConstant.StatusPartiallyReturned, actor: commonConstant.ActorAdmin, want: true},
+		{from: commonConstant.StatusPartiallyReturned, to: commonConstant.StatusReturnRequested, actor: commonConstant.ActorUser, want: true},
+		{from: commonConstant.StatusOrderReturned, to: commonConstant.StatusReturnRequested, actor: commonConstant.ActorUser, want: false},
+		{from: commonConstant.StatusReturnCancelled, to: commonConstant.StatusReturnRequested, actor: commonConstant.ActorUser, want: false},
+
+		// same status
+		{from: commonConstant.StatusOrderPlaced, to: commonConstant.StatusOrderPlaced, actor: commonConstant.ActorAdmin, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.from)+" to "+string(test.to)+" by "+string(test.actor), func(t *testing.T) {
+
+			got := isOrderStatusChangeAllowed(test.from, test.to, test.actor)
+			if got != test.want {
+				t.Fatalf("got allowed %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -2,46 +2,42 @@ package usecases
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"crypto/subtle"
-	"encoding/hex"
 	"errors"
+	"fmt"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/config"
 	"online-shop-2N/pkg/models"
 	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/payment"
 	service "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
 
 	commonConstant "online-shop-2N/pkg/common/constants"
-
-	"github.com/razorpay/razorpay-go"
-	"github.com/stripe/stripe-go/v72"
-	"github.com/stripe/stripe-go/v72/paymentintent"
 )
 
+// currency of all payments on gateways
+const paymentCurrency = "INR"
+
 type paymentUseCase struct {
 	paymentRepo interfaces.PaymentRepository
 	orderRepo   interfaces.OrderRepository
 	userRepo    interfaces.UserRepository
-	cartRepo    interfaces.CartRepository
-	couponRepo  interfaces.CouponRepository
 	config      config.Config
+
+	paymentGateways payment.Registry
 }
 
 func NewPaymentUseCase(paymentRepo interfaces.PaymentRepository,
 	orderRepo interfaces.OrderRepository, userRepo interfaces.UserRepository,
-	cartRepo interfaces.CartRepository, couponRepo interfaces.CouponRepository,
-	config config.Config) service.PaymentUseCase {
+	config config.Config, paymentGateways payment.Registry) service.PaymentUseCase {
 	return &paymentUseCase{
 		paymentRepo: paymentRepo,
 		orderRepo:   orderRepo,
 		userRepo:    userRepo,
-		cartRepo:    cartRepo,
-		couponRepo:  couponRepo,
 		config:      config,
+
+		paymentGateways: paymentGateways,
 	}
 }
 
@@ -66,230 +62,292 @@ func (c *paymentUseCase) UpdatePaymentMethod(ctx context.Context, paymentMethod
 // To create a razor pay order
 func (c *paymentUseCase) MakeRazorpayOrder(ctx context.Context, userID, shopOrderID uint) (responses.RazorpayOrder, error) {
 
-	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
-	if err != nil {
-		return responses.RazorpayOrder{}, utils.PrependMessageToError(err, "failed to find shop order from database")
-	}
-
-	// find the given payment
-	payment, err := c.paymentRepo.FindPaymentMethodByType(ctx, commonConstant.RazopayPayment)
-	if err != nil {
-		return responses.RazorpayOrder{}, utils.PrependMessageToError(err, "failed to find payment method details")
-	}
-	// payment is blocked
-	if payment.BlockStatus {
-		return responses.RazorpayOrder{}, ErrBlockedPayment
-	}
-
-	// check order total reached the payment method max amount
-	if shopOrder.OrderTotalPrice > payment.MaximumAmount {
-		return responses.RazorpayOrder{}, ErrPaymentAmountReachedMax
-	}
-
-	// get user details
-	userDetails, err := c.userRepo.FindUserByUserID(ctx, userID)
+	gatewayPayment, err := c.createGatewayPayment(ctx, userID, shopOrderID, commonConstant.RazopayPayment)
 	if err != nil {
 		return responses.RazorpayOrder{}, err
 	}
 
-	//razorpay amount is calculate on pisa for india so make the actual price into paisa
-	razorPayAmount := shopOrder.OrderTotalPrice * 100
-
-	razorpayKey := c.config.RazorPayKey
-	razorpaySecret := c.config.RazorPaySecret
-
-	client := razorpay.NewClient(razorpayKey, razorpaySecret)
-	// razor pay data for order
-	data := map[string]interface{}{
-		"amount":   razorPayAmount,
-		"currency": "INR",
-		"receipt":  "ecommerce purchase completed",
-	}
-
-	razorpayRes, err := client.Order.Create(data, nil)
-	if err != nil {
-		return responses.RazorpayOrder{}, utils.PrependMessageToError(err, "failed to create razorpay order")
-	}
-
-	razorpayOrderID := razorpayRes["id"]
-
 	razorPayOrder := responses.RazorpayOrder{
 		ShopOrderID:     shopOrderID,
-		AmountToPay:     shopOrder.OrderTotalPrice,
-		RazorpayAmount:  razorPayAmount,
-		RazorpayKey:     razorpayKey,
-		RazorpayOrderID: razorpayOrderID,
+		AmountToPay:     gatewayPayment.amountToPay,
+		RazorpayAmount:  gatewayPayment.intent.GatewayAmount,
+		RazorpayKey:     gatewayPayment.intent.PublicKey,
+		RazorpayOrderID: gatewayPayment.intent.GatewayOrderID,
 		UserID:          userID,
-		Email:           userDetails.Email,
-		Phone:           userDetails.Phone,
+		Email:           gatewayPayment.user.Email,
+		Phone:           gatewayPayment.user.Phone,
 	}
 
 	return razorPayOrder, nil
 }
 
-// To verify razor pay payment
-func (c *paymentUseCase) VerifyRazorPay(ctx context.Context, verifyReq requests.RazorpayVerify) error {
+// To verify razor pay payment and approve the order with the verified payment
+func (c *paymentUseCase) VerifyRazorPay(ctx context.Context, userID, shopOrderID uint,
+	verifyReq requests.RazorpayVerify) error {
 
-	razorpayKey := c.config.RazorPayKey
-	razorPaySecret := c.config.RazorPaySecret
+	return c.verifyGatewayPayment(ctx, userID, shopOrderID, commonConstant.RazopayPayment, payment.VerifyRequest{
+		GatewayOrderID:   verifyReq.OrderID,
+		GatewayPaymentID: verifyReq.PaymentID,
+		Signature:        verifyReq.Signature,
+	})
+}
 
-	data := verifyReq.OrderID + "|" + verifyReq.PaymentID
-	h := hmac.New(sha256.New, []byte(razorPaySecret))
-	_, err := h.Write([]byte(data))
+// To mak a stripe order
+func (c *paymentUseCase) MakeStripeOrder(ctx context.Context, userID, shopOrderID uint) (responses.StripeOrder, error) {
+
+	gatewayPayment, err := c.createGatewayPayment(ctx, userID, shopOrderID, commonConstant.StripePayment)
 	if err != nil {
-		return utils.PrependMessageToError(err, "failed to verify signature")
+		return responses.StripeOrder{}, err
 	}
 
-	sha := hex.EncodeToString(h.Sum(nil))
-	if subtle.ConstantTimeCompare([]byte(sha), []byte(verifyReq.Signature)) != 1 {
-		return errors.New("razorpay signature not match")
+	stripeOrder := responses.StripeOrder{
+		ShopOrderID:    shopOrderID,
+		AmountToPay:    gatewayPayment.amountToPay,
+		ClientSecret:   gatewayPayment.intent.ClientSecret,
+		PublishableKey: gatewayPayment.intent.PublicKey,
 	}
 
-	client := razorpay.NewClient(razorpayKey, razorPaySecret)
+	return stripeOrder, nil
+}
 
-	// fetch payment and verify
-	payment, err := client.Payment.Fetch(verifyReq.PaymentID, nil, nil)
+// To verify stripe payment and approve the order with the verified payment
+func (c *paymentUseCase) VerifyStripOrder(ctx context.Context, userID, shopOrderID uint, stripePaymentID string) error {
 
-	if err != nil {
-		return err
-	}
-
-	// check payment status
-	if payment["status"] != "captured" {
-		return ErrPaymentNotApproved
-	}
+	return c.verifyGatewayPayment(ctx, userID, shopOrderID, commonConstant.StripePayment, payment.VerifyRequest{
+		GatewayPaymentID: stripePaymentID,
+	})
+}
 
-	return nil
+type gatewayPayment struct {
+	shopOrder   models.ShopOrder
+	amountToPay uint
+	user        models.User
+	intent      payment.CreateIntentResponse
 }
 
-// To mak a stripe order
-func (c *paymentUseCase) MakeStripeOrder(ctx context.Context, userID, shopOrderID uint) (responses.StripeOrder, error) {
+// To create the payment intent on gateway of the payment type for the shop order and save it as order payment
+// the amount already paid from wallet is not included on the payment
+func (c *paymentUseCase) createGatewayPayment(ctx context.Context, userID, shopOrderID uint,
+	paymentType commonConstant.PaymentType) (gatewayPayment, error) {
 
 	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
 	if err != nil {
-		return responses.StripeOrder{}, utils.PrependMessageToError(err, "failed to find shop order from database")
+		return gatewayPayment{}, utils.PrependMessageToError(err, "failed to find shop order from database")
+	}
+	if shopOrder.ID == 0 || shopOrder.UserID != userID {
+		return gatewayPayment{}, ErrShopOrderNotExist
 	}
 
-	// find the given payment
-	payment, err := c.paymentRepo.FindPaymentMethodByType(ctx, commonConstant.RazopayPayment)
+	if err := checkShopOrderWaitingPayment(ctx, c.orderRepo, shopOrder); err != nil {
+		return gatewayPayment{}, err
+	}
+
+	amountToPay, err := findShopOrderAmountDue(ctx, c.orderRepo, shopOrder)
 	if err != nil {
-		return responses.StripeOrder{}, utils.PrependMessageToError(err, "failed to find payment method details")
+		return gatewayPayment{}, err
+	}
+	if amountToPay == 0 {
+		return gatewayPayment{}, ErrOrderNotWaitingPayment
 	}
 
+	// find the given payment
+	paymentMethod, err := c.paymentRepo.FindPaymentMethodByType(ctx, paymentType)
+	if err != nil {
+		return gatewayPayment{}, utils.PrependMessageToError(err, "failed to find payment method details")
+	}
 	// payment is blocked
-	if payment.BlockStatus {
-		return responses.StripeOrder{}, ErrBlockedPayment
+	if paymentMethod.BlockStatus {
+		return gatewayPayment{}, ErrBlockedPayment
 	}
 
 	// check order total reached the payment method max amount
-	if shopOrder.OrderTotalPrice > payment.MaximumAmount {
-		return responses.StripeOrder{}, ErrPaymentAmountReachedMax
+	if amountToPay > paymentMethod.MaximumAmount {
+		return gatewayPayment{}, ErrPaymentAmountReachedMax
 	}
 
+	// get user details
 	userDetails, err := c.userRepo.FindUserByUserID(ctx, userID)
 	if err != nil {
-		return responses.StripeOrder{}, err
+		return gatewayPayment{}, err
 	}
-	// set up the stripe secret key
-	stripe.Key = c.config.StripSecretKey
-
-	// create a payment param
-	params := &stripe.PaymentIntentParams{
 
-		Amount:       stripe.Int64(int64(shopOrder.OrderTotalPrice)),
-		ReceiptEmail: stripe.String(userDetails.Email),
-
-		Currency: stripe.String(string(stripe.CurrencyINR)),
-		AutomaticPaymentMethods: &stripe.PaymentIntentAutomaticPaymentMethodsParams{
-			Enabled: stripe.Bool(true),
-		},
+	gateway, err := c.paymentGateways.Get(paymentType)
+	if err != nil {
+		return gatewayPayment{}, err
 	}
 
-	// create new payment intent with this param
-	paymentIntent, err := paymentintent.New(params)
-
+	intent, err := gateway.CreateIntent(ctx, payment.CreateIntentRequest{
+		ShopOrderID: shopOrderID,
+		Amount:      amountToPay,
+		Currency:    paymentCurrency,
+		Email:       userDetails.Email,
+		Phone:       userDetails.Phone,
+	})
 	if err != nil {
-		return responses.StripeOrder{}, utils.PrependMessageToError(err, "failed to create new stripe payment intent")
+		return gatewayPayment{}, utils.PrependMessageToError(err, fmt.Sprintf("failed to create %s payment", paymentType))
 	}
 
-	clientSecret := paymentIntent.ClientSecret
-	stripePublishKey := c.config.StripPublishKey
-
-	stripeOrder := responses.StripeOrder{
-		ShopOrderID:    shopOrderID,
-		AmountToPay:    shopOrder.OrderTotalPrice,
-		ClientSecret:   clientSecret,
-		PublishableKey: stripePublishKey,
+	// save the gateway order id to find the order back from webhook events
+	_, err = c.orderRepo.SaveOrderPayment(ctx, models.OrderPayment{
+		ShopOrderID:     shopOrderID,
+		PaymentMethodID: paymentMethod.ID,
+		Amount:          amountToPay,
+		GatewayOrderID:  intent.GatewayOrderID,
+		Status:          commonConstant.PaymentStatusCreated,
+	})
+	if err != nil {
+		return gatewayPayment{}, utils.PrependMessageToError(err, fmt.Sprintf("failed to save %s order payment", paymentType))
 	}
 
-	return stripeOrder, nil
+	return gatewayPayment{
+		shopOrder:   shopOrder,
+		amountToPay: amountToPay,
+		user:        userDetails,
+		intent:      intent,
+	}, nil
 }
 
-func (c *paymentUseCase) VerifyStripOrder(ctx context.Context, stripePaymentID string) error {
-
-	stripe.Key = c.config.StripSecretKey
-
-	// get payment by payment_id
-	paymentIntent, err := paymentintent.Get(stripePaymentID, nil)
+// To verify the payment on gateway of the payment type and approve the order,
+// the order and amount of the payment are taken from gateway not from the user
+func (c *paymentUseCase) verifyGatewayPayment(ctx context.Context, userID, shopOrderID uint,
+	paymentType commonConstant.PaymentType, verifyReq payment.VerifyRequest) error {
 
+	gateway, err := c.paymentGateways.Get(paymentType)
 	if err != nil {
-		return utils.PrependMessageToError(err, "failed to get payment intent from stripe")
+		return err
 	}
 
-	// verify the payment intent
-	if paymentIntent.Status != stripe.PaymentIntentStatusSucceeded && paymentIntent.Status != stripe.PaymentIntentStatusRequiresCapture {
+	verified, err := gateway.Verify(ctx, verifyReq)
+	if errors.Is(err, payment.ErrPaymentNotApproved) {
 		return ErrPaymentNotApproved
 	}
+	if err != nil {
+		return utils.PrependMessageToError(err, fmt.Sprintf("failed to verify %s payment", paymentType))
+	}
 
-	return nil
+	return c.ApproveShopOrderAndClearCart(ctx, userID, requests.ApproveOrder{
+		ShopOrderID:      shopOrderID,
+		PaymentType:      paymentType,
+		PaymentReference: verified.GatewayOrderID,
+		GatewayPaymentID: verified.GatewayPaymentID,
+		PaidAmount:       verified.Amount,
+	})
 }
 
 // Approve the order and clear the cart (if coupon applied then change it used for this user)
 func (c *paymentUseCase) ApproveShopOrderAndClearCart(ctx context.Context, userID uint,
 	approveDetails requests.ApproveOrder) error {
 
-	// find the order status of order placed
-	orderPlacedStatus, err := c.orderRepo.FindOrderStatusByStatus(ctx, commonConstant.StatusOrderPlaced)
+	shopOrder, err := c.orderRepo.FindShopOrderByShopOrderID(ctx, approveDetails.ShopOrderID)
 	if err != nil {
-		return utils.PrependMessageToError(err, "failed to find order place status for shop order")
+		return utils.PrependMessageToError(err, "failed to find shop order from database")
+	}
+	if shopOrder.ID == 0 || shopOrder.UserID != userID {
+		return ErrShopOrderNotExist
 	}
+
+	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+
+		// lock the shop order so the order is not approved twice by verify request and webhook
+		shopOrder, err = trxRepo.FindShopOrderByShopOrderIDForUpdate(ctx, shopOrder.ID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find shop order from database")
+		}
+		return c.approveShopOrder(ctx, trxRepo, shopOrder, approveDetails, commonConstant.ActorUser, userID)
+	})
+	return err
+}
+
+// To change the order status to order placed, save the payment method and remove the ordered items from user cart
+// should call with a transaction repository
+func (c *paymentUseCase) approveShopOrder(ctx context.Context, trxRepo interfaces.OrderRepository,
+	shopOrder models.ShopOrder, approveDetails requests.ApproveOrder,
+	actor commonConstant.OrderActorType, actorID uint) error {
+
 	// find the payment method of given payment type
 	paymentMethod, err := c.paymentRepo.FindPaymentMethodByType(ctx, approveDetails.PaymentType)
 	if err != nil {
 		return utils.PrependMessageToError(err, "failed to find payment method from database")
 	}
 
-	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+	// the amount which is not paid yet, for cod it's collected on delivery
+	amountDue, err := findShopOrderAmountDue(ctx, trxRepo, shopOrder)
+	if err != nil {
+		return err
+	}
 
-		// change order status and save the payment method for the order
-		err = trxRepo.UpdateShopOrderStatusAndSavePaymentMethod(ctx, approveDetails.ShopOrderID,
-			orderPlacedStatus.ID, paymentMethod.ID)
+	// payment of gateway should be created for this order and paid the full amount due
+	var orderPayment models.OrderPayment
+	if isGatewayPayment(approveDetails.PaymentType) {
+		orderPayment, err = trxRepo.FindOrderPaymentByGatewayOrderID(ctx, approveDetails.PaymentReference)
 		if err != nil {
-			return utils.PrependMessageToError(err, "failed to update shop order status and payment method")
+			return utils.PrependMessageToError(err, "failed to find order payment")
 		}
-		// find the cart
-		cart, err := c.cartRepo.FindCartByUserID(ctx, userID)
-		if err != nil {
-			return utils.PrependMessageToError(err, "failed to find user cart from database")
+		if orderPayment.ID == 0 || orderPayment.ShopOrderID != shopOrder.ID ||
+			(orderPayment.Status != commonConstant.PaymentStatusCreated &&
+				orderPayment.Status != commonConstant.PaymentStatusFailed) {
+			return ErrInvalidOrderPayment
+		}
+		if approveDetails.PaidAmount != amountDue || orderPayment.Amount != amountDue {
+			return utils.AppendMessageToError(ErrPaymentAmountMismatch,
+				fmt.Sprintf("paid %v for amount due %v", approveDetails.PaidAmount, amountDue))
 		}
+	}
 
-		// if user applied a coupon on cart then save coupon uses for user
-		if cart.AppliedCouponID != 0 {
-			err = c.couponRepo.SaveCouponUses(ctx, models.CouponUses{
-				UserID:   userID,
-				CouponID: cart.AppliedCouponID,
-			})
+	// change order status to order placed and save the payment method for the order
+	_, err = changeOrderStatus(ctx, trxRepo, orderStatusChange{
+		ShopOrderID: shopOrder.ID,
+		ChangeTo:    commonConstant.StatusOrderPlaced,
+		Actor:       actor,
+		ActorID:     actorID,
+		Comment:     fmt.Sprintf("payment completed with %s", paymentMethod.Name),
+	})
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				return utils.PrependMessageToError(err, "failed to save coupon used for user")
-			}
+	err = trxRepo.UpdateShopOrderPaymentMethod(ctx, shopOrder.ID, paymentMethod.ID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update shop order payment method")
+	}
+
+	if approveDetails.PaymentType == commonConstant.CodPayment && amountDue > 0 {
+		_, err = trxRepo.SaveOrderPayment(ctx, models.OrderPayment{
+			ShopOrderID:     shopOrder.ID,
+			PaymentMethodID: paymentMethod.ID,
+			Amount:          amountDue,
+			Status:          commonConstant.PaymentStatusCreated,
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save cod order payment")
 		}
-		// delete the all cart item
-		err = c.cartRepo.DeleteAllCartItemsByCartID(ctx, cart.ID)
+	}
+
+	// if paid through a payment gateway then mark the gateway payment as succeeded
+	if orderPayment.ID != 0 {
+		err = trxRepo.UpdateOrderPaymentStatus(ctx, orderPayment.ID, commonConstant.PaymentStatusSucceeded,
+			approveDetails.GatewayPaymentID)
 		if err != nil {
-			return utils.PrependMessageToError(err, "failed to clear user cart")
+			return utils.PrependMessageToError(err, "failed to update order payment status")
 		}
-		return nil
-	})
-	return err
+	}
+
+	// if user applied a coupon on cart then save coupon uses for user,
+	// it's saved before removing the items as the coupon of cart is reset on change of items
+	err = trxRepo.SaveAppliedCouponUses(ctx, shopOrder.UserID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to save coupon used for user")
+	}
+
+	// remove the ordered items from cart on the same transaction
+	err = trxRepo.DeleteOrderedCartItems(ctx, shopOrder.UserID, shopOrder.ID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to clear ordered items from user cart")
+	}
+	return nil
+}
+
+// cod is collected on delivery and wallet is debited on our side, others are paid on a gateway
+func isGatewayPayment(paymentType commonConstant.PaymentType) bool {
+	return paymentType != commonConstant.CodPayment && paymentType != commonConstant.WalletPayment
 }
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/utils"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+)
+
+// To pay the order from user wallet, if the wallet balance is not enough for the order
+// then the balance is used and remaining amount should pay with another payment method
+func (c *paymentUseCase) PayWithWallet(ctx context.Context, userID, shopOrderID uint) (responses.WalletOrder, error) {
+
+	paymentMethod, err := c.paymentRepo.FindPaymentMethodByType(ctx, commonConstant.WalletPayment)
+	if err != nil {
+		return responses.WalletOrder{}, utils.PrependMessageToError(err, "failed to find payment method details")
+	}
+	// payment is blocked
+	if paymentMethod.BlockStatus {
+		return responses.WalletOrder{}, ErrBlockedPayment
+	}
+
+	var walletOrder responses.WalletOrder
+
+	err = c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+
+		// lock the order so the wallet can't use twice for the same amount
+		shopOrder, err := trxRepo.FindShopOrderByShopOrderIDForUpdate(ctx, shopOrderID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find shop order")
+		}
+		if shopOrder.ID == 0 || shopOrder.UserID != userID {
+			return ErrShopOrderNotExist
+		}
+
+		if err := checkShopOrderWaitingPayment(ctx, trxRepo, shopOrder); err != nil {
+			return err
+		}
+
+		amountDue, err := findShopOrderAmountDue(ctx, trxRepo, shopOrder)
+		if err != nil {
+			return err
+		}
+
+		wallet, err := findUserWalletForUpdate(ctx, trxRepo, userID)
+		if err != nil {
+			return err
+		}
+
+		// use the wallet balance up to the amount due and the wallet payment maximum amount
+		walletAmount := wallet.TotalAmount
+		if walletAmount > amountDue {
+			walletAmount = amountDue
+		}
+		if walletAmount > paymentMethod.MaximumAmount {
+			walletAmount = paymentMethod.MaximumAmount
+		}
+		if walletAmount == 0 {
+			return ErrEmptyWallet
+		}
+
+		err = debitUserWallet(ctx, trxRepo, walletEntry{
+			UserID:     userID,
+			Amount:     walletAmount,
+			SourceType: models.SourceOrder,
+			SourceID:   shopOrder.ID,
+			Note:       "order payment",
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = trxRepo.SaveOrderPayment(ctx, models.OrderPayment{
+			ShopOrderID:     shopOrder.ID,
+			PaymentMethodID: paymentMethod.ID,
+			Amount:          walletAmount,
+			Status:          commonConstant.PaymentStatusSucceeded,
+		})
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save wallet order payment")
+		}
+
+		// intents created before are for the old amount due, the amount to pay should be paid with a new intent
+		err = trxRepo.CancelUnpaidOrderPayments(ctx, shopOrder.ID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to cancel unpaid order payments")
+		}
+
+		walletOrder = responses.WalletOrder{
+			ShopOrderID:   shopOrder.ID,
+			WalletAmount:  walletAmount,
+			WalletBalance: wallet.TotalAmount - walletAmount,
+			AmountToPay:   amountDue - walletAmount,
+		}
+
+		// wallet paid the full amount
+		if walletOrder.AmountToPay == 0 {
+			approveDetails := requests.ApproveOrder{
+				ShopOrderID: shopOrder.ID,
+				PaymentType: commonConstant.WalletPayment,
+			}
+			err = c.approveShopOrder(ctx, trxRepo, shopOrder, approveDetails, commonConstant.ActorUser, userID)
+			if err != nil {
+				return err
+			}
+			walletOrder.OrderPlaced = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return responses.WalletOrder{}, utils.PrependMessageToError(err, "failed to pay order with wallet")
+	}
+
+	return walletOrder, nil
+}
+
+// To check the order is still waiting for payment
+func checkShopOrderWaitingPayment(ctx context.Context, repo interfaces.OrderRepository, shopOrder models.ShopOrder) error {
+
+	currentStatus, err := repo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find current order status")
+	}
+
+	if currentStatus.Status != commonConstant.StatusPaymentPending &&
+		currentStatus.Status != commonConstant.StatusPaymentFailed {
+		return ErrOrderNotWaitingPayment
+	}
+
+	return nil
+}
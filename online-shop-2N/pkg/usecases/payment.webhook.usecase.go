@@ -0,0 +1,335 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/payment"
+	"online-shop-2N/pkg/utils"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
+)
+
+// To verify and process the webhook event of the payment gateway
+func (c *paymentUseCase) HandlePaymentWebhook(ctx context.Context, paymentType commonConstant.PaymentType,
+	payload []byte, header http.Header) error {
+
+	gateway, err := c.paymentGateways.Get(paymentType)
+	if err != nil {
+		return err
+	}
+
+	event, err := gateway.ParseWebhook(ctx, payload, header)
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		return utils.AppendMessageToError(ErrInvalidWebhookSignature, err.Error())
+	}
+	if errors.Is(err, payment.ErrInvalidWebhookEvent) {
+		return utils.AppendMessageToError(ErrInvalidWebhookEvent, err.Error())
+	}
+	if err != nil {
+		return utils.PrependMessageToError(err, fmt.Sprintf("failed to parse %s webhook event", paymentType))
+	}
+
+	webhookEvent := models.WebhookEvent{
+		Provider:  paymentType,
+		EventID:   event.EventID,
+		EventType: event.GatewayEventType,
+	}
+
+	switch event.Type {
+	case payment.WebhookPaymentSucceeded, payment.WebhookPaymentFailed:
+		// payment without gateway order is not created by us
+		if event.GatewayOrderID == "" && event.ShopOrderID == 0 {
+			log.Printf("skipped %s webhook event %s of payment %s without order",
+				paymentType, event.EventID, event.GatewayPaymentID)
+			return nil
+		}
+
+		return c.processWebhookEvent(ctx, webhookEvent, func(trxRepo interfaces.OrderRepository) error {
+
+			orderPayment, shopOrder, err := c.findWebhookOrderPayment(ctx, trxRepo, event.GatewayOrderID, event.ShopOrderID)
+			if err != nil {
+				return err
+			}
+
+			if event.Type == payment.WebhookPaymentSucceeded {
+				return c.webhookPaymentSucceeded(ctx, trxRepo, orderPayment, shopOrder,
+					paymentType, event.GatewayPaymentID, event.Amount)
+			}
+			return c.webhookPaymentFailed(ctx, trxRepo, orderPayment, shopOrder,
+				paymentType, event.GatewayPaymentID, event.FailureReason)
+		})
+
+	case payment.WebhookPaymentRefunded:
+		return c.processWebhookEvent(ctx, webhookEvent, func(trxRepo interfaces.OrderRepository) error {
+			return c.webhookPaymentRefunded(ctx, trxRepo, paymentType, event)
+		})
+
+	case payment.WebhookRefundFailed:
+		return c.processWebhookEvent(ctx, webhookEvent, func(trxRepo interfaces.OrderRepository) error {
+			return c.webhookRefundFailed(ctx, trxRepo, paymentType, event)
+		})
+	}
+
+	log.Printf("skipped %s webhook event %s of type %s", paymentType, event.EventID, event.GatewayEventType)
+	return nil
+}
+
+// To save the webhook event and process it on the same transaction
+// an event which already saved is not processed again
+func (c *paymentUseCase) processWebhookEvent(ctx context.Context, webhookEvent models.WebhookEvent,
+	process func(trxRepo interfaces.OrderRepository) error) error {
+
+	err := c.orderRepo.Transaction(func(trxRepo interfaces.OrderRepository) error {
+
+		saved, err := trxRepo.SaveWebhookEvent(ctx, webhookEvent)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to save webhook event")
+		}
+		if !saved {
+			log.Printf("%s webhook event %s already processed", webhookEvent.Provider, webhookEvent.EventID)
+			return nil
+		}
+
+		return process(trxRepo)
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, fmt.Sprintf("failed to process %s webhook event %s",
+			webhookEvent.Provider, webhookEvent.EventID))
+	}
+
+	return nil
+}
+
+// To find the order payment using the order/intent id of payment gateway and its shop order
+// payments created before saving order payments can be found with the shop order id given by gateway
+func (c *paymentUseCase) findWebhookOrderPayment(ctx context.Context, trxRepo interfaces.OrderRepository,
+	gatewayOrderID string, gatewayShopOrderID uint) (models.OrderPayment, models.ShopOrder, error) {
+
+	var (
+		orderPayment models.OrderPayment
+		err          error
+	)
+	if gatewayOrderID != "" {
+		orderPayment, err = trxRepo.FindOrderPaymentByGatewayOrderID(ctx, gatewayOrderID)
+		if err != nil {
+			return models.OrderPayment{}, models.ShopOrder{}, utils.PrependMessageToError(err, "failed to find order payment")
+		}
+	}
+
+	shopOrderID := orderPayment.ShopOrderID
+	if shopOrderID == 0 {
+		shopOrderID = gatewayShopOrderID
+	}
+	if shopOrderID == 0 {
+		return models.OrderPayment{}, models.ShopOrder{}, fmt.Errorf("no shop order found for gateway order %s", gatewayOrderID)
+	}
+
+	shopOrder, err := trxRepo.FindShopOrderByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return models.OrderPayment{}, models.ShopOrder{}, utils.PrependMessageToError(err, "failed to find shop order")
+	}
+	if shopOrder.ID == 0 {
+		return models.OrderPayment{}, models.ShopOrder{}, ErrShopOrderNotExist
+	}
+
+	return orderPayment, shopOrder, nil
+}
+
+// To approve the order with the payment if it's still waiting for payment otherwise mark the order payment as succeeded.
+// a payment which didn't pay the order (order expired, intent cancelled or paid twice) is refunded
+func (c *paymentUseCase) webhookPaymentSucceeded(ctx context.Context, trxRepo interfaces.OrderRepository,
+	orderPayment models.OrderPayment, shopOrder models.ShopOrder,
+	paymentType commonConstant.PaymentType, gatewayPaymentID string, paidAmount uint) error {
+
+	shopOrder, err := trxRepo.FindShopOrderByShopOrderIDForUpdate(ctx, shopOrder.ID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find shop order")
+	}
+	// order payment is found again after the lock so the events of the same payment see the changes of each other
+	if orderPayment.ID != 0 {
+		orderPayment, err = trxRepo.FindOrderPaymentByGatewayOrderID(ctx, orderPayment.GatewayOrderID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find order payment")
+		}
+	}
+
+	currentStatus, err := trxRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find current order status")
+	}
+
+	if currentStatus.Status == commonConstant.StatusPaymentPending ||
+		currentStatus.Status == commonConstant.StatusPaymentFailed {
+
+		approveDetails := requests.ApproveOrder{
+			ShopOrderID:      shopOrder.ID,
+			PaymentType:      paymentType,
+			PaymentReference: orderPayment.GatewayOrderID,
+			GatewayPaymentID: gatewayPaymentID,
+			PaidAmount:       paidAmount,
+		}
+
+		err = c.approveShopOrder(ctx, trxRepo, shopOrder, approveDetails, commonConstant.ActorSystem, 0)
+		if err == nil || (!errors.Is(err, ErrInvalidOrderPayment) && !errors.Is(err, ErrPaymentAmountMismatch)) {
+			return err
+		}
+
+		log.Printf("%s payment %s of %v can't approve shop order %v \nerror:%v",
+			paymentType, gatewayPaymentID, paidAmount, shopOrder.ID, err)
+	} else {
+		// order already approved by user verify request or it's not waiting for payment
+		log.Printf("%s payment %s succeeded for shop order %v with status '%s'",
+			paymentType, gatewayPaymentID, shopOrder.ID, currentStatus.Status)
+	}
+
+	if orderPayment.ID == 0 {
+		log.Printf("%s payment %s of %v succeeded for shop order %v without order payment, needs manual handling",
+			paymentType, gatewayPaymentID, paidAmount, shopOrder.ID)
+		return nil
+	}
+	// payment already approved the order or succeeded by an earlier event, or it's refunded
+	if orderPayment.Status == commonConstant.PaymentStatusSucceeded ||
+		orderPayment.Status == commonConstant.PaymentStatusRefunded {
+		return nil
+	}
+
+	// payment is marked as refunded with the pending refund so it's not counted as paid for the amount due of order
+	err = trxRepo.UpdateOrderPaymentStatus(ctx, orderPayment.ID, commonConstant.PaymentStatusRefunded, gatewayPaymentID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update order payment status")
+	}
+
+	return saveUnusedPaymentRefund(ctx, trxRepo, orderPayment, paymentType, gatewayPaymentID, paidAmount,
+		string(currentStatus.Status))
+}
+
+// To save a pending gateway refund for a payment succeeded without paying its order,
+// the refund is sent to gateway by the refund reconcile and refunded to wallet when it fails on gateway
+func saveUnusedPaymentRefund(ctx context.Context, trxRepo interfaces.OrderRepository,
+	orderPayment models.OrderPayment, paymentType commonConstant.PaymentType,
+	gatewayPaymentID string, paidAmount uint, orderStatus string) error {
+
+	amount := paidAmount
+	if amount == 0 {
+		amount = orderPayment.Amount
+	}
+
+	refundID, err := trxRepo.SaveRefund(ctx, models.Refund{
+		ShopOrderID:    orderPayment.ShopOrderID,
+		OrderPaymentID: orderPayment.ID,
+		RefundTo:       paymentType,
+		Amount:         amount,
+		Status:         commonConstant.RefundStatusPending,
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to save refund of payment not used for order")
+	}
+
+	log.Printf("%s payment %s of %v with status '%s' not used for shop order %v with status '%s', saved refund_id %v to refund it",
+		paymentType, gatewayPaymentID, amount, orderPayment.Status, orderPayment.ShopOrderID, orderStatus, refundID)
+	return nil
+}
+
+// To mark the order payment as failed and change the order to payment failed if it's waiting for payment
+func (c *paymentUseCase) webhookPaymentFailed(ctx context.Context, trxRepo interfaces.OrderRepository,
+	orderPayment models.OrderPayment, shopOrder models.ShopOrder,
+	paymentType commonConstant.PaymentType, gatewayPaymentID, reason string) error {
+
+	if orderPayment.ID != 0 {
+		err := trxRepo.UpdateOrderPaymentStatus(ctx, orderPayment.ID, commonConstant.PaymentStatusFailed, gatewayPaymentID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to update order payment status")
+		}
+	}
+
+	currentStatus, err := trxRepo.FindOrderStatusByID(ctx, shopOrder.OrderStatusID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find current order status")
+	}
+	// only the order waiting for payment change to payment failed
+	if currentStatus.Status != commonConstant.StatusPaymentPending {
+		return nil
+	}
+
+	comment := fmt.Sprintf("%s payment failed", paymentType)
+	if reason != "" {
+		comment = fmt.Sprintf("%s: %s", comment, reason)
+	}
+
+	_, err = changeOrderStatus(ctx, trxRepo, orderStatusChange{
+		ShopOrderID: shopOrder.ID,
+		ChangeTo:    commonConstant.StatusPaymentFailed,
+		Actor:       commonConstant.ActorSystem,
+		Comment:     comment,
+	})
+	return err
+}
+
+// To complete our refund of the event and mark the order payment as refunded when the full payment is refunded on gateway
+func (c *paymentUseCase) webhookPaymentRefunded(ctx context.Context, trxRepo interfaces.OrderRepository,
+	paymentType commonConstant.PaymentType, event payment.WebhookEvent) error {
+
+	if event.GatewayRefundID != "" {
+		refund, err := trxRepo.FindRefundByGatewayRefundID(ctx, event.GatewayRefundID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to find refund")
+		}
+		if refund.ID != 0 {
+			err = completeGatewayRefund(ctx, trxRepo, refund.ID, event.GatewayRefundID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var (
+		orderPayment models.OrderPayment
+		err          error
+	)
+	// refund event may not contain the gateway order so find it with the gateway payment id
+	if event.GatewayOrderID != "" {
+		orderPayment, err = trxRepo.FindOrderPaymentByGatewayOrderID(ctx, event.GatewayOrderID)
+	} else {
+		orderPayment, err = trxRepo.FindOrderPaymentByGatewayPaymentID(ctx, event.GatewayPaymentID)
+	}
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find order payment")
+	}
+	if orderPayment.ID == 0 {
+		log.Printf("skipped %s refund of payment %s without order payment", paymentType, event.GatewayPaymentID)
+		return nil
+	}
+
+	// partial refund keep the payment as succeeded
+	if !event.FullyRefunded {
+		log.Printf("%s payment %s partially refunded for shop order %v",
+			paymentType, event.GatewayPaymentID, orderPayment.ShopOrderID)
+		return nil
+	}
+
+	return trxRepo.UpdateOrderPaymentStatus(ctx, orderPayment.ID, commonConstant.PaymentStatusRefunded, event.GatewayPaymentID)
+}
+
+// To fail our refund of the event and refund the amount to user wallet
+func (c *paymentUseCase) webhookRefundFailed(ctx context.Context, trxRepo interfaces.OrderRepository,
+	paymentType commonConstant.PaymentType, event payment.WebhookEvent) error {
+
+	refund, err := trxRepo.FindRefundByGatewayRefundID(ctx, event.GatewayRefundID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find refund")
+	}
+	if event.GatewayRefundID == "" || refund.ID == 0 {
+		log.Printf("skipped failed %s refund %s without refund", paymentType, event.GatewayRefundID)
+		return nil
+	}
+
+	log.Printf("%s refund %s failed for shop order %v, refunding to wallet", paymentType, event.GatewayRefundID,
+		refund.ShopOrderID)
+	return failGatewayRefund(ctx, trxRepo, refund.ID, fmt.Sprintf("%s refund failed", paymentType))
+}
@@ -5,12 +5,19 @@ import (
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 	"online-shop-2N/pkg/services/cloud"
 	service "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
 )
 
+// upper limits of the price ranges on facets, the last range is above the last limit
+var productPriceBuckets = []uint{500, 1000, 2000, 5000, 10000, 20000, 50000}
+
+// product item is shown as low stock when the quantity in stock is at or below this
+const productItemLowStockQty uint = 5
+
 type productUseCase struct {
 	productRepo  interfaces.ProductRepository
 	cloudService cloud.CloudService
@@ -96,14 +103,28 @@ func (c *productUseCase) FindAllVariationsAndItsValues(ctx context.Context, cate
 }
 
 // to get all product
-func (c *productUseCase) FindAllProducts(ctx context.Context, pagination requests.Pagination) ([]responses.Product, error) {
-	products, err := c.productRepo.FindAllProducts(ctx, pagination)
+func (c *productUseCase) FindAllProducts(ctx context.Context, filter requests.ProductFilter,
+	page pagination.Request) ([]responses.Product, pagination.Meta, error) {
+
+	// sort by relevance only when searching, otherwise the newest products first
+	switch {
+	case filter.Sort == "" && filter.Query != "":
+		filter.Sort = requests.ProductSortRelevance
+	case filter.Sort == "", filter.Sort == requests.ProductSortRelevance && filter.Query == "":
+		filter.Sort = requests.ProductSortNewest
+	}
+
+	products, meta, err := c.productRepo.FindAllProducts(ctx, filter, page)
 	if err != nil {
-		return nil, utils.PrependMessageToError(err, "failed to get product details from database")
+		return nil, meta, utils.PrependMessageToError(err, "failed to get product details from database")
 	}
 
 	for i := range products {
 
+		// products imported from catalog have no image until it's added
+		if products[i].Image == "" {
+			continue
+		}
 		url, err := c.cloudService.GetFileUrl(ctx, products[i].Image)
 		if err != nil {
 			continue
@@ -111,7 +132,126 @@ func (c *productUseCase) FindAllProducts(ctx context.Context, pagination request
 		products[i].Image = url
 	}
 
-	return products, nil
+	return products, meta, nil
+}
+
+// To find the count of products for each facet on the search and filters
+func (c *productUseCase) FindProductFacets(ctx context.Context,
+	filter requests.ProductFilter) (responses.ProductFacets, error) {
+
+	facets, err := c.productRepo.FindProductFacets(ctx, filter, productPriceBuckets)
+	if err != nil {
+		return responses.ProductFacets{}, utils.PrependMessageToError(err, "failed to find product facets from database")
+	}
+
+	return facets, nil
+}
+
+// To find the product detail with its brand, category path, items with variation values and images
+// and the offer running on it, images are given as pre signed urls
+func (c *productUseCase) FindProductDetail(ctx context.Context, productID uint) (responses.ProductDetail, error) {
+
+	product, err := c.productRepo.FindProductDetailByID(ctx, productID)
+	if err != nil {
+		return product, utils.PrependMessageToError(err, "failed to find product from database")
+	}
+	if product.ID == 0 {
+		return product, ErrProductNotExist
+	}
+
+	if product.MainCategoryID != 0 {
+		product.CategoryPath = append(product.CategoryPath, responses.ProductCategory{
+			ID:   product.MainCategoryID,
+			Name: product.MainCategoryName,
+		})
+	}
+	product.CategoryPath = append(product.CategoryPath, responses.ProductCategory{
+		ID:   product.CategoryID,
+		Name: product.CategoryName,
+	})
+
+	if product.Image != "" {
+		product.Image, err = c.cloudService.GetFileUrl(ctx, product.Image)
+		if err != nil {
+			return product, utils.PrependMessageToError(err, "failed to get image url of product")
+		}
+	}
+
+	items, err := c.productRepo.FindAllProductItemDetails(ctx, productID)
+	if err != nil {
+		return product, utils.PrependMessageToError(err, "failed to find product items")
+	}
+
+	variationValues, err := c.productRepo.FindAllVariationValuesOfProduct(ctx, productID)
+	if err != nil {
+		return product, utils.PrependMessageToError(err, "failed to find variation values of product items")
+	}
+
+	images, err := c.productRepo.FindAllProductItemImagesOfProduct(ctx, productID)
+	if err != nil {
+		return product, utils.PrependMessageToError(err, "failed to find images of product items")
+	}
+
+	itemIndex := make(map[uint]int, len(items))
+	for i := range items {
+		itemIndex[items[i].ID] = i
+		items[i].VariationValues = []responses.ProductVariationValue{}
+		items[i].Images = []string{}
+		items[i].StockStatus = stockStatusOf(items[i].QtyInStock)
+	}
+
+	for _, value := range variationValues {
+		i := itemIndex[value.ProductItemID]
+		items[i].VariationValues = append(items[i].VariationValues, value.ProductVariationValue)
+	}
+
+	for _, image := range images {
+		url, err := c.cloudService.GetFileUrl(ctx, image.Image)
+		if err != nil {
+			return product, utils.PrependMessageToError(err, "failed to get image url of product item")
+		}
+		i := itemIndex[image.ProductItemID]
+		items[i].Images = append(items[i].Images, url)
+	}
+
+	product.Items = items
+	product.StockStatus = productStockStatus(items)
+
+	offer, err := c.productRepo.FindActiveOfferOfProduct(ctx, productID)
+	if err != nil {
+		return product, utils.PrependMessageToError(err, "failed to find offer of product")
+	}
+	if offer.OfferID != 0 {
+		product.Offer = &offer
+	}
+
+	return product, nil
+}
+
+func stockStatusOf(qtyInStock uint) responses.StockStatus {
+	switch {
+	case qtyInStock == 0:
+		return responses.OutOfStock
+	case qtyInStock <= productItemLowStockQty:
+		return responses.LowStock
+	default:
+		return responses.InStock
+	}
+}
+
+// product is in stock when any of its items are in stock, otherwise low stock when any of them are low
+func productStockStatus(items []responses.ProductItemDetail) responses.StockStatus {
+
+	status := responses.OutOfStock
+	for _, item := range items {
+		switch item.StockStatus {
+		case responses.InStock:
+			return responses.InStock
+		case responses.LowStock:
+			status = responses.LowStock
+		}
+	}
+	return status
 }
 
 // to add new product
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/denylist"
+	"online-shop-2N/pkg/utils"
+	"time"
+
+	token "online-shop-2N/pkg/services/tokens"
+)
+
+// To block the refresh tokens of the session and deny its access tokens which are not expired yet
+// the deny is kept only for the access token duration as a refresh token is never accepted as an access token
+func revokeSession(ctx context.Context, authRepo interfaces.AuthRepository, tokenDenylist denylist.Denylist,
+	userType token.UserType, userID uint, sessionID string) (bool, error) {
+
+	blocked, err := authRepo.BlockRefreshSession(ctx, string(userType), userID, sessionID)
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to block refresh session")
+	}
+	if !blocked {
+		return false, nil
+	}
+
+	err = tokenDenylist.Deny(ctx, sessionID, time.Now().Add(AccessTokenDuration))
+	if err != nil {
+		return false, utils.PrependMessageToError(err, "failed to deny access tokens of session")
+	}
+
+	return true, nil
+}
+
+// To block the refresh tokens of all sessions of the user and deny the access tokens which are not expired yet
+func revokeAllSessions(ctx context.Context, authRepo interfaces.AuthRepository, tokenDenylist denylist.Denylist,
+	userType token.UserType, userID uint) error {
+
+	// access tokens are only given with refresh tokens so sessions refreshed before its duration have no valid access token
+	sessionIDs, err := authRepo.FindSessionIDsRefreshedAfter(ctx, string(userType), userID,
+		time.Now().Add(-AccessTokenDuration))
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find recently refreshed sessions")
+	}
+
+	err = authRepo.BlockAllRefreshSessions(ctx, string(userType), userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to block all refresh sessions")
+	}
+
+	for _, sessionID := range sessionIDs {
+		err = tokenDenylist.Deny(ctx, sessionID, time.Now().Add(AccessTokenDuration))
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to deny access tokens of session")
+		}
+	}
+
+	return nil
+}
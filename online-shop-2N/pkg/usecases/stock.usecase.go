@@ -5,6 +5,7 @@ import (
 	"log"
 	"online-shop-2N/pkg/api/handlers/requests"
 	"online-shop-2N/pkg/api/handlers/responses"
+	"online-shop-2N/pkg/pagination"
 	"online-shop-2N/pkg/repositories/interfaces"
 	service "online-shop-2N/pkg/usecases/interfaces"
 )
@@ -20,14 +21,15 @@ func NewStockUseCase(stockRepo interfaces.StockRepository) service.StockUseCase
 	}
 }
 
-func (c *stockUseCase) GetAllStockDetails(ctx context.Context, pagination requests.Pagination) (stocks []responses.Stock, err error) {
-	stocks, err = c.stockRepo.FindAll(ctx, pagination)
+func (c *stockUseCase) GetAllStockDetails(ctx context.Context,
+	page pagination.Request) (stocks []responses.Stock, meta pagination.Meta, err error) {
+	stocks, meta, err = c.stockRepo.FindAll(ctx, page)
 
 	if err != nil {
-		return stocks, err
+		return stocks, meta, err
 	}
 	log.Printf("successfully got stock details")
-	return stocks, nil
+	return stocks, meta, nil
 }
 
 func (c *stockUseCase) UpdateStockBySKU(ctx context.Context, updateDetails requests.UpdateStock) error {
@@ -9,25 +9,33 @@ import (
 	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
 	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/services/denylist"
 	service "online-shop-2N/pkg/usecases/interfaces"
 	"online-shop-2N/pkg/utils"
 
+	token "online-shop-2N/pkg/services/tokens"
+
 	"github.com/jinzhu/copier"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userUserCase struct {
-	userRepo    interfaces.UserRepository
-	cartRepo    interfaces.CartRepository
-	productRepo interfaces.ProductRepository
+	userRepo      interfaces.UserRepository
+	cartRepo      interfaces.CartRepository
+	productRepo   interfaces.ProductRepository
+	authRepo      interfaces.AuthRepository
+	tokenDenylist denylist.Denylist
 }
 
 func NewUserUseCase(userRepo interfaces.UserRepository, cartRepo interfaces.CartRepository,
-	productRepo interfaces.ProductRepository) service.UserUseCase {
+	productRepo interfaces.ProductRepository, authRepo interfaces.AuthRepository,
+	tokenDenylist denylist.Denylist) service.UserUseCase {
 	return &userUserCase{
-		userRepo:    userRepo,
-		cartRepo:    cartRepo,
-		productRepo: productRepo,
+		userRepo:      userRepo,
+		cartRepo:      cartRepo,
+		productRepo:   productRepo,
+		authRepo:      authRepo,
+		tokenDenylist: tokenDenylist,
 	}
 }
 
@@ -69,6 +77,45 @@ func (c *userUserCase) UpdateProfile(ctx context.Context, user models.User) erro
 		return err
 	}
 
+	// password changed so logout from all devices which signed in with the old password
+	if user.Password != "" {
+		err = revokeAllSessions(ctx, c.authRepo, c.tokenDenylist, token.User, user.ID)
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to revoke sessions after password change")
+		}
+	}
+
+	return nil
+}
+
+// To change the password after verifying the current password and logout from all devices
+func (c *userUserCase) ChangePassword(ctx context.Context, userID uint, passwordDetails requests.ChangePassword) error {
+
+	user, err := c.userRepo.FindUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find user details")
+	}
+
+	err = utils.ComparePasswordWithHashedPassword(passwordDetails.CurrentPassword, user.Password)
+	if err != nil {
+		return ErrWrongPassword
+	}
+
+	hashPass, err := utils.GenerateHashFromPassword(passwordDetails.Password)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to hash the password")
+	}
+
+	err = c.userRepo.UpdatePassword(ctx, userID, hashPass)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update password")
+	}
+
+	err = revokeAllSessions(ctx, c.authRepo, c.tokenDenylist, token.User, userID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to revoke sessions after password change")
+	}
+
 	return nil
 }
 
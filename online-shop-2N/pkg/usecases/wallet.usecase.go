@@ -4,21 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"online-shop-2N/pkg/api/handlers/requests"
+	"online-shop-2N/pkg/api/handlers/responses"
 	"online-shop-2N/pkg/models"
+	"online-shop-2N/pkg/pagination"
+	"online-shop-2N/pkg/repositories/interfaces"
+	"online-shop-2N/pkg/utils"
+
+	commonConstant "online-shop-2N/pkg/common/constants"
 )
 
 // FindUserWallet implements interfaces.OrderUseCase.
 func (c *OrderUseCase) FindUserWallet(ctx context.Context, userID uint) (wallet models.Wallet, err error) {
-	// first find the user wallet
-	wallet, err = c.orderRepo.FindWalletByUserID(ctx, userID)
+	// find the user wallet, if user have no wallet then create a wallet for user
+	wallet, err = findUserWallet(ctx, c.orderRepo, userID)
 	if err != nil {
 		return wallet, err
-	} else if wallet.ID == 0 { // if user have no wallet then create a wallet for user
-		wallet.ID, err = c.orderRepo.SaveWallet(ctx, userID)
-		if err != nil {
-			return wallet, err
-		}
 	}
 
 	log.Printf("successfully got user wallet with wallet_id %v for user user_id %v", wallet.ID, userID)
@@ -26,23 +26,209 @@ func (c *OrderUseCase) FindUserWallet(ctx context.Context, userID uint) (wallet
 }
 
 // FindUserWalletTransactions implements interfaces.OrderUseCase.
-func (c *OrderUseCase) FindUserWalletTransactions(ctx context.Context, userID uint, pagination requests.Pagination) (transactions []models.Transaction, err error) {
+func (c *OrderUseCase) FindUserWalletTransactions(ctx context.Context, userID uint,
+	page pagination.Request) (transactions []models.Transaction, meta pagination.Meta, err error) {
 	// first find the user wallet
 	wallet, err := c.orderRepo.FindWalletByUserID(ctx, userID)
 	if err != nil {
-		return transactions, err
+		return transactions, meta, err
 	} else if wallet.ID == 0 {
-		return transactions, fmt.Errorf("there is no wallet for user with user_id %v for showing transaction", userID)
+		return transactions, meta, fmt.Errorf("there is no wallet for user with user_id %v for showing transaction", userID)
 	}
 
 	// then find the transactions by wallet_id
-	transactions, err = c.orderRepo.FindWalletTransactions(ctx, wallet.ID, pagination)
+	transactions, meta, err = c.orderRepo.FindWalletTransactions(ctx, wallet.ID, page)
 
 	if err != nil {
-		return transactions, err
+		return transactions, meta, err
 	}
 
 	log.Printf("successfully got user transactions for user with user_id %v and wallet_id %v", userID, wallet.ID)
 
-	return transactions, nil
+	return transactions, meta, nil
+}
+
+// To find the user wallet with lock on it, if user have no wallet then create a new wallet
+// should call with a transaction repository so the wallet row stay locked until the balance is updated
+func findUserWalletForUpdate(ctx context.Context, trxRepo interfaces.OrderRepository, userID uint) (models.Wallet, error) {
+
+	wallet, err := trxRepo.FindWalletByUserIDForUpdate(ctx, userID)
+	if err != nil {
+		return models.Wallet{}, utils.PrependMessageToError(err, "failed to find user wallet")
+	}
+	if wallet.ID != 0 {
+		return wallet, nil
+	}
+
+	_, err = trxRepo.SaveWallet(ctx, userID)
+	if err != nil {
+		return models.Wallet{}, utils.PrependMessageToError(err, "failed to create a wallet for user")
+	}
+
+	// wallet is found again to lock it, it may be created by another request at the same time
+	wallet, err = trxRepo.FindWalletByUserIDForUpdate(ctx, userID)
+	if err != nil {
+		return models.Wallet{}, utils.PrependMessageToError(err, "failed to find user wallet")
+	}
+
+	return wallet, nil
+}
+
+// details of an amount credit or debit on user wallet and the source which caused it
+type walletEntry struct {
+	UserID     uint
+	Amount     uint
+	SourceType models.TransactionSourceType
+	SourceID   uint
+	Note       string
+}
+
+// To add the amount to user wallet and save the credit transaction with the balance after it
+func creditUserWallet(ctx context.Context, trxRepo interfaces.OrderRepository, entry walletEntry) error {
+
+	wallet, err := findUserWallet(ctx, trxRepo, entry.UserID)
+	if err != nil {
+		return err
+	}
+
+	balance, err := trxRepo.IncrementWalletAmount(ctx, wallet.ID, entry.Amount)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update user wallet")
+	}
+
+	return saveWalletTransaction(ctx, trxRepo, wallet.ID, models.Credit, balance, entry)
+}
+
+// To take the amount from user wallet and save the debit transaction with the balance after it
+func debitUserWallet(ctx context.Context, trxRepo interfaces.OrderRepository, entry walletEntry) error {
+
+	wallet, err := findUserWallet(ctx, trxRepo, entry.UserID)
+	if err != nil {
+		return err
+	}
+
+	balance, debited, err := trxRepo.DecrementWalletAmount(ctx, wallet.ID, entry.Amount)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to update user wallet")
+	}
+	if !debited {
+		return ErrInsufficientWalletBalance
+	}
+
+	return saveWalletTransaction(ctx, trxRepo, wallet.ID, models.Debit, balance, entry)
+}
+
+// To find the user wallet and create a new wallet if user have no wallet
+func findUserWallet(ctx context.Context, repo interfaces.OrderRepository, userID uint) (models.Wallet, error) {
+
+	wallet, err := repo.FindWalletByUserID(ctx, userID)
+	if err != nil {
+		return models.Wallet{}, utils.PrependMessageToError(err, "failed to find user wallet")
+	}
+	if wallet.ID != 0 {
+		return wallet, nil
+	}
+
+	_, err = repo.SaveWallet(ctx, userID)
+	if err != nil {
+		return models.Wallet{}, utils.PrependMessageToError(err, "failed to create a wallet for user")
+	}
+
+	// wallet may be created by another request at the same time
+	wallet, err = repo.FindWalletByUserID(ctx, userID)
+	if err != nil {
+		return models.Wallet{}, utils.PrependMessageToError(err, "failed to find user wallet")
+	}
+
+	return wallet, nil
+}
+
+func saveWalletTransaction(ctx context.Context, trxRepo interfaces.OrderRepository, walletID uint,
+	trxType models.TransactionType, balance uint, entry walletEntry) error {
+
+	err := trxRepo.SaveWalletTransaction(ctx, models.Transaction{
+		WalletID:        walletID,
+		TransactionType: trxType,
+		Amount:          entry.Amount,
+		SourceType:      entry.SourceType,
+		SourceID:        entry.SourceID,
+		BalanceAfter:    balance,
+		Note:            entry.Note,
+	})
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to save wallet transaction")
+	}
+
+	return nil
+}
+
+// To return the wallet amount paid for an order back to user wallet and mark those payments as refunded
+func refundOrderWalletPayments(ctx context.Context, trxRepo interfaces.OrderRepository, shopOrder models.ShopOrder) error {
+
+	orderPayments, err := trxRepo.FindOrderPaymentsByShopOrderID(ctx, shopOrder.ID)
+	if err != nil {
+		return utils.PrependMessageToError(err, "failed to find order payments")
+	}
+
+	for _, orderPayment := range orderPayments {
+		if orderPayment.PaymentType != string(commonConstant.WalletPayment) ||
+			orderPayment.Status != string(commonConstant.PaymentStatusSucceeded) {
+			continue
+		}
+
+		err = creditUserWallet(ctx, trxRepo, walletEntry{
+			UserID:     shopOrder.UserID,
+			Amount:     orderPayment.Amount,
+			SourceType: models.SourceOrder,
+			SourceID:   shopOrder.ID,
+			Note:       "wallet payment returned for unpaid order",
+		})
+		if err != nil {
+			return err
+		}
+
+		err = trxRepo.UpdateOrderPaymentStatus(ctx, orderPayment.OrderPaymentID, commonConstant.PaymentStatusRefunded, "")
+		if err != nil {
+			return utils.PrependMessageToError(err, "failed to update order payment status")
+		}
+	}
+
+	return nil
+}
+
+// To find the amount still need to pay for an order after the succeeded payments
+func findShopOrderAmountDue(ctx context.Context, repo interfaces.OrderRepository, shopOrder models.ShopOrder) (uint, error) {
+
+	orderPayments, err := repo.FindOrderPaymentsByShopOrderID(ctx, shopOrder.ID)
+	if err != nil {
+		return 0, utils.PrependMessageToError(err, "failed to find order payments")
+	}
+
+	var paidAmount uint
+	for _, orderPayment := range orderPayments {
+		if orderPayment.Status == string(commonConstant.PaymentStatusSucceeded) {
+			paidAmount += orderPayment.Amount
+		}
+	}
+
+	if paidAmount >= shopOrder.OrderTotalPrice {
+		return 0, nil
+	}
+	return shopOrder.OrderTotalPrice - paidAmount, nil
+}
+
+// To find the wallets which total amount is not same as the sum of its ledger transactions
+func (c *OrderUseCase) FindMismatchedWallets(ctx context.Context,
+	page pagination.Request) ([]responses.WalletReconciliation, pagination.Meta, error) {
+
+	wallets, meta, err := c.orderRepo.FindMismatchedWallets(ctx, page)
+	if err != nil {
+		return nil, meta, utils.PrependMessageToError(err, "failed to find mismatched wallets")
+	}
+
+	for i := range wallets {
+		wallets[i].Difference = int64(wallets[i].TotalAmount) - wallets[i].LedgerAmount
+	}
+
+	return wallets, meta, nil
 }
@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // To append the message to the error
@@ -20,3 +21,26 @@ func PrependMessageToError(err error, message string) error {
 	}
 	return fmt.Errorf("%s \n%w", message, err)
 }
+
+// error which can be retried after the duration, it wraps the actual error
+type retryAfterError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// To attach the time to wait before retry to the error, errors.Is still works with the given error
+func WithRetryAfter(err error, retryAfter time.Duration) error {
+	return &retryAfterError{err: err, retryAfter: retryAfter}
+}
+
+// To get the time to wait before retry from the error or from any error it wraps
+func GetRetryAfter(err error) (time.Duration, bool) {
+	var retryErr *retryAfterError
+	if errors.As(err, &retryErr) {
+		return retryErr.retryAfter, true
+	}
+	return 0, false
+}
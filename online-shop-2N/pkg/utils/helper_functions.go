@@ -18,6 +18,16 @@ func GetUserIdFromContext(ctx *gin.Context) uint {
 	return userID
 }
 
+// take session id of the access token from context
+func GetSessionIdFromContext(ctx *gin.Context) string {
+	return ctx.GetString("sessionId")
+}
+
+// take token id of the access token from context
+func GetTokenIdFromContext(ctx *gin.Context) string {
+	return ctx.GetString("tokenId")
+}
+
 func StringToUint(str string) (uint, error) {
 	val, err := strconv.Atoi(str)
 	return uint(val), err
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// E.164 allows maximum 15 digits including the country code
+const (
+	minPhoneDigits = 8
+	maxPhoneDigits = 15
+	// phone numbers without country code are from this country when it's not configured
+	DefaultCountryCode = "+84"
+)
+
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// To normalize the phone number to E.164 format (+<country code><number>).
+// numbers without country code (national numbers) get the default country code after the trunk prefix 0 is removed
+func NormalizePhoneNumber(phone, defaultCountryCode string) (string, error) {
+
+	// remove the separators people usually type on phone numbers
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+
+	switch {
+	case strings.HasPrefix(phone, "+"):
+		phone = phone[1:]
+	case strings.HasPrefix(phone, "00"): // international call prefix
+		phone = phone[2:]
+	default:
+		countryCode := strings.TrimPrefix(defaultCountryCode, "+")
+		if countryCode == "" || !isDigits(countryCode) {
+			return "", ErrInvalidPhoneNumber
+		}
+		phone = countryCode + strings.TrimLeft(phone, "0")
+	}
+
+	if !isDigits(phone) || phone[0] == '0' ||
+		len(phone) < minPhoneDigits || len(phone) > maxPhoneDigits {
+		return "", ErrInvalidPhoneNumber
+	}
+
+	return "+" + phone, nil
+}
+
+func isDigits(str string) bool {
+
+	if str == "" {
+		return false
+	}
+	for _, r := range str {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+
+	tests := []struct {
+		name               string
+		phone              string
+		defaultCountryCode string
+		want               string
+		wantErr            error
+	}{
+		{name: "e164", phone: "+84912345678", defaultCountryCode: "+84", want: "+84912345678"},
+		{name: "national with trunk prefix", phone: "0912345678", defaultCountryCode: "+84", want: "+84912345678"},
+		{name: "national without trunk prefix", phone: "912345678", defaultCountryCode: "+84", want: "+84912345678"},
+		{name: "other default country", phone: "09876543210", defaultCountryCode: "+91", want: "+919876543210"},
+		{name: "country code without plus", phone: "0912345678", defaultCountryCode: "84", want: "+84912345678"},
+		{name: "international call prefix", phone: "0084912345678", defaultCountryCode: "+91", want: "+84912345678"},
+		{name: "separators", phone: " +84 (91) 234-56.78 ", defaultCountryCode: "+84", want: "+84912345678"},
+		{name: "letters", phone: "+8491234abcd", defaultCountryCode: "+84", wantErr: ErrInvalidPhoneNumber},
+		{name: "too short", phone: "+841234", defaultCountryCode: "+84", wantErr: ErrInvalidPhoneNumber},
+		{name: "too long", phone: "+8491234567891234", defaultCountryCode: "+84", wantErr: ErrInvalidPhoneNumber},
+		{name: "country code starting with zero", phone: "+0912345678", defaultCountryCode: "+84",
+			wantErr: ErrInvalidPhoneNumber},
+		{name: "empty", phone: "", defaultCountryCode: "+84", wantErr: ErrInvalidPhoneNumber},
+		{name: "national without default country", phone: "0912345678", defaultCountryCode: "",
+			wantErr: ErrInvalidPhoneNumber},
+		{name: "invalid default country", phone: "0912345678", defaultCountryCode: "+8a",
+			wantErr: ErrInvalidPhoneNumber},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := NormalizePhoneNumber(test.phone, test.defaultCountryCode)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Fatalf("got phone %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/usecases/interfaces"
+	"time"
+)
+
+const (
+	defaultCatalogImportInterval = 5 * time.Second
+	defaultCatalogImportTimeout  = 30 * time.Minute
+)
+
+type CatalogImportWorker interface {
+	// Start run the pending imports on every interval until the context is done
+	Start(ctx context.Context)
+	// RunOnce run all the pending imports one by one at this moment
+	RunOnce(ctx context.Context) (jobIDs []uint, err error)
+}
+
+type catalogImportWorker struct {
+	catalogUseCase interfaces.CatalogUseCase
+	interval       time.Duration
+	// a job is stopped after the timeout, a job running longer than it is taken as stopped and run again
+	timeout time.Duration
+}
+
+func NewCatalogImportWorker(catalogUseCase interfaces.CatalogUseCase, cfg config.Config) CatalogImportWorker {
+
+	interval := cfg.CatalogImportInterval
+	if interval <= 0 {
+		interval = defaultCatalogImportInterval
+	}
+	timeout := cfg.CatalogImportTimeout
+	if timeout <= 0 {
+		timeout = defaultCatalogImportTimeout
+	}
+
+	return &catalogImportWorker{
+		catalogUseCase: catalogUseCase,
+		interval:       interval,
+		timeout:        timeout,
+	}
+}
+
+func (c *catalogImportWorker) Start(ctx context.Context) {
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	log.Printf("catalog import worker started with interval %v", c.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("catalog import worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := c.RunOnce(ctx); err != nil {
+				log.Printf("failed to run pending catalog imports \nerror:%v", err)
+			}
+		}
+	}
+}
+
+func (c *catalogImportWorker) RunOnce(ctx context.Context) ([]uint, error) {
+
+	var jobIDs []uint
+	for ctx.Err() == nil {
+		jobID, err := c.runNextJob(ctx)
+		if err != nil {
+			return jobIDs, err
+		}
+		if jobID == 0 {
+			break
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+
+	if len(jobIDs) > 0 {
+		log.Printf("successfully run catalog imports %v", jobIDs)
+	}
+
+	return jobIDs, nil
+}
+
+func (c *catalogImportWorker) runNextJob(ctx context.Context) (uint, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	return c.catalogUseCase.RunNextImportJob(ctx, time.Now().Add(-c.timeout))
+}
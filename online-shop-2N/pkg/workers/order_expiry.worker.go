@@ -0,0 +1,76 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/usecases/interfaces"
+	"time"
+)
+
+const (
+	defaultPaymentPendingTTL     = 30 * time.Minute
+	defaultPaymentExpiryInterval = time.Minute
+)
+
+type OrderExpiryWorker interface {
+	// Start run the expiry on every interval until the context is done
+	Start(ctx context.Context)
+	// RunOnce expire all pending payment orders older than ttl at this moment
+	RunOnce(ctx context.Context) (expiredOrderIDs []uint, err error)
+}
+
+type orderExpiryWorker struct {
+	orderUseCase interfaces.OrderUseCase
+	ttl          time.Duration
+	interval     time.Duration
+}
+
+func NewOrderExpiryWorker(orderUseCase interfaces.OrderUseCase, cfg config.Config) OrderExpiryWorker {
+
+	ttl := cfg.PaymentPendingTTL
+	if ttl <= 0 {
+		ttl = defaultPaymentPendingTTL
+	}
+	interval := cfg.PaymentExpiryInterval
+	if interval <= 0 {
+		interval = defaultPaymentExpiryInterval
+	}
+
+	return &orderExpiryWorker{
+		orderUseCase: orderUseCase,
+		ttl:          ttl,
+		interval:     interval,
+	}
+}
+
+func (c *orderExpiryWorker) Start(ctx context.Context) {
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	log.Printf("pending payment order expiry worker started with ttl %v and interval %v", c.ttl, c.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("pending payment order expiry worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := c.RunOnce(ctx); err != nil {
+				log.Printf("failed to expire pending payment orders \nerror:%v", err)
+			}
+		}
+	}
+}
+
+func (c *orderExpiryWorker) RunOnce(ctx context.Context) ([]uint, error) {
+
+	expireBefore := time.Now().Add(-c.ttl)
+
+	expiredOrderIDs, err := c.orderUseCase.ExpirePendingPaymentOrders(ctx, expireBefore)
+	if len(expiredOrderIDs) > 0 {
+		log.Printf("successfully expired pending payment orders %v", expiredOrderIDs)
+	}
+
+	return expiredOrderIDs, err
+}
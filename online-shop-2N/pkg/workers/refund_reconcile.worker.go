@@ -0,0 +1,76 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"online-shop-2N/pkg/config"
+	"online-shop-2N/pkg/usecases/interfaces"
+	"time"
+)
+
+const (
+	defaultRefundPendingTTL        = time.Hour
+	defaultRefundReconcileInterval = 10 * time.Minute
+)
+
+type RefundReconcileWorker interface {
+	// Start run the reconcile on every interval until the context is done
+	Start(ctx context.Context)
+	// RunOnce reconcile all gateway refunds pending for longer than ttl at this moment
+	RunOnce(ctx context.Context) (reconciledRefundIDs []uint, err error)
+}
+
+type refundReconcileWorker struct {
+	orderUseCase interfaces.OrderUseCase
+	ttl          time.Duration
+	interval     time.Duration
+}
+
+func NewRefundReconcileWorker(orderUseCase interfaces.OrderUseCase, cfg config.Config) RefundReconcileWorker {
+
+	ttl := cfg.RefundPendingTTL
+	if ttl <= 0 {
+		ttl = defaultRefundPendingTTL
+	}
+	interval := cfg.RefundReconcileInterval
+	if interval <= 0 {
+		interval = defaultRefundReconcileInterval
+	}
+
+	return &refundReconcileWorker{
+		orderUseCase: orderUseCase,
+		ttl:          ttl,
+		interval:     interval,
+	}
+}
+
+func (c *refundReconcileWorker) Start(ctx context.Context) {
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	log.Printf("pending refund reconcile worker started with ttl %v and interval %v", c.ttl, c.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("pending refund reconcile worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := c.RunOnce(ctx); err != nil {
+				log.Printf("failed to reconcile pending refunds \nerror:%v", err)
+			}
+		}
+	}
+}
+
+func (c *refundReconcileWorker) RunOnce(ctx context.Context) ([]uint, error) {
+
+	pendingBefore := time.Now().Add(-c.ttl)
+
+	reconciledRefundIDs, err := c.orderUseCase.ReconcilePendingRefunds(ctx, pendingBefore)
+	if len(reconciledRefundIDs) > 0 {
+		log.Printf("successfully reconciled pending refunds %v", reconciledRefundIDs)
+	}
+
+	return reconciledRefundIDs, err
+}